@@ -8,11 +8,17 @@ import (
 	"fmt"
 	"io/ioutil"
 	"log"
+	"mime"
+	"path/filepath"
 	"time"
 
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
 
 	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
 )
@@ -23,11 +29,21 @@ var (
 	certFile    = flag.String("cert", "certs/client-cert.pem", "Path to client certificate (for mTLS)")
 	keyFile     = flag.String("key", "certs/client-key.pem", "Path to client private key (for mTLS)")
 	enableMTLS  = flag.Bool("mtls", false, "Enable mutual TLS")
+	certOnly    = flag.Bool("cert-only", false, "Skip Register and authenticate with the client certificate alone (requires -mtls)")
 	useInsecure = flag.Bool("insecure", false, "Connect without TLS")
 	tenantID    = flag.String("tenant", "demo-tenant", "Tenant ID")
 	agentID     = flag.String("agent", "demo-agent", "Agent ID")
 	model       = flag.String("model", "gemma3:4b", "Model to use")
 	message     = flag.String("message", "Hello Gemma! How are you today?", "Message to send")
+	imageFile   = flag.String("image", "", "Image file to attach to the message (PNG, JPEG or WebP)")
+)
+
+const (
+	// maxAttempts bounds the retries of a rate limited call
+	maxAttempts = 3
+	// maxRetryDelay is the longest back-off worth waiting for; exhausted
+	// quotas ask to come back when they reset, hours later
+	maxRetryDelay = time.Minute
 )
 
 func main() {
@@ -49,29 +65,47 @@ func main() {
 	log.Printf("🔐 TLS: %v (mTLS: %v)", !*useInsecure, *enableMTLS)
 	log.Printf("")
 
-	// Step 1: Register and get session
-	log.Printf("1️⃣ Registering with gateway...")
-	sessionID, jwtToken, err := registerSession(handshakeClient)
-	if err != nil {
-		log.Fatalf("❌ Registration failed: %v", err)
-	}
-	log.Printf("✅ Registration successful!")
-	log.Printf("   📋 Session ID: %s", sessionID)
-	log.Printf("   🎫 JWT Token: %s...", jwtToken[:20])
-	log.Printf("")
-
-	// Step 2: Authenticate token
-	log.Printf("2️⃣ Authenticating token...")
-	if err := authenticateToken(handshakeClient, jwtToken); err != nil {
-		log.Fatalf("❌ Authentication failed: %v", err)
+	// With a mapped client certificate the gateway knows who we are: no
+	// session ID or token needed
+	var sessionID, jwtToken string
+	if *certOnly {
+		if !*enableMTLS {
+			log.Fatalf("❌ -cert-only requires -mtls")
+		}
+		log.Printf("1️⃣ Skipping registration, authenticating with the client certificate")
+		log.Printf("")
+	} else {
+		// Step 1: Register and get session
+		log.Printf("1️⃣ Registering with gateway...")
+		err = withRetry(func() (err error) {
+			sessionID, jwtToken, err = registerSession(handshakeClient)
+			return err
+		})
+		if err != nil {
+			log.Fatalf("❌ Registration failed: %v", err)
+		}
+		log.Printf("✅ Registration successful!")
+		log.Printf("   📋 Session ID: %s", sessionID)
+		log.Printf("   🎫 JWT Token: %s...", jwtToken[:20])
+		log.Printf("")
+
+		// Step 2: Authenticate token
+		log.Printf("2️⃣ Authenticating token...")
+		if err := authenticateToken(handshakeClient, jwtToken); err != nil {
+			log.Fatalf("❌ Authentication failed: %v", err)
+		}
+		log.Printf("✅ Authentication successful!")
+		log.Printf("")
 	}
-	log.Printf("✅ Authentication successful!")
-	log.Printf("")
 
 	// Step 3: Send chat message
 	log.Printf("3️⃣ Sending chat message...")
 	log.Printf("   💬 Message: %s", *message)
-	response, err := sendChatMessage(agentClient, sessionID, *message)
+	var response string
+	err = withRetry(func() (err error) {
+		response, err = sendChatMessage(agentClient, sessionID, jwtToken, *message)
+		return err
+	})
 	if err != nil {
 		log.Fatalf("❌ Chat failed: %v", err)
 	}
@@ -79,6 +113,13 @@ func main() {
 	log.Printf("   🤖 Gemma: %s", response)
 	log.Printf("")
 
+	// Step 4: Check the remaining budget of the tenant
+	log.Printf("4️⃣ Checking usage...")
+	if err := showUsage(agentClient, jwtToken); err != nil {
+		log.Fatalf("❌ Usage query failed: %v", err)
+	}
+	log.Printf("")
+
 	log.Printf("🎉 Demo completed successfully!")
 }
 
@@ -182,21 +223,108 @@ func authenticateToken(client mcpv1.HandshakeServiceClient, token string) error
 	return nil
 }
 
+// withRetry runs call, retrying it when the gateway rate limits it after
+// waiting as long as it asks
+func withRetry(call func() error) error {
+	err := call()
+	for attempt := 1; err != nil && attempt < maxAttempts; attempt++ {
+		delay, ok := retryDelay(err)
+		if !ok || delay > maxRetryDelay {
+			break
+		}
+		log.Printf("   ⏳ Rate limited, retrying in %s", delay)
+		time.Sleep(delay)
+		err = call()
+	}
+	return err
+}
+
+// showUsage prints how much of each tenant quota is used
+func showUsage(client mcpv1.AgentServiceClient, jwtToken string) error {
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	if jwtToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+jwtToken)
+	}
+
+	usage, err := client.GetUsage(ctx, &mcpv1.GetUsageRequest{})
+	if err != nil {
+		return err
+	}
+
+	log.Printf("   📊 Tenant %s, until %s:", usage.TenantId, usage.WindowEnd.AsTime().Local().Format(time.DateTime))
+	for _, quota := range []struct {
+		name  string
+		quota *mcpv1.Quota
+	}{
+		{"Sessions", usage.Sessions},
+		{"Requests", usage.Requests},
+		{"Tokens", usage.Tokens},
+	} {
+		if quota.quota.Limit == 0 {
+			log.Printf("      • %s: %d (unlimited)", quota.name, quota.quota.Used)
+		} else {
+			log.Printf("      • %s: %d of %d (%d left)", quota.name, quota.quota.Used, quota.quota.Limit, quota.quota.Remaining)
+		}
+	}
+	return nil
+}
+
+// retryDelay returns how long the gateway asked us to back off, from the
+// RetryInfo detail of a ResourceExhausted error
+func retryDelay(err error) (time.Duration, bool) {
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		return 0, false
+	}
+	for _, detail := range st.Details() {
+		if info, ok := detail.(*errdetails.RetryInfo); ok {
+			return info.RetryDelay.AsDuration(), true
+		}
+	}
+	return 0, false
+}
+
 // sendChatMessage sends a chat message and returns the response
-func sendChatMessage(client mcpv1.AgentServiceClient, sessionID, message string) (string, error) {
+func sendChatMessage(client mcpv1.AgentServiceClient, sessionID, jwtToken, message string) (string, error) {
 	ctx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
 
+	// AgentService requires the token issued by Register, unless the client
+	// certificate identifies us
+	if jwtToken != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+jwtToken)
+	}
+
 	req := &mcpv1.SingleChatRequest{
 		SessionId: sessionID,
 		Content:   message,
 		Model:     *model,
 	}
 
+	// Gemma 3 can look at an image along the message
+	if *imageFile != "" {
+		data, err := ioutil.ReadFile(*imageFile)
+		if err != nil {
+			return "", fmt.Errorf("failed to read image: %w", err)
+		}
+		req.Attachments = []*mcpv1.Attachment{{
+			Data:     data,
+			MimeType: mime.TypeByExtension(filepath.Ext(*imageFile)),
+			Name:     filepath.Base(*imageFile),
+		}}
+	}
+
 	resp, err := client.SingleChat(ctx, req)
 	if err != nil {
 		return "", err
 	}
 
+	if usage := resp.Usage; usage != nil {
+		log.Printf("   📊 %s: %d prompt + %d completion tokens in %s (%.1f tokens/s)",
+			usage.Model, usage.PromptTokens, usage.CompletionTokens,
+			usage.TotalDuration.AsDuration().Round(time.Millisecond), usage.TokensPerSecond)
+	}
+
 	return resp.Content, nil
 }
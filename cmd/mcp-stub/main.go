@@ -0,0 +1,151 @@
+// mcp-stub is a minimal MCP tool server for trying the MCP bridge of the
+// gateway without installing a real one. It serves a few tools on stdio,
+// or on streamable HTTP with -http:
+//
+//	mcp:
+//	  servers:
+//	    - name: stub
+//	      command: ./bin/mcp-stub
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"fmt"
+	"log"
+	"net/http"
+	"os"
+	"strings"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/mcp"
+)
+
+var (
+	httpAddr = flag.String("http", "", "Serve streamable HTTP on this address (e.g. localhost:9100) instead of stdio")
+	token    = flag.String("token", "", "Bearer token HTTP requests must send (empty accepts any request)")
+)
+
+func main() {
+	flag.Parse()
+	// stdout carries the messages, so logs go to stderr
+	log.SetOutput(os.Stderr)
+
+	server := mcp.NewServer(mcp.Config{
+		Info:         mcp.Implementation{Name: "mcp-stub", Version: "1.0.0"},
+		Instructions: "Test tools: echo, add, fail and sleep.",
+		Backend:      stubBackend{},
+	})
+
+	if *httpAddr == "" {
+		log.Printf("🧪 MCP stub serving on stdio")
+		err := server.ServeStdio(context.Background(), os.Stdin, os.Stdout, func(ctx context.Context) (context.Context, error) {
+			return ctx, nil
+		})
+		if err != nil {
+			log.Fatalf("❌ MCP stub failed: %v", err)
+		}
+		return
+	}
+
+	mux := http.NewServeMux()
+	mux.Handle("/mcp", server.HTTPHandler(mcp.HTTPConfig{
+		Authenticate: func(r *http.Request) (context.Context, string, error) {
+			if *token != "" && r.Header.Get("Authorization") != "Bearer "+*token {
+				return nil, "", status.Error(codes.Unauthenticated, "invalid token")
+			}
+			return r.Context(), "stub", nil
+		},
+	}))
+	log.Printf("🧪 MCP stub serving on http://%s/mcp", *httpAddr)
+	log.Fatal(http.ListenAndServe(*httpAddr, mux))
+}
+
+// stubBackend offers the test tools, and no prompts or resources
+type stubBackend struct{}
+
+func (stubBackend) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	return []mcp.Tool{
+		{
+			Name:        "echo",
+			Description: "Returns the text it gets.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"text":{"type":"string"}},"required":["text"]}`),
+		},
+		{
+			Name:        "add",
+			Description: "Adds two numbers.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"a":{"type":"number"},"b":{"type":"number"}},"required":["a","b"]}`),
+		},
+		{
+			Name:        "fail",
+			Description: "Always fails, with the message it gets.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"message":{"type":"string"}}}`),
+		},
+		{
+			Name:        "sleep",
+			Description: "Waits for some seconds before answering.",
+			InputSchema: json.RawMessage(`{"type":"object","properties":{"seconds":{"type":"number","minimum":0,"maximum":600}},"required":["seconds"]}`),
+		},
+	}, nil
+}
+
+func (stubBackend) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*mcp.CallToolResult, error) {
+	var args struct {
+		Text    string  `json:"text"`
+		A       float64 `json:"a"`
+		B       float64 `json:"b"`
+		Message string  `json:"message"`
+		Seconds float64 `json:"seconds"`
+	}
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, mcp.NewError(mcp.CodeInvalidParams, "invalid arguments: %v", err)
+		}
+	}
+	log.Printf("🧪 %s %s", name, arguments)
+
+	var text string
+	switch name {
+	case "echo":
+		text = args.Text
+	case "add":
+		text = fmt.Sprint(args.A + args.B)
+	case "fail":
+		message := strings.TrimSpace(args.Message)
+		if message == "" {
+			message = "failed on purpose"
+		}
+		return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(message)}, IsError: true}, nil
+	case "sleep":
+		select {
+		case <-time.After(time.Duration(args.Seconds * float64(time.Second))):
+		case <-ctx.Done():
+			log.Printf("🧪 sleep cancelled")
+			return nil, ctx.Err()
+		}
+		text = fmt.Sprintf("slept %gs", args.Seconds)
+	default:
+		return nil, mcp.NewError(mcp.CodeInvalidParams, "unknown tool %q", name)
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(text)}}, nil
+}
+
+func (stubBackend) ListPrompts(ctx context.Context) ([]mcp.Prompt, error) {
+	return nil, nil
+}
+
+func (stubBackend) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	return nil, mcp.NewError(mcp.CodeInvalidParams, "unknown prompt %q", name)
+}
+
+func (stubBackend) ListResources(ctx context.Context) ([]mcp.Resource, error) {
+	return nil, nil
+}
+
+func (stubBackend) ReadResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	return nil, mcp.NewError(mcp.CodeResourceNotFound, "resource %s not found", uri)
+}
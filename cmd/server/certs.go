@@ -0,0 +1,209 @@
+package main
+
+import (
+	"crypto/x509"
+	"errors"
+	"flag"
+	"fmt"
+	"log"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"time"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/pki"
+)
+
+const certsUsage = `Usage: server certs <command> [flags]
+
+Commands:
+  init     Create a CA (unless one exists), a server certificate and a client certificate
+  client   Issue a client certificate for a tenant and agent, signed by the CA
+  revoke   Add certificates to the CA's revocation list (for server.tls.mtls.crl_file)
+
+Run "server certs <command> -h" for the flags of each command.`
+
+const day = 24 * time.Hour
+
+// runCertsCommand implements the "certs" subcommand, a pure Go replacement of
+// scripts/gen-certs.sh
+func runCertsCommand(args []string) error {
+	if len(args) == 0 {
+		fmt.Fprintln(os.Stderr, certsUsage)
+		return errors.New("missing certs command")
+	}
+
+	switch args[0] {
+	case "init":
+		return certsInit(args[1:])
+	case "client":
+		return certsClient(args[1:])
+	case "revoke":
+		return certsRevoke(args[1:])
+	case "-h", "-help", "--help", "help":
+		fmt.Fprintln(os.Stderr, certsUsage)
+		return nil
+	default:
+		fmt.Fprintln(os.Stderr, certsUsage)
+		return fmt.Errorf("unknown certs command %q", args[0])
+	}
+}
+
+// certsInit creates the development PKI: CA, server and client certificates
+func certsInit(args []string) error {
+	flags := flag.NewFlagSet("certs init", flag.ExitOnError)
+	dir := flags.String("dir", "certs", "Directory for the certificates")
+	hosts := flags.String("hosts", "localhost,*.localhost,127.0.0.1,::1", "Comma-separated DNS names and IPs of the server certificate")
+	days := flags.Int("days", 365, "Validity of the server and client certificates, in days")
+	caDays := flags.Int("ca-days", 3650, "Validity of a new CA, in days")
+	trustDomain := flags.String("trust-domain", "gentleman-mcp", "Trust domain of the client certificate identity (server.tls.mtls.trust_domain)")
+	tenantID := flags.String("tenant", "demo-tenant", "Tenant of the client certificate")
+	agentID := flags.String("agent", "demo-agent", "Agent of the client certificate")
+	force := flags.Bool("force", false, "Replace an existing CA (invalidates every certificate it issued)")
+	flags.Parse(args)
+
+	log.Printf("🔐 Generating development certificates in %s/", *dir)
+
+	// 1. CA: reused when present, so re-running only renews the leaf certs
+	caCertFile, caKeyFile := filepath.Join(*dir, "ca-cert.pem"), filepath.Join(*dir, "ca-key.pem")
+	ca, err := pki.LoadCA(caCertFile, caKeyFile)
+	switch {
+	case err == nil && !*force:
+		log.Printf("1️⃣ Using existing CA %s (expires %s)", caCertFile, ca.Cert.NotAfter.Format(time.DateOnly))
+	case err != nil && !errors.Is(err, os.ErrNotExist) && !*force:
+		return fmt.Errorf("failed to load CA (use -force to create a new one): %w", err)
+	default:
+		ca, err = pki.NewCA(pki.Organization+" CA", time.Duration(*caDays)*day)
+		if err != nil {
+			return err
+		}
+		if err := ca.Save(caCertFile, caKeyFile); err != nil {
+			return err
+		}
+		log.Printf("1️⃣ Created CA %s", caCertFile)
+	}
+
+	// 2. Server certificate
+	serverHosts := splitList(*hosts)
+	if len(serverHosts) == 0 {
+		return errors.New("-hosts must list at least one name")
+	}
+	server, err := ca.IssueServer(serverHosts[0], serverHosts, time.Duration(*days)*day)
+	if err != nil {
+		return err
+	}
+	if err := server.Save(filepath.Join(*dir, "server-cert.pem"), filepath.Join(*dir, "server-key.pem")); err != nil {
+		return err
+	}
+	log.Printf("2️⃣ Created server certificate for %s", strings.Join(serverHosts, ", "))
+
+	// 3. Client certificate, with the identity the gateway maps to a tenant
+	identity := pki.SPIFFEID(*trustDomain, *tenantID, *agentID)
+	client, err := ca.IssueClient("client", []*url.URL{identity}, time.Duration(*days)*day)
+	if err != nil {
+		return err
+	}
+	if err := client.Save(filepath.Join(*dir, "client-cert.pem"), filepath.Join(*dir, "client-key.pem")); err != nil {
+		return err
+	}
+	log.Printf("3️⃣ Created client certificate for %s", identity)
+
+	log.Printf("✅ Certificates generated successfully!")
+	log.Printf("   📜 ca-cert.pem / 🔑 ca-key.pem         - CA")
+	log.Printf("   📜 server-cert.pem / 🔑 server-key.pem - Server")
+	log.Printf("   📜 client-cert.pem / 🔑 client-key.pem - Client (for mTLS)")
+	return nil
+}
+
+// certsClient issues a client certificate for a tenant and agent
+func certsClient(args []string) error {
+	flags := flag.NewFlagSet("certs client", flag.ExitOnError)
+	dir := flags.String("dir", "certs", "Directory of the CA and the new certificate")
+	tenantID := flags.String("tenant", "", "Tenant the certificate authenticates as (required)")
+	agentID := flags.String("agent", "", "Agent the certificate authenticates as (required)")
+	trustDomain := flags.String("trust-domain", "gentleman-mcp", "Trust domain of the identity (server.tls.mtls.trust_domain)")
+	name := flags.String("name", "", "File name prefix (default <tenant>-<agent>)")
+	days := flags.Int("days", 365, "Validity in days")
+	flags.Parse(args)
+
+	if *tenantID == "" || *agentID == "" {
+		return errors.New("-tenant and -agent are required")
+	}
+	if *name == "" {
+		*name = *tenantID + "-" + *agentID
+	}
+
+	ca, err := pki.LoadCA(filepath.Join(*dir, "ca-cert.pem"), filepath.Join(*dir, "ca-key.pem"))
+	if err != nil {
+		return fmt.Errorf("failed to load CA (run \"certs init\" first): %w", err)
+	}
+
+	identity := pki.SPIFFEID(*trustDomain, *tenantID, *agentID)
+	client, err := ca.IssueClient(*agentID, []*url.URL{identity}, time.Duration(*days)*day)
+	if err != nil {
+		return err
+	}
+	certFile, keyFile := filepath.Join(*dir, *name+"-cert.pem"), filepath.Join(*dir, *name+"-key.pem")
+	if err := client.Save(certFile, keyFile); err != nil {
+		return err
+	}
+
+	log.Printf("✅ Created client certificate %s for %s", certFile, identity)
+	return nil
+}
+
+// certsRevoke adds certificates to the CRL of the CA
+func certsRevoke(args []string) error {
+	flags := flag.NewFlagSet("certs revoke", flag.ExitOnError)
+	flags.Usage = func() {
+		fmt.Fprintln(flags.Output(), "Usage: server certs revoke [flags] <cert.pem>...")
+		flags.PrintDefaults()
+	}
+	dir := flags.String("dir", "certs", "Directory of the CA")
+	crlFile := flags.String("crl", "", "CRL file to update (default <dir>/ca-crl.pem)")
+	days := flags.Int("days", 30, "Validity of the new CRL in days; revoke again or re-run before it expires")
+	flags.Parse(args)
+
+	if flags.NArg() == 0 {
+		flags.Usage()
+		return errors.New("no certificates to revoke")
+	}
+	if *crlFile == "" {
+		*crlFile = filepath.Join(*dir, "ca-crl.pem")
+	}
+
+	ca, err := pki.LoadCA(filepath.Join(*dir, "ca-cert.pem"), filepath.Join(*dir, "ca-key.pem"))
+	if err != nil {
+		return fmt.Errorf("failed to load CA: %w", err)
+	}
+
+	var certs []*x509.Certificate
+	for _, path := range flags.Args() {
+		cert, err := pki.LoadCertificate(path)
+		if err != nil {
+			return err
+		}
+		certs = append(certs, cert)
+	}
+	if err := ca.Revoke(*crlFile, time.Duration(*days)*day, certs...); err != nil {
+		return err
+	}
+
+	for _, cert := range certs {
+		log.Printf("🚫 Revoked %s (serial %s)", cert.Subject, cert.SerialNumber.Text(16))
+	}
+	log.Printf("✅ Updated %s", *crlFile)
+	return nil
+}
+
+// splitList splits a comma-separated flag value
+func splitList(value string) []string {
+	var items []string
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
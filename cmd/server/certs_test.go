@@ -0,0 +1,161 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"net"
+	"path/filepath"
+	"testing"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/pki"
+)
+
+// handshake connects a client with the certificate at certFile to a server
+// with serverConfig, and returns the state the server saw
+func handshake(t *testing.T, dir string, serverConfig *tls.Config, certFile, keyFile string) (tls.ConnectionState, error) {
+	t.Helper()
+	clientCert, err := tls.LoadX509KeyPair(certFile, keyFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := pki.LoadCertificate(filepath.Join(dir, "ca-cert.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca)
+
+	serverConn, clientConn := net.Pipe()
+	defer serverConn.Close()
+	defer clientConn.Close()
+	client := tls.Client(clientConn, &tls.Config{
+		RootCAs:      roots,
+		Certificates: []tls.Certificate{clientCert},
+		ServerName:   "gateway.test",
+		NextProtos:   []string{"h2"},
+	})
+	go func() {
+		client.Handshake()
+		// TLS 1.3 reports client certificate failures on the first read
+		client.Read(make([]byte, 1))
+	}()
+
+	server := tls.Server(serverConn, serverConfig)
+	err = server.Handshake()
+	return server.ConnectionState(), err
+}
+
+func TestCertsCommandIssuesMappedCertificates(t *testing.T) {
+	dir := t.TempDir()
+	run := func(args ...string) {
+		t.Helper()
+		if err := runCertsCommand(args); err != nil {
+			t.Fatalf("certs %v: %v", args, err)
+		}
+	}
+	run("init", "-dir", dir, "-hosts", "gateway.test,10.0.0.1", "-trust-domain", "gateway.local", "-tenant", "acme", "-agent", "crawler")
+	run("client", "-dir", dir, "-trust-domain", "gateway.local", "-tenant", "acme", "-agent", "billing")
+
+	ca, err := pki.LoadCA(filepath.Join(dir, "ca-cert.pem"), filepath.Join(dir, "ca-key.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	roots := x509.NewCertPool()
+	roots.AddCert(ca.Cert)
+
+	// The server certificate chains to the CA for every requested host
+	server, err := pki.LoadCertificate(filepath.Join(dir, "server-cert.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, host := range []string{"gateway.test", "10.0.0.1"} {
+		if _, err := server.Verify(x509.VerifyOptions{Roots: roots, DNSName: host}); err != nil {
+			t.Errorf("server certificate for %s: %v", host, err)
+		}
+	}
+	if _, err := server.Verify(x509.VerifyOptions{Roots: roots, DNSName: "other.test"}); err == nil {
+		t.Error("server certificate valid for a host it was not issued for")
+	}
+
+	// The client certificates chain to the CA, carry their SPIFFE ID and map
+	// to their tenant and agent
+	mapper := auth.NewCertMapper("gateway.local", "gemma3:4b", nil)
+	for name, agentID := range map[string]string{"client": "crawler", "acme-billing": "billing"} {
+		client, err := pki.LoadCertificate(filepath.Join(dir, name+"-cert.pem"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		_, err = client.Verify(x509.VerifyOptions{Roots: roots, KeyUsages: []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}})
+		if err != nil {
+			t.Errorf("%s certificate: %v", name, err)
+		}
+		want := pki.SPIFFEID("gateway.local", "acme", agentID).String()
+		if len(client.URIs) != 1 || client.URIs[0].String() != want {
+			t.Errorf("%s certificate URIs = %v, want [%s]", name, client.URIs, want)
+		}
+		principal, ok := mapper.Principal(client)
+		if !ok || principal.TenantID != "acme" || principal.AgentID != agentID {
+			t.Errorf("%s certificate mapped to %+v, want acme/%s", name, principal, agentID)
+		}
+	}
+
+	// Running init again keeps the CA, so issued certificates stay valid
+	run("init", "-dir", dir, "-hosts", "gateway.test")
+	again, err := pki.LoadCertificate(filepath.Join(dir, "ca-cert.pem"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if !again.Equal(ca.Cert) {
+		t.Error("certs init replaced the existing CA")
+	}
+}
+
+func TestCertsCommandRevokesClients(t *testing.T) {
+	dir := t.TempDir()
+	if err := runCertsCommand([]string{"init", "-dir", dir, "-hosts", "gateway.test", "-trust-domain", "gateway.local", "-tenant", "acme", "-agent", "crawler"}); err != nil {
+		t.Fatal(err)
+	}
+	if err := runCertsCommand([]string{"client", "-dir", dir, "-trust-domain", "gateway.local", "-tenant", "acme", "-agent", "billing"}); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig := config.TLSConfig{
+		CertFile:   filepath.Join(dir, "server-cert.pem"),
+		KeyFile:    filepath.Join(dir, "server-key.pem"),
+		CACertFile: filepath.Join(dir, "ca-cert.pem"),
+		MTLS:       config.MTLSConfig{Enabled: true, RequireClientCert: true},
+	}
+	clientCert, clientKey := filepath.Join(dir, "client-cert.pem"), filepath.Join(dir, "client-key.pem")
+	billingCert, billingKey := filepath.Join(dir, "acme-billing-cert.pem"), filepath.Join(dir, "acme-billing-key.pem")
+
+	// The gateway accepts the certificate and maps the chain it verified
+	serverConfig, err := newServerTLSConfig(tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	state, err := handshake(t, dir, serverConfig, clientCert, clientKey)
+	if err != nil {
+		t.Fatalf("handshake: %v", err)
+	}
+	principal, ok := auth.NewCertMapper("gateway.local", "gemma3:4b", nil).Principal(state.VerifiedChains[0][0])
+	if !ok || principal.TenantID != "acme" || principal.AgentID != "crawler" {
+		t.Errorf("verified certificate mapped to %+v, want acme/crawler", principal)
+	}
+
+	// Once revoked, the certificate is rejected and the others still accepted
+	if err := runCertsCommand([]string{"revoke", "-dir", dir, clientCert}); err != nil {
+		t.Fatal(err)
+	}
+	tlsConfig.MTLS.CRLFile = filepath.Join(dir, "ca-crl.pem")
+	serverConfig, err = newServerTLSConfig(tlsConfig)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := handshake(t, dir, serverConfig, clientCert, clientKey); err == nil {
+		t.Error("handshake with a revoked certificate succeeded")
+	}
+	if _, err := handshake(t, dir, serverConfig, billingCert, billingKey); err != nil {
+		t.Errorf("handshake with a valid certificate: %v", err)
+	}
+}
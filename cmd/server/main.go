@@ -2,7 +2,9 @@ package main
 
 import (
 	"context"
+	"crypto/rand"
 	"crypto/tls"
+	"encoding/hex"
 	"flag"
 	"fmt"
 	"log"
@@ -11,6 +13,8 @@ import (
 	"os"
 	"os/signal"
 	"path/filepath"
+	"slices"
+	"strconv"
 	"strings"
 	"syscall"
 	"time"
@@ -18,69 +22,273 @@ import (
 	"github.com/improbable-eng/grpc-web/go/grpcweb"
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
 	"google.golang.org/grpc/reflection"
 
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
 	"github.com/Gentleman-Programming/gentleman-mcp/internal/handlers"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/logging"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/mcp"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/quota"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ratelimit"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
 	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
 )
 
+// Flags override the configuration file and environment when given
+// explicitly; their defaults only document the built-in values
+var defaults = config.Default()
+
 var (
-	port        = flag.String("port", "50051", "The server port")
-	webPort     = flag.String("web-port", "8080", "The gRPC-Web server port")
-	certFile    = flag.String("cert-file", "certs/server-cert.pem", "Path to the server certificate file")
-	keyFile     = flag.String("key-file", "certs/server-key.pem", "Path to the server private key file")
-	caCertFile  = flag.String("ca-cert-file", "certs/ca-cert.pem", "Path to the CA certificate file")
-	ollamaURL   = flag.String("ollama-url", "http://localhost:11434", "Ollama server URL")
-	enableMTLS  = flag.Bool("mtls", false, "Enable mutual TLS authentication")
-	insecure    = flag.Bool("insecure", false, "Run server without TLS (development only)")
-	enableWeb   = flag.Bool("enable-web", true, "Enable gRPC-Web proxy server")
-	corsOrigins = flag.String("cors-origins", "http://localhost:3000,http://localhost:8080", "Comma-separated list of allowed CORS origins")
+	configFile  = flag.String("config", envOr("GENTLEMAN_CONFIG", "config.yaml"), "Path to the YAML configuration file (skipped if the default is missing)")
+	environment = flag.String("env", os.Getenv("GENTLEMAN_ENV"), "Environment overlay from the config file (e.g. production, staging)")
+
+	port        = flag.Int("port", defaults.Server.Port, "The server port")
+	webPort     = flag.Int("web-port", defaults.Server.Web.Port, "The gRPC-Web server port")
+	certFile    = flag.String("cert-file", defaults.Server.TLS.CertFile, "Path to the server certificate file")
+	keyFile     = flag.String("key-file", defaults.Server.TLS.KeyFile, "Path to the server private key file")
+	caCertFile  = flag.String("ca-cert-file", defaults.Server.TLS.CACertFile, "Path to the CA certificate file")
+	ollamaURL   = flag.String("ollama-url", defaults.Ollama.BaseURL, "Ollama server URL")
+	enableMTLS  = flag.Bool("mtls", defaults.Server.TLS.MTLS.Enabled, "Enable mutual TLS authentication")
+	insecure    = flag.Bool("insecure", defaults.Server.Insecure, "Run server without TLS (development only)")
+	enableWeb   = flag.Bool("enable-web", defaults.Server.Web.Enabled, "Enable gRPC-Web proxy server")
+	enableMCP   = flag.Bool("enable-mcp", defaults.MCP.HTTP.Enabled, "Enable the MCP (Model Context Protocol) HTTP server")
+	mcpPort     = flag.Int("mcp-port", defaults.MCP.HTTP.Port, "The MCP HTTP server port")
+	mcpStdio    = flag.Bool("mcp-stdio", false, "Serve MCP on stdin/stdout for the host that launched the gateway, instead of the network servers")
+	corsOrigins = flag.String("cors-origins", strings.Join(defaults.Security.CORS.AllowedOrigins, ","), "Comma-separated list of allowed CORS origins")
+
+	historyMaxTurns = flag.Int("history-max-turns", defaults.Chat.History.MaxTurns, "Conversation exchanges remembered per session (0 = unlimited)")
+	historyMaxChars = flag.Int("history-max-chars", defaults.Chat.History.MaxChars, "Approximate conversation history size per session, in characters (0 = unlimited)")
+	systemPrompt    = flag.String("system-prompt", defaults.Chat.SystemPrompt, "Default system prompt for new chat sessions")
+
+	allowModelOverride = flag.Bool("allow-model-override", defaults.Chat.AllowModelOverride, "Let chat requests use a model other than the one registered for the session")
+
+	publicReflection = flag.Bool("public-reflection", defaults.Auth.PublicReflection, "Serve gRPC reflection without an authorization token")
+	publicHealth     = flag.Bool("public-health", defaults.Auth.PublicHealth, "Serve the gRPC health service without an authorization token")
+
+	jwtAlgorithm  = flag.String("jwt-algorithm", defaults.Auth.JWT.Algorithm, "JWT signing algorithm: HS256, RS256 or EdDSA")
+	jwtSecret     = flag.String("jwt-secret", defaults.Auth.JWT.SecretKey, "JWT secret key for HS256 (random per process if empty)")
+	jwtPrivateKey = flag.String("jwt-private-key", defaults.Auth.JWT.PrivateKeyFile, "PEM private key file for RS256/EdDSA token signing")
+	jwtPublicKey  = flag.String("jwt-public-key", defaults.Auth.JWT.PublicKeyFile, "PEM public key file for RS256/EdDSA token verification")
+	jwtIssuer     = flag.String("jwt-issuer", defaults.Auth.JWT.Issuer, "JWT issuer claim")
+
+	databaseType = flag.String("database-type", defaults.Database.Type, "Session store: memory or bolt")
+	databasePath = flag.String("database-path", defaults.Database.Path, "Session database file (bolt only)")
 )
 
 func main() {
+	logging.Setup(os.Stderr)
+
+	// "server certs ..." manages the development PKI instead of serving
+	if len(os.Args) > 1 && os.Args[1] == "certs" {
+		if err := runCertsCommand(os.Args[2:]); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
+		return
+	}
+
+	flag.Usage = func() {
+		fmt.Fprintf(flag.CommandLine.Output(), "Usage: %[1]s [flags]\n       %[1]s certs <init|client|revoke> [flags]\n\nFlags:\n", filepath.Base(os.Args[0]))
+		flag.PrintDefaults()
+	}
 	flag.Parse()
 
-	// Create listener
-	lis, err := net.Listen("tcp", fmt.Sprintf(":%s", *port))
+	cfg, err := loadConfig()
 	if err != nil {
-		log.Fatalf("❌ Failed to listen on port %s: %v", *port, err)
+		log.Fatalf("❌ Invalid configuration: %v", err)
+	}
+	setLogLevel(cfg)
+
+	// Check certificate files on startup (unless running insecure)
+	if cfg.TLSEnabled() {
+		if err := ensureCertFiles(cfg.Server.TLS); err != nil {
+			log.Fatalf("❌ %v", err)
+		}
 	}
 
 	// Setup gRPC server options
-	var opts []grpc.ServerOption
+	opts := []grpc.ServerOption{
+		grpc.MaxRecvMsgSize(cfg.Security.Validation.MaxMessageSize.Int()),
+	}
 
-	if !*insecure {
-		// Load TLS credentials
-		creds, err := loadTLSCredentials()
+	// Settings that can change on SIGHUP are read through the reloader
+	reload := newReloader(cfg)
+
+	if cfg.TLSEnabled() {
+		// Load TLS credentials. Every handshake uses the latest reloaded
+		// certificates.
+		serverTLS, err := newServerTLSConfig(cfg.Server.TLS)
 		if err != nil {
 			log.Fatalf("❌ Failed to load TLS credentials: %v", err)
 		}
-		opts = append(opts, grpc.Creds(creds))
-		log.Printf("🔐 TLS enabled (mTLS: %v)", *enableMTLS)
+		reload.storeTLS(serverTLS)
+		opts = append(opts, grpc.Creds(credentials.NewTLS(&tls.Config{
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				return reload.serverTLS.Load(), nil
+			},
+		})))
+		log.Printf("🔐 TLS enabled (mTLS: %v)", cfg.Server.TLS.MTLS.Enabled)
+		if cfg.Server.TLS.MTLS.Enabled {
+			log.Printf("🔒 Mutual TLS (mTLS) enabled")
+		}
 	} else {
 		log.Printf("⚠️  Running in INSECURE mode (no TLS)")
 	}
 
+	// Signed session tokens
+	tokenManager, err := loadTokenManager(cfg.Auth.JWT)
+	if err != nil {
+		log.Fatalf("❌ Failed to configure JWT signing: %v", err)
+	}
+
+	// Sessions survive restarts (and are shared between replicas) with a
+	// persistent store
+	sessionStore, err := store.Open(cfg.Database.Type, cfg.Database.Path)
+	if err != nil {
+		log.Fatalf("❌ Failed to open session store: %v", err)
+	}
+	defer sessionStore.Close()
+
+	// Only the configured tenants can register, each with its own defaults
+	// and allowed models
+	tenants := tenant.NewRegistry(tenantSettings(cfg))
+	logTenants(tenants)
+	reload.tenants = tenants
+
+	// Sessions, requests and tokens are counted per tenant in the session
+	// store, against the tenant quotas
+	quotas := quota.NewTracker(sessionStore, tenants)
+
+	// Every RPC needs a bearer token from Register, except the handshake itself
+	// and the reflection/health endpoints when configured as public
+	handshakeServer := handlers.NewHandshakeServer(handlers.HandshakeConfig{
+		Tokens:         tokenManager,
+		Sessions:       sessionStore,
+		Tenants:        tenants,
+		Quotas:         quotas,
+		AccessTokenTTL: cfg.Auth.JWT.Expiration.Std(),
+		SessionTTL:     cfg.Security.Validation.MaxSessionDuration.Std(),
+		DefaultModel:   cfg.Ollama.DefaultModel,
+	})
+	publicMethods := []string{
+		mcpv1.HandshakeService_Register_FullMethodName,
+		mcpv1.HandshakeService_Authenticate_FullMethodName,
+		mcpv1.HandshakeService_Refresh_FullMethodName,
+		mcpv1.HandshakeService_Revoke_FullMethodName,
+	}
+	if cfg.Auth.PublicReflection {
+		publicMethods = append(publicMethods, "/grpc.reflection.v1.ServerReflection/", "/grpc.reflection.v1alpha.ServerReflection/")
+	}
+	if cfg.Auth.PublicHealth {
+		publicMethods = append(publicMethods, "/grpc.health.v1.Health/")
+	}
+	authenticator := auth.NewAuthenticator(handshakeServer, publicMethods...)
+	authenticator.SetCertMapper(newCertMapper(cfg))
+	reload.authenticator = authenticator
+
+	// Tenants are throttled after authentication tells who they are. Health
+	// checks and reflection are never limited.
+	limiter := ratelimit.NewLimiter(tenants, globalLimit(cfg),
+		"/grpc.health.v1.Health/", "/grpc.reflection.v1.ServerReflection/", "/grpc.reflection.v1alpha.ServerReflection/")
+	reload.limiter = limiter
+	opts = append(opts,
+		grpc.ChainUnaryInterceptor(authenticator.UnaryServerInterceptor(), limiter.UnaryServerInterceptor()),
+		grpc.ChainStreamInterceptor(authenticator.StreamServerInterceptor(), limiter.StreamServerInterceptor()),
+	)
+
 	// Create gRPC server
 	server := grpc.NewServer(opts...)
 
+	// Tools the model can call, offered as chat.tools.enabled says
+	toolRegistry, err := tools.NewRegistry(tools.Builtin()...)
+	if err != nil {
+		log.Fatalf("❌ Failed to register tools: %v", err)
+	}
+	reload.tools = toolRegistry
+
+	// Tools of external MCP servers join them, for the tenants that allow them
+	bridge := mcp.ConnectBridge(context.Background(), mcpServers(cfg))
+	mcpTools := registerMCPTools(toolRegistry, bridge)
+	reload.mcpTools = mcpTools
+
 	// Register services
-	handshakeServer := handlers.NewHandshakeServer()
-	agentServer := handlers.NewAgentServer(*ollamaURL)
+	agentServer := handlers.NewAgentServer(handlers.AgentConfig{
+		OllamaBaseURL: cfg.Ollama.BaseURL,
+		OllamaTimeout: cfg.Ollama.Timeout.Std(),
+		Sessions:      handshakeServer,
+		Settings:      chatSettings(cfg, toolRegistry, mcpTools),
+		Tenants:       tenants,
+		Quotas:        quotas,
+		Attachments: handlers.AttachmentLimits{
+			MaxCount:     cfg.Security.Validation.Attachments.MaxCount,
+			MaxSize:      cfg.Security.Validation.Attachments.MaxSize.Int(),
+			AllowedTypes: cfg.Security.Validation.Attachments.AllowedTypes,
+		},
+		Tools:    toolRegistry,
+		MCPTools: mcpTools,
+	})
+
+	// Logging out a session also ends its chat streams
+	handshakeServer.OnSessionRevoked(agentServer.TerminateSession)
+	reload.agentServer = agentServer
+
+	// MCP hosts reach the same services through JSON-RPC
+	mcpBackend := handlers.NewMCPBackend(agentServer, mcpPrompts(cfg))
+	reload.mcpBackend = mcpBackend
+	mcpServer := newMCPServer(cfg, mcpBackend, limiter)
 
 	mcpv1.RegisterHandshakeServiceServer(server, handshakeServer)
 	mcpv1.RegisterAgentServiceServer(server, agentServer)
 
 	// Enable reflection for development (grpcurl support)
-	reflection.Register(server)
+	if cfg.Development.Reflection {
+		reflection.Register(server)
+	}
+
+	// Standard health checks for load balancers and orchestrators
+	healthServer := health.NewServer()
+	healthpb.RegisterHealthServer(server, healthServer)
 
 	// Start cleanup routine for expired sessions
-	go startSessionCleanup(handshakeServer)
+	go startSessionCleanup(handshakeServer, cfg.Auth.Sessions.CleanupInterval.Std())
+
+	// Reload the configuration on SIGHUP, and on file changes if enabled
+	go reload.watch(cfg.Development.HotReload)
+	if cfg.TLSEnabled() && cfg.Server.TLS.ReloadInterval > 0 {
+		go reload.watchCertificates(cfg.Server.TLS.ReloadInterval.Std())
+	}
+
+	// A host that launched the gateway talks MCP on stdio: no network
+	// servers, and the process ends with the host
+	if *mcpStdio {
+		err := serveMCPStdio(mcpServer, handshakeServer, cfg.MCP.Stdio)
+		bridge.Close()
+		if err != nil {
+			log.Fatalf("❌ MCP stdio server failed: %v", err)
+		}
+		return
+	}
+
+	// Create listener
+	lis, err := net.Listen("tcp", fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port))
+	if err != nil {
+		log.Fatalf("❌ Failed to listen on port %d: %v", cfg.Server.Port, err)
+	}
 
 	// Start gRPC-Web proxy if enabled
 	var webServer *http.Server
-	if *enableWeb {
-		webServer = startGRPCWebServer(server)
+	if cfg.Server.Web.Enabled {
+		webServer = startGRPCWebServer(server, reload)
+	}
+
+	// Start the MCP HTTP server if enabled
+	var mcpHTTPServer *http.Server
+	if cfg.MCP.HTTP.Enabled {
+		mcpHTTPServer = startMCPServer(mcpServer, authenticator, reload)
 	}
 
 	// Graceful shutdown handling
@@ -90,38 +298,61 @@ func main() {
 		<-sigChan
 
 		log.Printf("🛑 Shutting down servers...")
+		ctx, cancel := context.WithTimeout(context.Background(), cfg.Server.ShutdownTimeout.Std())
+		defer cancel()
 
-		// Shutdown web server first
+		// Shutdown the HTTP servers first
 		if webServer != nil {
-			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
-			defer cancel()
 			webServer.Shutdown(ctx)
 		}
+		if mcpHTTPServer != nil {
+			mcpHTTPServer.Shutdown(ctx)
+		}
+
+		// Then shutdown gRPC server, cutting streams still open at the deadline
+		healthServer.Shutdown()
+		stopped := make(chan struct{})
+		go func() {
+			server.GracefulStop()
+			close(stopped)
+		}()
+		select {
+		case <-stopped:
+		case <-ctx.Done():
+			log.Printf("⚠️  Shutdown timeout reached, closing remaining connections")
+			server.Stop()
+		}
 
-		// Then shutdown gRPC server
-		server.GracefulStop()
+		// Finally stop the MCP servers whose tools the model used
+		bridge.Close()
 	}()
 
 	// Start server
 	log.Printf("🚀 Gentleman MCP Gateway starting...")
-	log.Printf("📡 gRPC Server listening on :%s", *port)
-	if *enableWeb {
-		log.Printf("🌐 gRPC-Web Server listening on :%s", *webPort)
+	log.Printf("📡 gRPC Server listening on %s:%d", cfg.Server.Host, cfg.Server.Port)
+	if cfg.Server.Web.Enabled {
+		log.Printf("🌐 gRPC-Web Server listening on %s:%d", cfg.Server.Host, cfg.Server.Web.Port)
 	}
-	log.Printf("🤖 Ollama URL: %s", *ollamaURL)
+	if cfg.MCP.HTTP.Enabled {
+		log.Printf("🧩 MCP Server listening on %s:%d%s", cfg.Server.Host, cfg.MCP.HTTP.Port, cfg.MCP.HTTP.Path)
+	}
+	log.Printf("🤖 Ollama URL: %s", cfg.Ollama.BaseURL)
 	log.Printf("📋 Services registered:")
 	log.Printf("   • HandshakeService - Authentication & session management")
-	log.Printf("   • AgentService - Chat with LLM")
+	log.Printf("   • AgentService - Chat with LLM (requires authorization: Bearer <jwt_token>)")
+	if cfg.MCP.HTTP.Enabled {
+		log.Printf("   • MCP - tools, prompts and resources for MCP hosts (same bearer token)")
+	}
 	log.Printf("")
 	log.Printf("💡 Test with grpcurl:")
-	if *insecure {
-		log.Printf("   grpcurl -plaintext -d '{\"tenant_id\":\"demo\",\"agent_id\":\"test\",\"model\":\"gemma3:4b\"}' localhost:%s mcp.v1.HandshakeService/Register", *port)
+	if !cfg.TLSEnabled() {
+		log.Printf("   grpcurl -plaintext -d '{\"tenant_id\":\"demo\",\"agent_id\":\"test\",\"model\":\"gemma3:4b\"}' localhost:%d mcp.v1.HandshakeService/Register", cfg.Server.Port)
 	} else {
-		log.Printf("   grpcurl -cacert certs/ca-cert.pem -d '{\"tenant_id\":\"demo\",\"agent_id\":\"test\",\"model\":\"gemma3:4b\"}' localhost:%s mcp.v1.HandshakeService/Register", *port)
+		log.Printf("   grpcurl -cacert %s -d '{\"tenant_id\":\"demo\",\"agent_id\":\"test\",\"model\":\"gemma3:4b\"}' localhost:%d mcp.v1.HandshakeService/Register", cfg.Server.TLS.CACertFile, cfg.Server.Port)
 	}
-	if *enableWeb {
+	if cfg.Server.Web.Enabled {
 		log.Printf("🌐 Test with browser:")
-		log.Printf("   Open http://localhost:%s in your browser", *webPort)
+		log.Printf("   Open http://localhost:%d in your browser", cfg.Server.Web.Port)
 	}
 	log.Printf("")
 
@@ -130,37 +361,244 @@ func main() {
 	}
 }
 
-// loadTLSCredentials loads the TLS credentials for the server
-func loadTLSCredentials() (credentials.TransportCredentials, error) {
-	// Load server certificate
-	serverCert, err := tls.LoadX509KeyPair(*certFile, *keyFile)
+// loadConfig resolves the configuration: defaults, config file, environment
+// overlay, GENTLEMAN_* variables and then the flags given on the command line
+func loadConfig() (*config.Config, error) {
+	path := configPath()
+	cfg, err := config.Load(path, *environment)
 	if err != nil {
-		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+		return nil, err
 	}
 
-	// Configure TLS
-	config := &tls.Config{
-		Certificates: []tls.Certificate{serverCert},
-		MinVersion:   tls.VersionTLS13, // Force TLS 1.3 as mentioned in README
+	flag.Visit(func(f *flag.Flag) {
+		switch f.Name {
+		case "port":
+			cfg.Server.Port = *port
+		case "web-port":
+			cfg.Server.Web.Port = *webPort
+		case "cert-file":
+			cfg.Server.TLS.CertFile = *certFile
+		case "key-file":
+			cfg.Server.TLS.KeyFile = *keyFile
+		case "ca-cert-file":
+			cfg.Server.TLS.CACertFile = *caCertFile
+		case "ollama-url":
+			cfg.Ollama.BaseURL = *ollamaURL
+		case "mtls":
+			cfg.Server.TLS.MTLS.Enabled = *enableMTLS
+			cfg.Server.TLS.MTLS.RequireClientCert = *enableMTLS
+		case "insecure":
+			cfg.Server.Insecure = *insecure
+		case "enable-web":
+			cfg.Server.Web.Enabled = *enableWeb
+		case "enable-mcp":
+			cfg.MCP.HTTP.Enabled = *enableMCP
+		case "mcp-port":
+			cfg.MCP.HTTP.Port = *mcpPort
+		case "cors-origins":
+			cfg.Security.CORS.AllowedOrigins = splitList(*corsOrigins)
+		case "history-max-turns":
+			cfg.Chat.History.MaxTurns = *historyMaxTurns
+		case "history-max-chars":
+			cfg.Chat.History.MaxChars = *historyMaxChars
+		case "system-prompt":
+			cfg.Chat.SystemPrompt = *systemPrompt
+		case "allow-model-override":
+			cfg.Chat.AllowModelOverride = *allowModelOverride
+		case "public-reflection":
+			cfg.Auth.PublicReflection = *publicReflection
+		case "public-health":
+			cfg.Auth.PublicHealth = *publicHealth
+		case "jwt-algorithm":
+			cfg.Auth.JWT.Algorithm = *jwtAlgorithm
+		case "jwt-secret":
+			cfg.Auth.JWT.SecretKey = *jwtSecret
+		case "jwt-private-key":
+			cfg.Auth.JWT.PrivateKeyFile = *jwtPrivateKey
+		case "jwt-public-key":
+			cfg.Auth.JWT.PublicKeyFile = *jwtPublicKey
+		case "jwt-issuer":
+			cfg.Auth.JWT.Issuer = *jwtIssuer
+		case "database-type":
+			cfg.Database.Type = *databaseType
+		case "database-path":
+			cfg.Database.Path = *databasePath
+		}
+	})
+
+	if err := cfg.Validate(); err != nil {
+		return nil, err
 	}
 
-	if *enableMTLS {
-		// Load CA certificate for client verification
-		if *caCertFile == "" {
-			return nil, fmt.Errorf("CA certificate file is required for mTLS")
+	if path != "" {
+		log.Printf("📝 Loaded configuration from %s", path)
+	}
+	if cfg.Environment != "" {
+		log.Printf("📝 Environment: %s", cfg.Environment)
+	}
+	return cfg, nil
+}
+
+// configPath returns the config file to load, or "" when the default
+// config.yaml does not exist and the server runs on defaults and flags
+func configPath() string {
+	path := *configFile
+	if _, err := os.Stat(path); os.IsNotExist(err) && !flagSet("config") && os.Getenv("GENTLEMAN_CONFIG") == "" {
+		return ""
+	}
+	return path
+}
+
+// flagSet reports whether a flag was given on the command line
+func flagSet(name string) bool {
+	set := false
+	flag.Visit(func(f *flag.Flag) {
+		if f.Name == name {
+			set = true
+		}
+	})
+	return set
+}
+
+// envOr returns the environment variable, or fallback when unset
+func envOr(name, fallback string) string {
+	if value, ok := os.LookupEnv(name); ok {
+		return value
+	}
+	return fallback
+}
+
+// newCertMapper maps verified client certificates to tenants and agents, or
+// returns nil when mTLS is disabled
+func newCertMapper(cfg *config.Config) *auth.CertMapper {
+	mtls := cfg.Server.TLS.MTLS
+	if !cfg.TLSEnabled() || !mtls.Enabled {
+		return nil
+	}
+
+	identities := make([]auth.CertIdentity, 0, len(mtls.Identities))
+	for _, identity := range mtls.Identities {
+		identities = append(identities, auth.CertIdentity{
+			Match:    identity.Match,
+			TenantID: identity.TenantID,
+			AgentID:  identity.AgentID,
+			Model:    identity.Model,
+		})
+	}
+	// Identities without a model get the default model of their tenant
+	return auth.NewCertMapper(mtls.TrustDomain, "", identities)
+}
+
+// setLogLevel applies observability.logging.level, which Validate has
+// already checked
+func setLogLevel(cfg *config.Config) {
+	level, err := logging.ParseLevel(cfg.Observability.Logging.Level)
+	if err != nil {
+		log.Printf("⚠️  %v, logging at info", err)
+	}
+	logging.SetLevel(level)
+}
+
+// chatSettings returns the chat settings of the configuration. Enabled tools
+// that do not exist are left out with a warning, and so are the tools bridged
+// from MCP servers: only the mcp_tools of a tenant offer those.
+func chatSettings(cfg *config.Config, registry *tools.Registry, mcpTools []string) handlers.ChatSettings {
+	var offered []string
+	for _, name := range cfg.Chat.Tools.Enabled {
+		if slices.Contains(mcpTools, name) {
+			log.Printf("⚠️  chat.tools.enabled: %q is an MCP tool, allow it in the mcp_tools of the tenants instead", name)
+			continue
 		}
+		if !registry.Has(name) {
+			log.Printf("⚠️  chat.tools.enabled: unknown tool %q (available: %s)", name, strings.Join(registry.Names(), ", "))
+			continue
+		}
+		offered = append(offered, name)
+	}
+	if len(offered) > 0 {
+		log.Printf("🛠️  Tools offered to the model: %s", strings.Join(offered, ", "))
+	}
+
+	return handlers.ChatSettings{
+		History: handlers.HistoryConfig{
+			MaxTurns:     cfg.Chat.History.MaxTurns,
+			MaxChars:     cfg.Chat.History.MaxChars,
+			SystemPrompt: cfg.Chat.SystemPrompt,
+		},
+		AllowModelOverride: cfg.Chat.AllowModelOverride,
+		StructuredOutput: handlers.StructuredOutputConfig{
+			MaxRepairs: cfg.Chat.StructuredOutput.MaxRepairs,
+		},
+		Tools: handlers.ToolsConfig{
+			Enabled:       offered,
+			MaxRounds:     cfg.Chat.Tools.MaxRounds,
+			Timeout:       cfg.Chat.Tools.Timeout.Std(),
+			ClientTimeout: cfg.Chat.Tools.ClientTimeout.Std(),
+		},
+		StreamIdleTimeout: cfg.Chat.StreamIdleTimeout.Std(),
+	}
+}
 
-		// For mTLS, we would load the CA cert and set ClientAuth
-		config.ClientAuth = tls.RequireAndVerifyClientCert
-		log.Printf("🔒 Mutual TLS (mTLS) enabled")
+// tenantSettings returns the default tenant settings and the registered
+// tenants of the configuration
+func tenantSettings(cfg *config.Config) (tenant.Tenant, []tenant.Tenant) {
+	defaults := cfg.TenantSettings(config.DefaultTenant)
+	ids := cfg.TenantIDs()
+	tenants := make([]tenant.Tenant, 0, len(ids))
+	for _, id := range ids {
+		tenants = append(tenants, cfg.TenantSettings(id))
 	}
+	return defaults, tenants
+}
 
-	return credentials.NewTLS(config), nil
+// globalLimit returns the gateway-wide rate limit of security.rate_limiting
+func globalLimit(cfg *config.Config) ratelimit.GlobalLimit {
+	return ratelimit.GlobalLimit{
+		Enabled:           cfg.Security.RateLimiting.Enabled,
+		RequestsPerSecond: cfg.Security.RateLimiting.RequestsPerSecond,
+		Burst:             cfg.Security.RateLimiting.Burst,
+	}
+}
+
+// logTenants describes the tenant registry
+func logTenants(tenants *tenant.Registry) {
+	if tenants.Open() {
+		log.Printf("⚠️  No tenants configured: any tenant_id can register")
+		return
+	}
+	registered := tenants.List()
+	ids := make([]string, 0, len(registered))
+	for _, t := range registered {
+		ids = append(ids, t.ID)
+	}
+	log.Printf("🏢 %d tenant(s) registered: %s", len(ids), strings.Join(ids, ", "))
+}
+
+// loadTokenManager builds the JWT signer/verifier from auth.jwt
+func loadTokenManager(jwtConfig config.JWTConfig) (*auth.TokenManager, error) {
+	secret := jwtConfig.SecretKey
+	if strings.EqualFold(jwtConfig.Algorithm, "HS256") && secret == "" {
+		// Tokens will only be valid for this process
+		random := make([]byte, 32)
+		if _, err := rand.Read(random); err != nil {
+			return nil, fmt.Errorf("failed to generate JWT secret: %w", err)
+		}
+		secret = hex.EncodeToString(random)
+		log.Printf("⚠️  No JWT secret set (auth.jwt.secret_key or -jwt-secret): using a random key, tokens will not survive restarts or validate on other replicas")
+	}
+
+	return auth.NewTokenManager(auth.JWTConfig{
+		Algorithm:      jwtConfig.Algorithm,
+		SecretKey:      secret,
+		PrivateKeyFile: jwtConfig.PrivateKeyFile,
+		PublicKeyFile:  jwtConfig.PublicKeyFile,
+		Issuer:         jwtConfig.Issuer,
+	})
 }
 
 // startSessionCleanup runs a background goroutine to clean up expired sessions
-func startSessionCleanup(handshakeServer *handlers.HandshakeServer) {
-	ticker := time.NewTicker(1 * time.Minute) // Cleanup every minute
+func startSessionCleanup(handshakeServer *handlers.HandshakeServer, interval time.Duration) {
+	ticker := time.NewTicker(interval)
 	defer ticker.Stop()
 
 	for range ticker.C {
@@ -173,10 +611,10 @@ func startSessionCleanup(handshakeServer *handlers.HandshakeServer) {
 }
 
 // ensureCertFiles checks if certificate files exist and provides helpful error messages
-func ensureCertFiles() error {
-	files := []string{*certFile, *keyFile}
-	if *enableMTLS {
-		files = append(files, *caCertFile)
+func ensureCertFiles(tlsConfig config.TLSConfig) error {
+	files := []string{tlsConfig.CertFile, tlsConfig.KeyFile}
+	if tlsConfig.MTLS.Enabled {
+		files = append(files, tlsConfig.CACertFile)
 	}
 
 	for _, file := range files {
@@ -185,7 +623,7 @@ func ensureCertFiles() error {
 			return fmt.Errorf(`certificate file not found: %s
 
 💡 Generate development certificates with:
-   ./scripts/gen-certs.sh
+   go run ./cmd/server certs init
 
 📁 Expected certificate files:
    • %s (server certificate)
@@ -193,39 +631,36 @@ func ensureCertFiles() error {
    • %s (CA certificate, for mTLS)
 
 🔧 Or run without TLS for development:
-   go run cmd/server/main.go -insecure`, absPath, *certFile, *keyFile, *caCertFile)
+   go run ./cmd/server -insecure`, absPath, tlsConfig.CertFile, tlsConfig.KeyFile, tlsConfig.CACertFile)
 		}
 	}
 	return nil
 }
 
 // startGRPCWebServer starts the gRPC-Web proxy server
-func startGRPCWebServer(grpcServer *grpc.Server) *http.Server {
+func startGRPCWebServer(grpcServer *grpc.Server, reload *reloader) *http.Server {
+	cfg := reload.config()
+
 	// Create gRPC-Web wrapper
 	wrappedGrpc := grpcweb.WrapServer(grpcServer,
 		grpcweb.WithCorsForRegisteredEndpointsOnly(false),
 		grpcweb.WithOriginFunc(func(origin string) bool {
-			// Parse allowed origins
-			allowedOrigins := strings.Split(*corsOrigins, ",")
-			for _, allowedOrigin := range allowedOrigins {
-				if strings.TrimSpace(allowedOrigin) == origin {
-					return true
-				}
-			}
-			// Allow localhost for development
-			return strings.Contains(origin, "localhost") || strings.Contains(origin, "127.0.0.1")
+			// CORS settings are reloadable
+			return allowedOrigin(reload.config(), origin)
 		}),
 	)
 
 	// Create HTTP server
 	httpServer := &http.Server{
-		Addr: ":" + *webPort,
+		Addr: fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Web.Port),
 		Handler: http.HandlerFunc(func(resp http.ResponseWriter, req *http.Request) {
 			// Add CORS headers
-			resp.Header().Set("Access-Control-Allow-Origin", req.Header.Get("Origin"))
-			resp.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
-			resp.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Grpc-Web, X-User-Agent")
-			resp.Header().Set("Access-Control-Expose-Headers", "Grpc-Status, Grpc-Message")
+			if reload.config().Security.CORS.Enabled {
+				resp.Header().Set("Access-Control-Allow-Origin", req.Header.Get("Origin"))
+				resp.Header().Set("Access-Control-Allow-Methods", "GET, POST, OPTIONS")
+				resp.Header().Set("Access-Control-Allow-Headers", "Content-Type, Authorization, X-Grpc-Web, X-User-Agent")
+				resp.Header().Set("Access-Control-Expose-Headers", "Grpc-Status, Grpc-Message")
+			}
 
 			// Handle preflight requests
 			if req.Method == "OPTIONS" {
@@ -235,7 +670,7 @@ func startGRPCWebServer(grpcServer *grpc.Server) *http.Server {
 
 			// Serve static files for the web client
 			if req.URL.Path == "/" || req.URL.Path == "/index.html" {
-				serveWebClient(resp, req)
+				serveWebClient(resp, req, cfg)
 				return
 			}
 
@@ -252,7 +687,7 @@ func startGRPCWebServer(grpcServer *grpc.Server) *http.Server {
 
 	// Start server in goroutine
 	go func() {
-		log.Printf("🌐 Starting gRPC-Web server on :%s", *webPort)
+		log.Printf("🌐 Starting gRPC-Web server on %s", httpServer.Addr)
 		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
 			log.Printf("❌ gRPC-Web server error: %v", err)
 		}
@@ -261,8 +696,22 @@ func startGRPCWebServer(grpcServer *grpc.Server) *http.Server {
 	return httpServer
 }
 
+// allowedOrigin reports whether browsers on origin may call the gateway
+func allowedOrigin(cfg *config.Config, origin string) bool {
+	if !cfg.Security.CORS.Enabled {
+		return false
+	}
+	for _, allowedOrigin := range cfg.Security.CORS.AllowedOrigins {
+		if allowedOrigin == origin {
+			return true
+		}
+	}
+	// Allow localhost for development
+	return localOrigin(origin)
+}
+
 // serveWebClient serves a simple web client for testing
-func serveWebClient(w http.ResponseWriter, r *http.Request) {
+func serveWebClient(w http.ResponseWriter, r *http.Request, cfg *config.Config) {
 	html := `<!DOCTYPE html>
 <html>
 <head>
@@ -325,8 +774,8 @@ func serveWebClient(w http.ResponseWriter, r *http.Request) {
 
         function testConnection() {
             log('🔄 Testing gRPC-Web connection...');
-            log('✅ gRPC-Web proxy is running on port ` + *webPort + `');
-            log('📡 gRPC server is running on port ` + *port + `');
+            log('✅ gRPC-Web proxy is running on port ` + strconv.Itoa(cfg.Server.Web.Port) + `');
+            log('📡 gRPC server is running on port ` + strconv.Itoa(cfg.Server.Port) + `');
             log('🌐 Ready for frontend integration!');
         }
 
@@ -368,13 +817,3 @@ func serveWebClient(w http.ResponseWriter, r *http.Request) {
 	w.Header().Set("Content-Type", "text/html")
 	w.Write([]byte(html))
 }
-
-func init() {
-	// Check certificate files on startup (unless running insecure)
-	flag.Parse()
-	if !*insecure {
-		if err := ensureCertFiles(); err != nil {
-			log.Fatalf("❌ %v", err)
-		}
-	}
-}
@@ -0,0 +1,65 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"testing"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+)
+
+func TestAllowedOrigin(t *testing.T) {
+	cfg := config.Default()
+	cfg.Security.CORS.AllowedOrigins = []string{"https://app.example.com"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"http://localhost:3000", true},
+		{"https://127.0.0.1:8443", true},
+		{"http://[::1]:8080", true},
+		{"https://app.example.com", true},
+		{"http://localhost.evil.com", false},
+		{"http://127.0.0.1.attacker.net", false},
+		{"http://evil.com/localhost", false},
+		{"https://app.example.com.evil.com", false},
+	}
+	for _, tt := range tests {
+		if got := allowedOrigin(cfg, tt.origin); got != tt.want {
+			t.Errorf("allowedOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	// With CORS disabled browsers may not call gRPC-Web at all
+	cfg.Security.CORS.Enabled = false
+	for _, origin := range []string{"http://localhost:3000", "https://app.example.com"} {
+		if allowedOrigin(cfg, origin) {
+			t.Errorf("allowedOrigin(%q) with CORS disabled = true", origin)
+		}
+	}
+}
+
+func TestChatSettingsOffersKnownGatewayTools(t *testing.T) {
+	registry, err := tools.NewRegistry(tools.Builtin()...)
+	if err != nil {
+		t.Fatal(err)
+	}
+	err = registry.Register(tools.Tool{
+		Name:       "remote_delete",
+		Parameters: json.RawMessage(`{"type":"object"}`),
+		Handler:    func(context.Context, json.RawMessage) (string, error) { return "deleted", nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	cfg := config.Default()
+	cfg.Chat.Tools.Enabled = []string{"calculator", "missing", "remote_delete"}
+	settings := chatSettings(cfg, registry, []string{"remote_delete"})
+	if !slices.Equal(settings.Tools.Enabled, []string{"calculator"}) {
+		t.Errorf("enabled tools = %v, want only the gateway tool that exists", settings.Tools.Enabled)
+	}
+}
@@ -0,0 +1,262 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"errors"
+	"fmt"
+	"log"
+	"net"
+	"net/http"
+	"net/url"
+	"os"
+	"runtime/debug"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/handlers"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/mcp"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ratelimit"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// mcpInstructions tell MCP hosts what the gateway is for
+const mcpInstructions = "Gentleman MCP Gateway: use the chat tool to ask the local Gemma model, and the other tools for what the gateway offers the model. " +
+	"The session resource tells which tenant and model this connection uses."
+
+// newMCPServer builds the MCP server over the agent service. Every request
+// that reaches it counts against the rate limits of the caller's tenant.
+func newMCPServer(cfg *config.Config, backend *handlers.MCPBackend, limiter *ratelimit.Limiter) *mcp.Server {
+	return mcp.NewServer(mcp.Config{
+		Info:           mcp.Implementation{Name: "gentleman-mcp", Version: serverVersion()},
+		Instructions:   mcpInstructions,
+		Backend:        backend,
+		MaxMessageSize: cfg.Security.Validation.MaxMessageSize.Int(),
+		Admit: func(ctx context.Context, method string) error {
+			principal, ok := auth.FromContext(ctx)
+			if !ok {
+				return nil
+			}
+			return limiter.Allow(principal.TenantID, principal.AgentID)
+		},
+	})
+}
+
+// mcpPrompts returns the prompts of the configuration, warning about
+// placeholders that no argument fills
+func mcpPrompts(cfg *config.Config) []mcp.PromptTemplate {
+	prompts := make([]mcp.PromptTemplate, 0, len(cfg.MCP.Prompts))
+	for _, prompt := range cfg.MCP.Prompts {
+		template := mcp.PromptTemplate{
+			Prompt: mcp.Prompt{Name: prompt.Name, Description: prompt.Description},
+			Text:   prompt.Text,
+		}
+		for _, argument := range prompt.Arguments {
+			template.Arguments = append(template.Arguments, mcp.PromptArgument{
+				Name:        argument.Name,
+				Description: argument.Description,
+				Required:    argument.Required,
+			})
+		}
+		for _, name := range mcp.Placeholders(prompt.Text) {
+			if !slices.ContainsFunc(prompt.Arguments, func(argument config.PromptArgumentConfig) bool { return argument.Name == name }) {
+				log.Printf("⚠️  mcp.prompts: %s uses {{%s}}, which is not one of its arguments", prompt.Name, name)
+			}
+		}
+		prompts = append(prompts, template)
+	}
+	return prompts
+}
+
+// mcpServers returns the external MCP servers of the configuration
+func mcpServers(cfg *config.Config) []mcp.ServerConfig {
+	servers := make([]mcp.ServerConfig, 0, len(cfg.MCP.Servers))
+	for _, server := range cfg.MCP.Servers {
+		env := make([]string, 0, len(server.Env))
+		for name, value := range server.Env {
+			env = append(env, name+"="+value)
+		}
+		sort.Strings(env)
+
+		servers = append(servers, mcp.ServerConfig{
+			ClientConfig: mcp.ClientConfig{
+				Command: server.Command,
+				Args:    server.Args,
+				Env:     env,
+				Dir:     server.Dir,
+				URL:     server.URL,
+				Headers: server.Headers,
+				Name:    server.Name,
+				Info:    mcp.Implementation{Name: "gentleman-mcp", Version: serverVersion()},
+			},
+			Timeout: server.Timeout.Std(),
+			Tools:   server.Tools,
+		})
+	}
+	return servers
+}
+
+// registerMCPTools adds the tools of the bridged MCP servers to the registry
+// and returns their names. Tools named like a tool of the gateway are left
+// out.
+func registerMCPTools(registry *tools.Registry, bridge *mcp.Bridge) []string {
+	var names []string
+	for _, tool := range bridge.Tools() {
+		if registry.Has(tool.Name) {
+			log.Printf("⚠️  MCP tool %s left out: a tool of the gateway has that name", tool.Name)
+			continue
+		}
+		if err := registry.Register(tool); err != nil {
+			log.Printf("⚠️  MCP tool %s left out: %v", tool.Name, err)
+			continue
+		}
+		names = append(names, tool.Name)
+	}
+	if len(names) > 0 {
+		log.Printf("🔌 MCP tools for the tenants that allow them: %s", strings.Join(names, ", "))
+	}
+	return names
+}
+
+// allowedMCPOrigin vets the Origin of browser requests to the MCP endpoint:
+// the configured CORS origins, or pages served from this machine
+func allowedMCPOrigin(cfg *config.Config, origin string) bool {
+	if cfg.Security.CORS.Enabled && slices.Contains(cfg.Security.CORS.AllowedOrigins, origin) {
+		return true
+	}
+	return localOrigin(origin)
+}
+
+// localOrigin reports whether origin is a page served from this machine. The
+// host is compared exactly, so a rebinding domain like localhost.example.com
+// is not taken for localhost.
+func localOrigin(origin string) bool {
+	parsed, err := url.Parse(origin)
+	if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") {
+		return false
+	}
+	switch parsed.Hostname() {
+	case "localhost", "127.0.0.1", "::1":
+		return true
+	}
+	return false
+}
+
+// startMCPServer serves the streamable HTTP transport of MCP on its own port,
+// authenticated like the gRPC services: by the bearer token of a session,
+// or by a client certificate with mTLS. It uses the TLS settings of the
+// gRPC server.
+func startMCPServer(mcpServer *mcp.Server, authenticator *auth.Authenticator, reload *reloader) *http.Server {
+	cfg := reload.config()
+
+	mux := http.NewServeMux()
+	mux.Handle(cfg.MCP.HTTP.Path, mcpServer.HTTPHandler(mcp.HTTPConfig{
+		Authenticate: func(r *http.Request) (context.Context, string, error) {
+			ctx, err := authenticator.AuthenticateHTTP(r)
+			if err != nil {
+				return nil, "", err
+			}
+			// MCP sessions belong to the gateway session that opened them
+			principal, _ := auth.FromContext(ctx)
+			return ctx, principal.SessionID, nil
+		},
+		AllowOrigin: func(origin string) bool {
+			return allowedMCPOrigin(reload.config(), origin)
+		},
+		SessionTimeout: cfg.MCP.HTTP.SessionTimeout.Std(),
+	}))
+
+	addr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.MCP.HTTP.Port)
+	listener, err := net.Listen("tcp", addr)
+	if err != nil {
+		log.Fatalf("❌ Failed to listen on MCP port %d: %v", cfg.MCP.HTTP.Port, err)
+	}
+	if cfg.TLSEnabled() {
+		// Same certificates as the gRPC server, also for HTTP/1.1 hosts
+		listener = tls.NewListener(listener, &tls.Config{
+			GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+				serverTLS := reload.serverTLS.Load().Clone()
+				serverTLS.NextProtos = []string{"h2", "http/1.1"}
+				return serverTLS, nil
+			},
+		})
+	}
+
+	httpServer := &http.Server{Addr: addr, Handler: mux}
+	go func() {
+		log.Printf("🧩 Starting MCP server on %s%s", addr, cfg.MCP.HTTP.Path)
+		if err := httpServer.Serve(listener); err != nil && err != http.ErrServerClosed {
+			log.Printf("❌ MCP server error: %v", err)
+		}
+	}()
+
+	return httpServer
+}
+
+// serveMCPStdio serves MCP on stdin and stdout for the host that launched
+// the gateway, until stdin closes. The host gets a session registered for
+// mcp.stdio, as if it had called Register.
+func serveMCPStdio(mcpServer *mcp.Server, handshakeServer *handlers.HandshakeServer, identity config.MCPStdioConfig) error {
+	if identity.TenantID == "" {
+		return errors.New("mcp.stdio.tenant_id: required to serve MCP on stdio")
+	}
+	session := &stdioSession{handshake: handshakeServer, identity: identity}
+	if _, err := session.context(context.Background()); err != nil {
+		return err
+	}
+
+	log.Printf("🧩 Serving MCP on stdio (tenant: %s, agent: %s)", identity.TenantID, identity.AgentID)
+	return mcpServer.ServeStdio(context.Background(), os.Stdin, os.Stdout, session.context)
+}
+
+// stdioSession is the gateway session of the host of the stdio transport.
+// A new one is registered when it expires or is revoked.
+type stdioSession struct {
+	handshake *handlers.HandshakeServer
+	identity  config.MCPStdioConfig
+
+	mutex     sync.Mutex
+	principal *auth.Principal
+}
+
+// context returns ctx carrying the principal of a live session
+func (s *stdioSession) context(ctx context.Context) (context.Context, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.principal != nil {
+		if _, err := s.handshake.LookupSession(s.principal.SessionID); err == nil {
+			return auth.NewContext(ctx, s.principal), nil
+		}
+		log.Printf("🔄 MCP stdio session %s ended, registering a new one", s.principal.SessionID)
+	}
+
+	registered, err := s.handshake.Register(ctx, &mcpv1.RegisterRequest{
+		TenantId: s.identity.TenantID,
+		AgentId:  s.identity.AgentID,
+		Model:    s.identity.Model,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to register the MCP stdio session: %w", err)
+	}
+	principal, err := s.handshake.ValidateToken(ctx, registered.JwtToken)
+	if err != nil {
+		return nil, fmt.Errorf("failed to register the MCP stdio session: %w", err)
+	}
+
+	s.principal = principal
+	return auth.NewContext(ctx, principal), nil
+}
+
+// serverVersion is the module version the binary was built from
+func serverVersion() string {
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	return "dev"
+}
@@ -0,0 +1,41 @@
+package main
+
+import (
+	"testing"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+)
+
+func TestAllowedMCPOrigin(t *testing.T) {
+	cfg := config.Default()
+	cfg.Security.CORS.AllowedOrigins = []string{"https://app.example.com"}
+
+	tests := []struct {
+		origin string
+		want   bool
+	}{
+		{"http://localhost", true},
+		{"http://localhost:3000", true},
+		{"https://127.0.0.1:8443", true},
+		{"http://[::1]:8080", true},
+		{"https://app.example.com", true},
+		{"http://localhost.evil.com", false},
+		{"http://127.0.0.1.attacker.net", false},
+		{"http://evil.com/localhost", false},
+		{"http://evil.com?127.0.0.1", false},
+		{"file://localhost", false},
+		{"null", false},
+		{"https://app.example.com.evil.com", false},
+	}
+	for _, tt := range tests {
+		if got := allowedMCPOrigin(cfg, tt.origin); got != tt.want {
+			t.Errorf("allowedMCPOrigin(%q) = %v, want %v", tt.origin, got, tt.want)
+		}
+	}
+
+	// Configured origins only count while CORS is enabled
+	cfg.Security.CORS.Enabled = false
+	if allowedMCPOrigin(cfg, "https://app.example.com") {
+		t.Error("configured origin allowed with CORS disabled")
+	}
+}
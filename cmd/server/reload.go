@@ -0,0 +1,198 @@
+package main
+
+import (
+	"context"
+	"crypto/tls"
+	"log"
+	"os"
+	"os/signal"
+	"sync"
+	"sync/atomic"
+	"syscall"
+	"time"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/handlers"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ratelimit"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+)
+
+// configPollInterval is how often the config file is checked when
+// development.hot_reload is enabled
+const configPollInterval = 2 * time.Second
+
+// reloader re-reads the configuration while the server runs and swaps in the
+// settings that can change without a restart: CORS, chat settings, MCP
+// prompts, tenants, rate limits, the log level, TLS certificates and client
+// certificate identities. Active streams are not interrupted.
+type reloader struct {
+	mutex   sync.Mutex // serializes reloads
+	current atomic.Pointer[config.Config]
+
+	// serverTLS is served to every new TLS handshake
+	serverTLS     atomic.Pointer[tls.Config]
+	agentServer   *handlers.AgentServer
+	authenticator *auth.Authenticator
+	tenants       *tenant.Registry
+	limiter       *ratelimit.Limiter
+	tools         *tools.Registry
+	mcpTools      []string // of the registry, bridged from MCP servers
+	mcpBackend    *handlers.MCPBackend
+}
+
+func newReloader(cfg *config.Config) *reloader {
+	r := &reloader{}
+	r.current.Store(cfg)
+	return r
+}
+
+// config returns the configuration in effect
+func (r *reloader) config() *config.Config {
+	return r.current.Load()
+}
+
+// watch reloads on SIGHUP and, with hotReload, when the config file changes
+func (r *reloader) watch(hotReload bool) {
+	if hotReload {
+		if path := configPath(); path != "" {
+			log.Printf("👀 Watching %s for changes", path)
+			go config.WatchFiles(context.Background(), configPollInterval, func() {
+				r.reload("config file changed")
+			}, func() []string { return []string{path} })
+		}
+	}
+
+	sigChan := make(chan os.Signal, 1)
+	signal.Notify(sigChan, syscall.SIGHUP)
+	for range sigChan {
+		r.reload("SIGHUP")
+	}
+}
+
+// reload loads and validates the new configuration, then applies it. An
+// invalid configuration is rejected as a whole and the current one is kept.
+func (r *reloader) reload(reason string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	log.Printf("🔄 Reloading configuration (%s)", reason)
+	next, err := loadConfig()
+	if err != nil {
+		log.Printf("❌ Configuration reload rejected, keeping the current one: %v", err)
+		return
+	}
+
+	current := r.config()
+	applied := *current
+	applyReloadable(&applied, next)
+
+	// Certificates are re-read even when the paths did not change, so a
+	// SIGHUP picks up rotated files
+	var serverTLS *tls.Config
+	if applied.TLSEnabled() {
+		serverTLS, err = newServerTLSConfig(applied.Server.TLS)
+		if err != nil {
+			log.Printf("❌ Configuration reload rejected, keeping the current one: %v", err)
+			return
+		}
+	}
+
+	// Swap everything in
+	if serverTLS != nil {
+		r.storeTLS(serverTLS)
+	}
+	setLogLevel(&applied)
+	if r.agentServer != nil {
+		r.agentServer.UpdateSettings(chatSettings(&applied, r.tools, r.mcpTools))
+	}
+	if r.authenticator != nil {
+		r.authenticator.SetCertMapper(newCertMapper(&applied))
+	}
+	if r.tenants != nil {
+		r.tenants.Replace(tenantSettings(&applied))
+	}
+	if r.limiter != nil {
+		r.limiter.SetGlobalLimit(globalLimit(&applied))
+	}
+	if r.mcpBackend != nil {
+		r.mcpBackend.SetPrompts(mcpPrompts(&applied))
+	}
+	r.current.Store(&applied)
+
+	changes := config.Diff(current, &applied)
+	pending := config.Diff(&applied, next)
+	if len(changes) == 0 && len(pending) == 0 {
+		log.Printf("✅ Configuration reloaded, no changes")
+		return
+	}
+	log.Printf("✅ Configuration reloaded, %d change(s) applied", len(changes))
+	for _, change := range changes {
+		log.Printf("   • %s", change)
+	}
+	for _, change := range pending {
+		log.Printf("⚠️  %s (requires a restart)", change)
+	}
+}
+
+// applyReloadable copies the settings that can change at runtime from src to
+// dst. Everything else keeps its startup value until the next restart.
+func applyReloadable(dst, src *config.Config) {
+	dst.Chat = src.Chat
+	dst.MCP.Prompts = src.MCP.Prompts
+	dst.Tenants = src.Tenants
+	dst.Security.CORS = src.Security.CORS
+	dst.Security.RateLimiting = src.Security.RateLimiting
+	dst.Observability.Logging.Level = src.Observability.Logging.Level
+
+	// The listener can only switch certificates and TLS settings, not
+	// between TLS and plaintext
+	if dst.TLSEnabled() && src.TLSEnabled() {
+		reloadInterval := dst.Server.TLS.ReloadInterval
+		dst.Server.TLS = src.Server.TLS
+		dst.Server.TLS.ReloadInterval = reloadInterval
+	}
+}
+
+// watchCertificates reloads the TLS settings whenever the certificate, key,
+// CA or CRL files change on disk, so rotated certificates are served without
+// a restart or SIGHUP
+func (r *reloader) watchCertificates(interval time.Duration) {
+	config.WatchFiles(context.Background(), interval, func() {
+		r.reloadTLS("certificate files changed")
+	}, func() []string {
+		return tlsFiles(r.config().Server.TLS)
+	})
+}
+
+// reloadTLS re-reads the TLS files of the current configuration. On failure,
+// e.g. a key written before its certificate, the current settings are kept
+// and the next change is retried.
+func (r *reloader) reloadTLS(reason string) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	cfg := r.config()
+	if !cfg.TLSEnabled() {
+		return
+	}
+	serverTLS, err := newServerTLSConfig(cfg.Server.TLS)
+	if err != nil {
+		log.Printf("❌ TLS reload failed (%s), keeping the current certificates: %v", reason, err)
+		return
+	}
+	r.storeTLS(serverTLS)
+}
+
+// storeTLS swaps in new TLS settings, logging when the serving certificate
+// changes
+func (r *reloader) storeTLS(serverTLS *tls.Config) {
+	previous := r.serverTLS.Swap(serverTLS)
+	if previous == nil {
+		return
+	}
+	if before, after := describeCertificate(previous), describeCertificate(serverTLS); before != after {
+		log.Printf("🔁 TLS certificate rotated: %s → %s", before, after)
+	}
+}
@@ -0,0 +1,180 @@
+package main
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"encoding/pem"
+	"fmt"
+	"log"
+	"os"
+	"time"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+)
+
+// newServerTLSConfig loads the certificates, client CA and CRL of the gRPC
+// listener. It is called again on every config reload and certificate
+// rotation.
+func newServerTLSConfig(tlsConfig config.TLSConfig) (*tls.Config, error) {
+	// Load server certificate
+	serverCert, err := tls.LoadX509KeyPair(tlsConfig.CertFile, tlsConfig.KeyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load server certificate: %w", err)
+	}
+
+	// Configure TLS
+	minVersion := uint16(tls.VersionTLS13) // TLS 1.3 as mentioned in README
+	if tlsConfig.MinVersion == "1.2" {
+		minVersion = tls.VersionTLS12
+	}
+	serverConfig := &tls.Config{
+		Certificates: []tls.Certificate{serverCert},
+		MinVersion:   minVersion,
+		NextProtos:   []string{"h2"}, // gRPC clients require HTTP/2 ALPN
+	}
+
+	if tlsConfig.MTLS.Enabled {
+		// Load CA certificate for client verification
+		if tlsConfig.CACertFile == "" {
+			return nil, fmt.Errorf("CA certificate file is required for mTLS")
+		}
+		caCerts, err := loadCertificates(tlsConfig.CACertFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read CA certificate: %w", err)
+		}
+		caCertPool := x509.NewCertPool()
+		for _, caCert := range caCerts {
+			caCertPool.AddCert(caCert)
+		}
+		serverConfig.ClientCAs = caCertPool
+
+		// Clients without a certificate can still use bearer tokens, unless
+		// require_client_cert is set
+		serverConfig.ClientAuth = tls.VerifyClientCertIfGiven
+		if tlsConfig.MTLS.RequireClientCert {
+			serverConfig.ClientAuth = tls.RequireAndVerifyClientCert
+		}
+
+		if tlsConfig.MTLS.CRLFile != "" {
+			crl, err := loadCRL(tlsConfig.MTLS.CRLFile, caCerts)
+			if err != nil {
+				return nil, err
+			}
+			serverConfig.VerifyConnection = rejectRevoked(crl)
+		}
+	}
+
+	return serverConfig, nil
+}
+
+// loadCertificates reads every certificate of a PEM file
+func loadCertificates(path string) ([]*x509.Certificate, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	var certs []*x509.Certificate
+	for block, rest := pem.Decode(data); block != nil; block, rest = pem.Decode(rest) {
+		if block.Type != "CERTIFICATE" {
+			continue
+		}
+		cert, err := x509.ParseCertificate(block.Bytes)
+		if err != nil {
+			return nil, fmt.Errorf("invalid certificate in %s: %w", path, err)
+		}
+		certs = append(certs, cert)
+	}
+	if len(certs) == 0 {
+		return nil, fmt.Errorf("no certificates found in %s", path)
+	}
+	return certs, nil
+}
+
+// loadCRL reads a PEM or DER certificate revocation list and checks that one
+// of the client CAs signed it
+func loadCRL(path string, caCerts []*x509.Certificate) (*x509.RevocationList, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read CRL: %w", err)
+	}
+	if block, _ := pem.Decode(data); block != nil {
+		data = block.Bytes
+	}
+
+	crl, err := x509.ParseRevocationList(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid CRL %s: %w", path, err)
+	}
+
+	signed := false
+	for _, caCert := range caCerts {
+		if crl.CheckSignatureFrom(caCert) == nil {
+			signed = true
+			break
+		}
+	}
+	if !signed {
+		return nil, fmt.Errorf("CRL %s is not signed by the client CA", path)
+	}
+
+	if !crl.NextUpdate.IsZero() && time.Now().After(crl.NextUpdate) {
+		log.Printf("⚠️  CRL %s is out of date (next update was %s)", path, crl.NextUpdate.Format(time.RFC3339))
+	}
+	log.Printf("📜 Loaded CRL %s: %d revoked certificate(s)", path, len(crl.RevokedCertificateEntries))
+	return crl, nil
+}
+
+// rejectRevoked fails the handshake of clients whose certificate is on the
+// CRL. Serial numbers are only unique per CA, so entries are matched by the
+// issuer of the CRL as well: with several CAs in the pool, a certificate of
+// another CA sharing a revoked serial is still accepted.
+func rejectRevoked(crl *x509.RevocationList) func(tls.ConnectionState) error {
+	revoked := make(map[revokedKey]bool, len(crl.RevokedCertificateEntries))
+	for _, entry := range crl.RevokedCertificateEntries {
+		revoked[revokedKey{issuer: string(crl.RawIssuer), serial: entry.SerialNumber.String()}] = true
+	}
+
+	return func(state tls.ConnectionState) error {
+		for _, chain := range state.VerifiedChains {
+			if len(chain) == 0 {
+				continue
+			}
+			leaf := chain[0]
+			if revoked[revokedKey{issuer: string(leaf.RawIssuer), serial: leaf.SerialNumber.String()}] {
+				log.Printf("🚫 Rejected revoked client certificate (subject: %s, issuer: %s, serial: %s)", leaf.Subject, leaf.Issuer, leaf.SerialNumber.Text(16))
+				return fmt.Errorf("client certificate %s has been revoked", leaf.SerialNumber.Text(16))
+			}
+		}
+		return nil
+	}
+}
+
+// revokedKey identifies a certificate: the DER subject of its issuer plus its
+// serial number
+type revokedKey struct {
+	issuer string
+	serial string
+}
+
+// tlsFiles lists the files the listener TLS settings are loaded from
+func tlsFiles(tlsConfig config.TLSConfig) []string {
+	files := []string{tlsConfig.CertFile, tlsConfig.KeyFile}
+	if tlsConfig.MTLS.Enabled {
+		files = append(files, tlsConfig.CACertFile)
+		if tlsConfig.MTLS.CRLFile != "" {
+			files = append(files, tlsConfig.MTLS.CRLFile)
+		}
+	}
+	return files
+}
+
+// describeCertificate summarizes the serving certificate of a TLS config for
+// rotation logs
+func describeCertificate(serverConfig *tls.Config) string {
+	if serverConfig == nil || len(serverConfig.Certificates) == 0 || serverConfig.Certificates[0].Leaf == nil {
+		return "none"
+	}
+	leaf := serverConfig.Certificates[0].Leaf
+	return fmt.Sprintf("serial %s, expires %s", leaf.SerialNumber.Text(16), leaf.NotAfter.Format(time.RFC3339))
+}
@@ -11,12 +11,15 @@ import (
 	"io/ioutil"
 	"log"
 	"os"
+	"runtime"
 	"strings"
+	"sync"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/credentials"
 	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
 	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
@@ -32,8 +35,16 @@ var (
 	tenantID    = flag.String("tenant", "demo-tenant", "Tenant ID")
 	agentID     = flag.String("agent", "stream-agent", "Agent ID")
 	model       = flag.String("model", "gemma3:4b", "Model to use")
+	clientTools = flag.Bool("client-tools", false, "Declare the client_environment tool, which the model can call to learn about this machine")
 )
 
+// environmentTool is the tool this client runs for the model with -client-tools
+var environmentTool = &mcpv1.ToolDefinition{
+	Name:        "client_environment",
+	Description: "Returns the host name, operating system and working directory of the user's machine.",
+	Parameters:  `{"type":"object","properties":{}}`,
+}
+
 func main() {
 	flag.Parse()
 
@@ -71,7 +82,7 @@ func main() {
 	log.Printf("   💡 Type 'help' for available commands")
 	log.Printf("")
 
-	err = startStreamingChat(agentClient, sessionID)
+	err = startStreamingChat(agentClient, sessionID, jwtToken)
 	if err != nil {
 		log.Fatalf("❌ Streaming chat failed: %v", err)
 	}
@@ -158,22 +169,47 @@ func registerSession(client mcpv1.HandshakeServiceClient) (string, string, error
 }
 
 // startStreamingChat starts a bidirectional streaming chat session
-func startStreamingChat(client mcpv1.AgentServiceClient, sessionID string) error {
+func startStreamingChat(client mcpv1.AgentServiceClient, sessionID, jwtToken string) error {
 	ctx, cancel := context.WithCancel(context.Background())
 	defer cancel()
 
+	// AgentService requires the token issued by Register
+	ctx = metadata.AppendToOutgoingContext(ctx, "authorization", "Bearer "+jwtToken)
+
 	// Create streaming connection
 	stream, err := client.Chat(ctx)
 	if err != nil {
 		return fmt.Errorf("failed to create stream: %w", err)
 	}
 
+	// Tool results are sent from the receiving goroutine while the user types
+	var sendMutex sync.Mutex
+	send := func(msg *mcpv1.ChatMessage) error {
+		sendMutex.Lock()
+		defer sendMutex.Unlock()
+		return stream.Send(msg)
+	}
+
+	// Declare the tools of this client before the first question
+	if *clientTools {
+		err := send(&mcpv1.ChatMessage{
+			SessionId: sessionID,
+			Tools:     []*mcpv1.ToolDefinition{environmentTool},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to declare tools: %w", err)
+		}
+		log.Printf("🧰 Declared tool %s", environmentTool.Name)
+	}
+
 	// Channel to signal when to close
 	done := make(chan struct{})
 
 	// Goroutine to handle incoming messages from server
 	go func() {
 		defer close(done)
+		// id of the assistant answer currently being streamed
+		var streamingID string
 		for {
 			msg, err := stream.Recv()
 			if err == io.EOF {
@@ -191,9 +227,62 @@ func startStreamingChat(client mcpv1.AgentServiceClient, sessionID string) error
 				timestamp = msg.Timestamp.AsTime().Format("15:04:05")
 			}
 
+			// Assistant answers arrive token by token: print the chunks inline
+			// and only break the line on the done marker
+			if msg.Type == mcpv1.MessageType_MESSAGE_TYPE_ASSISTANT {
+				if streamingID != msg.MessageId {
+					streamingID = msg.MessageId
+					fmt.Printf("🤖 [%s] Gemma: ", timestamp)
+				}
+				fmt.Print(msg.Content)
+				if !msg.Done {
+					continue
+				}
+				streamingID = ""
+				fmt.Println()
+				fmt.Println()
+				fmt.Print("💬 You: ")
+				continue
+			}
+
+			// Tool calls happen in the middle of an answer, which goes on
+			// after them
+			if call := msg.ToolCall; call != nil {
+				if streamingID != "" {
+					streamingID = ""
+					fmt.Println()
+				}
+				log.Printf("🛠️  [%s] Calling %s %s", timestamp, call.Name, call.Arguments)
+
+				// The gateway waits for the result of the tools of this client
+				if *clientTools && call.Name == environmentTool.Name {
+					err := send(&mcpv1.ChatMessage{
+						MessageId: generateMessageID(),
+						SessionId: sessionID,
+						Type:      mcpv1.MessageType_MESSAGE_TYPE_TOOL_RESULT,
+						Timestamp: timestamppb.New(time.Now()),
+						ToolResult: &mcpv1.ToolResult{
+							Id:      call.Id,
+							Name:    call.Name,
+							Content: clientEnvironment(),
+						},
+					})
+					if err != nil {
+						log.Printf("❌ Failed to send tool result: %v", err)
+					}
+				}
+				continue
+			}
+			if result := msg.ToolResult; result != nil {
+				if result.IsError {
+					log.Printf("⚠️  [%s] %s failed: %s", timestamp, result.Name, result.Content)
+				} else {
+					log.Printf("📎 [%s] %s returned: %s", timestamp, result.Name, result.Content)
+				}
+				continue
+			}
+
 			switch msg.Type {
-			case mcpv1.MessageType_MESSAGE_TYPE_ASSISTANT:
-				log.Printf("🤖 [%s] Gemma: %s", timestamp, msg.Content)
 			case mcpv1.MessageType_MESSAGE_TYPE_SYSTEM:
 				log.Printf("🔔 [%s] System: %s", timestamp, msg.Content)
 			default:
@@ -251,7 +340,7 @@ func startStreamingChat(client mcpv1.AgentServiceClient, sessionID string) error
 			Timestamp: timestamppb.New(time.Now()),
 		}
 
-		err := stream.Send(msg)
+		err := send(msg)
 		if err != nil {
 			log.Printf("❌ Failed to send message: %v", err)
 			break
@@ -271,6 +360,13 @@ func startStreamingChat(client mcpv1.AgentServiceClient, sessionID string) error
 	return nil
 }
 
+// clientEnvironment describes this machine for the client_environment tool
+func clientEnvironment() string {
+	hostname, _ := os.Hostname()
+	workingDir, _ := os.Getwd()
+	return fmt.Sprintf("host: %s, os: %s/%s, working directory: %s", hostname, runtime.GOOS, runtime.GOARCH, workingDir)
+}
+
 // generateMessageID creates a simple message ID
 func generateMessageID() string {
 	return fmt.Sprintf("msg_%d", time.Now().UnixNano())
@@ -0,0 +1,193 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"net/http"
+	"strings"
+	"sync/atomic"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// TokenValidator resolves a bearer token to the principal it was issued to.
+// It returns an error for unknown, expired or otherwise invalid tokens.
+type TokenValidator interface {
+	ValidateToken(ctx context.Context, token string) (*Principal, error)
+}
+
+// Authenticator enforces bearer tokens on every RPC except the public ones.
+// With a CertMapper, a verified client certificate can replace the token.
+type Authenticator struct {
+	validator  TokenValidator
+	public     []string
+	certMapper atomic.Pointer[CertMapper]
+}
+
+// NewAuthenticator creates an Authenticator. Each public entry is either a
+// full method name ("/mcp.v1.HandshakeService/Register") or a service prefix
+// ending in a slash ("/grpc.health.v1.Health/").
+func NewAuthenticator(validator TokenValidator, public ...string) *Authenticator {
+	return &Authenticator{
+		validator: validator,
+		public:    public,
+	}
+}
+
+// SetCertMapper enables (or, with nil, disables) authentication by client
+// certificate. It can be called while serving.
+func (a *Authenticator) SetCertMapper(mapper *CertMapper) {
+	a.certMapper.Store(mapper)
+}
+
+// UnaryServerInterceptor authenticates unary RPCs
+func (a *Authenticator) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		ctx, err := a.authenticate(ctx, info.FullMethod)
+		if err != nil {
+			return nil, err
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor authenticates streaming RPCs once, when the stream
+// is opened
+func (a *Authenticator) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		ctx, err := a.authenticate(stream.Context(), info.FullMethod)
+		if err != nil {
+			return err
+		}
+		return handler(srv, &authenticatedStream{ServerStream: stream, ctx: ctx})
+	}
+}
+
+// authenticate validates the bearer token of a call, or else its client
+// certificate, and returns a context carrying its principal. Public methods
+// pass through untouched.
+func (a *Authenticator) authenticate(ctx context.Context, fullMethod string) (context.Context, error) {
+	if a.isPublic(fullMethod) {
+		return ctx, nil
+	}
+
+	token, err := bearerToken(ctx)
+	if err != nil {
+		if principal, ok := a.certificatePrincipal(ctx); ok {
+			return NewContext(ctx, principal), nil
+		}
+		return nil, err
+	}
+
+	return a.validate(ctx, token)
+}
+
+// AuthenticateHTTP authenticates an HTTP request like an RPC: by the bearer
+// token of its Authorization header, or else by its verified client
+// certificate. It returns the request context carrying the principal.
+func (a *Authenticator) AuthenticateHTTP(r *http.Request) (context.Context, error) {
+	ctx := r.Context()
+	header := r.Header.Get("Authorization")
+	if header == "" {
+		if principal, ok := a.tlsPrincipal(r.TLS); ok {
+			return NewContext(ctx, principal), nil
+		}
+		return nil, status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+
+	token, err := parseBearer(header)
+	if err != nil {
+		return nil, err
+	}
+	return a.validate(ctx, token)
+}
+
+// validate resolves a bearer token and returns ctx carrying its principal
+func (a *Authenticator) validate(ctx context.Context, token string) (context.Context, error) {
+	principal, err := a.validator.ValidateToken(ctx, token)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid token: %v", err)
+	}
+
+	return NewContext(ctx, principal), nil
+}
+
+// certificatePrincipal maps the verified client certificate of the
+// connection, if any, to a principal
+func (a *Authenticator) certificatePrincipal(ctx context.Context) (*Principal, bool) {
+	p, ok := peer.FromContext(ctx)
+	if !ok {
+		return nil, false
+	}
+	tlsInfo, ok := p.AuthInfo.(credentials.TLSInfo)
+	if !ok {
+		return nil, false
+	}
+
+	return a.tlsPrincipal(&tlsInfo.State)
+}
+
+// tlsPrincipal maps the verified client certificate of a TLS connection, if
+// any, to a principal
+func (a *Authenticator) tlsPrincipal(state *tls.ConnectionState) (*Principal, bool) {
+	mapper := a.certMapper.Load()
+	if mapper == nil || state == nil {
+		return nil, false
+	}
+	if len(state.VerifiedChains) == 0 || len(state.VerifiedChains[0]) == 0 {
+		return nil, false
+	}
+
+	return mapper.Principal(state.VerifiedChains[0][0])
+}
+
+func (a *Authenticator) isPublic(fullMethod string) bool {
+	for _, public := range a.public {
+		if public == fullMethod || (strings.HasSuffix(public, "/") && strings.HasPrefix(fullMethod, public)) {
+			return true
+		}
+	}
+	return false
+}
+
+// bearerToken extracts the token from the "authorization: Bearer <token>"
+// metadata header
+func bearerToken(ctx context.Context) (string, error) {
+	md, ok := metadata.FromIncomingContext(ctx)
+	if !ok {
+		return "", status.Error(codes.Unauthenticated, "missing metadata")
+	}
+
+	values := md.Get("authorization")
+	if len(values) == 0 {
+		return "", status.Error(codes.Unauthenticated, "missing authorization header")
+	}
+
+	return parseBearer(values[0])
+}
+
+// parseBearer extracts the token of a "Bearer <token>" authorization header
+func parseBearer(header string) (string, error) {
+	scheme, token, found := strings.Cut(header, " ")
+	if !found || !strings.EqualFold(scheme, "Bearer") || strings.TrimSpace(token) == "" {
+		return "", status.Error(codes.Unauthenticated, "authorization header must be \"Bearer <token>\"")
+	}
+
+	return strings.TrimSpace(token), nil
+}
+
+// authenticatedStream overrides the context of a stream so handlers can read
+// the principal from it
+type authenticatedStream struct {
+	grpc.ServerStream
+	ctx context.Context
+}
+
+func (s *authenticatedStream) Context() context.Context {
+	return s.ctx
+}
@@ -0,0 +1,167 @@
+package auth
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"errors"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// revokingValidator checks tokens like the handshake server: by signature,
+// and then against the revoked sessions
+type revokingValidator struct {
+	tokens  *TokenManager
+	revoked map[string]bool
+}
+
+func (v revokingValidator) ValidateToken(ctx context.Context, token string) (*Principal, error) {
+	claims, err := v.tokens.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+	if v.revoked[claims.SessionID] {
+		return nil, errors.New("session revoked")
+	}
+	return claims.Principal(), nil
+}
+
+func TestAuthenticatorChoosesPrincipal(t *testing.T) {
+	tokens, _ := newManagers(t, "HS256")
+	issue := func(sessionID string) string {
+		claims := testClaims(time.Hour)
+		claims.SessionID = sessionID
+		token, err := tokens.Issue(claims)
+		if err != nil {
+			t.Fatal(err)
+		}
+		return token
+	}
+	validToken, revokedToken := issue("session-1"), issue("session-2")
+
+	authenticator := NewAuthenticator(revokingValidator{tokens: tokens, revoked: map[string]bool{"session-2": true}},
+		"/mcp.v1.HandshakeService/Register", "/grpc.health.v1.Health/")
+	authenticator.SetCertMapper(NewCertMapper("gateway.local", "gemma3:4b", nil))
+	mapped := testCertificate(t, "", nil, "spiffe://gateway.local/tenant/acme/agent/crawler")
+	unmapped := testCertificate(t, "stranger", nil)
+
+	tests := []struct {
+		name          string
+		method        string
+		authorization string
+		cert          *x509.Certificate
+		verified      bool
+		sessionID     string // of the principal, "" for none
+		code          codes.Code
+	}{
+		{"public method", "/mcp.v1.HandshakeService/Register", "", nil, false, "", codes.OK},
+		{"public service", "/grpc.health.v1.Health/Check", "", nil, false, "", codes.OK},
+		{"bearer token", "/mcp.v1.AgentService/Chat", "Bearer " + validToken, nil, false, "session-1", codes.OK},
+		{"client certificate", "/mcp.v1.AgentService/Chat", "", mapped, true, "mtls:acme/crawler", codes.OK},
+		{"token before certificate", "/mcp.v1.AgentService/Chat", "Bearer " + validToken, mapped, true, "session-1", codes.OK},
+		{"revoked session", "/mcp.v1.AgentService/Chat", "Bearer " + revokedToken, nil, false, "", codes.Unauthenticated},
+		{"revoked session with certificate", "/mcp.v1.AgentService/Chat", "Bearer " + revokedToken, mapped, true, "", codes.Unauthenticated},
+		{"unverified certificate", "/mcp.v1.AgentService/Chat", "", mapped, false, "", codes.Unauthenticated},
+		{"unmapped certificate", "/mcp.v1.AgentService/Chat", "", unmapped, true, "", codes.Unauthenticated},
+		{"no credentials", "/mcp.v1.AgentService/Chat", "", nil, false, "", codes.Unauthenticated},
+		{"not a bearer token", "/mcp.v1.AgentService/Chat", "Basic " + validToken, nil, false, "", codes.Unauthenticated},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			if tt.authorization != "" {
+				ctx = metadata.NewIncomingContext(ctx, metadata.Pairs("authorization", tt.authorization))
+			}
+			if tt.cert != nil {
+				state := tls.ConnectionState{PeerCertificates: []*x509.Certificate{tt.cert}}
+				if tt.verified {
+					state.VerifiedChains = [][]*x509.Certificate{{tt.cert}}
+				}
+				ctx = peer.NewContext(ctx, &peer.Peer{AuthInfo: credentials.TLSInfo{State: state}})
+			}
+
+			var principal *Principal
+			handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+				principal, _ = FromContext(ctx)
+				return nil, nil
+			}
+			_, err := authenticator.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: tt.method}, handler)
+			if code := status.Code(err); code != tt.code {
+				t.Fatalf("code = %v (%v), want %v", code, err, tt.code)
+			}
+			var sessionID string
+			if principal != nil {
+				sessionID = principal.SessionID
+			}
+			if sessionID != tt.sessionID {
+				t.Errorf("principal session = %q, want %q", sessionID, tt.sessionID)
+			}
+		})
+	}
+
+	// Without a mapper certificates authenticate nobody
+	authenticator.SetCertMapper(nil)
+	ctx := peer.NewContext(context.Background(), &peer.Peer{AuthInfo: credentials.TLSInfo{State: tls.ConnectionState{
+		VerifiedChains: [][]*x509.Certificate{{mapped}},
+	}}})
+	_, err := authenticator.UnaryServerInterceptor()(ctx, nil, &grpc.UnaryServerInfo{FullMethod: "/mcp.v1.AgentService/Chat"},
+		func(ctx context.Context, req interface{}) (interface{}, error) { return nil, nil })
+	if status.Code(err) != codes.Unauthenticated {
+		t.Errorf("certificate without a mapper: %v, want Unauthenticated", err)
+	}
+}
+
+func TestAuthenticateHTTP(t *testing.T) {
+	tokens, _ := newManagers(t, "HS256")
+	token, err := tokens.Issue(testClaims(time.Hour))
+	if err != nil {
+		t.Fatal(err)
+	}
+	authenticator := NewAuthenticator(revokingValidator{tokens: tokens, revoked: map[string]bool{}})
+	authenticator.SetCertMapper(NewCertMapper("gateway.local", "gemma3:4b", nil))
+	cert := testCertificate(t, "", nil, "spiffe://gateway.local/tenant/acme/agent/crawler")
+
+	tests := []struct {
+		name          string
+		authorization string
+		tls           *tls.ConnectionState
+		sessionID     string
+	}{
+		{"bearer token", "Bearer " + token, nil, "session-1"},
+		{"client certificate", "", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}, "mtls:acme/crawler"},
+		{"invalid token with certificate", "Bearer nope", &tls.ConnectionState{VerifiedChains: [][]*x509.Certificate{{cert}}}, ""},
+		{"plain connection", "", nil, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			r := httptest.NewRequest("POST", "/mcp", nil)
+			if tt.authorization != "" {
+				r.Header.Set("Authorization", tt.authorization)
+			}
+			r.TLS = tt.tls
+
+			ctx, err := authenticator.AuthenticateHTTP(r)
+			if tt.sessionID == "" {
+				if status.Code(err) != codes.Unauthenticated {
+					t.Errorf("err = %v, want Unauthenticated", err)
+				}
+				return
+			}
+			if err != nil {
+				t.Fatalf("AuthenticateHTTP: %v", err)
+			}
+			if principal, ok := FromContext(ctx); !ok || principal.SessionID != tt.sessionID {
+				t.Errorf("principal = %+v, want session %s", principal, tt.sessionID)
+			}
+		})
+	}
+}
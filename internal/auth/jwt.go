@@ -0,0 +1,199 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"os"
+	"strings"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// Claims are the claims of a gateway session token. Any service holding the
+// verification key can trust them without calling the gateway.
+type Claims struct {
+	TenantID  string `json:"tenant_id"`
+	AgentID   string `json:"agent_id"`
+	SessionID string `json:"session_id"`
+	Model     string `json:"model,omitempty"`
+	jwt.RegisteredClaims
+}
+
+// JWTConfig selects the signing algorithm and its keys
+type JWTConfig struct {
+	Algorithm      string // HS256, RS256 or EdDSA
+	SecretKey      string // shared secret for HS256
+	PrivateKeyFile string // PEM private key for RS256/EdDSA signing
+	PublicKeyFile  string // PEM public key for RS256/EdDSA verification
+	Issuer         string
+}
+
+// TokenManager signs and verifies session JWTs
+type TokenManager struct {
+	method    jwt.SigningMethod
+	signKey   interface{}
+	verifyKey interface{}
+	issuer    string
+}
+
+// NewTokenManager loads the keys for the configured algorithm. For RS256 and
+// EdDSA the public key is derived from the private key when no public key file
+// is given; with only a public key the manager can verify but not issue.
+func NewTokenManager(config JWTConfig) (*TokenManager, error) {
+	manager := &TokenManager{issuer: config.Issuer}
+
+	switch strings.ToUpper(config.Algorithm) {
+	case "", "HS256":
+		if config.SecretKey == "" {
+			return nil, errors.New("HS256 requires a secret key")
+		}
+		manager.method = jwt.SigningMethodHS256
+		manager.signKey = []byte(config.SecretKey)
+		manager.verifyKey = []byte(config.SecretKey)
+
+	case "RS256":
+		manager.method = jwt.SigningMethodRS256
+		if config.PrivateKeyFile != "" {
+			data, err := os.ReadFile(config.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read private key: %w", err)
+			}
+			key, err := jwt.ParseRSAPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse RSA private key: %w", err)
+			}
+			manager.signKey = key
+			manager.verifyKey = &key.PublicKey
+		}
+		if config.PublicKeyFile != "" {
+			data, err := os.ReadFile(config.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read public key: %w", err)
+			}
+			key, err := jwt.ParseRSAPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse RSA public key: %w", err)
+			}
+			manager.verifyKey = key
+		}
+
+	case "EDDSA":
+		manager.method = jwt.SigningMethodEdDSA
+		if config.PrivateKeyFile != "" {
+			data, err := os.ReadFile(config.PrivateKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read private key: %w", err)
+			}
+			key, err := jwt.ParseEdPrivateKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Ed25519 private key: %w", err)
+			}
+			manager.signKey = key
+			manager.verifyKey = key.(ed25519.PrivateKey).Public()
+		}
+		if config.PublicKeyFile != "" {
+			data, err := os.ReadFile(config.PublicKeyFile)
+			if err != nil {
+				return nil, fmt.Errorf("failed to read public key: %w", err)
+			}
+			key, err := jwt.ParseEdPublicKeyFromPEM(data)
+			if err != nil {
+				return nil, fmt.Errorf("failed to parse Ed25519 public key: %w", err)
+			}
+			manager.verifyKey = key
+		}
+
+	default:
+		return nil, fmt.Errorf("unsupported JWT algorithm %q (use HS256, RS256 or EdDSA)", config.Algorithm)
+	}
+
+	if manager.verifyKey == nil {
+		return nil, fmt.Errorf("%s requires a private or public key file", manager.method.Alg())
+	}
+
+	return manager, nil
+}
+
+// Issue signs a token for the claims. The caller sets the expiration; the
+// issue time, issuer and a unique token ID (jti) are filled in when missing.
+func (m *TokenManager) Issue(claims *Claims) (string, error) {
+	if m.signKey == nil {
+		return "", errors.New("token manager has no signing key")
+	}
+
+	if claims.ID == "" {
+		id := make([]byte, 16)
+		if _, err := rand.Read(id); err != nil {
+			return "", fmt.Errorf("failed to generate token ID: %w", err)
+		}
+		claims.ID = hex.EncodeToString(id)
+	}
+	if claims.IssuedAt == nil {
+		claims.IssuedAt = jwt.NewNumericDate(time.Now())
+	}
+	if claims.Issuer == "" {
+		claims.Issuer = m.issuer
+	}
+	if claims.Subject == "" {
+		claims.Subject = claims.TenantID + "/" + claims.AgentID
+	}
+
+	token, err := jwt.NewWithClaims(m.method, claims).SignedString(m.signKey)
+	if err != nil {
+		return "", fmt.Errorf("failed to sign token: %w", err)
+	}
+	return token, nil
+}
+
+// Parse verifies the signature, algorithm, expiration and issuer of a token
+// and returns its claims
+func (m *TokenManager) Parse(token string) (*Claims, error) {
+	return m.ParseWithLeeway(token, 0)
+}
+
+// ParseWithLeeway is Parse, but also accepts tokens that expired less than
+// leeway ago. It is meant for refresh and logout, never for authorization.
+func (m *TokenManager) ParseWithLeeway(token string, leeway time.Duration) (*Claims, error) {
+	options := []jwt.ParserOption{
+		jwt.WithValidMethods([]string{m.method.Alg()}),
+		jwt.WithExpirationRequired(),
+		jwt.WithIssuedAt(),
+		jwt.WithLeeway(leeway),
+	}
+	if m.issuer != "" {
+		options = append(options, jwt.WithIssuer(m.issuer))
+	}
+
+	claims := &Claims{}
+	_, err := jwt.ParseWithClaims(token, claims, func(*jwt.Token) (interface{}, error) {
+		return m.verifyKey, nil
+	}, options...)
+	if err != nil {
+		return nil, err
+	}
+
+	if claims.SessionID == "" || claims.TenantID == "" {
+		return nil, errors.New("token is missing session or tenant claims")
+	}
+
+	return claims, nil
+}
+
+// Principal converts verified claims into the principal of a call
+func (c *Claims) Principal() *Principal {
+	principal := &Principal{
+		TenantID:  c.TenantID,
+		AgentID:   c.AgentID,
+		SessionID: c.SessionID,
+		Model:     c.Model,
+		TokenID:   c.ID,
+	}
+	if c.ExpiresAt != nil {
+		principal.ExpiresAt = c.ExpiresAt.Time
+	}
+	return principal
+}
@@ -0,0 +1,296 @@
+package auth
+
+import (
+	"crypto/ed25519"
+	"crypto/rand"
+	"crypto/rsa"
+	"crypto/x509"
+	"encoding/pem"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/golang-jwt/jwt/v5"
+)
+
+// writePEM writes a PEM block to a file of dir and returns its path
+func writePEM(t *testing.T, dir, name, blockType string, der []byte) string {
+	t.Helper()
+	file := filepath.Join(dir, name)
+	if err := os.WriteFile(file, pem.EncodeToMemory(&pem.Block{Type: blockType, Bytes: der}), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return file
+}
+
+// keyFiles generates a key pair for the algorithm and returns the paths of
+// its private and public PEM files
+func keyFiles(t *testing.T, algorithm string) (privateFile, publicFile string) {
+	t.Helper()
+	dir := t.TempDir()
+
+	var private, public any
+	switch algorithm {
+	case "RS256":
+		key, err := rsa.GenerateKey(rand.Reader, 2048)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = key, &key.PublicKey
+	case "EdDSA":
+		publicKey, privateKey, err := ed25519.GenerateKey(rand.Reader)
+		if err != nil {
+			t.Fatal(err)
+		}
+		private, public = privateKey, publicKey
+	default:
+		t.Fatalf("no key files for %s", algorithm)
+	}
+
+	privateDER, err := x509.MarshalPKCS8PrivateKey(private)
+	if err != nil {
+		t.Fatal(err)
+	}
+	publicDER, err := x509.MarshalPKIXPublicKey(public)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return writePEM(t, dir, "private.pem", "PRIVATE KEY", privateDER),
+		writePEM(t, dir, "public.pem", "PUBLIC KEY", publicDER)
+}
+
+// newManagers returns a manager that issues tokens for the algorithm, and a
+// verify-only manager holding just the public key (the same manager for HS256)
+func newManagers(t *testing.T, algorithm string) (issuer, verifier *TokenManager) {
+	t.Helper()
+	if algorithm == "HS256" {
+		manager, err := NewTokenManager(JWTConfig{Algorithm: algorithm, SecretKey: "test-secret", Issuer: "gateway"})
+		if err != nil {
+			t.Fatal(err)
+		}
+		return manager, manager
+	}
+
+	privateFile, publicFile := keyFiles(t, algorithm)
+	issuer, err := NewTokenManager(JWTConfig{Algorithm: algorithm, PrivateKeyFile: privateFile, Issuer: "gateway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	verifier, err = NewTokenManager(JWTConfig{Algorithm: algorithm, PublicKeyFile: publicFile, Issuer: "gateway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return issuer, verifier
+}
+
+func testClaims(expiresIn time.Duration) *Claims {
+	return &Claims{
+		TenantID:  "acme",
+		AgentID:   "agent-1",
+		SessionID: "session-1",
+		Model:     "gemma3:4b",
+		RegisteredClaims: jwt.RegisteredClaims{
+			ExpiresAt: jwt.NewNumericDate(time.Now().Add(expiresIn)),
+		},
+	}
+}
+
+func TestTokenManagerAlgorithms(t *testing.T) {
+	for _, algorithm := range []string{"HS256", "RS256", "EdDSA"} {
+		t.Run(algorithm, func(t *testing.T) {
+			issuer, verifier := newManagers(t, algorithm)
+
+			token, err := issuer.Issue(testClaims(time.Minute))
+			if err != nil {
+				t.Fatalf("Issue: %v", err)
+			}
+			claims, err := verifier.Parse(token)
+			if err != nil {
+				t.Fatalf("Parse: %v", err)
+			}
+			if claims.TenantID != "acme" || claims.AgentID != "agent-1" || claims.SessionID != "session-1" || claims.Model != "gemma3:4b" {
+				t.Errorf("claims = %+v", claims)
+			}
+			if claims.ID == "" || claims.IssuedAt == nil || claims.Issuer != "gateway" || claims.Subject != "acme/agent-1" {
+				t.Errorf("registered claims not filled in: %+v", claims.RegisteredClaims)
+			}
+
+			if _, err := verifier.Issue(testClaims(time.Minute)); algorithm != "HS256" && err == nil {
+				t.Error("a verify-only manager issued a token")
+			}
+		})
+	}
+}
+
+func TestTokenManagerRejects(t *testing.T) {
+	for _, algorithm := range []string{"HS256", "RS256", "EdDSA"} {
+		issuer, verifier := newManagers(t, algorithm)
+		otherIssuer, _ := newManagers(t, algorithm)
+		if algorithm == "HS256" {
+			var err error
+			otherIssuer, err = NewTokenManager(JWTConfig{Algorithm: algorithm, SecretKey: "another-secret", Issuer: "gateway"})
+			if err != nil {
+				t.Fatal(err)
+			}
+		}
+
+		tests := []struct {
+			name   string
+			issuer *TokenManager
+			claims func() *Claims
+			leeway time.Duration
+			valid  bool
+		}{
+			{
+				name:   "expired",
+				issuer: issuer,
+				claims: func() *Claims { return testClaims(-time.Minute) },
+			},
+			{
+				name:   "expired within leeway",
+				issuer: issuer,
+				claims: func() *Claims { return testClaims(-time.Minute) },
+				leeway: time.Hour,
+				valid:  true,
+			},
+			{
+				name:   "expired beyond leeway",
+				issuer: issuer,
+				claims: func() *Claims { return testClaims(-time.Hour) },
+				leeway: time.Minute,
+			},
+			{
+				name:   "not yet valid",
+				issuer: issuer,
+				claims: func() *Claims {
+					claims := testClaims(time.Hour)
+					claims.NotBefore = jwt.NewNumericDate(time.Now().Add(10 * time.Minute))
+					return claims
+				},
+			},
+			{
+				name:   "issued in the future",
+				issuer: issuer,
+				claims: func() *Claims {
+					claims := testClaims(time.Hour)
+					claims.IssuedAt = jwt.NewNumericDate(time.Now().Add(10 * time.Minute))
+					return claims
+				},
+			},
+			{
+				name:   "without expiration",
+				issuer: issuer,
+				claims: func() *Claims {
+					claims := testClaims(0)
+					claims.ExpiresAt = nil
+					return claims
+				},
+			},
+			{
+				name:   "other issuer",
+				issuer: issuer,
+				claims: func() *Claims {
+					claims := testClaims(time.Minute)
+					claims.Issuer = "someone-else"
+					return claims
+				},
+			},
+			{
+				name:   "missing session",
+				issuer: issuer,
+				claims: func() *Claims {
+					claims := testClaims(time.Minute)
+					claims.SessionID = ""
+					return claims
+				},
+			},
+			{
+				name:   "wrong key",
+				issuer: otherIssuer,
+				claims: func() *Claims { return testClaims(time.Minute) },
+			},
+		}
+
+		for _, tt := range tests {
+			t.Run(algorithm+"/"+tt.name, func(t *testing.T) {
+				token, err := tt.issuer.Issue(tt.claims())
+				if err != nil {
+					t.Fatalf("Issue: %v", err)
+				}
+				_, err = verifier.ParseWithLeeway(token, tt.leeway)
+				if tt.valid && err != nil {
+					t.Errorf("ParseWithLeeway: %v", err)
+				}
+				if !tt.valid && err == nil {
+					t.Error("ParseWithLeeway accepted the token")
+				}
+			})
+		}
+	}
+}
+
+func TestTokenManagerAlgorithmConfusion(t *testing.T) {
+	_, publicFile := keyFiles(t, "RS256")
+	publicPEM, err := os.ReadFile(publicFile)
+	if err != nil {
+		t.Fatal(err)
+	}
+	rs256, err := NewTokenManager(JWTConfig{Algorithm: "RS256", PublicKeyFile: publicFile})
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	// An HS256 token whose HMAC secret is the public key, which a verifier
+	// trusting the alg header would check against that same public key
+	forger, err := NewTokenManager(JWTConfig{Algorithm: "HS256", SecretKey: string(publicPEM)})
+	if err != nil {
+		t.Fatal(err)
+	}
+	token, err := forger.Issue(testClaims(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs256.Parse(token); err == nil {
+		t.Error("RS256 manager accepted an HS256 token")
+	}
+
+	// An unsigned token
+	unsigned, err := jwt.NewWithClaims(jwt.SigningMethodNone, testClaims(time.Minute)).SignedString(jwt.UnsafeAllowNoneSignatureType)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs256.Parse(unsigned); err == nil {
+		t.Error("RS256 manager accepted an unsigned token")
+	}
+
+	// An EdDSA token against an RS256 manager
+	eddsa, _ := newManagers(t, "EdDSA")
+	token, err = eddsa.Issue(testClaims(time.Minute))
+	if err != nil {
+		t.Fatal(err)
+	}
+	if _, err := rs256.Parse(token); err == nil {
+		t.Error("RS256 manager accepted an EdDSA token")
+	}
+}
+
+func TestNewTokenManagerErrors(t *testing.T) {
+	tests := []struct {
+		name   string
+		config JWTConfig
+	}{
+		{"HS256 without secret", JWTConfig{Algorithm: "HS256"}},
+		{"RS256 without keys", JWTConfig{Algorithm: "RS256"}},
+		{"EdDSA without keys", JWTConfig{Algorithm: "EdDSA"}},
+		{"missing key file", JWTConfig{Algorithm: "RS256", PrivateKeyFile: filepath.Join(t.TempDir(), "missing.pem")}},
+		{"unsupported algorithm", JWTConfig{Algorithm: "none", SecretKey: "secret"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := NewTokenManager(tt.config); err == nil {
+				t.Error("NewTokenManager succeeded")
+			}
+		})
+	}
+}
@@ -0,0 +1,105 @@
+package auth
+
+import (
+	"crypto/sha256"
+	"crypto/x509"
+	"encoding/hex"
+	"net/url"
+	"strings"
+)
+
+// CertIdentity maps a client certificate name (URI, DNS or email SAN, or the
+// subject common name) to a tenant and agent
+type CertIdentity struct {
+	Match    string
+	TenantID string
+	AgentID  string
+	Model    string // empty uses the mapper default model
+}
+
+// CertMapper resolves verified client certificates to principals, so services
+// holding a certificate from our CA can call the gateway without Register
+type CertMapper struct {
+	trustDomain  string
+	defaultModel string
+	identities   []CertIdentity
+}
+
+// NewCertMapper creates a CertMapper. Explicit identities are checked first;
+// then, when trustDomain is set, URI SANs of the form
+// spiffe://<trustDomain>/tenant/<tenant_id>/agent/<agent_id>.
+func NewCertMapper(trustDomain, defaultModel string, identities []CertIdentity) *CertMapper {
+	return &CertMapper{
+		trustDomain:  trustDomain,
+		defaultModel: defaultModel,
+		identities:   identities,
+	}
+}
+
+// Principal returns the principal of a verified client certificate, or false
+// when the certificate is not mapped to any tenant
+func (m *CertMapper) Principal(cert *x509.Certificate) (*Principal, bool) {
+	names := certificateNames(cert)
+
+	for _, identity := range m.identities {
+		for _, name := range names {
+			if name == identity.Match {
+				return m.principal(cert, identity.TenantID, identity.AgentID, identity.Model), true
+			}
+		}
+	}
+
+	if m.trustDomain != "" {
+		for _, uri := range cert.URIs {
+			if tenantID, agentID, ok := m.parseURI(uri); ok {
+				return m.principal(cert, tenantID, agentID, ""), true
+			}
+		}
+	}
+
+	return nil, false
+}
+
+// principal builds the principal of a certificate identity. Each identity
+// has a single implicit session, named after it, that lives as long as the
+// certificate.
+func (m *CertMapper) principal(cert *x509.Certificate, tenantID, agentID, model string) *Principal {
+	if model == "" {
+		model = m.defaultModel
+	}
+	fingerprint := sha256.Sum256(cert.Raw)
+	return &Principal{
+		TenantID:  tenantID,
+		AgentID:   agentID,
+		SessionID: "mtls:" + tenantID + "/" + agentID,
+		Model:     model,
+		TokenID:   hex.EncodeToString(fingerprint[:]),
+		ExpiresAt: cert.NotAfter,
+	}
+}
+
+// parseURI extracts the identity of a spiffe://<domain>/tenant/<t>/agent/<a> URI
+func (m *CertMapper) parseURI(uri *url.URL) (tenantID, agentID string, ok bool) {
+	if uri.Scheme != "spiffe" || uri.Host != m.trustDomain {
+		return "", "", false
+	}
+	parts := strings.Split(strings.Trim(uri.Path, "/"), "/")
+	if len(parts) != 4 || parts[0] != "tenant" || parts[2] != "agent" || parts[1] == "" || parts[3] == "" {
+		return "", "", false
+	}
+	return parts[1], parts[3], true
+}
+
+// certificateNames lists every name a certificate can be matched by
+func certificateNames(cert *x509.Certificate) []string {
+	var names []string
+	for _, uri := range cert.URIs {
+		names = append(names, uri.String())
+	}
+	names = append(names, cert.DNSNames...)
+	names = append(names, cert.EmailAddresses...)
+	if cert.Subject.CommonName != "" {
+		names = append(names, cert.Subject.CommonName)
+	}
+	return names
+}
@@ -0,0 +1,129 @@
+package auth
+
+import (
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"math/big"
+	"net/url"
+	"testing"
+	"time"
+)
+
+// testCertificate signs a client certificate with the given names by a
+// throwaway CA
+func testCertificate(t *testing.T, commonName string, dnsNames []string, uris ...string) *x509.Certificate {
+	t.Helper()
+	caKey, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	caTemplate := &x509.Certificate{
+		SerialNumber:          big.NewInt(1),
+		Subject:               pkix.Name{CommonName: "Test CA"},
+		NotBefore:             time.Now().Add(-time.Hour),
+		NotAfter:              time.Now().Add(time.Hour),
+		IsCA:                  true,
+		BasicConstraintsValid: true,
+		KeyUsage:              x509.KeyUsageCertSign,
+	}
+	caDER, err := x509.CreateCertificate(rand.Reader, caTemplate, caTemplate, &caKey.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	ca, err := x509.ParseCertificate(caDER)
+	if err != nil {
+		t.Fatal(err)
+	}
+
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		t.Fatal(err)
+	}
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(2),
+		Subject:      pkix.Name{CommonName: commonName},
+		DNSNames:     dnsNames,
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		KeyUsage:     x509.KeyUsageDigitalSignature,
+		ExtKeyUsage:  []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth},
+	}
+	for _, raw := range uris {
+		uri, err := url.Parse(raw)
+		if err != nil {
+			t.Fatal(err)
+		}
+		template.URIs = append(template.URIs, uri)
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, ca, &key.PublicKey, caKey)
+	if err != nil {
+		t.Fatal(err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		t.Fatal(err)
+	}
+	return cert
+}
+
+func TestCertMapperPrincipal(t *testing.T) {
+	mapper := NewCertMapper("gateway.local", "gemma3:4b", []CertIdentity{
+		{Match: "billing-service", TenantID: "acme", AgentID: "billing"},
+		{Match: "reports.acme.internal", TenantID: "acme", AgentID: "reports", Model: "llama3"},
+		{Match: "spiffe://gateway.local/legacy", TenantID: "legacy", AgentID: "batch"},
+	})
+
+	tests := []struct {
+		name       string
+		commonName string
+		dnsNames   []string
+		uris       []string
+		tenantID   string
+		agentID    string
+		model      string
+	}{
+		{"SPIFFE URI", "", nil, []string{"spiffe://gateway.local/tenant/acme/agent/crawler"}, "acme", "crawler", "gemma3:4b"},
+		{"common name", "billing-service", nil, nil, "acme", "billing", "gemma3:4b"},
+		{"DNS SAN with model", "", []string{"reports.acme.internal"}, nil, "acme", "reports", "llama3"},
+		{"identity before SPIFFE", "billing-service", nil, []string{"spiffe://gateway.local/tenant/other/agent/x"}, "acme", "billing", "gemma3:4b"},
+		{"URI identity", "", nil, []string{"spiffe://gateway.local/legacy"}, "legacy", "batch", "gemma3:4b"},
+		{"other trust domain", "", nil, []string{"spiffe://elsewhere/tenant/acme/agent/crawler"}, "", "", ""},
+		{"malformed SPIFFE path", "", nil, []string{"spiffe://gateway.local/tenant/acme"}, "", "", ""},
+		{"empty SPIFFE tenant", "", nil, []string{"spiffe://gateway.local/tenant//agent/crawler"}, "", "", ""},
+		{"unknown name", "someone", []string{"someone.example.com"}, nil, "", "", ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cert := testCertificate(t, tt.commonName, tt.dnsNames, tt.uris...)
+			principal, ok := mapper.Principal(cert)
+			if tt.tenantID == "" {
+				if ok {
+					t.Fatalf("certificate mapped to %+v, want it unmapped", principal)
+				}
+				return
+			}
+			if !ok {
+				t.Fatal("certificate not mapped")
+			}
+			if principal.TenantID != tt.tenantID || principal.AgentID != tt.agentID || principal.Model != tt.model {
+				t.Errorf("principal = %+v, want %s/%s on %s", principal, tt.tenantID, tt.agentID, tt.model)
+			}
+			// Every identity has one implicit session, as long as the certificate
+			if principal.SessionID != "mtls:"+tt.tenantID+"/"+tt.agentID || !principal.ExpiresAt.Equal(cert.NotAfter) {
+				t.Errorf("session %s expiring %v, want the implicit session of the certificate", principal.SessionID, principal.ExpiresAt)
+			}
+			if len(principal.TokenID) != 64 {
+				t.Errorf("token ID = %q, want the SHA-256 fingerprint of the certificate", principal.TokenID)
+			}
+		})
+	}
+
+	// Without a trust domain only explicit identities map
+	cert := testCertificate(t, "", nil, "spiffe://gateway.local/tenant/acme/agent/crawler")
+	if principal, ok := NewCertMapper("", "gemma3:4b", nil).Principal(cert); ok {
+		t.Errorf("SPIFFE URI mapped to %+v without a trust domain", principal)
+	}
+}
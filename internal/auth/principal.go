@@ -0,0 +1,29 @@
+package auth
+
+import (
+	"context"
+	"time"
+)
+
+// Principal is the authenticated caller of an RPC, as bound to its token
+type Principal struct {
+	TenantID  string
+	AgentID   string
+	SessionID string
+	Model     string
+	TokenID   string
+	ExpiresAt time.Time
+}
+
+type principalKey struct{}
+
+// NewContext returns a copy of ctx carrying the principal
+func NewContext(ctx context.Context, principal *Principal) context.Context {
+	return context.WithValue(ctx, principalKey{}, principal)
+}
+
+// FromContext returns the principal stored by the auth interceptors, if any
+func FromContext(ctx context.Context) (*Principal, bool) {
+	principal, ok := ctx.Value(principalKey{}).(*Principal)
+	return principal, ok
+}
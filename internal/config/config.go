@@ -0,0 +1,665 @@
+// Package config loads the gateway configuration. Values are resolved in
+// this order, later ones winning: built-in defaults, the YAML file, the
+// selected environment overlay, GENTLEMAN_* environment variables and
+// finally command line flags (applied by the caller).
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path"
+	"regexp"
+	"slices"
+	"sort"
+	"strings"
+	"time"
+
+	"gopkg.in/yaml.v3"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/logging"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+)
+
+// EnvPrefix prefixes the environment variables that override settings, e.g.
+// GENTLEMAN_SERVER_PORT for server.port
+const EnvPrefix = "GENTLEMAN"
+
+type Config struct {
+	Server ServerConfig `yaml:"server"`
+	Ollama OllamaConfig `yaml:"ollama"`
+	Chat   ChatConfig   `yaml:"chat"`
+	MCP    MCPConfig    `yaml:"mcp"`
+	Auth   AuthConfig   `yaml:"auth"`
+	// Tenants allowed to register, keyed by tenant ID. The "default" entry
+	// holds the settings every tenant inherits; with no other entry any
+	// tenant ID is accepted.
+	Tenants       map[string]TenantConfig `yaml:"tenants"`
+	Database      DatabaseConfig          `yaml:"database"`
+	Security      SecurityConfig          `yaml:"security"`
+	Observability ObservabilityConfig     `yaml:"observability"`
+	Development   DevelopmentConfig       `yaml:"development"`
+
+	// Environments are partial configs applied over the file, selected with
+	// -env or GENTLEMAN_ENV. Map entries are replaced whole: an overlay of
+	// tenants.acme must repeat every setting of acme it keeps.
+	Environments map[string]yaml.Node `yaml:"environments"`
+	// Environment is the name of the applied overlay, if any
+	Environment string `yaml:"-"`
+}
+
+type ServerConfig struct {
+	Host            string    `yaml:"host"`
+	Port            int       `yaml:"port"`
+	Web             WebConfig `yaml:"web"`
+	TLS             TLSConfig `yaml:"tls"`
+	Insecure        bool      `yaml:"insecure"` // development only, disables TLS
+	ShutdownTimeout Duration  `yaml:"shutdown_timeout"`
+}
+
+// WebConfig is the gRPC-Web proxy
+type WebConfig struct {
+	Enabled bool `yaml:"enabled"`
+	Port    int  `yaml:"port"`
+}
+
+type TLSConfig struct {
+	Enabled    bool       `yaml:"enabled"`
+	CertFile   string     `yaml:"cert_file"`
+	KeyFile    string     `yaml:"key_file"`
+	CACertFile string     `yaml:"ca_cert_file"`
+	MTLS       MTLSConfig `yaml:"mtls"`
+	MinVersion string     `yaml:"min_version"` // "1.2" or "1.3"
+
+	// ReloadInterval is how often the certificate, key, CA and CRL files are
+	// checked for changes, so rotated certificates are picked up (0 disables)
+	ReloadInterval Duration `yaml:"reload_interval"`
+}
+
+type MTLSConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	RequireClientCert bool `yaml:"require_client_cert"`
+	// CRLFile is an optional PEM or DER revocation list, signed by the CA,
+	// checked for every client certificate
+	CRLFile string `yaml:"crl_file"`
+
+	// Verified client certificates with a URI SAN
+	// spiffe://<trust_domain>/tenant/<tenant_id>/agent/<agent_id> authenticate
+	// as that tenant and agent without a bearer token
+	TrustDomain string `yaml:"trust_domain"`
+	// Identities maps other certificate names to a tenant and agent
+	Identities []CertIdentity `yaml:"identities"`
+}
+
+// CertIdentity maps a client certificate to the tenant and agent it
+// authenticates as. Match is compared with the URI, DNS and email SANs and
+// the subject common name.
+type CertIdentity struct {
+	Match    string `yaml:"match"`
+	TenantID string `yaml:"tenant_id"`
+	AgentID  string `yaml:"agent_id"`
+	Model    string `yaml:"model"` // optional, defaults to the tenant default model
+}
+
+type OllamaConfig struct {
+	BaseURL      string   `yaml:"base_url"`
+	Timeout      Duration `yaml:"timeout"` // non-streaming requests
+	DefaultModel string   `yaml:"default_model"`
+}
+
+type ChatConfig struct {
+	History            HistoryConfig          `yaml:"history"`
+	SystemPrompt       string                 `yaml:"system_prompt"`
+	AllowModelOverride bool                   `yaml:"allow_model_override"`
+	StructuredOutput   StructuredOutputConfig `yaml:"structured_output"`
+	Tools              ToolsConfig            `yaml:"tools"`
+	// StreamIdleTimeout closes Chat streams without messages for this long
+	// (0 = never). The conversation of the session lives on until the
+	// session expires.
+	StreamIdleTimeout Duration `yaml:"stream_idle_timeout"`
+}
+
+// ToolsConfig controls the tools of the gateway the model can call
+type ToolsConfig struct {
+	Enabled       []string `yaml:"enabled"`        // tools offered to the model, none by default
+	MaxRounds     int      `yaml:"max_rounds"`     // rounds of tool calls per answer
+	Timeout       Duration `yaml:"timeout"`        // per tool call
+	ClientTimeout Duration `yaml:"client_timeout"` // wait for the result of a tool of the agent
+}
+
+// StructuredOutputConfig controls the JSON answers requests ask for with a
+// schema
+type StructuredOutputConfig struct {
+	MaxRepairs int `yaml:"max_repairs"` // times an invalid answer goes back to the model
+}
+
+// HistoryConfig bounds the conversation memory kept per session (0 = unlimited)
+type HistoryConfig struct {
+	MaxTurns int `yaml:"max_turns"`
+	MaxChars int `yaml:"max_chars"`
+}
+
+// MCPConfig is the Model Context Protocol endpoint, which serves the gateway
+// to MCP hosts such as IDEs and desktop assistants, and the external MCP
+// servers whose tools the model can call
+type MCPConfig struct {
+	HTTP    MCPHTTPConfig     `yaml:"http"`
+	Stdio   MCPStdioConfig    `yaml:"stdio"`
+	Prompts []PromptConfig    `yaml:"prompts"` // offered to hosts with prompts/list
+	Servers []MCPServerConfig `yaml:"servers"`
+}
+
+// MCPHTTPConfig is the streamable HTTP transport, served on its own port
+// with the TLS settings of the gRPC server
+type MCPHTTPConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	Port           int      `yaml:"port"`
+	Path           string   `yaml:"path"`
+	SessionTimeout Duration `yaml:"session_timeout"` // idle MCP sessions are forgotten
+}
+
+// MCPStdioConfig is the identity registered for the session of a host that
+// runs the gateway with -mcp-stdio
+type MCPStdioConfig struct {
+	TenantID string `yaml:"tenant_id"`
+	AgentID  string `yaml:"agent_id"`
+	Model    string `yaml:"model"` // empty uses the tenant default
+}
+
+// PromptConfig is a prompt template. Its text refers to the arguments as
+// {{name}}.
+type PromptConfig struct {
+	Name        string                 `yaml:"name"`
+	Description string                 `yaml:"description"`
+	Text        string                 `yaml:"text"`
+	Arguments   []PromptArgumentConfig `yaml:"arguments"`
+}
+
+// MCPServerConfig is an external MCP server, launched with a command to talk
+// to on stdio or reached at the URL of a streamable HTTP endpoint. Its tools
+// are offered to the model as <name>_<tool>, in the sessions of tenants
+// whose mcp_tools allow them.
+type MCPServerConfig struct {
+	Name    string            `yaml:"name"`
+	Command string            `yaml:"command"`
+	Args    []string          `yaml:"args"`
+	Env     map[string]string `yaml:"env"` // added to the environment of the gateway
+	Dir     string            `yaml:"dir"` // working directory of the command
+	URL     string            `yaml:"url"`
+	Headers map[string]string `yaml:"headers"` // sent with every HTTP request, e.g. Authorization
+	Timeout Duration          `yaml:"timeout"` // bounds connecting and listing tools
+	Tools   []string          `yaml:"tools"`   // tools to offer, or patterns like read_*; empty offers all
+}
+
+// validServerName matches the names of MCP servers, which prefix the names
+// of their tools
+var validServerName = regexp.MustCompile(`^[a-zA-Z0-9_-]+$`)
+
+type PromptArgumentConfig struct {
+	Name        string `yaml:"name"`
+	Description string `yaml:"description"`
+	Required    bool   `yaml:"required"`
+}
+
+type AuthConfig struct {
+	JWT      JWTConfig      `yaml:"jwt"`
+	Sessions SessionsConfig `yaml:"sessions"`
+
+	// Serve reflection and health checks without a bearer token
+	PublicReflection bool `yaml:"public_reflection"`
+	PublicHealth     bool `yaml:"public_health"`
+}
+
+type JWTConfig struct {
+	Expiration     Duration `yaml:"expiration"` // access token lifetime
+	SecretKey      string   `yaml:"secret_key"`
+	Algorithm      string   `yaml:"algorithm"`
+	PrivateKeyFile string   `yaml:"private_key_file"`
+	PublicKeyFile  string   `yaml:"public_key_file"`
+	Issuer         string   `yaml:"issuer"`
+}
+
+type SessionsConfig struct {
+	CleanupInterval Duration `yaml:"cleanup_interval"`
+}
+
+// DefaultTenant is the tenants entry whose settings every tenant inherits.
+// It does not register a tenant itself.
+const DefaultTenant = "default"
+
+// TenantConfig holds the settings of a tenant. Unset fields are inherited
+// from tenants.default, and then from ollama.default_model and
+// chat.system_prompt.
+type TenantConfig struct {
+	DefaultModel  string           `yaml:"default_model"`
+	SystemPrompt  string           `yaml:"system_prompt"`
+	AllowedModels []string         `yaml:"allowed_models"` // empty allows any model
+	MCPTools      []string         `yaml:"mcp_tools"`      // tools of mcp.servers allowed, or patterns like files_*; none by default
+	RateLimit     RateLimitConfig  `yaml:"rate_limit"`
+	Quotas        QuotasConfig     `yaml:"quotas"`
+	Generation    GenerationConfig `yaml:"generation"`
+}
+
+// RateLimitConfig bounds the request rate of a tenant. Unset limits are
+// inherited, and 0 is unlimited, so a tenant can opt out of a default limit.
+type RateLimitConfig struct {
+	RequestsPerMinute *int  `yaml:"requests_per_minute"`
+	Burst             *int  `yaml:"burst"`
+	PerAgent          *bool `yaml:"per_agent"` // limit each agent of the tenant separately
+}
+
+// QuotasConfig bounds the usage of a tenant (unset inherits, 0 is
+// unlimited). Requests and tokens are counted per window, which defaults to
+// a day.
+type QuotasConfig struct {
+	MaxSessions       *int     `yaml:"max_sessions"` // active at once
+	MaxRequestsPerDay *int     `yaml:"max_requests_per_day"`
+	MaxTokensPerDay   *int     `yaml:"max_tokens_per_day"` // prompt plus completion
+	Window            Duration `yaml:"window"`
+}
+
+// GenerationConfig caps the generation options requests of a tenant can ask
+// for (unset inherits, 0 is no cap). Requests that set no value get the cap.
+type GenerationConfig struct {
+	MaxTokens  *int `yaml:"max_tokens"`  // tokens generated per answer
+	MaxContext *int `yaml:"max_context"` // context window (num_ctx)
+}
+
+type DatabaseConfig struct {
+	Type string `yaml:"type"` // memory or bolt
+	Path string `yaml:"path"`
+}
+
+type SecurityConfig struct {
+	CORS         CORSConfig         `yaml:"cors"`
+	Validation   ValidationConfig   `yaml:"validation"`
+	RateLimiting RateLimitingConfig `yaml:"rate_limiting"`
+}
+
+type CORSConfig struct {
+	Enabled        bool     `yaml:"enabled"`
+	AllowedOrigins []string `yaml:"allowed_origins"`
+}
+
+type ValidationConfig struct {
+	MaxMessageSize     ByteSize          `yaml:"max_message_size"`
+	MaxSessionDuration Duration          `yaml:"max_session_duration"` // how long Refresh can extend a session
+	Attachments        AttachmentsConfig `yaml:"attachments"`
+}
+
+// AttachmentsConfig limits the images sent along chat messages
+type AttachmentsConfig struct {
+	MaxCount     int      `yaml:"max_count"` // per message (0 rejects attachments)
+	MaxSize      ByteSize `yaml:"max_size"`  // per attachment
+	AllowedTypes []string `yaml:"allowed_types"`
+}
+
+// RateLimitingConfig limits the requests of the whole gateway, on top of the
+// tenant limits
+type RateLimitingConfig struct {
+	Enabled           bool `yaml:"enabled"`
+	RequestsPerSecond int  `yaml:"requests_per_second"`
+	Burst             int  `yaml:"burst"`
+}
+
+// ObservabilityConfig controls what the gateway reports about itself
+type ObservabilityConfig struct {
+	Logging LoggingConfig `yaml:"logging"`
+}
+
+type LoggingConfig struct {
+	Level string `yaml:"level"` // debug, info, warn or error
+}
+
+type DevelopmentConfig struct {
+	Reflection bool `yaml:"reflection"`
+	HotReload  bool `yaml:"hot_reload"` // reload when the config file changes, not only on SIGHUP
+}
+
+// Default returns the configuration used when no file is given
+func Default() *Config {
+	return &Config{
+		Server: ServerConfig{
+			Host: "0.0.0.0",
+			Port: 50051,
+			Web: WebConfig{
+				Enabled: true,
+				Port:    8080,
+			},
+			TLS: TLSConfig{
+				Enabled:        true,
+				CertFile:       "certs/server-cert.pem",
+				KeyFile:        "certs/server-key.pem",
+				CACertFile:     "certs/ca-cert.pem",
+				MinVersion:     "1.3",
+				ReloadInterval: Duration(30 * time.Second),
+			},
+			ShutdownTimeout: Duration(30 * time.Second),
+		},
+		Ollama: OllamaConfig{
+			BaseURL:      "http://localhost:11434",
+			Timeout:      Duration(30 * time.Second),
+			DefaultModel: "gemma3:4b",
+		},
+		Chat: ChatConfig{
+			History: HistoryConfig{
+				MaxTurns: 20,
+				MaxChars: 32000,
+			},
+			StructuredOutput: StructuredOutputConfig{
+				MaxRepairs: 2,
+			},
+			Tools: ToolsConfig{
+				MaxRounds:     5,
+				Timeout:       Duration(10 * time.Second),
+				ClientTimeout: Duration(time.Minute),
+			},
+			StreamIdleTimeout: Duration(5 * time.Minute),
+		},
+		MCP: MCPConfig{
+			HTTP: MCPHTTPConfig{
+				Port:           8090,
+				Path:           "/mcp",
+				SessionTimeout: Duration(30 * time.Minute),
+			},
+			Stdio: MCPStdioConfig{
+				AgentID: "mcp-stdio",
+			},
+		},
+		Auth: AuthConfig{
+			JWT: JWTConfig{
+				Expiration: Duration(5 * time.Minute),
+				Algorithm:  "HS256",
+				Issuer:     "gentleman-mcp",
+			},
+			Sessions: SessionsConfig{
+				CleanupInterval: Duration(time.Minute),
+			},
+			PublicReflection: true,
+			PublicHealth:     true,
+		},
+		Database: DatabaseConfig{
+			Type: "memory",
+			Path: "data/sessions.db",
+		},
+		Security: SecurityConfig{
+			CORS: CORSConfig{
+				Enabled:        true,
+				AllowedOrigins: []string{"http://localhost:3000", "http://localhost:8080"},
+			},
+			Validation: ValidationConfig{
+				MaxMessageSize:     4 << 20,
+				MaxSessionDuration: Duration(24 * time.Hour),
+				Attachments: AttachmentsConfig{
+					MaxCount:     4,
+					MaxSize:      3 << 20,
+					AllowedTypes: []string{"image/png", "image/jpeg", "image/webp"},
+				},
+			},
+		},
+		Observability: ObservabilityConfig{
+			Logging: LoggingConfig{
+				Level: "info",
+			},
+		},
+		Development: DevelopmentConfig{
+			Reflection: true,
+		},
+	}
+}
+
+// Load reads the YAML file at path (skipped when empty) over the defaults,
+// applies the named environment overlay (skipped when empty) and then the
+// GENTLEMAN_* environment variables. The result is not validated yet, so
+// callers can apply their own overrides before calling Validate.
+func Load(path, environment string) (*Config, error) {
+	cfg := Default()
+
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read config file: %w", err)
+		}
+		if err := decodeStrict(data, cfg); err != nil {
+			return nil, fmt.Errorf("failed to parse %s: %w", path, err)
+		}
+	}
+
+	if environment != "" {
+		overlay, ok := cfg.Environments[environment]
+		if !ok {
+			return nil, fmt.Errorf("unknown environment %q in config file", environment)
+		}
+		// Decoding over the loaded values only replaces the keys the overlay
+		// sets, but an entry of a map is decoded from scratch
+		data, err := yaml.Marshal(&overlay)
+		if err == nil {
+			err = decodeStrict(data, cfg)
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to apply environment %q: %w", environment, err)
+		}
+		cfg.Environment = environment
+	}
+
+	if err := applyEnv(cfg, os.LookupEnv); err != nil {
+		return nil, err
+	}
+
+	return cfg, nil
+}
+
+// decodeStrict decodes YAML over cfg. Keys Config does not know are errors,
+// so misspelled or stale settings fail loudly instead of being ignored.
+func decodeStrict(data []byte, cfg *Config) error {
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return err
+	}
+	return nil
+}
+
+// Validate reports every invalid setting at once
+func (c *Config) Validate() error {
+	var errs []error
+	check := func(ok bool, format string, args ...interface{}) {
+		if !ok {
+			errs = append(errs, fmt.Errorf(format, args...))
+		}
+	}
+
+	check(validPort(c.Server.Port), "server.port: %d is not a valid port", c.Server.Port)
+	if c.Server.Web.Enabled {
+		check(validPort(c.Server.Web.Port), "server.web.port: %d is not a valid port", c.Server.Web.Port)
+		check(c.Server.Web.Port != c.Server.Port, "server.web.port: must differ from server.port")
+	}
+	if c.MCP.HTTP.Enabled {
+		check(validPort(c.MCP.HTTP.Port), "mcp.http.port: %d is not a valid port", c.MCP.HTTP.Port)
+		check(c.MCP.HTTP.Port != c.Server.Port && (!c.Server.Web.Enabled || c.MCP.HTTP.Port != c.Server.Web.Port),
+			"mcp.http.port: must differ from server.port and server.web.port")
+		check(strings.HasPrefix(c.MCP.HTTP.Path, "/"), "mcp.http.path: %q must start with /", c.MCP.HTTP.Path)
+		check(c.MCP.HTTP.SessionTimeout > 0, "mcp.http.session_timeout: must be positive")
+	}
+	check(c.Server.ShutdownTimeout > 0, "server.shutdown_timeout: must be positive")
+	check(c.Server.TLS.ReloadInterval >= 0, "server.tls.reload_interval: must not be negative")
+	if c.TLSEnabled() {
+		check(c.Server.TLS.CertFile != "", "server.tls.cert_file: required when TLS is enabled")
+		check(c.Server.TLS.KeyFile != "", "server.tls.key_file: required when TLS is enabled")
+		check(c.Server.TLS.MinVersion == "1.2" || c.Server.TLS.MinVersion == "1.3",
+			"server.tls.min_version: %q is not supported (use 1.2 or 1.3)", c.Server.TLS.MinVersion)
+		if c.Server.TLS.MTLS.Enabled {
+			check(c.Server.TLS.CACertFile != "", "server.tls.ca_cert_file: required for mTLS")
+		}
+		for i, identity := range c.Server.TLS.MTLS.Identities {
+			check(identity.Match != "" && identity.TenantID != "" && identity.AgentID != "",
+				"server.tls.mtls.identities[%d]: match, tenant_id and agent_id are required", i)
+		}
+	}
+
+	check(strings.HasPrefix(c.Ollama.BaseURL, "http://") || strings.HasPrefix(c.Ollama.BaseURL, "https://"),
+		"ollama.base_url: %q must be an http(s) URL", c.Ollama.BaseURL)
+	check(c.Ollama.Timeout > 0, "ollama.timeout: must be positive")
+	check(c.Ollama.DefaultModel != "", "ollama.default_model: required")
+
+	check(c.Chat.History.MaxTurns >= 0, "chat.history.max_turns: must not be negative")
+	check(c.Chat.History.MaxChars >= 0, "chat.history.max_chars: must not be negative")
+	check(c.Chat.StructuredOutput.MaxRepairs >= 0, "chat.structured_output.max_repairs: must not be negative")
+	check(c.Chat.Tools.MaxRounds > 0, "chat.tools.max_rounds: must be positive")
+	check(c.Chat.Tools.Timeout >= 0, "chat.tools.timeout: must not be negative")
+	check(c.Chat.Tools.ClientTimeout >= 0, "chat.tools.client_timeout: must not be negative")
+	check(c.Chat.StreamIdleTimeout >= 0, "chat.stream_idle_timeout: must not be negative")
+	for i, name := range c.Chat.Tools.Enabled {
+		check(!slices.Contains(c.Chat.Tools.Enabled[:i], name), "chat.tools.enabled: %q is listed twice", name)
+	}
+
+	for i, prompt := range c.MCP.Prompts {
+		check(prompt.Name != "", "mcp.prompts[%d].name: required", i)
+		check(prompt.Text != "", "mcp.prompts[%d].text: required", i)
+		check(!slices.ContainsFunc(c.MCP.Prompts[:i], func(other PromptConfig) bool { return other.Name == prompt.Name }),
+			"mcp.prompts[%d].name: %q is used twice", i, prompt.Name)
+		for j, argument := range prompt.Arguments {
+			check(argument.Name != "", "mcp.prompts[%d].arguments[%d].name: required", i, j)
+		}
+	}
+	for i, server := range c.MCP.Servers {
+		check(validServerName.MatchString(server.Name), "mcp.servers[%d].name: %q must be letters, digits, _ or -", i, server.Name)
+		check(!slices.ContainsFunc(c.MCP.Servers[:i], func(other MCPServerConfig) bool { return other.Name == server.Name }),
+			"mcp.servers[%d].name: %q is used twice", i, server.Name)
+		check((server.Command == "") != (server.URL == ""), "mcp.servers[%d]: set either command or url", i)
+		check(server.URL == "" || strings.HasPrefix(server.URL, "http://") || strings.HasPrefix(server.URL, "https://"),
+			"mcp.servers[%d].url: %q must be an http(s) URL", i, server.URL)
+		check(server.Timeout >= 0, "mcp.servers[%d].timeout: must not be negative", i)
+		for _, pattern := range server.Tools {
+			_, err := path.Match(pattern, "")
+			check(err == nil, "mcp.servers[%d].tools: %q is not a valid pattern", i, pattern)
+		}
+	}
+
+	switch strings.ToUpper(c.Auth.JWT.Algorithm) {
+	case "HS256", "RS256", "EDDSA":
+	default:
+		errs = append(errs, fmt.Errorf("auth.jwt.algorithm: %q is not supported (use HS256, RS256 or EdDSA)", c.Auth.JWT.Algorithm))
+	}
+	check(c.Auth.JWT.Expiration > 0, "auth.jwt.expiration: must be positive")
+	check(c.Auth.Sessions.CleanupInterval > 0, "auth.sessions.cleanup_interval: must be positive")
+
+	for id, entry := range c.Tenants {
+		check(nonNegative(entry.RateLimit.RequestsPerMinute, entry.RateLimit.Burst),
+			"tenants.%s.rate_limit: must not be negative", id)
+		check(nonNegative(entry.Quotas.MaxSessions, entry.Quotas.MaxRequestsPerDay, entry.Quotas.MaxTokensPerDay) &&
+			entry.Quotas.Window >= 0,
+			"tenants.%s.quotas: must not be negative", id)
+		check(nonNegative(entry.Generation.MaxTokens, entry.Generation.MaxContext),
+			"tenants.%s.generation: must not be negative", id)
+		for _, pattern := range entry.MCPTools {
+			_, err := path.Match(pattern, "")
+			check(err == nil, "tenants.%s.mcp_tools: %q is not a valid pattern", id, pattern)
+		}
+		settings := c.TenantSettings(id)
+		check(len(settings.AllowedModels) == 0 || slices.Contains(settings.AllowedModels, settings.DefaultModel),
+			"tenants.%s: default model %q is not in allowed_models", id, settings.DefaultModel)
+	}
+
+	switch c.Database.Type {
+	case "memory":
+	case "bolt", "boltdb":
+		check(c.Database.Path != "", "database.path: required for %s", c.Database.Type)
+	default:
+		errs = append(errs, fmt.Errorf("database.type: %q is not supported (use memory or bolt)", c.Database.Type))
+	}
+
+	if c.Security.RateLimiting.Enabled {
+		check(c.Security.RateLimiting.RequestsPerSecond > 0, "security.rate_limiting.requests_per_second: must be positive")
+		check(c.Security.RateLimiting.Burst >= 0, "security.rate_limiting.burst: must not be negative")
+	}
+	check(c.Security.Validation.MaxMessageSize > 0, "security.validation.max_message_size: must be positive")
+	check(c.Security.Validation.MaxSessionDuration >= c.Auth.JWT.Expiration,
+		"security.validation.max_session_duration: must be at least auth.jwt.expiration")
+	if attachments := c.Security.Validation.Attachments; attachments.MaxCount != 0 {
+		check(attachments.MaxCount > 0, "security.validation.attachments.max_count: must not be negative")
+		check(attachments.MaxSize > 0 && attachments.MaxSize <= c.Security.Validation.MaxMessageSize,
+			"security.validation.attachments.max_size: must be positive and at most max_message_size")
+		check(len(attachments.AllowedTypes) > 0, "security.validation.attachments.allowed_types: required when attachments are enabled")
+		for _, mimeType := range attachments.AllowedTypes {
+			check(strings.HasPrefix(mimeType, "image/"),
+				"security.validation.attachments.allowed_types: %q is not an image type", mimeType)
+		}
+	}
+
+	if _, err := logging.ParseLevel(c.Observability.Logging.Level); err != nil {
+		errs = append(errs, fmt.Errorf("observability.logging.level: %w", err))
+	}
+
+	return errors.Join(errs...)
+}
+
+// TenantIDs returns the IDs of the configured tenants, without the default
+// entry, sorted
+func (c *Config) TenantIDs() []string {
+	ids := make([]string, 0, len(c.Tenants))
+	for id := range c.Tenants {
+		if id != DefaultTenant {
+			ids = append(ids, id)
+		}
+	}
+	sort.Strings(ids)
+	return ids
+}
+
+// TenantSettings returns the settings of a tenant with the inherited values
+// filled in: from tenants.default, and then from ollama.default_model and
+// chat.system_prompt
+func (c *Config) TenantSettings(id string) tenant.Tenant {
+	settings := c.Tenants[id].Tenant(id)
+	settings.Inherit(c.Tenants[DefaultTenant].Tenant(DefaultTenant))
+	settings.Inherit(tenant.Tenant{DefaultModel: c.Ollama.DefaultModel, SystemPrompt: c.Chat.SystemPrompt})
+	return settings
+}
+
+// Tenant converts the settings of a tenant entry, leaving unset values unset
+func (t TenantConfig) Tenant(id string) tenant.Tenant {
+	return tenant.Tenant{
+		ID:            id,
+		DefaultModel:  t.DefaultModel,
+		SystemPrompt:  t.SystemPrompt,
+		AllowedModels: t.AllowedModels,
+		MCPTools:      t.MCPTools,
+		RateLimit: tenant.RateLimit{
+			RequestsPerMinute: t.RateLimit.RequestsPerMinute,
+			Burst:             t.RateLimit.Burst,
+			PerAgent:          t.RateLimit.PerAgent,
+		},
+		Quotas: tenant.Quotas{
+			MaxSessions:       t.Quotas.MaxSessions,
+			MaxRequestsPerDay: t.Quotas.MaxRequestsPerDay,
+			MaxTokensPerDay:   t.Quotas.MaxTokensPerDay,
+			Window:            t.Quotas.Window.Std(),
+		},
+		Generation: tenant.Generation{
+			MaxTokens:  t.Generation.MaxTokens,
+			MaxContext: t.Generation.MaxContext,
+		},
+	}
+}
+
+// TLSEnabled reports whether the gRPC server should serve TLS
+func (c *Config) TLSEnabled() bool {
+	return c.Server.TLS.Enabled && !c.Server.Insecure
+}
+
+func validPort(port int) bool {
+	return port > 0 && port < 65536
+}
+
+// nonNegative reports whether none of the optional limits is negative
+func nonNegative(limits ...*int) bool {
+	for _, limit := range limits {
+		if limit != nil && *limit < 0 {
+			return false
+		}
+	}
+	return true
+}
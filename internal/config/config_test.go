@@ -0,0 +1,270 @@
+package config
+
+import (
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+)
+
+// writeConfig writes a config file for Load and returns its path
+func writeConfig(t *testing.T, content string) string {
+	t.Helper()
+	path := filepath.Join(t.TempDir(), "config.yaml")
+	if err := os.WriteFile(path, []byte(content), 0o600); err != nil {
+		t.Fatal(err)
+	}
+	return path
+}
+
+func TestLoadRejectsUnknownKeys(t *testing.T) {
+	tests := []struct {
+		name    string
+		content string
+		key     string
+	}{
+		{"top level", "sever:\n  port: 50051\n", "sever"},
+		{"nested", "server:\n  prot: 50051\n", "prot"},
+		{"tenant", "tenants:\n  acme:\n    default_modle: gemma3:4b\n", "default_modle"},
+		{"overlay", "environments:\n  production:\n    chat:\n      histroy: {}\n", "histroy"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			environment := ""
+			if strings.Contains(tt.content, "environments") {
+				environment = "production"
+			}
+			_, err := Load(writeConfig(t, tt.content), environment)
+			if err == nil || !strings.Contains(err.Error(), tt.key) {
+				t.Errorf("Load error = %v, want one naming %q", err, tt.key)
+			}
+		})
+	}
+}
+
+func TestLoadAppliesEnvironmentOverlay(t *testing.T) {
+	path := writeConfig(t, `
+server:
+  host: "127.0.0.1"
+  port: 50051
+tenants:
+  acme:
+    default_model: "gemma3:4b"
+    system_prompt: "You help Acme."
+environments:
+  production:
+    server:
+      port: 9000
+    tenants:
+      acme:
+        system_prompt: "You help Acme in production."
+`)
+
+	cfg, err := Load(path, "production")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Environment != "production" || cfg.Server.Port != 9000 {
+		t.Errorf("environment %q, port %d, want production and the port of the overlay", cfg.Environment, cfg.Server.Port)
+	}
+	if cfg.Server.Host != "127.0.0.1" {
+		t.Errorf("host = %q, the keys the overlay leaves out must keep the file value", cfg.Server.Host)
+	}
+	// Map entries are replaced whole
+	acme := cfg.Tenants["acme"]
+	if acme.SystemPrompt != "You help Acme in production." || acme.DefaultModel != "" {
+		t.Errorf("tenants.acme = %+v, want only the settings of the overlay", acme)
+	}
+
+	if _, err := Load(path, "staging"); err == nil || !strings.Contains(err.Error(), "staging") {
+		t.Errorf("Load of an unknown environment = %v, want an error naming it", err)
+	}
+}
+
+func TestLoadAppliesEnvironmentVariables(t *testing.T) {
+	path := writeConfig(t, "server:\n  port: 50051\n")
+	t.Setenv("GENTLEMAN_SERVER_PORT", "7000")
+	t.Setenv("GENTLEMAN_SERVER_INSECURE", "true")
+	t.Setenv("GENTLEMAN_CHAT_TOOLS_ENABLED", "clock, calculator,")
+	t.Setenv("GENTLEMAN_SECURITY_VALIDATION_MAX_MESSAGE_SIZE", "8MB")
+	t.Setenv("GENTLEMAN_AUTH_JWT_EXPIRATION", "90m")
+
+	cfg, err := Load(path, "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if cfg.Server.Port != 7000 || !cfg.Server.Insecure {
+		t.Errorf("server = %+v, want the port and insecure of the environment", cfg.Server)
+	}
+	if !slices.Equal(cfg.Chat.Tools.Enabled, []string{"clock", "calculator"}) {
+		t.Errorf("chat.tools.enabled = %v, want [clock calculator]", cfg.Chat.Tools.Enabled)
+	}
+	if cfg.Security.Validation.MaxMessageSize != 8<<20 {
+		t.Errorf("max_message_size = %d, want 8MB", cfg.Security.Validation.MaxMessageSize)
+	}
+	if cfg.Auth.JWT.Expiration.Std() != 90*time.Minute {
+		t.Errorf("jwt expiration = %v, want 90m", cfg.Auth.JWT.Expiration)
+	}
+
+	t.Setenv("GENTLEMAN_SERVER_PORT", "seven")
+	if _, err := Load(path, ""); err == nil || !strings.Contains(err.Error(), "GENTLEMAN_SERVER_PORT") {
+		t.Errorf("Load with an invalid variable = %v, want an error naming it", err)
+	}
+}
+
+func TestParseByteSize(t *testing.T) {
+	tests := []struct {
+		input string
+		want  ByteSize
+		valid bool
+	}{
+		{"1048576", 1 << 20, true},
+		{"512B", 512, true},
+		{"512KiB", 512 << 10, true},
+		{"512kb", 512 << 10, true},
+		{"4MB", 4 << 20, true},
+		{"4 MiB", 4 << 20, true},
+		{"1.5K", 1536, true},
+		{"2G", 2 << 30, true},
+		{"", 0, false},
+		{"MB", 0, false},
+		{"-1KB", 0, false},
+		{"4TB", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseByteSize(tt.input)
+		if (err == nil) != tt.valid || got != tt.want {
+			t.Errorf("ParseByteSize(%q) = %d, %v, want %d (valid %v)", tt.input, got, err, tt.want, tt.valid)
+		}
+	}
+}
+
+func TestParseDuration(t *testing.T) {
+	tests := []struct {
+		input string
+		want  time.Duration
+		valid bool
+	}{
+		{"30s", 30 * time.Second, true},
+		{" 5m ", 5 * time.Minute, true},
+		{"1h30m", 90 * time.Minute, true},
+		{"0", 0, true},
+		{"30", 0, false},
+		{"soon", 0, false},
+	}
+	for _, tt := range tests {
+		got, err := ParseDuration(tt.input)
+		if (err == nil) != tt.valid || got.Std() != tt.want {
+			t.Errorf("ParseDuration(%q) = %v, %v, want %v (valid %v)", tt.input, got, err, tt.want, tt.valid)
+		}
+	}
+
+	// YAML errors carry the line of the bad value
+	_, err := Load(writeConfig(t, "server:\n  port: 50051\n  shutdown_timeout: soon\n"), "")
+	if err == nil || !strings.Contains(err.Error(), "line 3") {
+		t.Errorf("Load error = %v, want one naming line 3", err)
+	}
+}
+
+func TestValidate(t *testing.T) {
+	if err := Default().Validate(); err != nil {
+		t.Fatalf("the defaults are invalid: %v", err)
+	}
+
+	tests := []struct {
+		name   string
+		modify func(*Config)
+		want   []string
+	}{
+		{"port", func(c *Config) { c.Server.Port = 70000 }, []string{"server.port"}},
+		{"ports clash", func(c *Config) { c.Server.Web.Enabled = true; c.Server.Web.Port = c.Server.Port }, []string{"server.web.port"}},
+		{"ollama", func(c *Config) { c.Ollama.BaseURL = "localhost:11434"; c.Ollama.DefaultModel = "" },
+			[]string{"ollama.base_url", "ollama.default_model"}},
+		{"duplicate tool", func(c *Config) { c.Chat.Tools.Enabled = []string{"clock", "clock"} }, []string{"chat.tools.enabled"}},
+		{"jwt algorithm", func(c *Config) { c.Auth.JWT.Algorithm = "none" }, []string{"auth.jwt.algorithm"}},
+		{"database", func(c *Config) { c.Database.Type = "bolt"; c.Database.Path = "" }, []string{"database.path"}},
+		{"tenant", func(c *Config) {
+			c.Tenants = map[string]TenantConfig{"acme": {
+				AllowedModels: []string{"llama3"},
+				MCPTools:      []string{"files_["},
+			}}
+		}, []string{"tenants.acme.mcp_tools", "tenants.acme: default model"}},
+		{"attachments", func(c *Config) {
+			c.Security.Validation.Attachments = AttachmentsConfig{MaxCount: 1, MaxSize: 1 << 30, AllowedTypes: []string{"text/plain"}}
+		}, []string{"attachments.max_size", "attachments.allowed_types"}},
+		{"log level", func(c *Config) { c.Observability.Logging.Level = "loud" }, []string{"observability.logging.level"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg := Default()
+			tt.modify(cfg)
+			err := cfg.Validate()
+			if err == nil {
+				t.Fatal("Validate accepted the config")
+			}
+			// Every invalid setting is reported at once
+			for _, want := range tt.want {
+				if !strings.Contains(err.Error(), want) {
+					t.Errorf("Validate error = %v, want one about %s", err, want)
+				}
+			}
+		})
+	}
+}
+
+func TestExampleConfigIsValid(t *testing.T) {
+	path := filepath.Join("..", "..", "config.example.yaml")
+	cfg, err := Load(path, "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	environments := []string{""}
+	for name := range cfg.Environments {
+		environments = append(environments, name)
+	}
+	for _, environment := range environments {
+		cfg, err := Load(path, environment)
+		if err == nil {
+			err = cfg.Validate()
+		}
+		if err != nil {
+			t.Errorf("environment %q: %v", environment, err)
+		}
+	}
+}
+
+func TestTenantSettingsOptOutOfDefaultLimits(t *testing.T) {
+	cfg, err := Load(writeConfig(t, `
+tenants:
+  default:
+    rate_limit:
+      requests_per_minute: 60
+    quotas:
+      max_sessions: 5
+  acme:
+    rate_limit:
+      requests_per_minute: 0
+    quotas:
+      max_sessions: 0
+  globex: {}
+`), "")
+	if err != nil {
+		t.Fatalf("Load: %v", err)
+	}
+	if err := cfg.Validate(); err != nil {
+		t.Fatalf("Validate: %v", err)
+	}
+
+	acme := cfg.TenantSettings("acme")
+	if acme.RateLimit.RequestsPerMinute == nil || *acme.RateLimit.RequestsPerMinute != 0 ||
+		acme.Quotas.MaxSessions == nil || *acme.Quotas.MaxSessions != 0 {
+		t.Errorf("acme = %+v, want the explicit zero limits", acme)
+	}
+	globex := cfg.TenantSettings("globex")
+	if globex.RateLimit.RequestsPerMinute == nil || *globex.RateLimit.RequestsPerMinute != 60 ||
+		globex.Quotas.MaxSessions == nil || *globex.Quotas.MaxSessions != 5 {
+		t.Errorf("globex = %+v, want the default limits", globex)
+	}
+}
@@ -0,0 +1,108 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"sort"
+	"strings"
+)
+
+// Change is a setting that differs between two configurations
+type Change struct {
+	Path     string // YAML path, e.g. "security.cors.allowed_origins"
+	Old, New string
+}
+
+func (c Change) String() string {
+	return fmt.Sprintf("%s: %s → %s", c.Path, c.Old, c.New)
+}
+
+// Diff lists the settings that differ between old and new. Secrets are
+// reported as changed without their values.
+func Diff(old, new *Config) []Change {
+	var changes []Change
+	diffValue(reflect.ValueOf(old).Elem(), reflect.ValueOf(new).Elem(), "", &changes)
+	return changes
+}
+
+func diffValue(old, new reflect.Value, path string, changes *[]Change) {
+	if old.Kind() == reflect.Struct {
+		for i := 0; i < old.NumField(); i++ {
+			key := strings.Split(old.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			if key == "" || key == "-" {
+				continue
+			}
+			if path != "" {
+				key = path + "." + key
+			}
+			diffValue(old.Field(i), new.Field(i), key, changes)
+		}
+		return
+	}
+
+	// Maps of settings, like tenants, are compared entry by entry. Other maps
+	// (the environment overlays) are not settings themselves.
+	if old.Kind() == reflect.Map {
+		if old.Type().Elem().Kind() != reflect.Struct {
+			return
+		}
+		for _, key := range mapKeys(old, new) {
+			oldEntry, newEntry := old.MapIndex(key), new.MapIndex(key)
+			entryPath := path + "." + key.String()
+			switch {
+			case !oldEntry.IsValid():
+				*changes = append(*changes, Change{Path: entryPath, Old: "(none)", New: "(added)"})
+			case !newEntry.IsValid():
+				*changes = append(*changes, Change{Path: entryPath, Old: "(set)", New: "(removed)"})
+			default:
+				diffValue(oldEntry, newEntry, entryPath, changes)
+			}
+		}
+		return
+	}
+
+	if reflect.DeepEqual(old.Interface(), new.Interface()) {
+		return
+	}
+	change := Change{Path: path, Old: formatValue(old), New: formatValue(new)}
+	if isSecret(path) {
+		change.Old, change.New = "(hidden)", "(hidden)"
+	}
+	*changes = append(*changes, change)
+}
+
+// mapKeys returns the keys of both maps, sorted
+func mapKeys(a, b reflect.Value) []reflect.Value {
+	seen := make(map[string]bool)
+	var keys []reflect.Value
+	for _, m := range []reflect.Value{a, b} {
+		for _, key := range m.MapKeys() {
+			if !seen[key.String()] {
+				seen[key.String()] = true
+				keys = append(keys, key)
+			}
+		}
+	}
+	sort.Slice(keys, func(i, j int) bool { return keys[i].String() < keys[j].String() })
+	return keys
+}
+
+func formatValue(v reflect.Value) string {
+	if v.Kind() == reflect.Pointer {
+		if v.IsNil() {
+			return "(unset)"
+		}
+		v = v.Elem()
+	}
+	if s, ok := v.Interface().(fmt.Stringer); ok {
+		return s.String()
+	}
+	if v.Kind() == reflect.String {
+		return fmt.Sprintf("%q", v.String())
+	}
+	return fmt.Sprint(v.Interface())
+}
+
+func isSecret(path string) bool {
+	return strings.HasSuffix(path, "secret_key") || strings.HasSuffix(path, "password")
+}
@@ -0,0 +1,117 @@
+package config
+
+import (
+	"fmt"
+	"reflect"
+	"strconv"
+	"strings"
+)
+
+var (
+	durationType = reflect.TypeOf(Duration(0))
+	byteSizeType = reflect.TypeOf(ByteSize(0))
+)
+
+// applyEnv overrides every setting that has a matching environment variable.
+// Names are built from the YAML keys: server.tls.cert_file is read from
+// GENTLEMAN_SERVER_TLS_CERT_FILE. Lists are comma separated.
+func applyEnv(cfg *Config, lookup func(string) (string, bool)) error {
+	return applyEnvValue(reflect.ValueOf(cfg).Elem(), EnvPrefix, lookup)
+}
+
+func applyEnvValue(v reflect.Value, name string, lookup func(string) (string, bool)) error {
+	if v.Kind() == reflect.Struct {
+		for i := 0; i < v.NumField(); i++ {
+			key := strings.Split(v.Type().Field(i).Tag.Get("yaml"), ",")[0]
+			if key == "" || key == "-" || !envSettable(v.Field(i)) {
+				continue
+			}
+			if err := applyEnvValue(v.Field(i), name+"_"+strings.ToUpper(key), lookup); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	raw, ok := lookup(name)
+	if !ok {
+		return nil
+	}
+	if err := setValue(v, raw); err != nil {
+		return fmt.Errorf("%s: %w", name, err)
+	}
+	return nil
+}
+
+// envSettable reports whether a setting can be written as a single variable:
+// maps and lists of objects cannot
+func envSettable(v reflect.Value) bool {
+	switch v.Kind() {
+	case reflect.Map:
+		return false
+	case reflect.Slice:
+		return v.Type().Elem().Kind() == reflect.String
+	}
+	return true
+}
+
+// setValue parses raw into a setting of any of the types used by Config
+func setValue(v reflect.Value, raw string) error {
+	switch v.Type() {
+	case durationType:
+		d, err := ParseDuration(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(d))
+		return nil
+	case byteSizeType:
+		b, err := ParseByteSize(raw)
+		if err != nil {
+			return err
+		}
+		v.Set(reflect.ValueOf(b))
+		return nil
+	}
+
+	switch v.Kind() {
+	case reflect.Pointer:
+		// Optional settings, unset unless given
+		value := reflect.New(v.Type().Elem())
+		if err := setValue(value.Elem(), raw); err != nil {
+			return err
+		}
+		v.Set(value)
+	case reflect.String:
+		v.SetString(raw)
+	case reflect.Bool:
+		b, err := strconv.ParseBool(raw)
+		if err != nil {
+			return fmt.Errorf("invalid boolean %q", raw)
+		}
+		v.SetBool(b)
+	case reflect.Int:
+		n, err := strconv.Atoi(raw)
+		if err != nil {
+			return fmt.Errorf("invalid integer %q", raw)
+		}
+		v.SetInt(int64(n))
+	case reflect.Float64:
+		f, err := strconv.ParseFloat(raw, 64)
+		if err != nil {
+			return fmt.Errorf("invalid number %q", raw)
+		}
+		v.SetFloat(f)
+	case reflect.Slice:
+		var items []string
+		for _, item := range strings.Split(raw, ",") {
+			if item = strings.TrimSpace(item); item != "" {
+				items = append(items, item)
+			}
+		}
+		v.Set(reflect.ValueOf(items))
+	default:
+		return fmt.Errorf("unsupported setting type %s", v.Type())
+	}
+	return nil
+}
@@ -0,0 +1,64 @@
+package config
+
+import (
+	"context"
+	"os"
+	"time"
+)
+
+// WatchFiles calls onChange whenever one of the files returned by paths is
+// modified, replaced, created or removed, or the list itself changes. Files
+// are polled, which also catches editors and orchestrators that replace a
+// file instead of writing it in place.
+func WatchFiles(ctx context.Context, interval time.Duration, onChange func(), paths func() []string) {
+	snapshot := func() map[string]os.FileInfo {
+		list := paths()
+		infos := make(map[string]os.FileInfo, len(list))
+		for _, path := range list {
+			info, err := os.Stat(path)
+			if err != nil {
+				info = nil
+			}
+			infos[path] = info
+		}
+		return infos
+	}
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	last := snapshot()
+	for {
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+
+		current := snapshot()
+		if snapshotChanged(last, current) {
+			onChange()
+		}
+		last = current
+	}
+}
+
+func snapshotChanged(old, new map[string]os.FileInfo) bool {
+	if len(old) != len(new) {
+		return true
+	}
+	for path, info := range new {
+		previous, ok := old[path]
+		if !ok || fileChanged(previous, info) {
+			return true
+		}
+	}
+	return false
+}
+
+func fileChanged(old, new os.FileInfo) bool {
+	if old == nil || new == nil {
+		return old != new
+	}
+	return !old.ModTime().Equal(new.ModTime()) || old.Size() != new.Size() || !os.SameFile(old, new)
+}
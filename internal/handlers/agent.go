@@ -4,43 +4,148 @@ import (
 	"context"
 	"crypto/rand"
 	"encoding/hex"
+	"errors"
 	"fmt"
 	"io"
 	"log"
+	"strings"
 	"sync"
+	"sync/atomic"
 	"time"
 
 	"google.golang.org/grpc"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/logging"
 	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/quota"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/schema"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
 	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
 )
 
 type AgentServer struct {
 	mcpv1.UnimplementedAgentServiceServer
 	ollamaClient  *ollama.Client
+	sessions      SessionResolver
 	activeStreams map[string]*StreamSession
 	streamsMutex  sync.RWMutex
+
+	// settings can change on a config reload and are guarded by settingsMutex
+	settings      ChatSettings
+	settingsMutex sync.RWMutex
+
+	// Sessions of unregistered tenants are rejected, and each tenant brings
+	// its allowed models and system prompt (nil accepts any tenant)
+	tenants *tenant.Registry
+	// quotas counts the requests and tokens of every tenant and rejects
+	// generations beyond its quotas (nil = unlimited)
+	quotas *quota.Tracker
+	// attachments bounds the images of user messages
+	attachments AttachmentLimits
+	// tools holds the tools the model can call (nil disables tool calling)
+	tools *tools.Registry
+	// mcpTools are the tools of the registry bridged from external MCP
+	// servers, offered only to the tenants that allow them
+	mcpTools []string
+
+	// Conversation memory, keyed by session ID. It outlives a single stream so
+	// a client can reconnect and keep talking where it left off.
+	conversations      map[string]*conversation
+	conversationsMutex sync.Mutex
+}
+
+// AgentConfig holds the dependencies and settings of an AgentServer
+type AgentConfig struct {
+	OllamaBaseURL string
+	OllamaTimeout time.Duration // non-streaming requests (0 = default)
+	Sessions      SessionResolver
+	Settings      ChatSettings
+	Tenants       *tenant.Registry // nil accepts any tenant and model
+	Quotas        *quota.Tracker   // nil disables usage accounting
+	Attachments   AttachmentLimits // zero rejects attachments
+	Tools         *tools.Registry  // nil disables tool calling
+	MCPTools      []string         // tools of the registry bridged from MCP servers
+}
+
+// ChatSettings are the chat settings that can change on a config reload
+type ChatSettings struct {
+	History HistoryConfig
+	// AllowModelOverride lets a request pick a model other than the one
+	// chosen at Register time
+	AllowModelOverride bool
+	StructuredOutput   StructuredOutputConfig
+	Tools              ToolsConfig
+	// StreamIdleTimeout closes Chat streams without messages for this long
+	// (0 = never)
+	StreamIdleTimeout time.Duration
+}
+
+// SessionResolver looks up the sessions created by HandshakeService.Register.
+// It returns ErrSessionNotFound or ErrSessionExpired for unusable sessions.
+type SessionResolver interface {
+	LookupSession(sessionID string) (*SessionInfo, error)
+}
+
+// HistoryConfig controls the conversation memory kept for every session
+type HistoryConfig struct {
+	MaxTurns     int    // exchanges kept per session (0 = unlimited)
+	MaxChars     int    // approximate size budget of the history, images included (0 = unlimited)
+	SystemPrompt string // system prompt for new sessions (without a tenant registry)
+}
+
+// conversation is the memory of one session. Turns are serialized with mu,
+// so two RPCs on the same session cannot interleave their exchanges. It is
+// forgotten once the session expires or is revoked.
+type conversation struct {
+	mu   sync.Mutex
+	chat *ollama.ChatSession
 }
 
 // StreamSession holds information about an active streaming session
 type StreamSession struct {
 	SessionID    string
+	Info         *SessionInfo // the registered session
 	Stream       grpc.BidiStreamingServer[mcpv1.ChatMessage, mcpv1.ChatMessage]
 	Model        string
 	CreatedAt    time.Time
 	LastActivity time.Time
 	Context      context.Context
-	Cancel       context.CancelFunc
+	Cancel       context.CancelCauseFunc
+	// tools are the tools the agent declared on the stream (nil for none)
+	tools *clientTools
+	// busy is set while the worker answers a message. A stream waiting for
+	// its answer is not idle, however long the model takes.
+	busy atomic.Bool
+	// gRPC streams are not safe for concurrent Send calls, and responses are
+	// generated in their own goroutine
+	sendMutex sync.Mutex
+}
+
+// Send writes a message to the client, serializing concurrent senders
+func (ss *StreamSession) Send(msg *mcpv1.ChatMessage) error {
+	ss.sendMutex.Lock()
+	defer ss.sendMutex.Unlock()
+	return ss.Stream.Send(msg)
 }
 
-func NewAgentServer(ollamaBaseURL string) *AgentServer {
+func NewAgentServer(config AgentConfig) *AgentServer {
 	server := &AgentServer{
-		ollamaClient:  ollama.NewClient(ollamaBaseURL),
+		ollamaClient:  ollama.NewClient(config.OllamaBaseURL, config.OllamaTimeout),
+		sessions:      config.Sessions,
 		activeStreams: make(map[string]*StreamSession),
+		settings:      config.Settings,
+		tenants:       config.Tenants,
+		quotas:        config.Quotas,
+		attachments:   config.Attachments,
+		tools:         config.Tools,
+		mcpTools:      config.MCPTools,
+		conversations: make(map[string]*conversation),
 	}
 
 	// Start cleanup routine for inactive streams
@@ -51,181 +156,796 @@ func NewAgentServer(ollamaBaseURL string) *AgentServer {
 
 func (s *AgentServer) SingleChat(ctx context.Context, req *mcpv1.SingleChatRequest) (*mcpv1.SingleChatResponse, error) {
 	// 1. Validar request
+	req.SessionId = requestSessionID(ctx, req.SessionId)
 	if req.SessionId == "" {
 		return nil, status.Error(codes.InvalidArgument, "session_id is required")
 	}
-	if req.Content == "" {
+	if req.Content == "" && len(req.Attachments) == 0 {
 		return nil, status.Error(codes.InvalidArgument, "content is required")
 	}
+	images, err := s.images(req.Attachments)
+	if err != nil {
+		return nil, err
+	}
+	answerSchema, err := responseSchema(req.ResponseFormat)
+	if err != nil {
+		return nil, err
+	}
 
-	// 2. Determinar modelo (default: gemma3:4b)
-	model := req.Model
-	if model == "" {
-		model = "gemma3:4b"
+	// 2. Determinar modelo (el registrado en la sesión)
+	session, model, err := s.resolveSession(ctx, req.SessionId, req.Model)
+	if err != nil {
+		return nil, err
 	}
 
-	// 3. Llamar a Ollama
-	response, err := s.ollamaClient.Generate(ctx, model, req.Content)
+	// 3. Validar las opciones de generación
+	options, err := s.requestOptions(session, req.Options)
 	if err != nil {
+		return nil, err
+	}
+
+	// 4. Verificar la cuota del tenant
+	if err := s.startRequest(session); err != nil {
+		return nil, err
+	}
+
+	// 5. Llamar a Ollama, ejecutando las herramientas que pida el modelo (y
+	// validando el JSON si se pidió un esquema)
+	response, _, err := s.toolLoop(ctx, session, ollama.ChatRequest{
+		Model:    model,
+		Messages: []ollama.Message{{Role: ollama.RoleUser, Content: req.Content, Images: images}},
+		Options:  options.Map(),
+	}, nil, func(req ollama.ChatRequest) (*ollama.ChatResponse, error) {
+		return s.complete(ctx, session, req, answerSchema)
+	}, nil)
+	if err != nil {
+		// An answer that never matched its schema fails with its own status
+		if _, ok := status.FromError(err); ok {
+			return nil, err
+		}
 		return nil, status.Errorf(codes.Internal, "failed to generate response: %v", err)
 	}
 
-	// 4. Retornar respuesta
+	// 6. Retornar respuesta con el uso de la generación
 	return &mcpv1.SingleChatResponse{
-		Content:   response,
+		Content:   response.Message.Content,
 		Timestamp: timestamppb.New(time.Now()),
+		Usage:     newUsage(response, model),
 	}, nil
 }
 
-// Chat implements bidirectional streaming chat with Gemma 3
+// Chat implements bidirectional streaming chat with Gemma 3. Messages of one
+// stream are answered in order, each one with the session history as context.
+// A SYSTEM message from the client replaces the session system prompt, and an
+// ASSISTANT message is added to the history without being answered. Tools the
+// model calls while answering are announced with TOOL_CALL and TOOL_RESULT
+// messages before the answer goes on. The first message can declare tools the
+// agent runs itself: their calls wait for the TOOL_RESULT of the agent.
 func (s *AgentServer) Chat(stream grpc.BidiStreamingServer[mcpv1.ChatMessage, mcpv1.ChatMessage]) error {
-	// Create context for this stream
-	ctx, cancel := context.WithCancel(stream.Context())
-	defer cancel()
+	// Create context for this stream. Cancelling it with a status error (see
+	// TerminateSession) ends the stream with that status.
+	ctx, cancel := context.WithCancelCause(stream.Context())
+	defer cancel(nil)
 
 	var sessionID string
-	var model string = "gemma3:4b" // default model
+	var model string
+	var session *SessionInfo
+	var streamSession *StreamSession
 
 	log.Printf("🔄 New streaming chat session started")
 
+	// Messages are processed by a single worker so the turns of the
+	// conversation keep their order without blocking the receive loop
+	queue := make(chan *mcpv1.ChatMessage, 16)
+	var worker sync.WaitGroup
+	defer func() {
+		close(queue)
+		worker.Wait()
+
+		// Cleanup when stream ends
+		if sessionID != "" {
+			s.streamsMutex.Lock()
+			if s.activeStreams[sessionID] == streamSession {
+				delete(s.activeStreams, sessionID)
+			}
+			s.streamsMutex.Unlock()
+			log.Printf("🧹 Cleaned up stream session: %s", sessionID)
+		}
+	}()
+
+	// Receive in the background, so a terminated session ends the stream even
+	// while the client is silent
+	incoming := make(chan *mcpv1.ChatMessage)
+	recvErr := make(chan error, 1)
+	go func() {
+		for {
+			msg, err := stream.Recv()
+			if err != nil {
+				recvErr <- err
+				return
+			}
+			select {
+			case incoming <- msg:
+			case <-ctx.Done():
+				return
+			}
+		}
+	}()
+
 	// Handle incoming messages
+receive:
 	for {
 		// Receive message from client
-		msg, err := stream.Recv()
-		if err == io.EOF {
-			log.Printf("✅ Client closed stream for session: %s", sessionID)
-			break
-		}
-		if err != nil {
+		var msg *mcpv1.ChatMessage
+		select {
+		case msg = <-incoming:
+		case err := <-recvErr:
+			if err == io.EOF {
+				log.Printf("✅ Client closed stream for session: %s", sessionID)
+				break receive
+			}
 			log.Printf("❌ Stream receive error: %v", err)
+			// Errors with a status, like a rate limited message, end the
+			// stream with it
+			if _, ok := status.FromError(err); ok {
+				return err
+			}
 			return status.Errorf(codes.Internal, "failed to receive message: %v", err)
+		case <-ctx.Done():
+			if _, ok := status.FromError(context.Cause(ctx)); ok {
+				return context.Cause(ctx)
+			}
+			return status.FromContextError(ctx.Err()).Err()
 		}
 
 		// Extract session info from first message
-		if sessionID == "" {
-			sessionID = msg.SessionId
-			if sessionID == "" {
+		first := sessionID == ""
+		if first {
+			msg.SessionId = requestSessionID(ctx, msg.SessionId)
+			if msg.SessionId == "" {
 				return status.Error(codes.InvalidArgument, "session_id is required in first message")
 			}
 
+			// Chat with the model registered for this session
+			var err error
+			session, model, err = s.resolveSession(ctx, msg.SessionId, "")
+			if err != nil {
+				return err
+			}
+			sessionID = msg.SessionId
+
+			// The agent declares the tools it runs on the first message
+			var agentTools *clientTools
+			if len(msg.Tools) > 0 {
+				agentTools, err = newClientTools(msg.Tools, s.tools)
+				if err != nil {
+					return err
+				}
+				log.Printf("🧰 Session %s declared %d tool(s)", sessionID, len(msg.Tools))
+			}
+
 			// Register this stream session
-			streamSession := &StreamSession{
+			streamSession = &StreamSession{
 				SessionID:    sessionID,
+				Info:         session,
 				Stream:       stream,
 				Model:        model,
 				CreatedAt:    time.Now(),
 				LastActivity: time.Now(),
 				Context:      ctx,
 				Cancel:       cancel,
+				tools:        agentTools,
 			}
 
 			s.streamsMutex.Lock()
 			s.activeStreams[sessionID] = streamSession
 			s.streamsMutex.Unlock()
 
+			worker.Add(1)
+			go func() {
+				defer worker.Done()
+				for message := range queue {
+					streamSession.busy.Store(true)
+					s.processStreamMessage(ctx, streamSession, message, model)
+					streamSession.busy.Store(false)
+
+					// The idle time counts from the end of the answer
+					s.streamsMutex.Lock()
+					streamSession.LastActivity = time.Now()
+					s.streamsMutex.Unlock()
+				}
+			}()
+
 			log.Printf("📝 Registered stream session: %s", sessionID)
 		}
 
 		// Update last activity
 		s.streamsMutex.Lock()
-		if session, exists := s.activeStreams[sessionID]; exists {
-			session.LastActivity = time.Now()
-		}
+		streamSession.LastActivity = time.Now()
 		s.streamsMutex.Unlock()
 
-		// Validate message
-		if msg.Content == "" {
+		// Validate message: a stream is bound to the session it started with
+		sameSession := msg.SessionId == "" || msg.SessionId == sessionID
+		isResult := msg.Type == mcpv1.MessageType_MESSAGE_TYPE_TOOL_RESULT
+
+		// Results of the tools of the agent go straight to the call waiting
+		// for them: the worker is busy with the answer that made the call
+		if isResult && sameSession && msg.ToolResult != nil && streamSession.tools.deliver(msg.ToolResult) {
+			logging.Debugf("📎 Session %s returned the result of tool call %s", sessionID, msg.ToolResult.Id)
+			continue
+		}
+
+		var invalid string
+		switch {
+		case !sameSession:
+			invalid = "Error: session_id cannot change within a stream"
+		case isResult && msg.ToolResult == nil:
+			invalid = "Error: tool_result is required on TOOL_RESULT messages"
+		case isResult:
+			invalid = fmt.Sprintf("Error: no tool call %s is waiting for a result", msg.ToolResult.Id)
+		case msg.Type == mcpv1.MessageType_MESSAGE_TYPE_TOOL_CALL:
+			invalid = "Error: tool calls are only sent by the server"
+		case len(msg.Tools) > 0 && !first:
+			invalid = "Error: tools can only be declared in the first message of a stream"
+		case len(msg.Tools) > 0 && msg.Content == "" && len(msg.Attachments) == 0:
+			// The first message only declares the tools of the agent
+			continue
+		case msg.Content == "" && len(msg.Attachments) == 0:
+			invalid = "Error: message content cannot be empty"
+		}
+		if invalid != "" {
 			// Send error message back to client
 			errorMsg := &mcpv1.ChatMessage{
 				MessageId: generateMessageID(),
 				SessionId: sessionID,
-				Content:   "Error: message content cannot be empty",
+				Content:   invalid,
 				Type:      mcpv1.MessageType_MESSAGE_TYPE_SYSTEM,
 				Timestamp: timestamppb.New(time.Now()),
 			}
-			if err := stream.Send(errorMsg); err != nil {
+			if err := streamSession.Send(errorMsg); err != nil {
 				log.Printf("❌ Failed to send error message: %v", err)
 			}
 			continue
 		}
+		msg.SessionId = sessionID
 
-		log.Printf("💬 Received message from session %s: %s", sessionID, msg.Content[:min(50, len(msg.Content))]+"...")
+		logging.Debugf("💬 Received message from session %s: %s", sessionID, preview(msg.Content, 50))
 
-		// Process message asynchronously to not block receiving
-		go func(message *mcpv1.ChatMessage) {
-			s.processStreamMessage(ctx, stream, message, model)
-		}(msg)
+		select {
+		case queue <- msg:
+		case <-ctx.Done():
+			return status.FromContextError(ctx.Err()).Err()
+		}
 	}
 
-	// Cleanup when stream ends
-	s.streamsMutex.Lock()
-	delete(s.activeStreams, sessionID)
-	s.streamsMutex.Unlock()
+	return nil
+}
+
+// GenerateStream implements server-streaming chat for clients without
+// bidirectional streams, such as browsers going through gRPC-Web. It is
+// SingleChat with the answer streamed: every request stands alone, without
+// the conversation memory of Chat.
+func (s *AgentServer) GenerateStream(req *mcpv1.SingleChatRequest, stream grpc.ServerStreamingServer[mcpv1.ChatMessage]) error {
+	// 1. Validate request
+	req.SessionId = requestSessionID(stream.Context(), req.SessionId)
+	if req.SessionId == "" {
+		return status.Error(codes.InvalidArgument, "session_id is required")
+	}
+	if req.Content == "" && len(req.Attachments) == 0 {
+		return status.Error(codes.InvalidArgument, "content is required")
+	}
+	images, err := s.images(req.Attachments)
+	if err != nil {
+		return err
+	}
+	answerSchema, err := responseSchema(req.ResponseFormat)
+	if err != nil {
+		return err
+	}
+
+	// 2. Resolve model (the one registered for the session)
+	session, model, err := s.resolveSession(stream.Context(), req.SessionId, req.Model)
+	if err != nil {
+		return err
+	}
+
+	// 3. Validate the generation options
+	options, err := s.requestOptions(session, req.Options)
+	if err != nil {
+		return err
+	}
+
+	// 4. Check the quota of the tenant
+	if err := s.startRequest(session); err != nil {
+		return err
+	}
+
+	// 5. Stream the generation straight to the caller
+	_, _, err = s.streamGeneration(stream.Context(), session, ollama.ChatRequest{
+		Model:    model,
+		Messages: []ollama.Message{{Role: ollama.RoleUser, Content: req.Content, Images: images}},
+		Options:  options.Map(),
+	}, answerSchema, nil, stream.Send)
+	if err != nil {
+		if ctxErr := stream.Context().Err(); ctxErr != nil {
+			return status.FromContextError(ctxErr).Err()
+		}
+		if _, ok := status.FromError(err); ok {
+			return err
+		}
+		log.Printf("❌ Ollama error for session %s: %v", req.SessionId, err)
+		return status.Errorf(codes.Internal, "failed to generate response: %v", err)
+	}
 
-	log.Printf("🧹 Cleaned up stream session: %s", sessionID)
 	return nil
 }
 
-// processStreamMessage handles individual message processing
-func (s *AgentServer) processStreamMessage(ctx context.Context, stream grpc.BidiStreamingServer[mcpv1.ChatMessage, mcpv1.ChatMessage], msg *mcpv1.ChatMessage, model string) {
-	// Generate response from Ollama
-	response, err := s.ollamaClient.Generate(ctx, model, msg.Content)
+// processStreamMessage handles individual message processing. User messages
+// are answered; system and assistant messages only shape the conversation.
+func (s *AgentServer) processStreamMessage(ctx context.Context, session *StreamSession, msg *mcpv1.ChatMessage, model string) {
+	switch messageRole(msg.Type) {
+	case ollama.RoleSystem:
+		// A system message replaces the system prompt of the session
+		conv := s.conversation(session.Info, model)
+		conv.mu.Lock()
+		conv.chat.System = msg.Content
+		conv.mu.Unlock()
+		log.Printf("📝 Updated system prompt for session %s", msg.SessionId)
+		return
+	case ollama.RoleAssistant:
+		// An assistant message goes into the history as if the model had
+		// said it, e.g. to restore a conversation kept by the client
+		conv := s.conversation(session.Info, model)
+		conv.mu.Lock()
+		conv.chat.Append(ollama.Message{Role: ollama.RoleAssistant, Content: msg.Content})
+		conv.mu.Unlock()
+		return
+	}
+
+	err := s.answer(ctx, session, msg, model)
+	if status.Code(err) == codes.ResourceExhausted {
+		// Out of quota: end the stream with the status, so the client gets
+		// the details saying when to come back
+		session.Cancel(err)
+		return
+	}
 	if err != nil {
-		log.Printf("❌ Ollama error for session %s: %v", msg.SessionId, err)
+		content := fmt.Sprintf("Error generating response: %v", err)
+		if code := status.Code(err); code == codes.InvalidArgument || code == codes.FailedPrecondition {
+			// Bad options or attachments, or an answer that never matched its
+			// schema, fail this message only, like other invalid messages
+			content = "Error: " + status.Convert(err).Message()
+		} else {
+			log.Printf("❌ Ollama error for session %s: %v", msg.SessionId, err)
+		}
 
 		// Send error response
 		errorMsg := &mcpv1.ChatMessage{
 			MessageId: generateMessageID(),
 			SessionId: msg.SessionId,
-			Content:   fmt.Sprintf("Error generating response: %v", err),
+			Content:   content,
 			Type:      mcpv1.MessageType_MESSAGE_TYPE_SYSTEM,
 			Timestamp: timestamppb.New(time.Now()),
+			Done:      true,
 		}
 
-		if err := stream.Send(errorMsg); err != nil {
+		if err := session.Send(errorMsg); err != nil {
 			log.Printf("❌ Failed to send error response: %v", err)
 		}
-		return
+	}
+}
+
+// answer validates the options, attachments and response format of a user
+// message of a Chat stream and answers it. Options of the message override those of the session
+// for this answer.
+func (s *AgentServer) answer(ctx context.Context, session *StreamSession, msg *mcpv1.ChatMessage, model string) error {
+	options, err := s.requestOptions(session.Info, msg.Options)
+	if err != nil {
+		return err
+	}
+	images, err := s.images(msg.Attachments)
+	if err != nil {
+		return err
+	}
+	answerSchema, err := responseSchema(msg.ResponseFormat)
+	if err != nil {
+		return err
+	}
+
+	message := ollama.Message{Role: ollama.RoleUser, Content: msg.Content, Images: images}
+	return s.converse(ctx, session.Info, model, message, options, answerSchema, session.tools, session.Send)
+}
+
+// converse runs one conversation turn: it sends the session history along with
+// the user message, streams the answer and the tool calls leading to it through
+// send and records the exchange.
+// answerSchema, when set, asks for a JSON answer matching it, and agentTools
+// are the tools the agent declared on its stream (nil for none).
+func (s *AgentServer) converse(ctx context.Context, session *SessionInfo, model string, message ollama.Message, options *ollama.Options, answerSchema *schema.Schema, agentTools *clientTools, send func(*mcpv1.ChatMessage) error) error {
+	if err := s.startRequest(session); err != nil {
+		return err
+	}
+
+	conv := s.conversation(session, model)
+	conv.mu.Lock()
+	defer conv.mu.Unlock()
+
+	final, toolMessages, err := s.streamGeneration(ctx, session, ollama.ChatRequest{
+		Model:    model,
+		Messages: conv.chat.Messages(message),
+		Options:  options.Map(),
+	}, answerSchema, agentTools, send)
+	if err != nil {
+		return err
+	}
+
+	conv.chat.Record(message, toolMessages, final.Message.Content)
+	return nil
+}
+
+// requestOptions returns the generation options of a request: the session
+// options overridden by the requested ones, within the caps of the tenant
+func (s *AgentServer) requestOptions(session *SessionInfo, requested *mcpv1.GenerationOptions) (*ollama.Options, error) {
+	var settings *tenant.Tenant
+	if s.tenants != nil {
+		settings, _ = s.tenants.Get(session.TenantID)
+	}
+	override, err := generationOptions(requested, settings)
+	if err != nil {
+		return nil, err
+	}
+	return capOptions(sessionOptions(session).Merge(override), settings), nil
+}
+
+// startRequest counts a generation against the quotas of the session tenant,
+// rejecting it when they are exhausted
+func (s *AgentServer) startRequest(session *SessionInfo) error {
+	if s.quotas == nil {
+		return nil
+	}
+	return s.quotas.StartRequest(session.TenantID)
+}
+
+// recordTokens counts the tokens of a finished generation
+func (s *AgentServer) recordTokens(session *SessionInfo, response *ollama.ChatResponse) {
+	if s.quotas != nil {
+		s.quotas.AddTokens(session.TenantID, response.PromptEvalCount, response.EvalCount)
+	}
+}
+
+// GetUsage reports the usage of the caller's tenant in the current quota
+// window and what remains of each quota
+func (s *AgentServer) GetUsage(ctx context.Context, req *mcpv1.GetUsageRequest) (*mcpv1.GetUsageResponse, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization required")
+	}
+	if s.quotas == nil {
+		return nil, status.Error(codes.Unimplemented, "usage accounting is disabled")
+	}
+
+	report, err := s.quotas.Usage(principal.TenantID)
+	if errors.Is(err, tenant.ErrUnknownTenant) {
+		return nil, status.Errorf(codes.PermissionDenied, "tenant %s is not registered", principal.TenantID)
+	}
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to read usage: %v", err)
+	}
+
+	usage := report.Usage
+	return &mcpv1.GetUsageResponse{
+		TenantId:         principal.TenantID,
+		WindowStart:      timestamppb.New(usage.WindowStart),
+		WindowEnd:        timestamppb.New(usage.WindowEnd),
+		Sessions:         newQuota(int64(report.ActiveSessions), tenant.Value(report.Quotas.MaxSessions)),
+		Requests:         newQuota(usage.Requests, tenant.Value(report.Quotas.MaxRequestsPerDay)),
+		Tokens:           newQuota(usage.Tokens(), tenant.Value(report.Quotas.MaxTokensPerDay)),
+		PromptTokens:     usage.PromptTokens,
+		CompletionTokens: usage.CompletionTokens,
+	}, nil
+}
+
+// newQuota describes the use of one quota; a 0 limit is unlimited
+func newQuota(used int64, limit int) *mcpv1.Quota {
+	remaining := int64(-1)
+	if limit > 0 {
+		remaining = max(int64(limit)-used, 0)
+	}
+	return &mcpv1.Quota{
+		Used:      used,
+		Limit:     int64(limit),
+		Remaining: remaining,
+	}
+}
+
+// newUsage describes the generation of response. Ollama names the model
+// that answered; model is the one requested, in case it does not.
+func newUsage(response *ollama.ChatResponse, model string) *mcpv1.Usage {
+	if response.Model != "" {
+		model = response.Model
+	}
+	return &mcpv1.Usage{
+		Model:              model,
+		PromptTokens:       int32(response.PromptEvalCount),
+		CompletionTokens:   int32(response.EvalCount),
+		TotalDuration:      durationpb.New(time.Duration(response.TotalDuration)),
+		LoadDuration:       durationpb.New(time.Duration(response.LoadDuration)),
+		PromptEvalDuration: durationpb.New(time.Duration(response.PromptEvalDuration)),
+		EvalDuration:       durationpb.New(time.Duration(response.EvalDuration)),
+		TokensPerSecond:    response.TokensPerSecond(),
+		DoneReason:         response.DoneReason,
+	}
+}
+
+// messageRole maps the type of a client message onto its chat role. Messages
+// without a type are user messages.
+func messageRole(messageType mcpv1.MessageType) string {
+	switch messageType {
+	case mcpv1.MessageType_MESSAGE_TYPE_SYSTEM:
+		return ollama.RoleSystem
+	case mcpv1.MessageType_MESSAGE_TYPE_ASSISTANT:
+		return ollama.RoleAssistant
+	default:
+		return ollama.RoleUser
+	}
+}
+
+// requestSessionID returns the session a request is for. Requests may omit
+// it: it defaults to the session bound to the caller's credentials, the only
+// one they can use anyway (and the only one a client certificate has).
+func requestSessionID(ctx context.Context, sessionID string) string {
+	if sessionID == "" {
+		if principal, ok := auth.FromContext(ctx); ok {
+			return principal.SessionID
+		}
+	}
+	return sessionID
+}
+
+// resolveSession checks that sessionID belongs to a live registered session
+// of a registered tenant and picks the model to use: the one chosen at
+// Register time, unless the request asks for another one and overrides are
+// allowed. Either way the tenant must be allowed to use it.
+func (s *AgentServer) resolveSession(ctx context.Context, sessionID, requestedModel string) (*SessionInfo, string, error) {
+	// The caller's token is bound to one session: it cannot chat on others
+	principal, authenticated := auth.FromContext(ctx)
+	if authenticated && principal.SessionID != sessionID {
+		return nil, "", status.Errorf(codes.PermissionDenied, "token is not valid for session %s", sessionID)
+	}
+
+	session, err := s.sessions.LookupSession(sessionID)
+	switch {
+	case errors.Is(err, ErrSessionNotFound) && authenticated:
+		// Registered on another replica: the signed token carries the session
+		session = &SessionInfo{
+			SessionID: principal.SessionID,
+			TenantID:  principal.TenantID,
+			AgentID:   principal.AgentID,
+			Model:     principal.Model,
+			ExpiresAt: principal.ExpiresAt,
+		}
+	case errors.Is(err, ErrSessionNotFound):
+		return nil, "", status.Errorf(codes.NotFound, "session %s not found", sessionID)
+	case errors.Is(err, ErrSessionExpired):
+		return nil, "", status.Errorf(codes.Unauthenticated, "session %s has expired", sessionID)
+	case err != nil:
+		return nil, "", status.Errorf(codes.Internal, "failed to look up session: %v", err)
+	}
+
+	// The tenant may have been removed since the session was registered
+	var settings *tenant.Tenant
+	if s.tenants != nil {
+		settings, err = s.tenants.Get(session.TenantID)
+		if err != nil {
+			return nil, "", status.Errorf(codes.PermissionDenied, "tenant %s is not registered", session.TenantID)
+		}
+	}
+
+	// Certificate identities without a model get the tenant default
+	model := session.Model
+	if model == "" && settings != nil {
+		model = settings.DefaultModel
+	}
+
+	if requestedModel != "" && requestedModel != model {
+		s.settingsMutex.RLock()
+		allowModelOverride := s.settings.AllowModelOverride
+		s.settingsMutex.RUnlock()
+		if !allowModelOverride {
+			return nil, "", status.Errorf(codes.PermissionDenied, "session %s is registered for model %s", sessionID, model)
+		}
+		model = requestedModel
 	}
 
-	// Send response back to client
-	responseMsg := &mcpv1.ChatMessage{
-		MessageId: generateMessageID(),
-		SessionId: msg.SessionId,
-		Content:   response,
+	// The tenant may also have lost access to the model
+	if settings != nil && !settings.AllowsModel(model) {
+		return nil, "", status.Errorf(codes.PermissionDenied, "model %s is not allowed for tenant %s", model, session.TenantID)
+	}
+
+	return session, model, nil
+}
+
+// UpdateSettings applies reloaded chat settings. Conversations already in
+// memory keep the limits and system prompt they started with.
+func (s *AgentServer) UpdateSettings(settings ChatSettings) {
+	s.settingsMutex.Lock()
+	defer s.settingsMutex.Unlock()
+	s.settings = settings
+}
+
+// conversation returns the memory of a session, creating it on first use
+// with the system prompt of its tenant
+func (s *AgentServer) conversation(session *SessionInfo, model string) *conversation {
+	s.conversationsMutex.Lock()
+	defer s.conversationsMutex.Unlock()
+
+	conv, exists := s.conversations[session.SessionID]
+	if !exists {
+		s.settingsMutex.RLock()
+		history := s.settings.History
+		s.settingsMutex.RUnlock()
+
+		systemPrompt := history.SystemPrompt
+		if s.tenants != nil {
+			if settings, err := s.tenants.Get(session.TenantID); err == nil {
+				systemPrompt = settings.SystemPrompt
+			}
+		}
+
+		chat := ollama.NewChatSession(model, systemPrompt)
+		chat.MaxTurns = history.MaxTurns
+		chat.MaxChars = history.MaxChars
+		conv = &conversation{chat: chat}
+		s.conversations[session.SessionID] = conv
+	}
+
+	return conv
+}
+
+// streamGeneration runs a streaming Ollama chat, counts its tokens and
+// forwards them through send as they arrive. Every chunk of one answer shares
+// the same message_id and the last one is an empty message flagged as done.
+// Tool calls of the model are run before the done message, and announced
+// through send as they happen; agentTools are the tools of the agent at the
+// other end of send (nil for none). JSON answers for answerSchema are validated
+// whole first, so they arrive in a single chunk. The returned final response
+// holds the whole answer in Message, and the messages the tool calls that
+// led to it.
+func (s *AgentServer) streamGeneration(ctx context.Context, session *SessionInfo, req ollama.ChatRequest, answerSchema *schema.Schema, agentTools *clientTools, send func(*mcpv1.ChatMessage) error) (*ollama.ChatResponse, []ollama.Message, error) {
+	sessionID := session.SessionID
+	messageID := generateMessageID()
+	sendChunk := func(content string) error {
+		return send(&mcpv1.ChatMessage{
+			MessageId: messageID,
+			SessionId: sessionID,
+			Content:   content,
+			Type:      mcpv1.MessageType_MESSAGE_TYPE_ASSISTANT,
+			Timestamp: timestamppb.New(time.Now()),
+		})
+	}
+
+	// generate runs one round of the tool loop
+	generate := func(req ollama.ChatRequest) (*ollama.ChatResponse, error) {
+		if answerSchema != nil {
+			response, err := s.complete(ctx, session, req, answerSchema)
+			if err == nil && response.Message.Content != "" {
+				err = sendChunk(response.Message.Content)
+			}
+			return response, err
+		}
+
+		var answer strings.Builder
+		var toolCalls []ollama.ToolCall
+		final, err := s.ollamaClient.ChatStream(ctx, req, func(chunk *ollama.ChatResponse) error {
+			toolCalls = append(toolCalls, chunk.Message.ToolCalls...)
+			if chunk.Message.Content == "" {
+				return nil
+			}
+			answer.WriteString(chunk.Message.Content)
+			return sendChunk(chunk.Message.Content)
+		})
+		if err != nil {
+			return nil, err
+		}
+		s.recordTokens(session, final)
+		final.Message = ollama.Message{Role: ollama.RoleAssistant, Content: answer.String(), ToolCalls: toolCalls}
+		return final, nil
+	}
+
+	final, toolMessages, err := s.toolLoop(ctx, session, req, agentTools, generate, send)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Close the answer with the done marker, which carries the usage
+	doneMsg := &mcpv1.ChatMessage{
+		MessageId: messageID,
+		SessionId: sessionID,
 		Type:      mcpv1.MessageType_MESSAGE_TYPE_ASSISTANT,
 		Timestamp: timestamppb.New(time.Now()),
+		Done:      true,
+		Usage:     newUsage(final, req.Model),
 	}
-
-	if err := stream.Send(responseMsg); err != nil {
-		log.Printf("❌ Failed to send response for session %s: %v", msg.SessionId, err)
-		return
+	if err := send(doneMsg); err != nil {
+		log.Printf("❌ Failed to send response for session %s: %v", sessionID, err)
+		return nil, nil, err
 	}
 
-	log.Printf("✅ Sent response to session %s: %s", msg.SessionId, response[:min(50, len(response))]+"...")
+	logging.Debugf("✅ Streamed response to session %s: %d bytes, %d tokens", sessionID, len(final.Message.Content), final.EvalCount)
+	return final, toolMessages, nil
 }
 
-// cleanupInactiveStreams removes streams that haven't been active for a while
+// cleanupInactiveStreams closes idle streams and forgets the conversations
+// of sessions that expired
 func (s *AgentServer) cleanupInactiveStreams() {
 	ticker := time.NewTicker(30 * time.Second) // Check every 30 seconds
 	defer ticker.Stop()
 
 	for range ticker.C {
-		now := time.Now()
-		inactiveThreshold := 5 * time.Minute // 5 minutes of inactivity
+		s.settingsMutex.RLock()
+		inactiveThreshold := s.settings.StreamIdleTimeout
+		s.settingsMutex.RUnlock()
 
-		s.streamsMutex.Lock()
-		for sessionID, session := range s.activeStreams {
-			if now.Sub(session.LastActivity) > inactiveThreshold {
-				log.Printf("🧹 Cleaning up inactive stream session: %s", sessionID)
-				session.Cancel() // Cancel the context
-				delete(s.activeStreams, sessionID)
-			}
+		if inactiveThreshold > 0 {
+			s.closeIdleStreams(time.Now(), inactiveThreshold)
 		}
-		s.streamsMutex.Unlock()
+		s.cleanupExpiredConversations()
+	}
+}
+
+// cleanupExpiredConversations forgets the conversations of sessions that
+// expired or no longer exist. Conversations live as long as their session,
+// however long the client stays away between streams.
+func (s *AgentServer) cleanupExpiredConversations() {
+	s.conversationsMutex.Lock()
+	sessionIDs := make([]string, 0, len(s.conversations))
+	for sessionID := range s.conversations {
+		sessionIDs = append(sessionIDs, sessionID)
+	}
+	s.conversationsMutex.Unlock()
+
+	for _, sessionID := range sessionIDs {
+		_, err := s.sessions.LookupSession(sessionID)
+		if !errors.Is(err, ErrSessionNotFound) && !errors.Is(err, ErrSessionExpired) {
+			continue
+		}
+		s.conversationsMutex.Lock()
+		delete(s.conversations, sessionID)
+		s.conversationsMutex.Unlock()
+	}
+}
+
+// closeIdleStreams closes the streams without activity for longer than
+// threshold. Streams whose worker is still answering are left open.
+func (s *AgentServer) closeIdleStreams(now time.Time, threshold time.Duration) {
+	s.streamsMutex.Lock()
+	defer s.streamsMutex.Unlock()
+	for sessionID, session := range s.activeStreams {
+		if session.busy.Load() || now.Sub(session.LastActivity) <= threshold {
+			continue
+		}
+		log.Printf("🧹 Cleaning up inactive stream session: %s", sessionID)
+		session.Cancel(status.Error(codes.DeadlineExceeded, "stream closed after inactivity")) // Cancel the context
+		delete(s.activeStreams, sessionID)
 	}
 }
 
+// TerminateSession ends the active chat stream of a session and forgets its
+// conversation, e.g. after the session was revoked
+func (s *AgentServer) TerminateSession(sessionID string) {
+	s.streamsMutex.Lock()
+	if session, exists := s.activeStreams[sessionID]; exists {
+		session.Cancel(status.Error(codes.Unauthenticated, "session has been revoked"))
+		delete(s.activeStreams, sessionID)
+		log.Printf("🔒 Terminated stream session: %s", sessionID)
+	}
+	s.streamsMutex.Unlock()
+
+	s.conversationsMutex.Lock()
+	delete(s.conversations, sessionID)
+	s.conversationsMutex.Unlock()
+}
+
 // GetActiveStreamsCount returns the number of active streaming sessions
 func (s *AgentServer) GetActiveStreamsCount() int {
 	s.streamsMutex.RLock()
@@ -240,10 +960,11 @@ func generateMessageID() string {
 	return hex.EncodeToString(bytes)
 }
 
-// min returns the minimum of two integers
-func min(a, b int) int {
-	if a < b {
-		return a
+// preview shortens text to at most n runes for the logs
+func preview(text string, n int) string {
+	runes := []rune(text)
+	if len(runes) <= n {
+		return text
 	}
-	return b
+	return string(runes[:n]) + "..."
 }
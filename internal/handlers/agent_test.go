@@ -0,0 +1,282 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/quota"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// stubSessions resolves every session ID to a live session of one tenant
+type stubSessions struct{}
+
+func (stubSessions) LookupSession(sessionID string) (*SessionInfo, error) {
+	return &SessionInfo{
+		SessionID: sessionID,
+		TenantID:  "acme",
+		AgentID:   "agent-1",
+		Model:     "gemma3:4b",
+		ExpiresAt: time.Now().Add(time.Hour),
+	}, nil
+}
+
+// sessionLookup resolves sessions with a function
+type sessionLookup func(sessionID string) (*SessionInfo, error)
+
+func (f sessionLookup) LookupSession(sessionID string) (*SessionInfo, error) { return f(sessionID) }
+
+// stubOllama answers /api/chat with the given answers in order and keeps
+// the requests it received
+type stubOllama struct {
+	*httptest.Server
+
+	mutex    sync.Mutex
+	answers  []string
+	requests []ollama.ChatRequest
+}
+
+func newStubOllama(t *testing.T, answers ...string) *stubOllama {
+	stub := &stubOllama{answers: answers}
+	stub.Server = httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != "/api/chat" {
+			http.NotFound(w, r)
+			return
+		}
+		var req ollama.ChatRequest
+		if err := json.NewDecoder(r.Body).Decode(&req); err != nil {
+			http.Error(w, err.Error(), http.StatusBadRequest)
+			return
+		}
+
+		stub.mutex.Lock()
+		defer stub.mutex.Unlock()
+		if len(stub.requests) >= len(stub.answers) {
+			http.Error(w, "no more answers", http.StatusInternalServerError)
+			return
+		}
+		answer := stub.answers[len(stub.requests)]
+		stub.requests = append(stub.requests, req)
+
+		json.NewEncoder(w).Encode(ollama.ChatResponse{
+			Model:   req.Model,
+			Message: ollama.Message{Role: ollama.RoleAssistant, Content: answer},
+			Done:    true,
+			Metrics: ollama.Metrics{PromptEvalCount: 10, EvalCount: 5},
+		})
+	}))
+	t.Cleanup(stub.Close)
+	return stub
+}
+
+func (s *stubOllama) received() []ollama.ChatRequest {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+	return s.requests
+}
+
+// recordingStream is the server side of a GenerateStream call that keeps
+// the messages sent to the client
+type recordingStream struct {
+	grpc.ServerStream
+	ctx  context.Context
+	sent []*mcpv1.ChatMessage
+}
+
+func (r *recordingStream) Context() context.Context { return r.ctx }
+
+func (r *recordingStream) Send(msg *mcpv1.ChatMessage) error {
+	r.sent = append(r.sent, msg)
+	return nil
+}
+
+func TestGenerateStreamIsStateless(t *testing.T) {
+	stub := newStubOllama(t, "Hello!", "Hello again!")
+	server := NewAgentServer(AgentConfig{OllamaBaseURL: stub.URL, Sessions: stubSessions{}})
+
+	for _, content := range []string{"Hi", "Do you remember me?"} {
+		stream := &recordingStream{ctx: context.Background()}
+		if err := server.GenerateStream(&mcpv1.SingleChatRequest{SessionId: "session-1", Content: content}, stream); err != nil {
+			t.Fatalf("GenerateStream: %v", err)
+		}
+
+		if len(stream.sent) != 2 {
+			t.Fatalf("sent %d messages, want an answer chunk and the done message", len(stream.sent))
+		}
+		chunk, done := stream.sent[0], stream.sent[1]
+		if chunk.Content == "" || chunk.Done || chunk.MessageId != done.MessageId {
+			t.Errorf("answer chunk = %+v", chunk)
+		}
+		if !done.Done || done.Usage == nil || done.Usage.CompletionTokens != 5 {
+			t.Errorf("done message = %+v, want done with the usage", done)
+		}
+	}
+
+	// Like SingleChat, each request is sent alone and nothing is remembered
+	requests := stub.received()
+	if len(requests) != 2 {
+		t.Fatalf("Ollama was called %d times, want 2", len(requests))
+	}
+	for i, req := range requests {
+		if len(req.Messages) != 1 || req.Messages[0].Role != ollama.RoleUser {
+			t.Errorf("request %d sent %d messages, want only the user message", i, len(req.Messages))
+		}
+	}
+	if got := len(server.conversations); got != 0 {
+		t.Errorf("GenerateStream kept %d conversations", got)
+	}
+}
+
+func TestGenerateStreamCountsAgainstTheQuota(t *testing.T) {
+	stub := newStubOllama(t, "Hello!", "Hello again!")
+	limited := 1
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", Quotas: tenant.Quotas{MaxRequestsPerDay: &limited}}})
+	tracker := quota.NewTracker(store.NewMemoryStore(), tenants)
+	server := NewAgentServer(AgentConfig{OllamaBaseURL: stub.URL, Sessions: stubSessions{}, Tenants: tenants, Quotas: tracker})
+
+	req := &mcpv1.SingleChatRequest{SessionId: "session-1", Content: "Hi"}
+	if err := server.GenerateStream(req, &recordingStream{ctx: context.Background()}); err != nil {
+		t.Fatalf("GenerateStream: %v", err)
+	}
+	report, err := tracker.Usage("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Usage.Requests != 1 || report.Usage.PromptTokens != 10 || report.Usage.CompletionTokens != 5 {
+		t.Errorf("usage = %+v, want the request and its tokens", report.Usage)
+	}
+
+	err = server.GenerateStream(req, &recordingStream{ctx: context.Background()})
+	if status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("GenerateStream over the quota = %v, want ResourceExhausted", err)
+	}
+	if len(stub.received()) != 1 {
+		t.Error("a request over the quota reached Ollama")
+	}
+}
+
+func TestCloseIdleStreams(t *testing.T) {
+	now := time.Now()
+	tests := []struct {
+		name         string
+		lastActivity time.Time
+		busy         bool
+		closed       bool
+	}{
+		{"idle", now.Add(-10 * time.Minute), false, true},
+		{"still answering", now.Add(-10 * time.Minute), true, false},
+		{"recently active", now.Add(-time.Minute), false, false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewAgentServer(AgentConfig{Sessions: stubSessions{}})
+			ctx, cancel := context.WithCancelCause(context.Background())
+			defer cancel(nil)
+			session := &StreamSession{SessionID: "session-1", LastActivity: tt.lastActivity, Context: ctx, Cancel: cancel}
+			session.busy.Store(tt.busy)
+			server.activeStreams[session.SessionID] = session
+
+			server.closeIdleStreams(now, 5*time.Minute)
+
+			_, open := server.activeStreams[session.SessionID]
+			if closed := ctx.Err() != nil; closed != tt.closed || open == tt.closed {
+				t.Errorf("closed = %v, still registered = %v, want closed = %v", closed, open, tt.closed)
+			}
+		})
+	}
+}
+
+func TestConversationsLiveAsLongAsTheirSession(t *testing.T) {
+	server := NewAgentServer(AgentConfig{Sessions: sessionLookup(func(sessionID string) (*SessionInfo, error) {
+		switch sessionID {
+		case "expired":
+			return nil, ErrSessionExpired
+		case "unknown":
+			return nil, ErrSessionNotFound
+		}
+		return &SessionInfo{SessionID: sessionID, ExpiresAt: time.Now().Add(time.Hour)}, nil
+	})})
+	for _, sessionID := range []string{"live", "expired", "unknown"} {
+		server.conversation(&SessionInfo{SessionID: sessionID}, "gemma3:4b")
+	}
+
+	server.cleanupExpiredConversations()
+
+	if _, kept := server.conversations["live"]; !kept || len(server.conversations) != 1 {
+		t.Errorf("conversations = %v, want only that of the live session", server.conversations)
+	}
+}
+
+func TestPreviewKeepsRunesWhole(t *testing.T) {
+	tests := []struct {
+		text string
+		want string
+	}{
+		{"short", "short"},
+		{strings.Repeat("a", 60), strings.Repeat("a", 50) + "..."},
+		{strings.Repeat("ñ", 60), strings.Repeat("ñ", 50) + "..."},
+		{strings.Repeat("🙂", 50), strings.Repeat("🙂", 50)},
+	}
+	for _, tt := range tests {
+		if got := preview(tt.text, 50); got != tt.want {
+			t.Errorf("preview(%q) = %q, want %q", tt.text, got, tt.want)
+		}
+	}
+}
+
+func TestGetUsageReportsRemainingQuota(t *testing.T) {
+	sessions := store.NewMemoryStore()
+	limited := 10
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", Quotas: tenant.Quotas{MaxRequestsPerDay: &limited}}})
+	tracker := quota.NewTracker(sessions, tenants)
+	server := NewAgentServer(AgentConfig{Sessions: stubSessions{}, Tenants: tenants, Quotas: tracker})
+
+	if _, err := server.GetUsage(context.Background(), &mcpv1.GetUsageRequest{}); status.Code(err) != codes.Unauthenticated {
+		t.Errorf("GetUsage without a principal = %v, want Unauthenticated", err)
+	}
+
+	for i := 0; i < 3; i++ {
+		if err := tracker.StartRequest("acme"); err != nil {
+			t.Fatal(err)
+		}
+	}
+	tracker.AddTokens("acme", 20, 30)
+
+	ctx := auth.NewContext(context.Background(), &auth.Principal{TenantID: "acme", AgentID: "agent-1"})
+	usage, err := server.GetUsage(ctx, &mcpv1.GetUsageRequest{})
+	if err != nil {
+		t.Fatalf("GetUsage: %v", err)
+	}
+	tests := []struct {
+		name  string
+		quota *mcpv1.Quota
+		want  *mcpv1.Quota
+	}{
+		{"requests", usage.Requests, &mcpv1.Quota{Used: 3, Limit: 10, Remaining: 7}},
+		{"tokens", usage.Tokens, &mcpv1.Quota{Used: 50, Limit: 0, Remaining: -1}},
+		{"sessions", usage.Sessions, &mcpv1.Quota{Used: 0, Limit: 0, Remaining: -1}},
+	}
+	for _, tt := range tests {
+		if !proto.Equal(tt.quota, tt.want) {
+			t.Errorf("%s = %v, want %v", tt.name, tt.quota, tt.want)
+		}
+	}
+	if usage.PromptTokens != 20 || usage.CompletionTokens != 30 {
+		t.Errorf("tokens = %d+%d, want 20+30", usage.PromptTokens, usage.CompletionTokens)
+	}
+}
@@ -0,0 +1,80 @@
+package handlers
+
+import (
+	"encoding/base64"
+	"fmt"
+	"mime"
+	"net/http"
+	"slices"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// AttachmentLimits bound the images sent along a message
+type AttachmentLimits struct {
+	MaxCount     int      // per message (0 rejects attachments)
+	MaxSize      int      // bytes per attachment
+	AllowedTypes []string // MIME types, e.g. "image/png"
+}
+
+// images validates the attachments of a message and encodes them for the
+// images field of Ollama. The type of every attachment is detected from its
+// data: a declared mime_type must agree with it.
+func (s *AgentServer) images(attachments []*mcpv1.Attachment) ([]string, error) {
+	if len(attachments) == 0 {
+		return nil, nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	violate := func(field, format string, args ...interface{}) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       field,
+			Description: fmt.Sprintf(format, args...),
+		})
+	}
+
+	limits := s.attachments
+	if len(attachments) > limits.MaxCount {
+		if limits.MaxCount == 0 {
+			violate("attachments", "attachments are disabled")
+		} else {
+			violate("attachments", "at most %d attachments are allowed", limits.MaxCount)
+		}
+		return nil, badRequest("attachments", violations)
+	}
+
+	images := make([]string, 0, len(attachments))
+	for i, attachment := range attachments {
+		field := fmt.Sprintf("attachments[%d]", i)
+		if len(attachment.Data) == 0 {
+			violate(field+".data", "must not be empty")
+			continue
+		}
+		if len(attachment.Data) > limits.MaxSize {
+			violate(field+".data", "%d bytes exceed the limit of %d", len(attachment.Data), limits.MaxSize)
+			continue
+		}
+
+		detected, _, _ := mime.ParseMediaType(http.DetectContentType(attachment.Data))
+		if attachment.MimeType != "" {
+			declared, _, err := mime.ParseMediaType(attachment.MimeType)
+			if err != nil || declared != detected {
+				violate(field+".mime_type", "%q does not match the content (%s)", attachment.MimeType, detected)
+				continue
+			}
+		}
+		if !slices.Contains(limits.AllowedTypes, detected) {
+			violate(field+".data", "%s is not an accepted type", detected)
+			continue
+		}
+
+		images = append(images, base64.StdEncoding.EncodeToString(attachment.Data))
+	}
+
+	if len(violations) > 0 {
+		return nil, badRequest("attachments", violations)
+	}
+	return images, nil
+}
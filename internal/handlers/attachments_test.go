@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"context"
+	"encoding/base64"
+	"slices"
+	"strings"
+	"testing"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/config"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// Smallest data http.DetectContentType recognizes for each type
+var (
+	pngData  = []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	jpegData = []byte("\xff\xd8\xff\xe0\x00\x10JFIF\x00")
+	webpData = []byte("RIFF\x24\x00\x00\x00WEBPVP8 ")
+	gifData  = []byte("GIF89a\x01\x00\x01\x00")
+)
+
+// attachmentLimits are the limits of the default
+// security.validation.attachments settings
+func attachmentLimits() AttachmentLimits {
+	attachments := config.Default().Security.Validation.Attachments
+	return AttachmentLimits{
+		MaxCount:     attachments.MaxCount,
+		MaxSize:      attachments.MaxSize.Int(),
+		AllowedTypes: attachments.AllowedTypes,
+	}
+}
+
+func TestImagesDetectTypes(t *testing.T) {
+	server := NewAgentServer(AgentConfig{Sessions: stubSessions{}, Attachments: attachmentLimits()})
+
+	tests := []struct {
+		name       string
+		attachment *mcpv1.Attachment
+		field      string // violated, none when accepted
+	}{
+		{"png", &mcpv1.Attachment{Data: pngData}, ""},
+		{"jpeg", &mcpv1.Attachment{Data: jpegData, MimeType: "image/jpeg"}, ""},
+		{"webp", &mcpv1.Attachment{Data: webpData, MimeType: "image/webp"}, ""},
+		{"declared type with parameters", &mcpv1.Attachment{Data: pngData, MimeType: "image/png; name=chart.png"}, ""},
+		{"type not allowed", &mcpv1.Attachment{Data: gifData}, "attachments[0].data"},
+		{"text", &mcpv1.Attachment{Data: []byte("just some text")}, "attachments[0].data"},
+		{"declared type disagrees with the data", &mcpv1.Attachment{Data: gifData, MimeType: "image/png"}, "attachments[0].mime_type"},
+		{"invalid declared type", &mcpv1.Attachment{Data: pngData, MimeType: "image/"}, "attachments[0].mime_type"},
+		{"empty", &mcpv1.Attachment{MimeType: "image/png"}, "attachments[0].data"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			images, err := server.images([]*mcpv1.Attachment{tt.attachment})
+			if tt.field == "" {
+				if err != nil {
+					t.Fatalf("images: %v", err)
+				}
+				if want := base64.StdEncoding.EncodeToString(tt.attachment.Data); !slices.Equal(images, []string{want}) {
+					t.Errorf("images = %v, want the data in base64", images)
+				}
+				return
+			}
+			if fields := violatedFields(t, err); !slices.Equal(fields, []string{tt.field}) {
+				t.Errorf("violations = %v, want %s", fields, tt.field)
+			}
+		})
+	}
+}
+
+func TestImagesLimits(t *testing.T) {
+	limits := attachmentLimits()
+	tooLarge := append(slices.Clone(pngData), make([]byte, limits.MaxSize)...)
+
+	tests := []struct {
+		name        string
+		limits      AttachmentLimits
+		attachments []*mcpv1.Attachment
+		fields      []string
+	}{
+		{"at the count limit", limits, slices.Repeat([]*mcpv1.Attachment{{Data: pngData}}, limits.MaxCount), nil},
+		{"over the count limit", limits, slices.Repeat([]*mcpv1.Attachment{{Data: pngData}}, limits.MaxCount+1), []string{"attachments"}},
+		{"disabled", AttachmentLimits{}, []*mcpv1.Attachment{{Data: pngData}}, []string{"attachments"}},
+		{"at the size limit", limits, []*mcpv1.Attachment{{Data: tooLarge[:limits.MaxSize]}}, nil},
+		{"over the size limit", limits, []*mcpv1.Attachment{{Data: tooLarge}}, []string{"attachments[0].data"}},
+		{"every invalid attachment is listed", limits, []*mcpv1.Attachment{{Data: pngData}, {Data: gifData}, {Data: tooLarge}},
+			[]string{"attachments[1].data", "attachments[2].data"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			server := NewAgentServer(AgentConfig{Sessions: stubSessions{}, Attachments: tt.limits})
+			images, err := server.images(tt.attachments)
+			if tt.fields == nil {
+				if err != nil || len(images) != len(tt.attachments) {
+					t.Errorf("images = %d images, %v, want all %d accepted", len(images), err, len(tt.attachments))
+				}
+				return
+			}
+			if images != nil {
+				t.Errorf("images = %d images, want none when any attachment is rejected", len(images))
+			}
+			if fields := violatedFields(t, err); !slices.Equal(fields, tt.fields) {
+				t.Errorf("violations = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+}
+
+func TestSingleChatSendsAttachments(t *testing.T) {
+	stub := newStubOllama(t, "A chart.")
+	server := NewAgentServer(AgentConfig{OllamaBaseURL: stub.URL, Sessions: stubSessions{}, Attachments: attachmentLimits()})
+
+	_, err := server.SingleChat(context.Background(), &mcpv1.SingleChatRequest{
+		SessionId:   "session-1",
+		Content:     "What is this?",
+		Attachments: []*mcpv1.Attachment{{Data: pngData, Name: "chart.png"}},
+	})
+	if err != nil {
+		t.Fatalf("SingleChat: %v", err)
+	}
+	requests := stub.received()
+	if len(requests) != 1 || len(requests[0].Messages) != 1 {
+		t.Fatalf("Ollama received %v, want one request with the user message", requests)
+	}
+	if images := requests[0].Messages[0].Images; !slices.Equal(images, []string{base64.StdEncoding.EncodeToString(pngData)}) {
+		t.Errorf("images = %v, want the attachment", images)
+	}
+
+	// Rejected attachments never reach Ollama
+	_, err = server.SingleChat(context.Background(), &mcpv1.SingleChatRequest{
+		SessionId:   "session-1",
+		Content:     "And this?",
+		Attachments: []*mcpv1.Attachment{{Data: []byte("<svg></svg>"), MimeType: "image/svg+xml"}},
+	})
+	if err == nil || !strings.Contains(err.Error(), "mime_type") {
+		t.Errorf("SingleChat with an SVG = %v, want a mime_type violation", err)
+	}
+	if len(stub.received()) != 1 {
+		t.Error("a rejected attachment was sent to Ollama")
+	}
+}
@@ -0,0 +1,181 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/schema"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// maxClientTools bounds the tools an agent can declare on one stream
+const maxClientTools = 32
+
+// clientTools are the tools an agent declared on its Chat stream. The
+// gateway does not run them: a call goes down the stream as a TOOL_CALL
+// message and the agent answers it with a TOOL_RESULT message.
+type clientTools struct {
+	definitions []ollama.Tool
+	parameters  map[string]*schema.Schema
+
+	// Calls waiting for their result, by call id
+	pending      map[string]chan *mcpv1.ToolResult
+	pendingMutex sync.Mutex
+}
+
+// newClientTools validates the tools declared on a stream. Names must be
+// unique and cannot shadow the tools of the gateway registry (nil for
+// none). Invalid declarations return InvalidArgument with a BadRequest
+// detail.
+func newClientTools(declared []*mcpv1.ToolDefinition, registry *tools.Registry) (*clientTools, error) {
+	var violations []*errdetails.BadRequest_FieldViolation
+	violation := func(i int, field, description string) {
+		violations = append(violations, &errdetails.BadRequest_FieldViolation{
+			Field:       fmt.Sprintf("tools[%d].%s", i, field),
+			Description: description,
+		})
+	}
+
+	if len(declared) > maxClientTools {
+		return nil, badRequest("tools", []*errdetails.BadRequest_FieldViolation{{
+			Field:       "tools",
+			Description: fmt.Sprintf("at most %d tools can be declared", maxClientTools),
+		}})
+	}
+
+	c := &clientTools{
+		parameters: make(map[string]*schema.Schema),
+		pending:    make(map[string]chan *mcpv1.ToolResult),
+	}
+	for i, tool := range declared {
+		if err := tools.CheckName(tool.Name); err != nil {
+			violation(i, "name", err.Error())
+			continue
+		}
+		if _, exists := c.parameters[tool.Name]; exists {
+			violation(i, "name", "declared twice")
+			continue
+		}
+		if registry != nil && registry.Has(tool.Name) {
+			violation(i, "name", "is a tool of the gateway")
+			continue
+		}
+
+		parameters := tool.Parameters
+		if parameters == "" {
+			parameters = `{"type":"object","properties":{}}`
+		}
+		compiled, err := schema.Compile(parameters)
+		if err != nil {
+			violation(i, "parameters", err.Error())
+			continue
+		}
+
+		c.parameters[tool.Name] = compiled
+		c.definitions = append(c.definitions, ollama.Tool{
+			Type: "function",
+			Function: ollama.ToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  json.RawMessage(parameters),
+			},
+		})
+	}
+
+	if len(violations) > 0 {
+		return nil, badRequest("tools", violations)
+	}
+	return c, nil
+}
+
+// has reports whether the agent declared a tool. It is safe on nil.
+func (c *clientTools) has(name string) bool {
+	if c == nil {
+		return false
+	}
+	_, exists := c.parameters[name]
+	return exists
+}
+
+// call asks the agent to run a tool, announcing the call with the
+// arguments, and waits up to timeout (0 = no limit) for the result.
+// Arguments that do not match the declared parameters, a failure reported by
+// the agent or a late result all become an error result for the model. The
+// returned error is that of announce, or of ctx when the stream ends while
+// waiting.
+func (c *clientTools) call(ctx context.Context, callID string, call ollama.ToolCall, timeout time.Duration, announce func(arguments string) error) (string, error) {
+	name := call.Function.Name
+	arguments := call.Function.Arguments
+	if len(arguments) == 0 {
+		arguments = json.RawMessage(`{}`)
+	}
+	if _, err := c.parameters[name].Validate(string(arguments)); err != nil {
+		var invalid *schema.ValidationError
+		if errors.As(err, &invalid) {
+			return "Error: invalid arguments: " + invalid.Reasons(), nil
+		}
+		return "Error: invalid arguments: " + err.Error(), nil
+	}
+
+	// Wait for the result before asking, so a fast agent cannot answer a
+	// call nobody waits for yet
+	result := make(chan *mcpv1.ToolResult, 1)
+	c.pendingMutex.Lock()
+	c.pending[callID] = result
+	c.pendingMutex.Unlock()
+	defer func() {
+		c.pendingMutex.Lock()
+		delete(c.pending, callID)
+		c.pendingMutex.Unlock()
+	}()
+
+	if err := announce(string(arguments)); err != nil {
+		return "", err
+	}
+
+	var expired <-chan time.Time
+	if timeout > 0 {
+		timer := time.NewTimer(timeout)
+		defer timer.Stop()
+		expired = timer.C
+	}
+	select {
+	case r := <-result:
+		if r.IsError {
+			return "Error: " + r.Content, nil
+		}
+		return r.Content, nil
+	case <-expired:
+		log.Printf("⏰ Agent did not return the result of %s (call %s) within %s", name, callID, timeout)
+		return fmt.Sprintf("Error: %s did not return a result within %s", name, timeout), nil
+	case <-ctx.Done():
+		return "", ctx.Err()
+	}
+}
+
+// deliver hands a TOOL_RESULT of the agent to the call waiting for it. It
+// reports false when no call with that id is pending, e.g. after it timed out.
+func (c *clientTools) deliver(result *mcpv1.ToolResult) bool {
+	if c == nil {
+		return false
+	}
+	c.pendingMutex.Lock()
+	defer c.pendingMutex.Unlock()
+
+	pending, exists := c.pending[result.Id]
+	if !exists {
+		return false
+	}
+	delete(c.pending, result.Id)
+	pending <- result
+	return true
+}
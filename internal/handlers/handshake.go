@@ -3,46 +3,108 @@ package handlers
 import (
 	"context"
 	"crypto/rand"
+	"crypto/sha256"
 	"encoding/hex"
+	"errors"
+	"log"
+	"sync"
 	"time"
 
+	"github.com/golang-jwt/jwt/v5"
 	"google.golang.org/grpc/codes"
 	"google.golang.org/grpc/status"
 	"google.golang.org/protobuf/types/known/timestamppb"
 
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/quota"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
 	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
 )
 
 type HandshakeServer struct {
 	mcpv1.UnimplementedHandshakeServiceServer
-	// In-memory storage for demo purposes
-	// In production, this would be Redis or a database
-	sessions map[string]*SessionInfo
-	tokens   map[string]*TokenInfo
-}
+	// Sessions, refresh tokens and revocations live in a pluggable store
+	// (in-memory or an embedded database, see database.type)
+	sessions store.SessionStore
+
+	// Tokens are signed JWTs: any replica (or backend service) holding the
+	// verification key can validate them without this server's state
+	tokens *auth.TokenManager
+
+	// Only registered tenants can register sessions, for the models they are
+	// allowed to use (nil accepts any tenant and model)
+	tenants *tenant.Registry
+	// quotas limits the active sessions of every tenant (nil = unlimited)
+	quotas *quota.Tracker
 
-type SessionInfo struct {
-	SessionID string
-	TenantID  string
-	AgentID   string
-	Model     string
-	CreatedAt time.Time
-	ExpiresAt time.Time
+	accessTokenTTL time.Duration
+	sessionTTL     time.Duration
+	defaultModel   string
+
+	onRevoke      func(sessionID string)
+	onRevokeMutex sync.RWMutex
 }
 
-type TokenInfo struct {
-	TenantID  string
-	AgentID   string
-	SessionID string
-	CreatedAt time.Time
-	ExpiresAt time.Time
+const (
+	// defaultAccessTokenTTL is the lifetime of a JWT (5 minutes as mentioned in the README)
+	defaultAccessTokenTTL = 5 * time.Minute
+	// defaultSessionTTL bounds how long a session can be kept alive with Refresh
+	defaultSessionTTL = 24 * time.Hour
+)
+
+// HandshakeConfig configures the HandshakeServer. Zero durations and an
+// empty DefaultModel use the defaults.
+type HandshakeConfig struct {
+	Tokens         *auth.TokenManager
+	Sessions       store.SessionStore
+	Tenants        *tenant.Registry // nil accepts any tenant and model
+	Quotas         *quota.Tracker   // nil disables session quotas
+	AccessTokenTTL time.Duration    // lifetime of each JWT
+	SessionTTL     time.Duration    // how long Refresh can keep a session alive
+	DefaultModel   string           // model for sessions that do not request one
 }
 
-func NewHandshakeServer() *HandshakeServer {
-	return &HandshakeServer{
-		sessions: make(map[string]*SessionInfo),
-		tokens:   make(map[string]*TokenInfo),
+var (
+	// ErrSessionNotFound is returned when a session ID was never registered
+	ErrSessionNotFound = errors.New("session not found")
+	// ErrSessionExpired is returned when a session existed but has expired
+	ErrSessionExpired = errors.New("session expired")
+	// ErrSessionRevoked is returned for tokens of a session that logged out
+	ErrSessionRevoked = errors.New("session revoked")
+)
+
+// SessionInfo describes a registered session
+type SessionInfo = store.Session
+
+func NewHandshakeServer(config HandshakeConfig) *HandshakeServer {
+	server := &HandshakeServer{
+		sessions:       config.Sessions,
+		tokens:         config.Tokens,
+		tenants:        config.Tenants,
+		quotas:         config.Quotas,
+		accessTokenTTL: config.AccessTokenTTL,
+		sessionTTL:     config.SessionTTL,
+		defaultModel:   config.DefaultModel,
 	}
+	if server.accessTokenTTL <= 0 {
+		server.accessTokenTTL = defaultAccessTokenTTL
+	}
+	if server.sessionTTL <= 0 {
+		server.sessionTTL = defaultSessionTTL
+	}
+	if server.defaultModel == "" {
+		server.defaultModel = "gemma3:4b"
+	}
+	return server
+}
+
+// OnSessionRevoked registers a callback run after a session is revoked, used
+// to terminate its active chat streams
+func (s *HandshakeServer) OnSessionRevoked(callback func(sessionID string)) {
+	s.onRevokeMutex.Lock()
+	defer s.onRevokeMutex.Unlock()
+	s.onRevoke = callback
 }
 
 // Register creates a new session and returns a JWT token
@@ -54,74 +116,233 @@ func (s *HandshakeServer) Register(ctx context.Context, req *mcpv1.RegisterReque
 	if req.AgentId == "" {
 		return nil, status.Error(codes.InvalidArgument, "agent_id is required")
 	}
-	if req.Model == "" {
-		req.Model = "gemma3:4b" // default model
+
+	// Unknown tenants are rejected, and the model must be one the tenant is
+	// allowed to use
+	var settings *tenant.Tenant
+	if s.tenants != nil {
+		var err error
+		settings, err = s.tenants.Get(req.TenantId)
+		if err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "tenant %s is not registered", req.TenantId)
+		}
+		model, err := settings.Model(req.Model)
+		if err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "model %s is not allowed for tenant %s", req.Model, req.TenantId)
+		}
+		req.Model = model
+	} else if req.Model == "" {
+		req.Model = s.defaultModel
 	}
 
-	// Generate session ID
-	sessionID, err := generateRandomID()
+	// The generation options become the defaults of the session
+	options, err := generationOptions(req.Options, settings)
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to generate session ID: %v", err)
+		return nil, err
 	}
 
-	// Generate JWT token (simplified for demo)
-	jwtToken, err := generateRandomID()
+	// Generate session ID
+	sessionID, err := generateRandomID()
 	if err != nil {
-		return nil, status.Errorf(codes.Internal, "failed to generate JWT token: %v", err)
+		return nil, status.Errorf(codes.Internal, "failed to generate session ID: %v", err)
 	}
 
-	// Set expiration (5 minutes as mentioned in the README)
+	// The session outlives its tokens: it can be refreshed until it expires
 	now := time.Now()
-	expiresAt := now.Add(5 * time.Minute)
-
-	// Store session info
 	sessionInfo := &SessionInfo{
 		SessionID: sessionID,
 		TenantID:  req.TenantId,
 		AgentID:   req.AgentId,
 		Model:     req.Model,
 		CreatedAt: now,
-		ExpiresAt: expiresAt,
+		ExpiresAt: now.Add(s.sessionTTL),
+		Options:   storedOptions(options),
 	}
-	s.sessions[sessionID] = sessionInfo
 
-	// Store token info
-	tokenInfo := &TokenInfo{
-		TenantID:  req.TenantId,
-		AgentID:   req.AgentId,
-		SessionID: sessionID,
-		CreatedAt: now,
-		ExpiresAt: expiresAt,
+	// Store session info, counted against the tenant quota only once stored
+	putSession := func() error {
+		if err := s.sessions.PutSession(sessionInfo); err != nil {
+			return status.Errorf(codes.Internal, "failed to store session: %v", err)
+		}
+		return nil
+	}
+	if s.quotas != nil {
+		err = s.quotas.StartSession(req.TenantId, putSession)
+	} else {
+		err = putSession()
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	// Sign the JWT token and its refresh token. A session nobody can use
+	// must not hold a slot of the tenant.
+	tokens, err := s.issueTokens(sessionInfo)
+	if err != nil {
+		if err := s.sessions.DeleteSession(sessionID); err != nil {
+			log.Printf("❌ Failed to delete session %s: %v", sessionID, err)
+		}
+		return nil, err
 	}
-	s.tokens[jwtToken] = tokenInfo
 
 	return &mcpv1.RegisterResponse{
-		SessionId: sessionID,
-		JwtToken:  jwtToken,
-		ExpiresAt: timestamppb.New(expiresAt),
+		SessionId:        sessionID,
+		JwtToken:         tokens.JwtToken,
+		ExpiresAt:        tokens.ExpiresAt,
+		RefreshToken:     tokens.RefreshToken,
+		RefreshExpiresAt: tokens.RefreshExpiresAt,
 	}, nil
 }
 
-// Authenticate validates a JWT token and returns session info
-func (s *HandshakeServer) Authenticate(ctx context.Context, req *mcpv1.AuthRequest) (*mcpv1.AuthResponse, error) {
+// Refresh rotates the tokens of a session. Every refresh token works once:
+// presenting a used one again means it leaked, so the session is revoked.
+func (s *HandshakeServer) Refresh(ctx context.Context, req *mcpv1.RefreshRequest) (*mcpv1.RefreshResponse, error) {
+	if req.RefreshToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "refresh_token is required")
+	}
 	if req.JwtToken == "" {
 		return nil, status.Error(codes.InvalidArgument, "jwt_token is required")
 	}
 
-	// Look up token
-	tokenInfo, exists := s.tokens[req.JwtToken]
-	if !exists {
-		return &mcpv1.AuthResponse{
-			Valid: false,
-		}, nil
+	// The access token may have expired, but only just
+	claims, err := s.tokens.ParseWithLeeway(req.JwtToken, s.accessTokenTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid jwt_token: %v", err)
+	}
+
+	refresh, err := s.sessions.UseRefreshToken(hashToken(req.RefreshToken), claims.SessionID)
+	switch {
+	case errors.Is(err, store.ErrNotFound):
+		return nil, status.Error(codes.Unauthenticated, "invalid refresh_token")
+	case err != nil:
+		return nil, status.Errorf(codes.Internal, "failed to look up refresh token: %v", err)
+
+	case refresh.Used:
+		log.Printf("🚨 Refresh token reuse detected, revoking session %s", claims.SessionID)
+		if err := s.revokeSession(claims.SessionID); err != nil {
+			return nil, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
+		}
+		return nil, status.Error(codes.Unauthenticated, "refresh_token was already used, session revoked")
+
+	case time.Now().After(refresh.ExpiresAt):
+		return nil, status.Error(codes.Unauthenticated, "session has expired, register again")
 	}
 
-	// Check if token is expired
-	if time.Now().After(tokenInfo.ExpiresAt) {
-		// Clean up expired token
-		delete(s.tokens, req.JwtToken)
-		delete(s.sessions, tokenInfo.SessionID)
+	revoked, err := s.sessions.IsRevoked(claims.SessionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to check revocation: %v", err)
+	}
+	if revoked {
+		return nil, status.Error(codes.Unauthenticated, "session has been revoked")
+	}
+
+	sessionInfo, err := s.LookupSession(claims.SessionID)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "%v, register again", err)
+	}
+
+	// Tenants removed from the registry cannot keep their sessions alive
+	if s.tenants != nil {
+		if _, err := s.tenants.Get(sessionInfo.TenantID); err != nil {
+			return nil, status.Errorf(codes.PermissionDenied, "tenant %s is not registered", sessionInfo.TenantID)
+		}
+	}
+
+	return s.issueTokens(sessionInfo)
+}
+
+// Revoke logs a session out: its tokens stop validating, its refresh tokens
+// are dropped and its active chat streams are terminated
+func (s *HandshakeServer) Revoke(ctx context.Context, req *mcpv1.RevokeRequest) (*mcpv1.RevokeResponse, error) {
+	if req.JwtToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "jwt_token is required")
+	}
 
+	// Logging out must work with an expired token too
+	claims, err := s.tokens.ParseWithLeeway(req.JwtToken, s.sessionTTL)
+	if err != nil {
+		return nil, status.Errorf(codes.Unauthenticated, "invalid jwt_token: %v", err)
+	}
+
+	if err := s.revokeSession(claims.SessionID); err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to revoke session: %v", err)
+	}
+	log.Printf("🔒 Revoked session %s (tenant: %s, agent: %s)", claims.SessionID, claims.TenantID, claims.AgentID)
+
+	return &mcpv1.RevokeResponse{
+		SessionId: claims.SessionID,
+	}, nil
+}
+
+// issueTokens signs a new access token for the session and stores a new
+// refresh token for it
+func (s *HandshakeServer) issueTokens(session *SessionInfo) (*mcpv1.RefreshResponse, error) {
+	now := time.Now()
+	expiresAt := now.Add(s.accessTokenTTL)
+	if expiresAt.After(session.ExpiresAt) {
+		expiresAt = session.ExpiresAt
+	}
+
+	jwtToken, err := s.tokens.Issue(&auth.Claims{
+		TenantID:  session.TenantID,
+		AgentID:   session.AgentID,
+		SessionID: session.SessionID,
+		Model:     session.Model,
+		RegisteredClaims: jwt.RegisteredClaims{
+			IssuedAt:  jwt.NewNumericDate(now),
+			ExpiresAt: jwt.NewNumericDate(expiresAt),
+		},
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate JWT token: %v", err)
+	}
+
+	refreshToken, err := generateRandomID()
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to generate refresh token: %v", err)
+	}
+	err = s.sessions.PutRefreshToken(hashToken(refreshToken), &store.RefreshToken{
+		SessionID: session.SessionID,
+		ExpiresAt: session.ExpiresAt,
+	})
+	if err != nil {
+		return nil, status.Errorf(codes.Internal, "failed to store refresh token: %v", err)
+	}
+
+	return &mcpv1.RefreshResponse{
+		SessionId:        session.SessionID,
+		JwtToken:         jwtToken,
+		ExpiresAt:        timestamppb.New(expiresAt),
+		RefreshToken:     refreshToken,
+		RefreshExpiresAt: timestamppb.New(session.ExpiresAt),
+	}, nil
+}
+
+// revokeSession forgets a session and its refresh tokens, remembers it as
+// revoked until its last access token expires, and notifies onRevoke
+func (s *HandshakeServer) revokeSession(sessionID string) error {
+	if err := s.sessions.RevokeSession(sessionID, time.Now().Add(s.accessTokenTTL)); err != nil {
+		return err
+	}
+
+	s.onRevokeMutex.RLock()
+	onRevoke := s.onRevoke
+	s.onRevokeMutex.RUnlock()
+	if onRevoke != nil {
+		onRevoke(sessionID)
+	}
+	return nil
+}
+
+// Authenticate validates a JWT token and returns session info
+func (s *HandshakeServer) Authenticate(ctx context.Context, req *mcpv1.AuthRequest) (*mcpv1.AuthResponse, error) {
+	if req.JwtToken == "" {
+		return nil, status.Error(codes.InvalidArgument, "jwt_token is required")
+	}
+
+	// Verify signature and expiration, no lookup needed
+	principal, err := s.ValidateToken(ctx, req.JwtToken)
+	if err != nil {
 		return &mcpv1.AuthResponse{
 			Valid: false,
 		}, nil
@@ -129,26 +350,60 @@ func (s *HandshakeServer) Authenticate(ctx context.Context, req *mcpv1.AuthReque
 
 	return &mcpv1.AuthResponse{
 		Valid:    true,
-		TenantId: tokenInfo.TenantID,
-		AgentId:  tokenInfo.AgentID,
+		TenantId: principal.TenantID,
+		AgentId:  principal.AgentID,
 	}, nil
 }
 
+// ValidateToken resolves a token to the tenant, agent and session it was
+// issued for (implements auth.TokenValidator)
+func (s *HandshakeServer) ValidateToken(ctx context.Context, token string) (*auth.Principal, error) {
+	claims, err := s.tokens.Parse(token)
+	if err != nil {
+		return nil, err
+	}
+
+	revoked, err := s.sessions.IsRevoked(claims.SessionID)
+	if err != nil {
+		return nil, err
+	}
+	if revoked {
+		return nil, ErrSessionRevoked
+	}
+
+	return claims.Principal(), nil
+}
+
 // GetSessionInfo returns session information by session ID (helper method)
 func (s *HandshakeServer) GetSessionInfo(sessionID string) (*SessionInfo, bool) {
-	session, exists := s.sessions[sessionID]
-	if !exists {
+	session, err := s.LookupSession(sessionID)
+	if err != nil {
 		return nil, false
 	}
+	return session, true
+}
+
+// LookupSession returns a live session, or ErrSessionNotFound /
+// ErrSessionExpired so callers can tell both cases apart
+func (s *HandshakeServer) LookupSession(sessionID string) (*SessionInfo, error) {
+	session, err := s.sessions.GetSession(sessionID)
+	if errors.Is(err, store.ErrNotFound) {
+		return nil, ErrSessionNotFound
+	}
+	if err != nil {
+		return nil, err
+	}
 
 	// Check if session is expired
 	if time.Now().After(session.ExpiresAt) {
 		// Clean up expired session
-		delete(s.sessions, sessionID)
-		return nil, false
+		if err := s.sessions.DeleteSession(sessionID); err != nil {
+			log.Printf("❌ Failed to delete expired session %s: %v", sessionID, err)
+		}
+		return nil, ErrSessionExpired
 	}
 
-	return session, true
+	return session, nil
 }
 
 // generateRandomID creates a cryptographically secure random ID
@@ -160,27 +415,28 @@ func generateRandomID() (string, error) {
 	return hex.EncodeToString(bytes), nil
 }
 
-// CleanupExpiredSessions removes expired sessions and tokens (should be called periodically)
-func (s *HandshakeServer) CleanupExpiredSessions() {
-	now := time.Now()
-
-	// Clean up expired sessions
-	for sessionID, session := range s.sessions {
-		if now.After(session.ExpiresAt) {
-			delete(s.sessions, sessionID)
-		}
-	}
+// hashToken is how refresh tokens are stored, so a leaked store does not
+// leak usable tokens
+func hashToken(token string) string {
+	sum := sha256.Sum256([]byte(token))
+	return hex.EncodeToString(sum[:])
+}
 
-	// Clean up expired tokens
-	for token, tokenInfo := range s.tokens {
-		if now.After(tokenInfo.ExpiresAt) {
-			delete(s.tokens, token)
-		}
+// CleanupExpiredSessions removes expired sessions, refresh tokens and
+// revocations (should be called periodically)
+func (s *HandshakeServer) CleanupExpiredSessions() {
+	if err := s.sessions.DeleteExpired(time.Now()); err != nil {
+		log.Printf("❌ Session cleanup failed: %v", err)
 	}
 }
 
 // GetActiveSessionsCount returns the number of active sessions (for monitoring)
 func (s *HandshakeServer) GetActiveSessionsCount() int {
 	s.CleanupExpiredSessions()
-	return len(s.sessions)
+
+	count, err := s.sessions.CountSessions()
+	if err != nil {
+		log.Printf("❌ Failed to count sessions: %v", err)
+	}
+	return count
 }
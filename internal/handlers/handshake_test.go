@@ -0,0 +1,70 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+func newHandshakeServer(t *testing.T) *HandshakeServer {
+	t.Helper()
+	tokens, err := auth.NewTokenManager(auth.JWTConfig{Algorithm: "HS256", SecretKey: "test-secret", Issuer: "gateway"})
+	if err != nil {
+		t.Fatal(err)
+	}
+	return NewHandshakeServer(HandshakeConfig{Tokens: tokens, Sessions: store.NewMemoryStore()})
+}
+
+func TestRevokedSessionsAreRejected(t *testing.T) {
+	tests := []struct {
+		name   string
+		revoke func(ctx context.Context, server *HandshakeServer, registered *mcpv1.RegisterResponse) error
+	}{
+		{"revoke", func(ctx context.Context, server *HandshakeServer, registered *mcpv1.RegisterResponse) error {
+			_, err := server.Revoke(ctx, &mcpv1.RevokeRequest{JwtToken: registered.JwtToken})
+			return err
+		}},
+		{"refresh token reuse", func(ctx context.Context, server *HandshakeServer, registered *mcpv1.RegisterResponse) error {
+			refresh := &mcpv1.RefreshRequest{JwtToken: registered.JwtToken, RefreshToken: registered.RefreshToken}
+			if _, err := server.Refresh(ctx, refresh); err != nil {
+				return err
+			}
+			if _, err := server.Refresh(ctx, refresh); err == nil {
+				return errors.New("a used refresh token was accepted")
+			}
+			return nil
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			ctx := context.Background()
+			server := newHandshakeServer(t)
+			var terminated string
+			server.OnSessionRevoked(func(sessionID string) { terminated = sessionID })
+
+			registered, err := server.Register(ctx, &mcpv1.RegisterRequest{TenantId: "acme", AgentId: "agent-1"})
+			if err != nil {
+				t.Fatalf("Register: %v", err)
+			}
+			if _, err := server.ValidateToken(ctx, registered.JwtToken); err != nil {
+				t.Fatalf("ValidateToken before revoking: %v", err)
+			}
+
+			if err := tt.revoke(ctx, server, registered); err != nil {
+				t.Fatal(err)
+			}
+
+			// The access token is still signed and unexpired, but its session is gone
+			if _, err := server.ValidateToken(ctx, registered.JwtToken); !errors.Is(err, ErrSessionRevoked) {
+				t.Errorf("ValidateToken after revoking = %v, want ErrSessionRevoked", err)
+			}
+			if terminated != registered.SessionId {
+				t.Errorf("revocation callback got %q, want %q", terminated, registered.SessionId)
+			}
+		})
+	}
+}
@@ -0,0 +1,236 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/encoding/protojson"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/mcp"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// chatToolName is the MCP tool that asks the model
+const chatToolName = "chat"
+
+// URIs of the resources served to MCP hosts
+const (
+	sessionResourceURI = "gentleman://session"
+	usageResourceURI   = "gentleman://usage"
+)
+
+// MCPBackend answers MCP hosts with the services of the gateway: the model
+// as the chat tool, the tools offered to the caller's tenant (bridged MCP
+// servers included), the caller's session and usage as resources, and the
+// configured prompts. Requests go through the AgentServer, with the same
+// session, tenant and quota checks as RPCs.
+type MCPBackend struct {
+	agent *AgentServer
+
+	// prompts can change on a config reload and are guarded by promptsMutex
+	prompts      []mcp.PromptTemplate
+	promptsMutex sync.RWMutex
+}
+
+func NewMCPBackend(agent *AgentServer, prompts []mcp.PromptTemplate) *MCPBackend {
+	return &MCPBackend{agent: agent, prompts: prompts}
+}
+
+// SetPrompts applies reloaded prompts
+func (b *MCPBackend) SetPrompts(prompts []mcp.PromptTemplate) {
+	b.promptsMutex.Lock()
+	defer b.promptsMutex.Unlock()
+	b.prompts = prompts
+}
+
+// ListTools offers the chat tool and the tools offered to the model
+func (b *MCPBackend) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	principal, err := mcpPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	list := []mcp.Tool{{
+		Name:        chatToolName,
+		Description: "Asks the gateway model a question and returns its answer. Every call is independent: the model does not remember previous calls.",
+		InputSchema: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"message": {"type": "string", "description": "The question or instruction for the model."},
+				"model": {"type": "string", "description": "Model to use instead of the one of the session, if the gateway allows it."}
+			},
+			"required": ["message"]
+		}`),
+	}}
+
+	if b.agent.tools != nil {
+		for _, tool := range b.agent.tools.Definitions(b.agent.offeredTools(principal.TenantID)) {
+			list = append(list, mcp.Tool{
+				Name:        tool.Function.Name,
+				Description: tool.Function.Description,
+				InputSchema: tool.Function.Parameters,
+			})
+		}
+	}
+	return list, nil
+}
+
+// CallTool runs the chat tool or a tool of the registry. Failures are
+// returned as error results, for the model of the host to see.
+func (b *MCPBackend) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*mcp.CallToolResult, error) {
+	principal, err := mcpPrincipal(ctx)
+	if err != nil {
+		return nil, err
+	}
+	log.Printf("🧩 MCP session %s calls tool %s", principal.SessionID, name)
+
+	if name == chatToolName {
+		return b.chat(ctx, arguments)
+	}
+	if b.agent.tools == nil || !b.agent.tools.Has(name) || !slices.Contains(b.agent.offeredTools(principal.TenantID), name) {
+		return nil, mcp.NewError(mcp.CodeInvalidParams, "unknown tool %q", name)
+	}
+
+	content, err := b.agent.runTool(ctx, name, arguments)
+	if err != nil {
+		log.Printf("⚠️  Tool %s failed for MCP session %s: %v", name, principal.SessionID, err)
+		return toolError(err.Error()), nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(content)}}, nil
+}
+
+// chat asks the model through SingleChat
+func (b *MCPBackend) chat(ctx context.Context, arguments json.RawMessage) (*mcp.CallToolResult, error) {
+	var args struct {
+		Message string `json:"message"`
+		Model   string `json:"model"`
+	}
+	if len(arguments) > 0 {
+		if err := json.Unmarshal(arguments, &args); err != nil {
+			return nil, mcp.NewError(mcp.CodeInvalidParams, "invalid arguments: %v", err)
+		}
+	}
+	if args.Message == "" {
+		return nil, mcp.NewError(mcp.CodeInvalidParams, "message is required")
+	}
+
+	response, err := b.agent.SingleChat(ctx, &mcpv1.SingleChatRequest{
+		Content: args.Message,
+		Model:   args.Model,
+	})
+	if err != nil {
+		return toolError(status.Convert(err).Message()), nil
+	}
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(response.Content)}}, nil
+}
+
+// ListPrompts lists the configured prompts
+func (b *MCPBackend) ListPrompts(ctx context.Context) ([]mcp.Prompt, error) {
+	b.promptsMutex.RLock()
+	defer b.promptsMutex.RUnlock()
+
+	list := make([]mcp.Prompt, len(b.prompts))
+	for i, prompt := range b.prompts {
+		list[i] = prompt.Prompt
+	}
+	return list, nil
+}
+
+// GetPrompt renders a configured prompt
+func (b *MCPBackend) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	b.promptsMutex.RLock()
+	defer b.promptsMutex.RUnlock()
+
+	for _, prompt := range b.prompts {
+		if prompt.Name == name {
+			return prompt.Render(arguments)
+		}
+	}
+	return nil, mcp.NewError(mcp.CodeInvalidParams, "unknown prompt %q", name)
+}
+
+// ListResources lists the session of the caller and, with usage accounting,
+// the usage of its tenant
+func (b *MCPBackend) ListResources(ctx context.Context) ([]mcp.Resource, error) {
+	list := []mcp.Resource{{
+		URI:         sessionResourceURI,
+		Name:        "session",
+		Description: "The gateway session of this connection: tenant, agent, model and expiry.",
+		MimeType:    "application/json",
+	}}
+	if b.agent.quotas != nil {
+		list = append(list, mcp.Resource{
+			URI:         usageResourceURI,
+			Name:        "usage",
+			Description: "Requests and tokens used by the tenant in the current quota window, and what remains of each quota.",
+			MimeType:    "application/json",
+		})
+	}
+	return list, nil
+}
+
+// ReadResource reads the session or usage resource
+func (b *MCPBackend) ReadResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	var text []byte
+	switch uri {
+	case sessionResourceURI:
+		principal, err := mcpPrincipal(ctx)
+		if err != nil {
+			return nil, err
+		}
+		session, model, err := b.agent.resolveSession(ctx, principal.SessionID, "")
+		if err != nil {
+			return nil, err
+		}
+		text, err = json.MarshalIndent(map[string]string{
+			"session_id": session.SessionID,
+			"tenant_id":  session.TenantID,
+			"agent_id":   session.AgentID,
+			"model":      model,
+			"expires_at": session.ExpiresAt.Format(time.RFC3339),
+		}, "", "  ")
+		if err != nil {
+			return nil, err
+		}
+
+	case usageResourceURI:
+		usage, err := b.agent.GetUsage(ctx, &mcpv1.GetUsageRequest{})
+		if err != nil {
+			return nil, err
+		}
+		text, err = protojson.MarshalOptions{Multiline: true}.Marshal(usage)
+		if err != nil {
+			return nil, fmt.Errorf("failed to encode usage: %w", err)
+		}
+
+	default:
+		return nil, mcp.NewError(mcp.CodeResourceNotFound, "resource %s not found", uri)
+	}
+
+	return []mcp.ResourceContents{{URI: uri, MimeType: "application/json", Text: string(text)}}, nil
+}
+
+// mcpPrincipal returns the principal the transport authenticated
+func mcpPrincipal(ctx context.Context) (*auth.Principal, error) {
+	principal, ok := auth.FromContext(ctx)
+	if !ok {
+		return nil, status.Error(codes.Unauthenticated, "authorization required")
+	}
+	return principal, nil
+}
+
+// toolError is the result of a tool that failed
+func toolError(message string) *mcp.CallToolResult {
+	return &mcp.CallToolResult{
+		Content: []mcp.Content{mcp.TextContent("Error: " + message)},
+		IsError: true,
+	}
+}
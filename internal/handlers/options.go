@@ -0,0 +1,140 @@
+package handlers
+
+import (
+	"fmt"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// Ranges accepted for the generation options
+const (
+	maxTopK        = 1000
+	maxContext     = 1 << 20
+	maxStops       = 8
+	maxStopLength  = 100
+	maxTemperature = 2
+)
+
+// generationOptions validates the options of a request against the accepted
+// ranges and the caps of the tenant (nil for no caps) and converts them for
+// Ollama. Violations return InvalidArgument with a BadRequest detail listing
+// every offending field.
+func generationOptions(options *mcpv1.GenerationOptions, settings *tenant.Tenant) (*ollama.Options, error) {
+	if options == nil {
+		return nil, nil
+	}
+
+	var violations []*errdetails.BadRequest_FieldViolation
+	check := func(ok bool, field, format string, args ...interface{}) {
+		if !ok {
+			violations = append(violations, &errdetails.BadRequest_FieldViolation{
+				Field:       "options." + field,
+				Description: fmt.Sprintf(format, args...),
+			})
+		}
+	}
+
+	var caps tenant.Generation
+	if settings != nil {
+		caps = settings.Generation
+	}
+
+	converted := &ollama.Options{
+		Temperature: options.Temperature,
+		TopP:        options.TopP,
+		Seed:        options.Seed,
+		Stop:        options.Stop,
+	}
+	if options.Temperature != nil {
+		check(*options.Temperature >= 0 && *options.Temperature <= maxTemperature,
+			"temperature", "must be between 0 and %d", maxTemperature)
+	}
+	if options.TopP != nil {
+		check(*options.TopP >= 0 && *options.TopP <= 1, "top_p", "must be between 0 and 1")
+	}
+	if options.TopK != nil {
+		check(*options.TopK >= 1 && *options.TopK <= maxTopK, "top_k", "must be between 1 and %d", maxTopK)
+		converted.TopK = intPointer(*options.TopK)
+	}
+	if options.NumCtx != nil {
+		check(*options.NumCtx >= 1 && *options.NumCtx <= maxContext, "num_ctx", "must be between 1 and %d", maxContext)
+		if limit := tenant.Value(caps.MaxContext); limit > 0 {
+			check(int(*options.NumCtx) <= limit, "num_ctx", "exceeds the limit of %d for tenant %s", limit, settings.ID)
+		}
+		converted.NumCtx = intPointer(*options.NumCtx)
+	}
+	if options.MaxTokens != nil {
+		check(*options.MaxTokens >= 1, "max_tokens", "must be positive")
+		if limit := tenant.Value(caps.MaxTokens); limit > 0 {
+			check(int(*options.MaxTokens) <= limit, "max_tokens", "exceeds the limit of %d for tenant %s", limit, settings.ID)
+		}
+		converted.NumPredict = intPointer(*options.MaxTokens)
+	}
+	check(len(options.Stop) <= maxStops, "stop", "at most %d stop sequences are allowed", maxStops)
+	for _, stop := range options.Stop {
+		check(stop != "" && len(stop) <= maxStopLength, "stop", "stop sequences must have 1 to %d bytes", maxStopLength)
+	}
+
+	if len(violations) > 0 {
+		return nil, badRequest("generation options", violations)
+	}
+	return converted, nil
+}
+
+// capOptions bounds merged options by the caps of the tenant. Unset values
+// get the cap, and values stored with a session before the caps were lowered
+// are clamped to them.
+func capOptions(options *ollama.Options, settings *tenant.Tenant) *ollama.Options {
+	if settings == nil {
+		return options
+	}
+	if limit := tenant.Value(settings.Generation.MaxTokens); limit > 0 && (options.NumPredict == nil || *options.NumPredict > limit) {
+		options.NumPredict = &limit
+	}
+	if limit := tenant.Value(settings.Generation.MaxContext); limit > 0 && (options.NumCtx == nil || *options.NumCtx > limit) {
+		options.NumCtx = &limit
+	}
+	return options
+}
+
+// storedOptions converts options to be stored with a session
+func storedOptions(options *ollama.Options) *store.GenerationOptions {
+	if options == nil {
+		return nil
+	}
+	stored := store.GenerationOptions(*options)
+	return &stored
+}
+
+// sessionOptions converts the options stored with session for Ollama
+func sessionOptions(session *SessionInfo) *ollama.Options {
+	if session.Options == nil {
+		return nil
+	}
+	options := ollama.Options(*session.Options)
+	return &options
+}
+
+func intPointer(value int32) *int {
+	converted := int(value)
+	return &converted
+}
+
+// badRequest builds the InvalidArgument error of an invalid request, naming
+// the first violation in its message and listing all of them in a BadRequest
+// detail
+func badRequest(what string, violations []*errdetails.BadRequest_FieldViolation) error {
+	st := status.Newf(codes.InvalidArgument, "invalid %s: %s %s", what, violations[0].Field, violations[0].Description)
+	detailed, err := st.WithDetails(&errdetails.BadRequest{FieldViolations: violations})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
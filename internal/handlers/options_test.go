@@ -0,0 +1,158 @@
+package handlers
+
+import (
+	"slices"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/proto"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// violatedFields returns the fields of the BadRequest detail of err
+func violatedFields(t *testing.T, err error) []string {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.InvalidArgument {
+		t.Fatalf("code = %v (%v), want InvalidArgument", st.Code(), err)
+	}
+	var fields []string
+	for _, detail := range st.Details() {
+		if badRequest, ok := detail.(*errdetails.BadRequest); ok {
+			for _, violation := range badRequest.FieldViolations {
+				fields = append(fields, violation.Field)
+			}
+		}
+	}
+	return fields
+}
+
+func TestGenerationOptionsRanges(t *testing.T) {
+	tests := []struct {
+		name    string
+		options *mcpv1.GenerationOptions
+		fields  []string // violated, none when valid
+	}{
+		{"valid", &mcpv1.GenerationOptions{
+			Temperature: proto.Float64(2), TopP: proto.Float64(0), TopK: proto.Int32(1000),
+			NumCtx: proto.Int32(4096), MaxTokens: proto.Int32(1), Stop: []string{"\n\n"},
+		}, nil},
+		{"temperature", &mcpv1.GenerationOptions{Temperature: proto.Float64(2.5)}, []string{"options.temperature"}},
+		{"top_p", &mcpv1.GenerationOptions{TopP: proto.Float64(-0.1)}, []string{"options.top_p"}},
+		{"top_k", &mcpv1.GenerationOptions{TopK: proto.Int32(0)}, []string{"options.top_k"}},
+		{"num_ctx", &mcpv1.GenerationOptions{NumCtx: proto.Int32(maxContext + 1)}, []string{"options.num_ctx"}},
+		{"max_tokens", &mcpv1.GenerationOptions{MaxTokens: proto.Int32(-1)}, []string{"options.max_tokens"}},
+		{"too many stops", &mcpv1.GenerationOptions{Stop: slices.Repeat([]string{"x"}, maxStops+1)}, []string{"options.stop"}},
+		{"empty stop", &mcpv1.GenerationOptions{Stop: []string{""}}, []string{"options.stop"}},
+		{"long stop", &mcpv1.GenerationOptions{Stop: []string{strings.Repeat("x", maxStopLength+1)}}, []string{"options.stop"}},
+		{"every violation is listed", &mcpv1.GenerationOptions{Temperature: proto.Float64(3), TopK: proto.Int32(1001)},
+			[]string{"options.temperature", "options.top_k"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			converted, err := generationOptions(tt.options, nil)
+			if tt.fields == nil {
+				if err != nil {
+					t.Fatalf("generationOptions: %v", err)
+				}
+				if *converted.TopK != 1000 || *converted.NumCtx != 4096 || *converted.NumPredict != 1 {
+					t.Errorf("converted = %+v, want max_tokens as num_predict", converted)
+				}
+				return
+			}
+			if fields := violatedFields(t, err); !slices.Equal(fields, tt.fields) {
+				t.Errorf("violations = %v, want %v", fields, tt.fields)
+			}
+		})
+	}
+
+	if converted, err := generationOptions(nil, nil); converted != nil || err != nil {
+		t.Errorf("generationOptions(nil) = %+v, %v, want no options", converted, err)
+	}
+}
+
+func TestGenerationOptionsTenantCaps(t *testing.T) {
+	maxTokens, maxContext := 256, 2048
+	settings := &tenant.Tenant{ID: "acme", Generation: tenant.Generation{MaxTokens: &maxTokens, MaxContext: &maxContext}}
+
+	tests := []struct {
+		name    string
+		options *mcpv1.GenerationOptions
+		fields  []string
+	}{
+		{"within the caps", &mcpv1.GenerationOptions{MaxTokens: proto.Int32(256), NumCtx: proto.Int32(2048)}, nil},
+		{"max_tokens", &mcpv1.GenerationOptions{MaxTokens: proto.Int32(257)}, []string{"options.max_tokens"}},
+		{"num_ctx", &mcpv1.GenerationOptions{NumCtx: proto.Int32(4096)}, []string{"options.num_ctx"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := generationOptions(tt.options, settings)
+			if tt.fields == nil {
+				if err != nil {
+					t.Errorf("generationOptions: %v", err)
+				}
+				return
+			}
+			if fields := violatedFields(t, err); !slices.Equal(fields, tt.fields) {
+				t.Errorf("violations = %v, want %v", fields, tt.fields)
+			}
+			if !strings.Contains(status.Convert(err).Message(), "tenant acme") {
+				t.Errorf("message = %q, want it to name the tenant", status.Convert(err).Message())
+			}
+		})
+	}
+
+	// Without caps the same options are accepted
+	uncapped := &tenant.Tenant{ID: "acme", Generation: tenant.Generation{MaxTokens: new(int)}}
+	if _, err := generationOptions(&mcpv1.GenerationOptions{MaxTokens: proto.Int32(4096)}, uncapped); err != nil {
+		t.Errorf("generationOptions without caps: %v", err)
+	}
+}
+
+func TestRequestOptions(t *testing.T) {
+	maxTokens := 256
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{
+		{ID: "acme", Generation: tenant.Generation{MaxTokens: &maxTokens}},
+	})
+	server := NewAgentServer(AgentConfig{Sessions: stubSessions{}, Tenants: tenants})
+
+	// Sessions keep the options of Register, possibly from before the caps
+	// were lowered
+	numPredict, numCtx := 1024, 8192
+	session := &SessionInfo{SessionID: "session-1", TenantID: "acme", Options: &store.GenerationOptions{
+		Temperature: proto.Float64(0.2),
+		NumPredict:  &numPredict,
+		NumCtx:      &numCtx,
+		Stop:        []string{"END"},
+	}}
+
+	options, err := server.requestOptions(session, &mcpv1.GenerationOptions{Temperature: proto.Float64(0.9)})
+	if err != nil {
+		t.Fatalf("requestOptions: %v", err)
+	}
+	if *options.Temperature != 0.9 || *options.NumCtx != 8192 || !slices.Equal(options.Stop, []string{"END"}) {
+		t.Errorf("options = %+v, want the session options overridden by the request", options)
+	}
+	if *options.NumPredict != 256 {
+		t.Errorf("num_predict = %d, want it clamped to the tenant cap", *options.NumPredict)
+	}
+	if *session.Options.NumPredict != 1024 || *session.Options.Temperature != 0.2 {
+		t.Errorf("session options = %+v, a request must not change them", session.Options)
+	}
+
+	// Requests without options get the cap too
+	options, err = server.requestOptions(&SessionInfo{TenantID: "acme"}, nil)
+	if err != nil || options.NumPredict == nil || *options.NumPredict != 256 {
+		t.Errorf("requestOptions = %+v, %v, want the cap as num_predict", options, err)
+	}
+
+	if _, err := server.requestOptions(session, &mcpv1.GenerationOptions{MaxTokens: proto.Int32(512)}); status.Code(err) != codes.InvalidArgument {
+		t.Errorf("requestOptions over the cap = %v, want InvalidArgument", err)
+	}
+}
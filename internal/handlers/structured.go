@@ -0,0 +1,122 @@
+package handlers
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/schema"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// repairPrompt sends an invalid answer back to the model with the reasons
+const repairPrompt = "Your answer does not match the required JSON schema:\n%s\n\nReply again with only the corrected JSON."
+
+// StructuredOutputConfig controls the JSON answers requested with a
+// ResponseFormat
+type StructuredOutputConfig struct {
+	MaxRepairs int // times an invalid answer is sent back for repair
+}
+
+// responseSchema compiles the schema of a response format, nil when the
+// request does not ask for JSON
+func responseSchema(format *mcpv1.ResponseFormat) (*schema.Schema, error) {
+	if format == nil {
+		return nil, nil
+	}
+	compiled, err := schema.Compile(format.JsonSchema)
+	if err != nil {
+		return nil, badRequest("response format", []*errdetails.BadRequest_FieldViolation{{
+			Field:       "response_format.json_schema",
+			Description: err.Error(),
+		}})
+	}
+	return compiled, nil
+}
+
+// complete runs a non-streaming generation and counts its tokens. With a
+// schema Ollama is constrained to it, and answers that still do not match are
+// sent back to the model for repair until one does or the repairs run out.
+// The response then holds the valid JSON, or the tool calls the model asks
+// for instead, and the metrics of every attempt.
+func (s *AgentServer) complete(ctx context.Context, session *SessionInfo, req ollama.ChatRequest, answerSchema *schema.Schema) (*ollama.ChatResponse, error) {
+	if answerSchema == nil {
+		response, err := s.ollamaClient.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		s.recordTokens(session, response)
+		return response, nil
+	}
+
+	s.settingsMutex.RLock()
+	maxRepairs := s.settings.StructuredOutput.MaxRepairs
+	s.settingsMutex.RUnlock()
+
+	req.Format = answerSchema.Format()
+	var metrics ollama.Metrics
+	for attempt := 0; ; attempt++ {
+		response, err := s.ollamaClient.Chat(ctx, req)
+		if err != nil {
+			return nil, err
+		}
+		s.recordTokens(session, response)
+		metrics.Add(response.Metrics)
+
+		// Tool calls are no answer yet: the tool loop runs them first
+		if len(req.Tools) > 0 && len(response.Message.ToolCalls) > 0 {
+			response.Metrics = metrics
+			return response, nil
+		}
+
+		answer, err := answerSchema.Validate(response.Message.Content)
+		if err == nil {
+			response.Message.Content = answer
+			response.Metrics = metrics
+			return response, nil
+		}
+		var invalid *schema.ValidationError
+		if !errors.As(err, &invalid) {
+			return nil, err
+		}
+		if attempt == maxRepairs {
+			return nil, schemaFailure(invalid, attempt+1)
+		}
+
+		log.Printf("🔧 Answer for session %s does not match its schema, asking for a repair (%d/%d)", session.SessionID, attempt+1, maxRepairs)
+		req.Messages = append(req.Messages,
+			ollama.Message{Role: ollama.RoleAssistant, Content: response.Message.Content},
+			ollama.Message{Role: ollama.RoleUser, Content: fmt.Sprintf(repairPrompt, invalid)},
+		)
+	}
+}
+
+// schemaFailure builds the FailedPrecondition error of an answer that never
+// matched its schema, with a PreconditionFailure detail per violation
+func schemaFailure(invalid *schema.ValidationError, attempts int) error {
+	violations := make([]*errdetails.PreconditionFailure_Violation, len(invalid.Violations))
+	for i, violation := range invalid.Violations {
+		subject := violation.Location
+		if subject == "" {
+			subject = "/"
+		}
+		violations[i] = &errdetails.PreconditionFailure_Violation{
+			Type:        "SCHEMA",
+			Subject:     subject,
+			Description: violation.Description,
+		}
+	}
+
+	st := status.Newf(codes.FailedPrecondition, "answer does not match the JSON schema after %d attempts: %s", attempts, invalid.Violations[0])
+	detailed, err := st.WithDetails(&errdetails.PreconditionFailure{Violations: violations})
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,126 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"strings"
+	"testing"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+const personSchema = `{
+	"type": "object",
+	"properties": {"name": {"type": "string"}, "age": {"type": "integer"}},
+	"required": ["name", "age"]
+}`
+
+func newStructuredServer(ollamaURL string, maxRepairs int) *AgentServer {
+	return NewAgentServer(AgentConfig{
+		OllamaBaseURL: ollamaURL,
+		Sessions:      stubSessions{},
+		Settings: ChatSettings{
+			StructuredOutput: StructuredOutputConfig{MaxRepairs: maxRepairs},
+		},
+	})
+}
+
+func structuredRequest() *mcpv1.SingleChatRequest {
+	return &mcpv1.SingleChatRequest{
+		SessionId:      "session-1",
+		Content:        "Who wrote this?",
+		ResponseFormat: &mcpv1.ResponseFormat{JsonSchema: personSchema},
+	}
+}
+
+func TestStructuredOutputValidAnswer(t *testing.T) {
+	stub := newStubOllama(t, `{"name": "Ada", "age": 36}`)
+	server := newStructuredServer(stub.URL, 2)
+
+	response, err := server.SingleChat(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("SingleChat: %v", err)
+	}
+
+	var person struct {
+		Name string `json:"name"`
+		Age  int    `json:"age"`
+	}
+	if err := json.Unmarshal([]byte(response.Content), &person); err != nil || person.Name != "Ada" || person.Age != 36 {
+		t.Errorf("answer = %s (%v)", response.Content, err)
+	}
+
+	requests := stub.received()
+	if len(requests) != 1 {
+		t.Fatalf("Ollama was called %d times, want 1", len(requests))
+	}
+	if len(requests[0].Format) == 0 || !strings.Contains(string(requests[0].Format), `"required"`) {
+		t.Errorf("request format = %s, want the schema", requests[0].Format)
+	}
+}
+
+func TestStructuredOutputRepairedAnswer(t *testing.T) {
+	stub := newStubOllama(t, `{"name": "Ada"}`, `{"name": "Ada", "age": 36}`)
+	server := newStructuredServer(stub.URL, 2)
+
+	response, err := server.SingleChat(context.Background(), structuredRequest())
+	if err != nil {
+		t.Fatalf("SingleChat: %v", err)
+	}
+	if !strings.Contains(response.Content, `"age"`) {
+		t.Errorf("answer = %s, want the repaired one", response.Content)
+	}
+
+	// Both attempts are counted in the usage
+	if response.Usage.PromptTokens != 20 || response.Usage.CompletionTokens != 10 {
+		t.Errorf("usage = %d prompt, %d completion tokens, want 20 and 10", response.Usage.PromptTokens, response.Usage.CompletionTokens)
+	}
+
+	// The repair request carries the invalid answer and why it is invalid
+	requests := stub.received()
+	if len(requests) != 2 {
+		t.Fatalf("Ollama was called %d times, want 2", len(requests))
+	}
+	messages := requests[1].Messages
+	if len(messages) != 3 {
+		t.Fatalf("repair request has %d messages, want 3", len(messages))
+	}
+	if messages[1].Role != ollama.RoleAssistant || messages[1].Content != `{"name": "Ada"}` {
+		t.Errorf("repair request does not echo the invalid answer: %+v", messages[1])
+	}
+	if messages[2].Role != ollama.RoleUser || !strings.Contains(messages[2].Content, "age") {
+		t.Errorf("repair prompt does not name the missing property: %q", messages[2].Content)
+	}
+}
+
+func TestStructuredOutputRepairsExhausted(t *testing.T) {
+	stub := newStubOllama(t, `not json`, `{"name": 42, "age": 36}`)
+	server := newStructuredServer(stub.URL, 1)
+
+	_, err := server.SingleChat(context.Background(), structuredRequest())
+	st, ok := status.FromError(err)
+	if !ok || st.Code() != codes.FailedPrecondition {
+		t.Fatalf("SingleChat error = %v, want FailedPrecondition", err)
+	}
+	if calls := len(stub.received()); calls != 2 {
+		t.Errorf("Ollama was called %d times, want 2", calls)
+	}
+
+	var failure *errdetails.PreconditionFailure
+	for _, detail := range st.Details() {
+		if f, ok := detail.(*errdetails.PreconditionFailure); ok {
+			failure = f
+		}
+	}
+	if failure == nil || len(failure.Violations) == 0 {
+		t.Fatalf("error has no PreconditionFailure detail: %v", st.Details())
+	}
+	if violation := failure.Violations[0]; violation.Type != "SCHEMA" || violation.Subject != "/name" {
+		t.Errorf("violation = %+v, want a SCHEMA violation of /name", violation)
+	}
+}
@@ -0,0 +1,204 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"slices"
+	"time"
+
+	"google.golang.org/protobuf/types/known/timestamppb"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// ToolsConfig controls the tools offered to the model
+type ToolsConfig struct {
+	Enabled       []string      // tools of the registry offered to every tenant (none disables them)
+	MaxRounds     int           // rounds of tool calls per answer, after which the model must answer
+	Timeout       time.Duration // bounds every call of a gateway tool (0 = no limit)
+	ClientTimeout time.Duration // bounds the wait for the result of an agent tool (0 = no limit)
+}
+
+// toolLoop answers req letting the model call tools: while its response
+// asks for tool calls, they are run and their results fed back for another
+// round. Besides the tools of the gateway offered to the tenant, the model
+// gets those the agent declared on its stream (nil for none). generate runs
+// one round, and notify, when set, gets a TOOL_CALL message before each call
+// and a TOOL_RESULT message after the gateway ran it.
+//
+// It returns the final response, with the metrics of every round, and the
+// tool calls and results that led to it, to be kept in the history.
+func (s *AgentServer) toolLoop(ctx context.Context, session *SessionInfo, req ollama.ChatRequest, agentTools *clientTools, generate func(ollama.ChatRequest) (*ollama.ChatResponse, error), notify func(*mcpv1.ChatMessage) error) (*ollama.ChatResponse, []ollama.Message, error) {
+	s.settingsMutex.RLock()
+	settings := s.settings.Tools
+	s.settingsMutex.RUnlock()
+
+	offered := s.offeredTools(session.TenantID)
+	var definitions []ollama.Tool
+	if s.tools != nil {
+		definitions = s.tools.Definitions(offered)
+	}
+	if agentTools != nil {
+		definitions = append(definitions, agentTools.definitions...)
+	}
+
+	var metrics ollama.Metrics
+	var transcript []ollama.Message
+	for round := 0; ; round++ {
+		// Out of rounds, the tools are withdrawn so the model has to answer
+		req.Tools = definitions
+		if round >= settings.MaxRounds {
+			req.Tools = nil
+		}
+
+		response, err := generate(req)
+		if err != nil {
+			return nil, nil, err
+		}
+		metrics.Add(response.Metrics)
+
+		calls := response.Message.ToolCalls
+		if len(calls) == 0 || req.Tools == nil {
+			response.Message.ToolCalls = nil
+			response.Metrics = metrics
+			return response, transcript, nil
+		}
+
+		messages := []ollama.Message{{Role: ollama.RoleAssistant, Content: response.Message.Content, ToolCalls: calls}}
+		for _, call := range calls {
+			result, err := s.callTool(ctx, session, offered, agentTools, call, notify)
+			if err != nil {
+				return nil, nil, err
+			}
+			messages = append(messages, result)
+		}
+		req.Messages = append(req.Messages, messages...)
+		transcript = append(transcript, messages...)
+	}
+}
+
+// callTool runs one tool call of the model and returns the tool message with
+// its result. Only the offered tools of the gateway run. A failing tool is
+// not an error of the request: the model gets the error as the result and
+// can try something else. The returned error is that of notify, or of ctx
+// while waiting for an agent.
+func (s *AgentServer) callTool(ctx context.Context, session *SessionInfo, offered []string, agentTools *clientTools, call ollama.ToolCall, notify func(*mcpv1.ChatMessage) error) (ollama.Message, error) {
+	s.settingsMutex.RLock()
+	settings := s.settings.Tools
+	s.settingsMutex.RUnlock()
+
+	callID := generateMessageID()
+	name := call.Function.Name
+	log.Printf("🛠️  Session %s calls tool %s with %s", session.SessionID, name, call.Function.Arguments)
+
+	announce := func(arguments string) error {
+		if notify == nil {
+			return nil
+		}
+		return notify(&mcpv1.ChatMessage{
+			MessageId: callID,
+			SessionId: session.SessionID,
+			Type:      mcpv1.MessageType_MESSAGE_TYPE_TOOL_CALL,
+			Timestamp: timestamppb.New(time.Now()),
+			ToolCall: &mcpv1.ToolCall{
+				Id:        callID,
+				Name:      name,
+				Arguments: arguments,
+			},
+		})
+	}
+
+	// Tools of the agent run on its side, which sends the result back
+	if agentTools.has(name) {
+		content, err := agentTools.call(ctx, callID, call, settings.ClientTimeout, announce)
+		if err != nil {
+			return ollama.Message{}, err
+		}
+		return ollama.Message{Role: ollama.RoleTool, Content: content, ToolName: name}, nil
+	}
+
+	if err := announce(string(call.Function.Arguments)); err != nil {
+		return ollama.Message{}, err
+	}
+
+	var content string
+	var err error
+	if s.tools != nil && slices.Contains(offered, name) {
+		content, err = s.runTool(ctx, name, call.Function.Arguments)
+	} else {
+		err = fmt.Errorf("%w: %s", tools.ErrUnknownTool, name)
+	}
+	failed := err != nil
+	if failed {
+		log.Printf("⚠️  Tool %s failed for session %s: %v", name, session.SessionID, err)
+		content = "Error: " + err.Error()
+	}
+
+	if notify != nil {
+		err := notify(&mcpv1.ChatMessage{
+			MessageId: callID,
+			SessionId: session.SessionID,
+			Content:   content,
+			Type:      mcpv1.MessageType_MESSAGE_TYPE_TOOL_RESULT,
+			Timestamp: timestamppb.New(time.Now()),
+			ToolResult: &mcpv1.ToolResult{
+				Id:      callID,
+				Name:    name,
+				Content: content,
+				IsError: failed,
+			},
+		})
+		if err != nil {
+			return ollama.Message{}, err
+		}
+	}
+
+	return ollama.Message{Role: ollama.RoleTool, Content: content, ToolName: name}, nil
+}
+
+// offeredTools returns the tools of the registry offered to the model in the
+// sessions of a tenant: the enabled ones, and those of MCP servers the
+// tenant allows. MCP tools are only offered to the tenants that allow them,
+// even when enabled.
+func (s *AgentServer) offeredTools(tenantID string) []string {
+	s.settingsMutex.RLock()
+	var offered []string
+	for _, name := range s.settings.Tools.Enabled {
+		if !slices.Contains(s.mcpTools, name) {
+			offered = append(offered, name)
+		}
+	}
+	s.settingsMutex.RUnlock()
+
+	if len(s.mcpTools) == 0 || s.tenants == nil {
+		return offered
+	}
+	settings, err := s.tenants.Get(tenantID)
+	if err != nil {
+		return offered
+	}
+	for _, name := range s.mcpTools {
+		if settings.AllowsMCPTool(name) && !slices.Contains(offered, name) {
+			offered = append(offered, name)
+		}
+	}
+	return offered
+}
+
+// runTool runs a tool of the registry within the configured timeout
+func (s *AgentServer) runTool(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+	s.settingsMutex.RLock()
+	timeout := s.settings.Tools.Timeout
+	s.settingsMutex.RUnlock()
+
+	if timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, timeout)
+		defer cancel()
+	}
+	return s.tools.Call(ctx, name, arguments)
+}
@@ -0,0 +1,192 @@
+package handlers
+
+import (
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"slices"
+	"testing"
+	"time"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/mcp"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// mcpBackend offers a few tools over MCP that answer with their name
+type mcpBackend struct{}
+
+func (mcpBackend) ListTools(ctx context.Context) ([]mcp.Tool, error) {
+	var list []mcp.Tool
+	for _, name := range []string{"echo", "search", "delete"} {
+		list = append(list, mcp.Tool{Name: name, Description: "The " + name + " tool", InputSchema: json.RawMessage(`{"type":"object"}`)})
+	}
+	return list, nil
+}
+
+func (mcpBackend) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*mcp.CallToolResult, error) {
+	return &mcp.CallToolResult{Content: []mcp.Content{mcp.TextContent(name)}}, nil
+}
+
+func (mcpBackend) ListPrompts(ctx context.Context) ([]mcp.Prompt, error) { return nil, nil }
+
+func (mcpBackend) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*mcp.GetPromptResult, error) {
+	return nil, mcp.NewError(mcp.CodeInvalidParams, "unknown prompt %q", name)
+}
+
+func (mcpBackend) ListResources(ctx context.Context) ([]mcp.Resource, error) { return nil, nil }
+
+func (mcpBackend) ReadResource(ctx context.Context, uri string) ([]mcp.ResourceContents, error) {
+	return nil, mcp.NewError(mcp.CodeResourceNotFound, "resource %s not found", uri)
+}
+
+// bridgedRegistry bridges the tools of mcpBackend, served over HTTP, into a
+// registry holding a gateway tool too, and returns the names of the bridged
+// ones
+func bridgedRegistry(t *testing.T) (*tools.Registry, []string) {
+	t.Helper()
+	server := mcp.NewServer(mcp.Config{Info: mcp.Implementation{Name: "remote", Version: "1.0.0"}, Backend: mcpBackend{}})
+	httpServer := httptest.NewServer(server.HTTPHandler(mcp.HTTPConfig{
+		Authenticate: func(r *http.Request) (context.Context, string, error) {
+			return r.Context(), "gateway", nil
+		},
+	}))
+	t.Cleanup(httpServer.Close)
+
+	bridge := mcp.ConnectBridge(context.Background(), []mcp.ServerConfig{{
+		ClientConfig: mcp.ClientConfig{Name: "remote", URL: httpServer.URL},
+		Timeout:      5 * time.Second,
+	}})
+	t.Cleanup(bridge.Close)
+
+	registry, err := tools.NewRegistry(tools.Tool{
+		Name:       "clock",
+		Parameters: json.RawMessage(`{"type":"object"}`),
+		Handler:    func(context.Context, json.RawMessage) (string, error) { return "noon", nil },
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	var names []string
+	for _, tool := range bridge.Tools() {
+		if err := registry.Register(tool); err != nil {
+			t.Fatal(err)
+		}
+		names = append(names, tool.Name)
+	}
+	if len(names) != 3 {
+		t.Fatalf("bridged tools = %v, want the 3 of the backend", names)
+	}
+	return registry, names
+}
+
+func TestOfferedToolsFollowTenantAllowlist(t *testing.T) {
+	registry, mcpTools := bridgedRegistry(t)
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{
+		{ID: "acme", MCPTools: []string{"remote_echo", "remote_s*"}},
+		{ID: "other"},
+	})
+	server := NewAgentServer(AgentConfig{
+		Sessions: stubSessions{},
+		Tenants:  tenants,
+		Tools:    registry,
+		MCPTools: mcpTools,
+		Settings: ChatSettings{Tools: ToolsConfig{Enabled: []string{"clock"}, MaxRounds: 1}},
+	})
+
+	tests := []struct {
+		tenantID string
+		want     []string
+	}{
+		{"acme", []string{"clock", "remote_echo", "remote_search"}},
+		{"other", []string{"clock"}},
+		{"unknown", []string{"clock"}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenantID, func(t *testing.T) {
+			offered := server.offeredTools(tt.tenantID)
+			slices.Sort(offered)
+			if !slices.Equal(offered, tt.want) {
+				t.Errorf("offered tools = %v, want %v", offered, tt.want)
+			}
+		})
+	}
+}
+
+func TestDisallowedToolsHiddenFromModel(t *testing.T) {
+	registry, mcpTools := bridgedRegistry(t)
+	stub := newStubOllama(t, "Done.")
+	server := NewAgentServer(AgentConfig{
+		OllamaBaseURL: stub.URL,
+		Sessions:      stubSessions{},
+		Tenants:       tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", MCPTools: []string{"remote_echo"}}}),
+		Tools:         registry,
+		MCPTools:      mcpTools,
+		Settings:      ChatSettings{Tools: ToolsConfig{MaxRounds: 1}},
+	})
+
+	_, err := server.SingleChat(context.Background(), &mcpv1.SingleChatRequest{SessionId: "session-1", Content: "Clean up"})
+	if err != nil {
+		t.Fatalf("SingleChat: %v", err)
+	}
+	requests := stub.received()
+	if len(requests) != 1 {
+		t.Fatalf("Ollama was called %d times, want 1", len(requests))
+	}
+	var offered []string
+	for _, tool := range requests[0].Tools {
+		offered = append(offered, tool.Function.Name)
+	}
+	if !slices.Equal(offered, []string{"remote_echo"}) {
+		t.Errorf("tools offered to the model = %v, want [remote_echo]", offered)
+	}
+}
+
+func TestEnabledMCPToolsFollowTenantAllowlist(t *testing.T) {
+	registry, mcpTools := bridgedRegistry(t)
+	server := NewAgentServer(AgentConfig{
+		Sessions: stubSessions{},
+		Tenants: tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{
+			{ID: "acme", MCPTools: []string{"remote_delete"}},
+			{ID: "other"},
+		}),
+		Tools:    registry,
+		MCPTools: mcpTools,
+		// Enabling an MCP tool does not offer it to every tenant
+		Settings: ChatSettings{Tools: ToolsConfig{Enabled: []string{"clock", "remote_delete"}, MaxRounds: 1}},
+	})
+	backend := NewMCPBackend(server, nil)
+
+	tests := []struct {
+		tenantID string
+		allowed  bool
+	}{
+		{"acme", true},
+		{"other", false},
+	}
+	for _, tt := range tests {
+		t.Run(tt.tenantID, func(t *testing.T) {
+			if offered := server.offeredTools(tt.tenantID); slices.Contains(offered, "remote_delete") != tt.allowed {
+				t.Errorf("offered tools = %v, want remote_delete offered %v", offered, tt.allowed)
+			}
+
+			ctx := auth.NewContext(context.Background(), &auth.Principal{TenantID: tt.tenantID, AgentID: "agent-1", SessionID: "session-1"})
+			list, err := backend.ListTools(ctx)
+			if err != nil {
+				t.Fatalf("ListTools: %v", err)
+			}
+			listed := slices.ContainsFunc(list, func(tool mcp.Tool) bool { return tool.Name == "remote_delete" })
+			if listed != tt.allowed {
+				t.Errorf("tools/list lists remote_delete = %v, want %v", listed, tt.allowed)
+			}
+
+			result, err := backend.CallTool(ctx, "remote_delete", json.RawMessage(`{}`))
+			if called := err == nil && !result.IsError; called != tt.allowed {
+				t.Errorf("tools/call of remote_delete = %+v, %v, want it called %v", result, err, tt.allowed)
+			}
+		})
+	}
+}
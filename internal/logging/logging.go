@@ -0,0 +1,123 @@
+// Package logging filters the log output of the gateway by level. Code keeps
+// logging with the standard log package, and the level of a message is told
+// by the emoji it starts with: ❌ for errors, ⚠️, 🚨 and ⏰ for warnings and
+// anything else for information. Debug messages go through Debugf.
+package logging
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"strings"
+	"sync/atomic"
+	"time"
+)
+
+// Level is the minimum severity of the messages written
+type Level int32
+
+const (
+	LevelDebug Level = iota
+	LevelInfo
+	LevelWarn
+	LevelError
+)
+
+var levelNames = map[Level]string{
+	LevelDebug: "debug",
+	LevelInfo:  "info",
+	LevelWarn:  "warn",
+	LevelError: "error",
+}
+
+func (l Level) String() string {
+	if name, ok := levelNames[l]; ok {
+		return name
+	}
+	return fmt.Sprintf("Level(%d)", int32(l))
+}
+
+// ParseLevel parses debug, info, warn (or warning) and error
+func ParseLevel(name string) (Level, error) {
+	switch strings.ToLower(name) {
+	case "debug":
+		return LevelDebug, nil
+	case "", "info":
+		return LevelInfo, nil
+	case "warn", "warning":
+		return LevelWarn, nil
+	case "error":
+		return LevelError, nil
+	}
+	return LevelInfo, fmt.Errorf("unknown log level %q (use debug, info, warn or error)", name)
+}
+
+var level atomic.Int32
+
+func init() {
+	level.Store(int32(LevelInfo))
+}
+
+// SetLevel changes the level of the messages written from now on. It is safe
+// to call while other goroutines log.
+func SetLevel(l Level) {
+	level.Store(int32(l))
+}
+
+// Enabled reports whether messages of level l are written
+func Enabled(l Level) bool {
+	return l >= Level(level.Load())
+}
+
+// Setup sends the standard logger to out through the level filter. The
+// filter writes the timestamp itself, so the standard logger's flags are
+// cleared.
+func Setup(out io.Writer) {
+	log.SetFlags(0)
+	log.SetOutput(&filter{out: out})
+}
+
+// Debugf logs a message only at the debug level
+func Debugf(format string, args ...any) {
+	if Enabled(LevelDebug) {
+		log.Output(2, fmt.Sprintf(format, args...))
+	}
+}
+
+// filter drops the messages below the current level
+type filter struct {
+	out io.Writer
+}
+
+var (
+	errorMarkers   = [][]byte{[]byte("❌")}
+	warningMarkers = [][]byte{[]byte("⚠️"), []byte("🚨"), []byte("⏰")}
+)
+
+func (f *filter) Write(p []byte) (int, error) {
+	if !Enabled(levelOf(p)) {
+		return len(p), nil
+	}
+	line := time.Now().AppendFormat(make([]byte, 0, 20+len(p)), "2006/01/02 15:04:05 ")
+	if _, err := f.out.Write(append(line, p...)); err != nil {
+		return 0, err
+	}
+	return len(p), nil
+}
+
+// levelOf tells the level of a message by its leading emoji
+func levelOf(message []byte) Level {
+	message = bytes.TrimLeft(message, " ")
+	for _, marker := range errorMarkers {
+		if bytes.HasPrefix(message, marker) {
+			return LevelError
+		}
+	}
+	for _, marker := range warningMarkers {
+		if bytes.HasPrefix(message, marker) {
+			return LevelWarn
+		}
+	}
+	return LevelInfo
+}
@@ -0,0 +1,230 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"path"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+)
+
+// ServerConfig is an external MCP server whose tools the model can call
+type ServerConfig struct {
+	ClientConfig
+	// Timeout bounds connecting to the server and listing its tools
+	// (0 = 30 seconds)
+	Timeout time.Duration
+	// Tools names the tools of the server to bridge, or patterns such as
+	// read_*; empty bridges them all
+	Tools []string
+}
+
+// Bridge offers the tools of external MCP servers as tools of the gateway.
+// Each one is named after its server: the read_file tool of the files server
+// becomes files_read_file. A server that goes away is connected again on
+// the next call of one of its tools.
+type Bridge struct {
+	servers []*bridgedServer
+}
+
+// bridgedServer is the connection to one server, and the tools it listed
+type bridgedServer struct {
+	config ServerConfig
+	tools  []Tool
+
+	mutex  sync.Mutex
+	client *Client
+	closed bool
+}
+
+// ConnectBridge connects to every server and lists its tools. Servers that
+// cannot be reached are logged and left out, so the gateway works without
+// them.
+func ConnectBridge(ctx context.Context, servers []ServerConfig) *Bridge {
+	connected := make([]*bridgedServer, len(servers))
+	var wait sync.WaitGroup
+	for i, config := range servers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			server := &bridgedServer{config: config}
+			if err := server.start(ctx); err != nil {
+				log.Printf("❌ MCP server %s unavailable: %v", config.Name, err)
+				return
+			}
+			connected[i] = server
+		}()
+	}
+	wait.Wait()
+
+	bridge := &Bridge{}
+	for _, server := range connected {
+		if server != nil {
+			bridge.servers = append(bridge.servers, server)
+		}
+	}
+	return bridge
+}
+
+// start connects to the server and lists the tools to bridge
+func (s *bridgedServer) start(ctx context.Context) error {
+	ctx, cancel := context.WithTimeout(ctx, s.timeout())
+	defer cancel()
+
+	client, err := Connect(ctx, s.config.ClientConfig)
+	if err != nil {
+		return err
+	}
+	listed, err := client.ListTools(ctx)
+	if err != nil {
+		client.Close()
+		return fmt.Errorf("tools/list failed: %w", err)
+	}
+	for _, tool := range listed {
+		if s.bridges(tool.Name) {
+			s.tools = append(s.tools, tool)
+		}
+	}
+
+	info := client.ServerInfo()
+	log.Printf("🔌 Connected to MCP server %s (%s %s): %d of %d tools bridged", s.config.Name, info.Name, info.Version, len(s.tools), len(listed))
+	s.client = client
+	return nil
+}
+
+// bridges reports whether a tool of the server is to be bridged
+func (s *bridgedServer) bridges(name string) bool {
+	if len(s.config.Tools) == 0 {
+		return true
+	}
+	for _, pattern := range s.config.Tools {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+func (s *bridgedServer) timeout() time.Duration {
+	if s.config.Timeout > 0 {
+		return s.config.Timeout
+	}
+	return 30 * time.Second
+}
+
+// Tools returns the bridged tools, ready for a tools.Registry. Tools whose
+// bridged name models cannot handle are logged and left out.
+func (b *Bridge) Tools() []tools.Tool {
+	var bridged []tools.Tool
+	for _, server := range b.servers {
+		for _, tool := range server.tools {
+			name := server.config.Name + "_" + tool.Name
+			if err := tools.CheckName(name); err != nil {
+				log.Printf("⚠️  MCP server %s: tool %s left out: %v", server.config.Name, tool.Name, err)
+				continue
+			}
+			description := tool.Description
+			if description == "" {
+				description = fmt.Sprintf("Tool %s of the %s MCP server.", tool.Name, server.config.Name)
+			}
+			bridged = append(bridged, tools.Tool{
+				Name:        name,
+				Description: description,
+				Parameters:  tool.InputSchema,
+				Handler: func(ctx context.Context, arguments json.RawMessage) (string, error) {
+					return server.call(ctx, tool.Name, arguments)
+				},
+			})
+		}
+	}
+	return bridged
+}
+
+// call runs a tool of the server and returns its text for the model. A tool
+// that fails returns its text as the error.
+func (s *bridgedServer) call(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+	client, err := s.connected(ctx)
+	if err != nil {
+		return "", err
+	}
+	result, err := client.CallTool(ctx, name, arguments)
+	if err != nil {
+		return "", fmt.Errorf("MCP server %s: %w", s.config.Name, err)
+	}
+
+	text := resultText(result)
+	if result.IsError {
+		if text == "" {
+			text = "the tool failed without telling why"
+		}
+		return "", errors.New(text)
+	}
+	return text, nil
+}
+
+// connected returns the client of the server, connecting again when the
+// connection ended. Calls are not retried: a call cut short may have run.
+func (s *bridgedServer) connected(ctx context.Context) (*Client, error) {
+	s.mutex.Lock()
+	defer s.mutex.Unlock()
+
+	if s.closed {
+		return nil, fmt.Errorf("MCP server %s: %w", s.config.Name, ErrClosed)
+	}
+	if s.client != nil {
+		if s.client.Err() == nil {
+			return s.client, nil
+		}
+		s.client.Close()
+		s.client = nil
+	}
+
+	log.Printf("🔄 Connecting to MCP server %s again", s.config.Name)
+	ctx, cancel := context.WithTimeout(ctx, s.timeout())
+	defer cancel()
+	client, err := Connect(ctx, s.config.ClientConfig)
+	if err != nil {
+		return nil, fmt.Errorf("MCP server %s unavailable: %w", s.config.Name, err)
+	}
+	s.client = client
+	return client, nil
+}
+
+// resultText joins the content of a tool result. The model only reads text,
+// so other content is only mentioned.
+func resultText(result *CallToolResult) string {
+	parts := make([]string, 0, len(result.Content))
+	for _, content := range result.Content {
+		if content.Type == "text" {
+			parts = append(parts, content.Text)
+		} else {
+			parts = append(parts, fmt.Sprintf("[%s content omitted]", content.Type))
+		}
+	}
+	return strings.Join(parts, "\n")
+}
+
+// Close disconnects from every server, stopping the processes of commands
+func (b *Bridge) Close() {
+	var wait sync.WaitGroup
+	for _, server := range b.servers {
+		wait.Add(1)
+		go func() {
+			defer wait.Done()
+			server.mutex.Lock()
+			defer server.mutex.Unlock()
+			server.closed = true
+			if server.client != nil {
+				server.client.Close()
+				server.client = nil
+			}
+		}()
+	}
+	wait.Wait()
+}
@@ -0,0 +1,244 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"slices"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tools"
+)
+
+// stubBinary is the mcp-stub command, built once for the tests
+var stubBinary string
+
+func TestMain(m *testing.M) {
+	dir, err := os.MkdirTemp("", "mcp-stub")
+	if err != nil {
+		fmt.Fprintln(os.Stderr, err)
+		os.Exit(1)
+	}
+	stubBinary = filepath.Join(dir, "mcp-stub")
+	build := exec.Command("go", "build", "-o", stubBinary, "github.com/Gentleman-Programming/gentleman-mcp/cmd/mcp-stub")
+	if output, err := build.CombinedOutput(); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to build mcp-stub: %v\n%s", err, output)
+		os.RemoveAll(dir)
+		os.Exit(1)
+	}
+
+	code := m.Run()
+	os.RemoveAll(dir)
+	os.Exit(code)
+}
+
+// stubServer is the mcp-stub command as a bridged server
+func stubServer(patterns ...string) ServerConfig {
+	return ServerConfig{
+		ClientConfig: ClientConfig{Name: "stub", Command: stubBinary},
+		Timeout:      10 * time.Second,
+		Tools:        patterns,
+	}
+}
+
+func connectBridge(t *testing.T, servers ...ServerConfig) *Bridge {
+	t.Helper()
+	bridge := ConnectBridge(context.Background(), servers)
+	t.Cleanup(bridge.Close)
+	return bridge
+}
+
+func toolNames(bridged []tools.Tool) []string {
+	names := make([]string, len(bridged))
+	for i, tool := range bridged {
+		names[i] = tool.Name
+	}
+	slices.Sort(names)
+	return names
+}
+
+func findTool(t *testing.T, bridged []tools.Tool, name string) tools.Tool {
+	t.Helper()
+	for _, tool := range bridged {
+		if tool.Name == name {
+			return tool
+		}
+	}
+	t.Fatalf("tool %s not bridged (got %v)", name, toolNames(bridged))
+	return tools.Tool{}
+}
+
+func TestBridgeFiltersAndPrefixesTools(t *testing.T) {
+	tests := []struct {
+		name     string
+		patterns []string
+		want     []string
+	}{
+		{"all", nil, []string{"stub_add", "stub_echo", "stub_fail", "stub_sleep"}},
+		{"by name", []string{"echo"}, []string{"stub_echo"}},
+		{"by pattern", []string{"e*", "a?d"}, []string{"stub_add", "stub_echo"}},
+		{"no match", []string{"missing"}, []string{}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			bridge := connectBridge(t, stubServer(tt.patterns...))
+			bridged := bridge.Tools()
+			if got := toolNames(bridged); !slices.Equal(got, tt.want) {
+				t.Errorf("bridged tools = %v, want %v", got, tt.want)
+			}
+			for _, tool := range bridged {
+				if len(tool.Parameters) == 0 || tool.Description == "" {
+					t.Errorf("tool %s lost its schema or description", tool.Name)
+				}
+			}
+		})
+	}
+}
+
+func TestBridgeCallsTools(t *testing.T) {
+	bridged := connectBridge(t, stubServer()).Tools()
+	ctx := context.Background()
+
+	text, err := findTool(t, bridged, "stub_echo").Handler(ctx, json.RawMessage(`{"text":"hello"}`))
+	if err != nil || text != "hello" {
+		t.Errorf("stub_echo = %q, %v, want hello", text, err)
+	}
+	text, err = findTool(t, bridged, "stub_add").Handler(ctx, json.RawMessage(`{"a":2,"b":3}`))
+	if err != nil || text != "5" {
+		t.Errorf("stub_add = %q, %v, want 5", text, err)
+	}
+
+	// Results flagged as errors become errors, with the text of the tool
+	_, err = findTool(t, bridged, "stub_fail").Handler(ctx, json.RawMessage(`{"message":"disk on fire"}`))
+	if err == nil || err.Error() != "disk on fire" {
+		t.Errorf("stub_fail error = %v, want the message of the tool", err)
+	}
+
+	// Protocol errors name the server
+	_, err = findTool(t, bridged, "stub_echo").Handler(ctx, json.RawMessage(`"not an object"`))
+	if err == nil || !strings.Contains(err.Error(), "stub") {
+		t.Errorf("invalid arguments error = %v, want an error naming the server", err)
+	}
+}
+
+func TestBridgeReconnectsAfterExit(t *testing.T) {
+	bridge := connectBridge(t, stubServer("echo"))
+	echo := findTool(t, bridge.Tools(), "stub_echo")
+	ctx := context.Background()
+
+	if _, err := echo.Handler(ctx, json.RawMessage(`{"text":"before"}`)); err != nil {
+		t.Fatalf("stub_echo: %v", err)
+	}
+
+	// Kill the process and wait until the client notices
+	server := bridge.servers[0]
+	server.mutex.Lock()
+	first := server.client
+	server.mutex.Unlock()
+	transport := first.transport.(*stdioClient)
+	if err := transport.cmd.Process.Kill(); err != nil {
+		t.Fatal(err)
+	}
+	select {
+	case <-transport.done():
+	case <-time.After(10 * time.Second):
+		t.Fatal("the client did not notice the process exited")
+	}
+
+	text, err := echo.Handler(ctx, json.RawMessage(`{"text":"after"}`))
+	if err != nil || text != "after" {
+		t.Fatalf("stub_echo after the exit = %q, %v, want after", text, err)
+	}
+	server.mutex.Lock()
+	reconnected := server.client != first
+	server.mutex.Unlock()
+	if !reconnected {
+		t.Error("the bridge kept the dead client")
+	}
+}
+
+func TestBridgeLeavesOutUnavailableServers(t *testing.T) {
+	missing := ServerConfig{
+		ClientConfig: ClientConfig{Name: "missing", Command: filepath.Join(t.TempDir(), "missing")},
+		Timeout:      5 * time.Second,
+	}
+	bridged := connectBridge(t, missing, stubServer("echo")).Tools()
+	if got := toolNames(bridged); !slices.Equal(got, []string{"stub_echo"}) {
+		t.Errorf("bridged tools = %v, want only those of the reachable server", got)
+	}
+}
+
+func TestBridgeClosed(t *testing.T) {
+	bridge := ConnectBridge(context.Background(), []ServerConfig{stubServer("echo")})
+	echo := findTool(t, bridge.Tools(), "stub_echo")
+	bridge.Close()
+
+	if _, err := echo.Handler(context.Background(), json.RawMessage(`{"text":"hi"}`)); err == nil {
+		t.Error("a closed bridge called a tool")
+	}
+}
+
+// httpBackend offers two tools over streamable HTTP that answer with their
+// name and arguments
+type httpBackend struct{}
+
+func (httpBackend) ListTools(ctx context.Context) ([]Tool, error) {
+	return []Tool{
+		{Name: "echo", InputSchema: json.RawMessage(`{"type":"object"}`)},
+		{Name: "secret", InputSchema: json.RawMessage(`{"type":"object"}`)},
+	}, nil
+}
+
+func (httpBackend) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	return &CallToolResult{Content: []Content{TextContent(name + " " + string(arguments))}}, nil
+}
+
+func (httpBackend) ListPrompts(ctx context.Context) ([]Prompt, error) { return nil, nil }
+
+func (httpBackend) GetPrompt(ctx context.Context, name string, arguments map[string]string) (*GetPromptResult, error) {
+	return nil, NewError(CodeInvalidParams, "unknown prompt %q", name)
+}
+
+func (httpBackend) ListResources(ctx context.Context) ([]Resource, error) { return nil, nil }
+
+func (httpBackend) ReadResource(ctx context.Context, uri string) ([]ResourceContents, error) {
+	return nil, NewError(CodeResourceNotFound, "resource %s not found", uri)
+}
+
+func TestBridgeOverHTTP(t *testing.T) {
+	server := NewServer(Config{Info: Implementation{Name: "http-test", Version: "1.0.0"}, Backend: httpBackend{}})
+	httpServer := httptest.NewServer(server.HTTPHandler(HTTPConfig{
+		Authenticate: func(r *http.Request) (context.Context, string, error) {
+			if r.Header.Get("Authorization") != "Bearer secret-token" {
+				return nil, "", fmt.Errorf("invalid token")
+			}
+			return r.Context(), "bridge", nil
+		},
+	}))
+	t.Cleanup(httpServer.Close)
+
+	bridged := connectBridge(t, ServerConfig{
+		ClientConfig: ClientConfig{
+			Name:    "remote",
+			URL:     httpServer.URL,
+			Headers: map[string]string{"Authorization": "Bearer secret-token"},
+		},
+		Timeout: 5 * time.Second,
+		Tools:   []string{"echo"},
+	}).Tools()
+
+	if got := toolNames(bridged); !slices.Equal(got, []string{"remote_echo"}) {
+		t.Fatalf("bridged tools = %v, want [remote_echo]", got)
+	}
+	text, err := bridged[0].Handler(context.Background(), json.RawMessage(`{"x":1}`))
+	if err != nil || text != `echo {"x":1}` {
+		t.Errorf("remote_echo = %q, %v", text, err)
+	}
+}
@@ -0,0 +1,202 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"sync/atomic"
+	"time"
+)
+
+// ErrClosed is returned for calls on a connection to a server that ended,
+// e.g. because its process exited or it forgot the session
+var ErrClosed = errors.New("connection to MCP server closed")
+
+// ClientConfig says how to reach an MCP server: a Command to launch and talk
+// to on stdio, or the URL of a streamable HTTP endpoint
+type ClientConfig struct {
+	Command string
+	Args    []string
+	Env     []string // KEY=value pairs added to the environment of the gateway
+	Dir     string   // working directory of the command (empty for the current one)
+
+	URL     string
+	Headers map[string]string // sent with every HTTP request, e.g. Authorization
+
+	// Name tells the server in the logs of the gateway
+	Name string
+	// Info is the client reported to the server
+	Info Implementation
+}
+
+// Client is a connection to an MCP server, used to list and call its tools.
+// It is safe for concurrent use.
+type Client struct {
+	name      string
+	transport clientTransport
+	nextID    atomic.Int64
+	server    InitializeResult
+}
+
+// clientTransport carries the messages of a Client
+type clientTransport interface {
+	// roundTrip sends a request and waits for its response
+	roundTrip(ctx context.Context, request *Request) (*clientResponse, error)
+	// notify sends a notification
+	notify(ctx context.Context, request *Request) error
+	// initialized tells the transport the protocol version of the session
+	initialized(version string)
+	// done is closed when the connection ends, and err tells why
+	done() <-chan struct{}
+	err() error
+	close() error
+}
+
+// clientResponse is a message from the server: the response to a request of
+// the client, or a request or notification of its own when Method is set
+type clientResponse struct {
+	ID     json.RawMessage `json:"id"`
+	Method string          `json:"method"`
+	Result json.RawMessage `json:"result"`
+	Error  *Error          `json:"error"`
+}
+
+// Connect reaches an MCP server and opens a session with it
+func Connect(ctx context.Context, config ClientConfig) (*Client, error) {
+	var transport clientTransport
+	var err error
+	switch {
+	case config.Command != "" && config.URL != "":
+		return nil, errors.New("set either a command or a URL, not both")
+	case config.Command != "":
+		transport, err = startStdioClient(config)
+	case config.URL != "":
+		transport = newHTTPClient(config)
+	default:
+		return nil, errors.New("a command or a URL is required")
+	}
+	if err != nil {
+		return nil, err
+	}
+
+	client := &Client{name: config.Name, transport: transport}
+	if err := client.initialize(ctx, config.Info); err != nil {
+		transport.close()
+		return nil, err
+	}
+	return client, nil
+}
+
+// initialize opens the session: the server answers with its version and
+// capabilities, and learns the client is ready
+func (c *Client) initialize(ctx context.Context, info Implementation) error {
+	params := InitializeParams{
+		ProtocolVersion: ProtocolVersions[0],
+		Capabilities:    json.RawMessage(`{}`),
+		ClientInfo:      info,
+	}
+	if err := c.call(ctx, "initialize", params, &c.server); err != nil {
+		return fmt.Errorf("initialize failed: %w", err)
+	}
+	if !supportedVersion(c.server.ProtocolVersion) {
+		return fmt.Errorf("server speaks protocol %s, which is not supported", c.server.ProtocolVersion)
+	}
+	c.transport.initialized(c.server.ProtocolVersion)
+	return c.transport.notify(ctx, &Request{JSONRPC: "2.0", Method: "notifications/initialized"})
+}
+
+// ServerInfo is the name and version the server reported
+func (c *Client) ServerInfo() Implementation {
+	return c.server.ServerInfo
+}
+
+// ListTools lists the tools of the server, following its pages
+func (c *Client) ListTools(ctx context.Context) ([]Tool, error) {
+	var tools []Tool
+	cursor := ""
+	for {
+		var params interface{}
+		if cursor != "" {
+			params = map[string]string{"cursor": cursor}
+		}
+		var page struct {
+			Tools      []Tool `json:"tools"`
+			NextCursor string `json:"nextCursor"`
+		}
+		if err := c.call(ctx, "tools/list", params, &page); err != nil {
+			return nil, err
+		}
+		tools = append(tools, page.Tools...)
+		if page.NextCursor == "" || page.NextCursor == cursor {
+			return tools, nil
+		}
+		cursor = page.NextCursor
+	}
+}
+
+// CallTool calls a tool of the server. A tool that failed is not an error:
+// its result has IsError set.
+func (c *Client) CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error) {
+	var result CallToolResult
+	if err := c.call(ctx, "tools/call", CallToolParams{Name: name, Arguments: arguments}, &result); err != nil {
+		return nil, err
+	}
+	return &result, nil
+}
+
+// Err returns why the connection ended, or nil while it is open
+func (c *Client) Err() error {
+	select {
+	case <-c.transport.done():
+		return c.transport.err()
+	default:
+		return nil
+	}
+}
+
+// Close ends the session, stopping the server process of a command
+func (c *Client) Close() error {
+	return c.transport.close()
+}
+
+// call sends a request and decodes its result. When ctx ends first, the
+// server is told to stop working on the request.
+func (c *Client) call(ctx context.Context, method string, params, result interface{}) error {
+	request := &Request{
+		JSONRPC: "2.0",
+		ID:      json.RawMessage(strconv.FormatInt(c.nextID.Add(1), 10)),
+		Method:  method,
+	}
+	if params != nil {
+		data, err := json.Marshal(params)
+		if err != nil {
+			return fmt.Errorf("failed to encode %s params: %w", method, err)
+		}
+		request.Params = data
+	}
+
+	response, err := c.transport.roundTrip(ctx, request)
+	if err != nil {
+		if ctx.Err() != nil && method != "initialize" {
+			c.cancel(request.ID, ctx.Err())
+		}
+		return err
+	}
+	if response.Error != nil {
+		return response.Error
+	}
+	if err := json.Unmarshal(response.Result, result); err != nil {
+		return fmt.Errorf("invalid %s result: %w", method, err)
+	}
+	return nil
+}
+
+// cancel tells the server a request is no longer wanted
+func (c *Client) cancel(id json.RawMessage, reason error) {
+	params, _ := json.Marshal(map[string]interface{}{"requestId": id, "reason": reason.Error()})
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	c.transport.notify(ctx, &Request{JSONRPC: "2.0", Method: "notifications/cancelled", Params: params})
+}
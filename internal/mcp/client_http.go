@@ -0,0 +1,242 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+)
+
+// httpClient talks to a server over streamable HTTP: every message is
+// POSTed, and responses come back as JSON or as an event stream
+type httpClient struct {
+	url     string
+	headers map[string]string
+	client  *http.Client
+
+	mutex           sync.Mutex
+	sessionID       string
+	protocolVersion string
+
+	closeOnce sync.Once
+	closed    chan struct{}
+	closeErr  error
+}
+
+func newHTTPClient(config ClientConfig) *httpClient {
+	return &httpClient{
+		url:     config.URL,
+		headers: config.Headers,
+		client:  &http.Client{},
+		closed:  make(chan struct{}),
+	}
+}
+
+func (t *httpClient) roundTrip(ctx context.Context, request *Request) (*clientResponse, error) {
+	response, err := t.post(ctx, request)
+	if err != nil {
+		return nil, err
+	}
+	defer response.Body.Close()
+
+	mediaType, _, _ := mime.ParseMediaType(response.Header.Get("Content-Type"))
+	switch mediaType {
+	case "application/json":
+		return readJSONResponse(response.Body, request.ID)
+	case "text/event-stream":
+		return readEventStream(response.Body, request.ID)
+	}
+	return nil, fmt.Errorf("unexpected response of type %q", mediaType)
+}
+
+func (t *httpClient) notify(ctx context.Context, request *Request) error {
+	response, err := t.post(ctx, request)
+	if err != nil {
+		return err
+	}
+	io.Copy(io.Discard, response.Body)
+	return response.Body.Close()
+}
+
+// post sends a message, keeping the session the server opens. Responses
+// other than 200 and 202 are returned as errors.
+func (t *httpClient) post(ctx context.Context, message *Request) (*http.Response, error) {
+	select {
+	case <-t.closed:
+		return nil, t.closeErr
+	default:
+	}
+
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodPost, t.url, bytes.NewReader(encode(message)))
+	if err != nil {
+		return nil, err
+	}
+	httpRequest.Header.Set("Content-Type", "application/json")
+	httpRequest.Header.Set("Accept", "application/json, text/event-stream")
+	t.setHeaders(httpRequest)
+
+	response, err := t.client.Do(httpRequest)
+	if err != nil {
+		return nil, err
+	}
+	if sessionID := response.Header.Get(SessionHeader); sessionID != "" {
+		t.mutex.Lock()
+		t.sessionID = sessionID
+		t.mutex.Unlock()
+	}
+
+	switch response.StatusCode {
+	case http.StatusOK, http.StatusAccepted:
+		return response, nil
+	}
+
+	defer response.Body.Close()
+	body, _ := io.ReadAll(io.LimitReader(response.Body, 64*1024))
+	// The server forgot the session, so a new connection is needed
+	if response.StatusCode == http.StatusNotFound && t.session() != "" {
+		t.end(fmt.Errorf("%w: session expired", ErrClosed))
+		return nil, t.closeErr
+	}
+	var failed Response
+	if json.Unmarshal(body, &failed) == nil && failed.Error != nil {
+		return nil, fmt.Errorf("HTTP %d: %w", response.StatusCode, failed.Error)
+	}
+	return nil, fmt.Errorf("HTTP %d: %s", response.StatusCode, strings.TrimSpace(string(body)))
+}
+
+// setHeaders adds the configured headers and those of the session
+func (t *httpClient) setHeaders(httpRequest *http.Request) {
+	for name, value := range t.headers {
+		httpRequest.Header.Set(name, value)
+	}
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	if t.sessionID != "" {
+		httpRequest.Header.Set(SessionHeader, t.sessionID)
+	}
+	if t.protocolVersion != "" {
+		httpRequest.Header.Set(ProtocolVersionHeader, t.protocolVersion)
+	}
+}
+
+func (t *httpClient) session() string {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	return t.sessionID
+}
+
+func (t *httpClient) initialized(version string) {
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+	t.protocolVersion = version
+}
+
+func (t *httpClient) done() <-chan struct{} {
+	return t.closed
+}
+
+func (t *httpClient) err() error {
+	return t.closeErr
+}
+
+// end marks the connection as closed with err
+func (t *httpClient) end(err error) {
+	t.closeOnce.Do(func() {
+		t.closeErr = err
+		close(t.closed)
+	})
+}
+
+// close ends the session on the server, if it opened one
+func (t *httpClient) close() error {
+	sessionID := t.session()
+	t.end(fmt.Errorf("%w: client closed", ErrClosed))
+	if sessionID == "" {
+		return nil
+	}
+
+	ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+	defer cancel()
+	httpRequest, err := http.NewRequestWithContext(ctx, http.MethodDelete, t.url, nil)
+	if err != nil {
+		return err
+	}
+	t.setHeaders(httpRequest)
+	response, err := t.client.Do(httpRequest)
+	if err != nil {
+		return err
+	}
+	return response.Body.Close()
+}
+
+// readJSONResponse reads the response to the request with id from a JSON
+// body, a single response or a batch
+func readJSONResponse(body io.Reader, id json.RawMessage) (*clientResponse, error) {
+	data, err := io.ReadAll(io.LimitReader(body, maxServerMessageSize))
+	if err != nil {
+		return nil, err
+	}
+	if response := findResponse(data, id); response != nil {
+		return response, nil
+	}
+	return nil, fmt.Errorf("no response to request %s", id)
+}
+
+// readEventStream reads the response to the request with id from an event
+// stream, skipping the other messages the server sends on it
+func readEventStream(body io.Reader, id json.RawMessage) (*clientResponse, error) {
+	scanner := bufio.NewScanner(body)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxServerMessageSize)
+	var data []string
+	for scanner.Scan() {
+		line := scanner.Text()
+		if line != "" {
+			if value, found := strings.CutPrefix(line, "data:"); found {
+				data = append(data, strings.TrimPrefix(value, " "))
+			}
+			continue
+		}
+		// A blank line ends an event
+		if len(data) > 0 {
+			if response := findResponse([]byte(strings.Join(data, "\n")), id); response != nil {
+				return response, nil
+			}
+			data = nil
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, err
+	}
+	return nil, fmt.Errorf("event stream ended without a response to request %s", id)
+}
+
+// findResponse returns the response to the request with id in a message,
+// or nil
+func findResponse(data []byte, id json.RawMessage) *clientResponse {
+	var messages []*clientResponse
+	if trimmed := bytes.TrimSpace(data); len(trimmed) > 0 && trimmed[0] == '[' {
+		if json.Unmarshal(trimmed, &messages) != nil {
+			return nil
+		}
+	} else {
+		var message clientResponse
+		if json.Unmarshal(trimmed, &message) != nil {
+			return nil
+		}
+		messages = append(messages, &message)
+	}
+
+	for _, message := range messages {
+		if message != nil && message.Method == "" && bytes.Equal(message.ID, id) {
+			return message
+		}
+	}
+	return nil
+}
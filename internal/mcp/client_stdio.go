@@ -0,0 +1,231 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"os/exec"
+	"sync"
+	"time"
+)
+
+// maxServerMessageSize bounds the messages read from MCP servers
+const maxServerMessageSize = 16 << 20
+
+// stopTimeout is how long a server process gets to exit once its stdin is
+// closed, before it is killed
+const stopTimeout = 5 * time.Second
+
+// stdioClient talks to a server process over its stdin and stdout, with
+// newline-delimited messages. Responses are matched to requests by id.
+type stdioClient struct {
+	name  string
+	cmd   *exec.Cmd
+	stdin io.WriteCloser
+
+	writeMutex sync.Mutex
+
+	mutex   sync.Mutex
+	pending map[string]chan *clientResponse
+
+	stderrDone chan struct{} // closed once stderr is logged to the end
+	exited     chan struct{} // closed once the process output ends
+	exitErr    error
+}
+
+// startStdioClient launches the command of a server
+func startStdioClient(config ClientConfig) (*stdioClient, error) {
+	cmd := exec.Command(config.Command, config.Args...)
+	cmd.Env = append(os.Environ(), config.Env...)
+	cmd.Dir = config.Dir
+
+	stdin, err := cmd.StdinPipe()
+	if err != nil {
+		return nil, err
+	}
+	stdout, err := cmd.StdoutPipe()
+	if err != nil {
+		return nil, err
+	}
+	stderr, err := cmd.StderrPipe()
+	if err != nil {
+		return nil, err
+	}
+	if err := cmd.Start(); err != nil {
+		return nil, fmt.Errorf("failed to start %s: %w", config.Command, err)
+	}
+
+	t := &stdioClient{
+		name:       config.Name,
+		cmd:        cmd,
+		stdin:      stdin,
+		pending:    make(map[string]chan *clientResponse),
+		stderrDone: make(chan struct{}),
+		exited:     make(chan struct{}),
+	}
+	go t.logStderr(stderr)
+	go t.read(stdout)
+	return t, nil
+}
+
+// read dispatches the messages of the server until its output ends, then
+// fails the requests still waiting
+func (t *stdioClient) read(stdout io.Reader) {
+	scanner := bufio.NewScanner(stdout)
+	scanner.Buffer(make([]byte, 0, 64*1024), maxServerMessageSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+		var message clientResponse
+		if err := json.Unmarshal(line, &message); err != nil {
+			log.Printf("⚠️  MCP server %s sent an invalid message: %v", t.name, err)
+			continue
+		}
+		if message.Method != "" {
+			t.answerServer(&message)
+			continue
+		}
+
+		t.mutex.Lock()
+		waiting, exists := t.pending[string(message.ID)]
+		delete(t.pending, string(message.ID))
+		t.mutex.Unlock()
+		if exists {
+			waiting <- &message
+		}
+	}
+
+	err := scanner.Err()
+	if errors.Is(err, bufio.ErrTooLong) {
+		err = fmt.Errorf("message exceeds %d bytes", maxServerMessageSize)
+		// Nothing more can be read, so the process is of no further use
+		t.cmd.Process.Kill()
+	}
+	<-t.stderrDone
+	waitErr := t.cmd.Wait()
+	if err == nil {
+		err = waitErr
+	}
+	if err != nil {
+		t.exitErr = fmt.Errorf("%w: %v", ErrClosed, err)
+	} else {
+		t.exitErr = fmt.Errorf("%w: process exited", ErrClosed)
+	}
+	log.Printf("🔌 MCP server %s stopped: %v", t.name, t.exitErr)
+
+	t.mutex.Lock()
+	t.pending = nil
+	t.mutex.Unlock()
+	close(t.exited)
+}
+
+// answerServer replies to the requests a server sends on its own: pings are
+// answered, anything else is not supported. Notifications are ignored.
+func (t *stdioClient) answerServer(message *clientResponse) {
+	if !validID(message.ID) {
+		return
+	}
+	response := &Response{JSONRPC: "2.0", ID: message.ID}
+	if message.Method == "ping" {
+		response.Result = struct{}{}
+	} else {
+		response.Error = NewError(CodeMethodNotFound, "method %s not supported by the gateway", message.Method)
+	}
+	t.write(encode(response))
+}
+
+// logStderr logs what the server writes on stderr, line by line
+func (t *stdioClient) logStderr(stderr io.Reader) {
+	defer close(t.stderrDone)
+	scanner := bufio.NewScanner(stderr)
+	for scanner.Scan() {
+		log.Printf("📜 MCP server %s: %s", t.name, scanner.Text())
+	}
+}
+
+func (t *stdioClient) roundTrip(ctx context.Context, request *Request) (*clientResponse, error) {
+	waiting := make(chan *clientResponse, 1)
+	t.mutex.Lock()
+	if t.pending == nil {
+		t.mutex.Unlock()
+		return nil, t.exitErr
+	}
+	t.pending[string(request.ID)] = waiting
+	t.mutex.Unlock()
+
+	forget := func() {
+		t.mutex.Lock()
+		if t.pending != nil {
+			delete(t.pending, string(request.ID))
+		}
+		t.mutex.Unlock()
+	}
+
+	if err := t.write(encode(request)); err != nil {
+		forget()
+		return nil, err
+	}
+
+	select {
+	case response := <-waiting:
+		return response, nil
+	case <-t.exited:
+		return nil, t.exitErr
+	case <-ctx.Done():
+		forget()
+		return nil, ctx.Err()
+	}
+}
+
+func (t *stdioClient) notify(ctx context.Context, request *Request) error {
+	return t.write(encode(request))
+}
+
+// write sends one message
+func (t *stdioClient) write(message []byte) error {
+	t.writeMutex.Lock()
+	defer t.writeMutex.Unlock()
+	if _, err := t.stdin.Write(append(message, '\n')); err != nil {
+		select {
+		case <-t.exited:
+			return t.exitErr
+		default:
+			return fmt.Errorf("%w: %v", ErrClosed, err)
+		}
+	}
+	return nil
+}
+
+func (t *stdioClient) initialized(string) {}
+
+func (t *stdioClient) done() <-chan struct{} {
+	return t.exited
+}
+
+func (t *stdioClient) err() error {
+	return t.exitErr
+}
+
+// close closes the stdin of the process, which ends a well behaved server,
+// and kills it if it does not exit in time
+func (t *stdioClient) close() error {
+	t.writeMutex.Lock()
+	t.stdin.Close()
+	t.writeMutex.Unlock()
+
+	select {
+	case <-t.exited:
+	case <-time.After(stopTimeout):
+		t.cmd.Process.Kill()
+		<-t.exited
+	}
+	return nil
+}
@@ -0,0 +1,347 @@
+package mcp
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"strings"
+	"sync"
+	"time"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// Header names of the streamable HTTP transport
+const (
+	SessionHeader         = "Mcp-Session-Id"
+	ProtocolVersionHeader = "Mcp-Protocol-Version"
+)
+
+// keepAliveInterval spaces the comments sent on the event stream of a long
+// request, so proxies do not drop it as idle
+const keepAliveInterval = 15 * time.Second
+
+// HTTPConfig holds the settings of the streamable HTTP transport
+type HTTPConfig struct {
+	// Authenticate resolves the caller of a request. It returns the context
+	// to answer the request with, carrying the principal, and who the caller
+	// is: a session can only be used by the caller that initialized it.
+	Authenticate func(r *http.Request) (ctx context.Context, caller string, err error)
+	// AllowOrigin vets the Origin header browsers send, against DNS
+	// rebinding. Requests without one are always accepted; nil rejects
+	// every origin.
+	AllowOrigin func(origin string) bool
+	// SessionTimeout forgets sessions idle for longer (0 = 30 minutes)
+	SessionTimeout time.Duration
+}
+
+// httpTransport serves MCP over streamable HTTP at a single endpoint: hosts
+// POST their messages and get the responses as JSON, or as an event stream
+// for tool calls when they accept one. The gateway sends no requests of its
+// own, so GET streams are not offered.
+type httpTransport struct {
+	server *Server
+	config HTTPConfig
+
+	sessions      map[string]*httpSession
+	sessionsMutex sync.Mutex
+}
+
+// httpSession is an MCP session opened by initialize
+type httpSession struct {
+	caller   string
+	lastUsed time.Time
+	inFlight *inFlight
+}
+
+// HTTPHandler returns the handler of the streamable HTTP transport
+func (s *Server) HTTPHandler(config HTTPConfig) http.Handler {
+	if config.SessionTimeout <= 0 {
+		config.SessionTimeout = 30 * time.Minute
+	}
+	return &httpTransport{
+		server:   s,
+		config:   config,
+		sessions: make(map[string]*httpSession),
+	}
+}
+
+func (t *httpTransport) ServeHTTP(w http.ResponseWriter, r *http.Request) {
+	// Browsers can only reach the endpoint from allowed origins
+	if origin := r.Header.Get("Origin"); origin != "" {
+		if t.config.AllowOrigin == nil || !t.config.AllowOrigin(origin) {
+			writeHTTPError(w, http.StatusForbidden, NewError(CodeInvalidRequest, "origin %s is not allowed", origin))
+			return
+		}
+		w.Header().Set("Access-Control-Allow-Origin", origin)
+		w.Header().Set("Access-Control-Expose-Headers", SessionHeader)
+		w.Header().Set("Vary", "Origin")
+	}
+	if r.Method == http.MethodOptions {
+		w.Header().Set("Access-Control-Allow-Methods", "POST, DELETE, OPTIONS")
+		w.Header().Set("Access-Control-Allow-Headers", "Content-Type, Accept, Authorization, "+SessionHeader+", "+ProtocolVersionHeader)
+		w.WriteHeader(http.StatusNoContent)
+		return
+	}
+
+	if r.Method != http.MethodPost && r.Method != http.MethodDelete {
+		w.Header().Set("Allow", "POST, DELETE")
+		writeHTTPError(w, http.StatusMethodNotAllowed, NewError(CodeInvalidRequest, "use POST to send messages"))
+		return
+	}
+
+	if version := r.Header.Get(ProtocolVersionHeader); version != "" && !supportedVersion(version) {
+		writeHTTPError(w, http.StatusBadRequest, NewError(CodeInvalidRequest, "protocol version %s is not supported (use one of %s)", version, strings.Join(ProtocolVersions, ", ")))
+		return
+	}
+
+	ctx, caller, err := t.config.Authenticate(r)
+	if err != nil {
+		code := authErrorStatus(err)
+		if code == http.StatusUnauthorized {
+			w.Header().Set("WWW-Authenticate", "Bearer")
+		}
+		writeHTTPError(w, code, toError(err))
+		return
+	}
+
+	if r.Method == http.MethodDelete {
+		t.deleteSession(w, r, caller)
+		return
+	}
+	t.post(ctx, w, r, caller)
+}
+
+// post answers the messages of a POST
+func (t *httpTransport) post(ctx context.Context, w http.ResponseWriter, r *http.Request, caller string) {
+	body, err := io.ReadAll(http.MaxBytesReader(w, r.Body, int64(t.server.maxMessageSize())))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			writeHTTPError(w, http.StatusRequestEntityTooLarge, NewError(CodeInvalidRequest, "message exceeds %d bytes", tooLarge.Limit))
+			return
+		}
+		writeHTTPError(w, http.StatusBadRequest, NewError(CodeParseError, "failed to read message: %v", err))
+		return
+	}
+
+	requests, batch, parseErr := parseMessage(body)
+	if parseErr != nil {
+		writeHTTPError(w, http.StatusBadRequest, parseErr)
+		return
+	}
+
+	// initialize opens a session; everything else needs one
+	var session *httpSession
+	if isInitialize(requests) {
+		if batch {
+			writeHTTPError(w, http.StatusBadRequest, NewError(CodeInvalidRequest, "initialize cannot be batched"))
+			return
+		}
+		sessionID, created, err := t.createSession(caller)
+		if err != nil {
+			writeHTTPError(w, http.StatusInternalServerError, NewError(CodeInternalError, "failed to create session: %v", err))
+			return
+		}
+		session = created
+		w.Header().Set(SessionHeader, sessionID)
+	} else {
+		var code int
+		session, code, err = t.session(r, caller)
+		if err != nil {
+			writeHTTPError(w, code, toError(err))
+			return
+		}
+	}
+
+	session.inFlight.cancelRequested(requests)
+	if !hasCalls(requests) {
+		// Notifications and responses are only acknowledged
+		t.server.handleRequests(ctx, requests, batch)
+		w.WriteHeader(http.StatusAccepted)
+		return
+	}
+
+	ctx, done := session.inFlight.start(ctx, requests)
+	defer done()
+
+	if acceptsEventStream(r) && hasToolCall(requests) {
+		t.stream(ctx, w, requests, batch)
+		return
+	}
+	reply := t.server.handleRequests(ctx, requests, batch)
+	w.Header().Set("Content-Type", "application/json")
+	w.Write(reply)
+}
+
+// stream answers requests as an event stream, with comments to keep the
+// connection alive while they run
+func (t *httpTransport) stream(ctx context.Context, w http.ResponseWriter, requests []*Request, batch bool) {
+	replies := make(chan []byte, 1)
+	go func() {
+		replies <- t.server.handleRequests(ctx, requests, batch)
+	}()
+
+	w.Header().Set("Content-Type", "text/event-stream")
+	w.Header().Set("Cache-Control", "no-cache")
+	w.WriteHeader(http.StatusOK)
+	flusher, _ := w.(http.Flusher)
+	flush := func() {
+		if flusher != nil {
+			flusher.Flush()
+		}
+	}
+	flush()
+
+	keepAlive := time.NewTicker(keepAliveInterval)
+	defer keepAlive.Stop()
+	for {
+		select {
+		case reply := <-replies:
+			if reply != nil && ctx.Err() == nil {
+				fmt.Fprintf(w, "event: message\ndata: %s\n\n", reply)
+				flush()
+			}
+			return
+		case <-keepAlive.C:
+			fmt.Fprint(w, ": keep-alive\n\n")
+			flush()
+		}
+	}
+}
+
+// createSession opens a session for caller
+func (t *httpTransport) createSession(caller string) (string, *httpSession, error) {
+	id := make([]byte, 16)
+	if _, err := rand.Read(id); err != nil {
+		return "", nil, err
+	}
+	sessionID := hex.EncodeToString(id)
+	session := &httpSession{caller: caller, lastUsed: time.Now(), inFlight: newInFlight()}
+
+	t.sessionsMutex.Lock()
+	defer t.sessionsMutex.Unlock()
+	t.pruneSessions()
+	t.sessions[sessionID] = session
+	return sessionID, session, nil
+}
+
+// session returns the session a request names in its header, with the HTTP
+// status to fail with. Sessions of other callers are reported as missing.
+func (t *httpTransport) session(r *http.Request, caller string) (*httpSession, int, error) {
+	sessionID := r.Header.Get(SessionHeader)
+	if sessionID == "" {
+		return nil, http.StatusBadRequest, NewError(CodeInvalidRequest, "%s header is required; send initialize first", SessionHeader)
+	}
+
+	t.sessionsMutex.Lock()
+	defer t.sessionsMutex.Unlock()
+	t.pruneSessions()
+	session, exists := t.sessions[sessionID]
+	if !exists || session.caller != caller {
+		return nil, http.StatusNotFound, NewError(CodeInvalidRequest, "session %s not found; send initialize again", sessionID)
+	}
+	session.lastUsed = time.Now()
+	return session, http.StatusOK, nil
+}
+
+// deleteSession ends the session of a DELETE request
+func (t *httpTransport) deleteSession(w http.ResponseWriter, r *http.Request, caller string) {
+	session, code, err := t.session(r, caller)
+	if err != nil {
+		writeHTTPError(w, code, toError(err))
+		return
+	}
+
+	t.sessionsMutex.Lock()
+	for id, s := range t.sessions {
+		if s == session {
+			delete(t.sessions, id)
+		}
+	}
+	t.sessionsMutex.Unlock()
+	w.WriteHeader(http.StatusNoContent)
+}
+
+// pruneSessions forgets idle sessions. The caller holds sessionsMutex.
+func (t *httpTransport) pruneSessions() {
+	for id, session := range t.sessions {
+		if time.Since(session.lastUsed) > t.config.SessionTimeout {
+			log.Printf("🧹 MCP session %s expired", id)
+			delete(t.sessions, id)
+		}
+	}
+}
+
+// isInitialize reports whether a message opens a session
+func isInitialize(requests []*Request) bool {
+	for _, request := range requests {
+		if request.Method == "initialize" {
+			return true
+		}
+	}
+	return false
+}
+
+// hasCalls reports whether a message holds requests to answer, rather than
+// only notifications
+func hasCalls(requests []*Request) bool {
+	for _, request := range requests {
+		if !request.IsNotification() {
+			return true
+		}
+	}
+	return false
+}
+
+// hasToolCall reports whether a message calls a tool, which can take long
+func hasToolCall(requests []*Request) bool {
+	for _, request := range requests {
+		if request.Method == "tools/call" {
+			return true
+		}
+	}
+	return false
+}
+
+// acceptsEventStream reports whether the host takes responses as an event
+// stream
+func acceptsEventStream(r *http.Request) bool {
+	for _, accept := range r.Header.Values("Accept") {
+		for _, mediaType := range strings.Split(accept, ",") {
+			mediaType, _, _ = strings.Cut(mediaType, ";")
+			if strings.TrimSpace(mediaType) == "text/event-stream" {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// writeHTTPError fails a request with an HTTP status and a JSON-RPC error
+func writeHTTPError(w http.ResponseWriter, httpStatus int, err *Error) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(httpStatus)
+	w.Write(encode(&Response{JSONRPC: "2.0", Error: err}))
+}
+
+// authErrorStatus maps the gRPC status of an authentication error onto the
+// HTTP status to fail with
+func authErrorStatus(err error) int {
+	switch status.Code(err) {
+	case codes.Unauthenticated:
+		return http.StatusUnauthorized
+	case codes.PermissionDenied:
+		return http.StatusForbidden
+	case codes.ResourceExhausted:
+		return http.StatusTooManyRequests
+	default:
+		return http.StatusInternalServerError
+	}
+}
@@ -0,0 +1,165 @@
+// Package mcp serves the gateway to Model Context Protocol hosts: JSON-RPC
+// 2.0 messages over stdio or streamable HTTP, answered by a Backend that
+// exposes tools, prompts and resources. It is also a client of external MCP
+// servers, whose tools the Bridge offers to the model.
+package mcp
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+// JSON-RPC error codes. CodeRequestFailed is the server error returned for
+// gateway errors without a JSON-RPC equivalent, such as a rate limit.
+const (
+	CodeParseError       = -32700
+	CodeInvalidRequest   = -32600
+	CodeMethodNotFound   = -32601
+	CodeInvalidParams    = -32602
+	CodeInternalError    = -32603
+	CodeRequestFailed    = -32000
+	CodeResourceNotFound = -32002
+)
+
+// Request is a JSON-RPC request, or a notification when it has no id
+type Request struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id,omitempty"`
+	Method  string          `json:"method"`
+	Params  json.RawMessage `json:"params,omitempty"`
+}
+
+// IsNotification reports whether the request expects no response
+func (r *Request) IsNotification() bool {
+	return r.ID == nil
+}
+
+// Response is the JSON-RPC response to a request: a result or an error
+type Response struct {
+	JSONRPC string          `json:"jsonrpc"`
+	ID      json.RawMessage `json:"id"`
+	Result  interface{}     `json:"result,omitempty"`
+	Error   *Error          `json:"error,omitempty"`
+}
+
+// Error is a JSON-RPC error object. Backends return it to choose the code
+// the host gets.
+type Error struct {
+	Code    int         `json:"code"`
+	Message string      `json:"message"`
+	Data    interface{} `json:"data,omitempty"`
+}
+
+func (e *Error) Error() string {
+	return fmt.Sprintf("%s (%d)", e.Message, e.Code)
+}
+
+// NewError builds an Error with a formatted message
+func NewError(code int, format string, args ...interface{}) *Error {
+	return &Error{Code: code, Message: fmt.Sprintf(format, args...)}
+}
+
+// toError turns an error of a backend into a JSON-RPC error. gRPC status
+// errors of the gateway handlers keep their status name in the data.
+func toError(err error) *Error {
+	var rpcErr *Error
+	if errors.As(err, &rpcErr) {
+		return rpcErr
+	}
+
+	st, ok := status.FromError(err)
+	if !ok {
+		return &Error{Code: CodeInternalError, Message: err.Error()}
+	}
+	code := CodeRequestFailed
+	switch st.Code() {
+	case codes.InvalidArgument, codes.NotFound:
+		code = CodeInvalidParams
+	case codes.Internal, codes.Unknown:
+		code = CodeInternalError
+	}
+	return &Error{
+		Code:    code,
+		Message: st.Message(),
+		Data:    map[string]string{"status": st.Code().String()},
+	}
+}
+
+// parseMessage decodes a message, a request or a batch of them. Malformed
+// JSON fails with a parse error; anything else that is not a request is
+// reported per request by validate.
+func parseMessage(data []byte) ([]*Request, bool, *Error) {
+	data = bytes.TrimSpace(data)
+	if len(data) > 0 && data[0] == '[' {
+		var batch []json.RawMessage
+		if err := json.Unmarshal(data, &batch); err != nil {
+			return nil, true, NewError(CodeParseError, "invalid JSON: %v", err)
+		}
+		if len(batch) == 0 {
+			return nil, true, NewError(CodeInvalidRequest, "empty batch")
+		}
+		requests := make([]*Request, len(batch))
+		for i, raw := range batch {
+			requests[i] = decodeRequest(raw)
+		}
+		return requests, true, nil
+	}
+
+	if !json.Valid(data) {
+		return nil, false, NewError(CodeParseError, "invalid JSON")
+	}
+	return []*Request{decodeRequest(data)}, false, nil
+}
+
+// decodeRequest decodes one request; a value that is not a request object
+// decodes to an empty request, which validate rejects
+func decodeRequest(data json.RawMessage) *Request {
+	var request Request
+	if err := json.Unmarshal(data, &request); err != nil {
+		return &Request{}
+	}
+	return &request
+}
+
+// validate checks the envelope of a request
+func (r *Request) validate() *Error {
+	if r.JSONRPC != "2.0" {
+		return NewError(CodeInvalidRequest, `jsonrpc must be "2.0"`)
+	}
+	if r.Method == "" {
+		return NewError(CodeInvalidRequest, "method is required")
+	}
+	if r.ID != nil && !validID(r.ID) {
+		return NewError(CodeInvalidRequest, "id must be a string or a number")
+	}
+	return nil
+}
+
+// validID reports whether id is a string or a number
+func validID(id json.RawMessage) bool {
+	if len(id) == 0 {
+		return false
+	}
+	switch id[0] {
+	case '"', '-', '0', '1', '2', '3', '4', '5', '6', '7', '8', '9':
+		return true
+	}
+	return false
+}
+
+// decodeParams decodes the params of a request into v. Missing params leave
+// v untouched.
+func decodeParams(params json.RawMessage, v interface{}) *Error {
+	if len(params) == 0 || string(params) == "null" {
+		return nil
+	}
+	if err := json.Unmarshal(params, v); err != nil {
+		return NewError(CodeInvalidParams, "invalid params: %v", err)
+	}
+	return nil
+}
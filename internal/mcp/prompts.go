@@ -0,0 +1,48 @@
+package mcp
+
+import (
+	"regexp"
+	"strings"
+)
+
+// placeholder matches the {{argument}} placeholders of a prompt template
+var placeholder = regexp.MustCompile(`\{\{\s*([a-zA-Z0-9_-]+)\s*\}\}`)
+
+// PromptTemplate is a prompt rendered as one user message, with a
+// {{name}} placeholder for each of its arguments
+type PromptTemplate struct {
+	Prompt
+	Text string
+}
+
+// Render fills in the placeholders with the arguments of a prompts/get
+// request. Missing required arguments fail with invalid params; missing
+// optional ones render empty.
+func (t *PromptTemplate) Render(arguments map[string]string) (*GetPromptResult, error) {
+	var missing []string
+	for _, argument := range t.Arguments {
+		if argument.Required && arguments[argument.Name] == "" {
+			missing = append(missing, argument.Name)
+		}
+	}
+	if len(missing) > 0 {
+		return nil, NewError(CodeInvalidParams, "prompt %s requires %s", t.Name, strings.Join(missing, ", "))
+	}
+
+	text := placeholder.ReplaceAllStringFunc(t.Text, func(match string) string {
+		return arguments[placeholder.FindStringSubmatch(match)[1]]
+	})
+	return &GetPromptResult{
+		Description: t.Description,
+		Messages:    []PromptMessage{{Role: "user", Content: TextContent(text)}},
+	}, nil
+}
+
+// Placeholders returns the argument names a template text refers to
+func Placeholders(text string) []string {
+	var names []string
+	for _, match := range placeholder.FindAllStringSubmatch(text, -1) {
+		names = append(names, match[1])
+	}
+	return names
+}
@@ -0,0 +1,142 @@
+package mcp
+
+import "encoding/json"
+
+// ProtocolVersions are the MCP revisions the server speaks, latest first
+var ProtocolVersions = []string{"2025-06-18", "2025-03-26", "2024-11-05"}
+
+// negotiateVersion answers the version a host asks for with the same one
+// when supported, and with the latest otherwise
+func negotiateVersion(requested string) string {
+	for _, version := range ProtocolVersions {
+		if version == requested {
+			return version
+		}
+	}
+	return ProtocolVersions[0]
+}
+
+// supportedVersion reports whether version is one of ProtocolVersions
+func supportedVersion(version string) bool {
+	return negotiateVersion(version) == version
+}
+
+// Implementation names a host or server and its version
+type Implementation struct {
+	Name    string `json:"name"`
+	Version string `json:"version"`
+}
+
+// InitializeParams are sent by the host to open a session
+type InitializeParams struct {
+	ProtocolVersion string          `json:"protocolVersion"`
+	Capabilities    json.RawMessage `json:"capabilities,omitempty"`
+	ClientInfo      Implementation  `json:"clientInfo"`
+}
+
+// InitializeResult tells the host what the server offers
+type InitializeResult struct {
+	ProtocolVersion string             `json:"protocolVersion"`
+	Capabilities    ServerCapabilities `json:"capabilities"`
+	ServerInfo      Implementation     `json:"serverInfo"`
+	Instructions    string             `json:"instructions,omitempty"`
+}
+
+// ServerCapabilities lists the features of the server. The lists it serves
+// do not change during a session, so no list_changed notifications are sent.
+type ServerCapabilities struct {
+	Tools     *ListCapability     `json:"tools,omitempty"`
+	Prompts   *ListCapability     `json:"prompts,omitempty"`
+	Resources *ResourceCapability `json:"resources,omitempty"`
+}
+
+type ListCapability struct {
+	ListChanged bool `json:"listChanged"`
+}
+
+type ResourceCapability struct {
+	Subscribe   bool `json:"subscribe"`
+	ListChanged bool `json:"listChanged"`
+}
+
+// Tool is a tool the host can call
+type Tool struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	InputSchema json.RawMessage `json:"inputSchema"` // JSON Schema of the arguments object
+}
+
+// Content is a block of a tool result or prompt message. The gateway only
+// produces text.
+type Content struct {
+	Type string `json:"type"`
+	Text string `json:"text"`
+}
+
+// TextContent builds a text content block
+func TextContent(text string) Content {
+	return Content{Type: "text", Text: text}
+}
+
+// CallToolParams name the tool to call and its arguments
+type CallToolParams struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments,omitempty"`
+}
+
+// CallToolResult is the outcome of a tool. A tool that fails sets IsError,
+// so the model sees the failure and can react to it.
+type CallToolResult struct {
+	Content []Content `json:"content"`
+	IsError bool      `json:"isError,omitempty"`
+}
+
+// Prompt is a prompt template the host can offer its user
+type Prompt struct {
+	Name        string           `json:"name"`
+	Description string           `json:"description,omitempty"`
+	Arguments   []PromptArgument `json:"arguments,omitempty"`
+}
+
+type PromptArgument struct {
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	Required    bool   `json:"required,omitempty"`
+}
+
+// GetPromptParams name the prompt to render and its arguments
+type GetPromptParams struct {
+	Name      string            `json:"name"`
+	Arguments map[string]string `json:"arguments,omitempty"`
+}
+
+// GetPromptResult is a rendered prompt
+type GetPromptResult struct {
+	Description string          `json:"description,omitempty"`
+	Messages    []PromptMessage `json:"messages"`
+}
+
+type PromptMessage struct {
+	Role    string  `json:"role"` // user or assistant
+	Content Content `json:"content"`
+}
+
+// Resource is a piece of context the host can read
+type Resource struct {
+	URI         string `json:"uri"`
+	Name        string `json:"name"`
+	Description string `json:"description,omitempty"`
+	MimeType    string `json:"mimeType,omitempty"`
+}
+
+// ReadResourceParams name the resource to read
+type ReadResourceParams struct {
+	URI string `json:"uri"`
+}
+
+// ResourceContents is the text of a resource
+type ResourceContents struct {
+	URI      string `json:"uri"`
+	MimeType string `json:"mimeType,omitempty"`
+	Text     string `json:"text"`
+}
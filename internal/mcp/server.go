@@ -0,0 +1,215 @@
+package mcp
+
+import (
+	"context"
+	"encoding/json"
+	"log"
+	"strings"
+)
+
+// Backend answers the MCP requests of a session. Every call gets the context
+// of the request, which carries the authenticated principal. Errors become
+// JSON-RPC errors (see Error); a tool that fails should rather return a
+// result with IsError set.
+type Backend interface {
+	ListTools(ctx context.Context) ([]Tool, error)
+	CallTool(ctx context.Context, name string, arguments json.RawMessage) (*CallToolResult, error)
+	ListPrompts(ctx context.Context) ([]Prompt, error)
+	GetPrompt(ctx context.Context, name string, arguments map[string]string) (*GetPromptResult, error)
+	ListResources(ctx context.Context) ([]Resource, error)
+	ReadResource(ctx context.Context, uri string) ([]ResourceContents, error)
+}
+
+// Config holds the dependencies and settings of a Server
+type Config struct {
+	Info         Implementation // name and version reported to hosts
+	Instructions string         // tells the host how to use the server
+	Backend      Backend
+	// MaxMessageSize bounds the messages read from hosts (0 = 4MB)
+	MaxMessageSize int
+	// Admit, when set, is asked before every request that reaches the
+	// backend, e.g. to apply rate limits. Its error is returned instead.
+	Admit func(ctx context.Context, method string) error
+}
+
+// Server dispatches MCP requests to a Backend. It keeps no state of its own:
+// the transports track sessions.
+type Server struct {
+	config Config
+}
+
+func NewServer(config Config) *Server {
+	return &Server{config: config}
+}
+
+// handleRequests answers the requests of a message, a single request or a
+// batch, in order. It returns nil when there is nothing to answer, i.e. for
+// notifications.
+func (s *Server) handleRequests(ctx context.Context, requests []*Request, batch bool) []byte {
+	var responses []*Response
+	for _, request := range requests {
+		if response := s.handleRequest(ctx, request); response != nil {
+			responses = append(responses, response)
+		}
+	}
+	return encodeResponses(responses, batch)
+}
+
+// encodeResponses encodes the responses to a message: an array for a batch,
+// and nothing when only notifications were sent
+func encodeResponses(responses []*Response, batch bool) []byte {
+	switch {
+	case len(responses) == 0:
+		return nil
+	case batch:
+		return encode(responses)
+	default:
+		return encode(responses[0])
+	}
+}
+
+// handleRequest answers one request, or returns nil for a notification
+func (s *Server) handleRequest(ctx context.Context, request *Request) *Response {
+	if err := request.validate(); err != nil {
+		// Invalid requests are answered even without an id, which then is null
+		id := request.ID
+		if !validID(id) {
+			id = nil
+		}
+		return &Response{JSONRPC: "2.0", ID: id, Error: err}
+	}
+
+	result, err := s.dispatch(ctx, request)
+	if request.IsNotification() {
+		if err != nil {
+			log.Printf("⚠️  MCP notification %s failed: %v", request.Method, err)
+		}
+		return nil
+	}
+	if err != nil {
+		return &Response{JSONRPC: "2.0", ID: request.ID, Error: toError(err)}
+	}
+	return &Response{JSONRPC: "2.0", ID: request.ID, Result: result}
+}
+
+// dispatch runs a request by method
+func (s *Server) dispatch(ctx context.Context, request *Request) (interface{}, error) {
+	switch request.Method {
+	case "initialize":
+		var params InitializeParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		return s.initialize(params), nil
+	case "ping":
+		return struct{}{}, nil
+	}
+	// Notifications only tell the server about the host; cancellations are
+	// handled by the transports
+	if strings.HasPrefix(request.Method, "notifications/") {
+		return nil, nil
+	}
+
+	if s.config.Admit != nil {
+		if err := s.config.Admit(ctx, request.Method); err != nil {
+			return nil, err
+		}
+	}
+	backend := s.config.Backend
+
+	switch request.Method {
+	case "tools/list":
+		tools, err := backend.ListTools(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"tools": nonNil(tools)}, nil
+
+	case "tools/call":
+		var params CallToolParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Name == "" {
+			return nil, NewError(CodeInvalidParams, "name is required")
+		}
+		return backend.CallTool(ctx, params.Name, params.Arguments)
+
+	case "prompts/list":
+		prompts, err := backend.ListPrompts(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"prompts": nonNil(prompts)}, nil
+
+	case "prompts/get":
+		var params GetPromptParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.Name == "" {
+			return nil, NewError(CodeInvalidParams, "name is required")
+		}
+		return backend.GetPrompt(ctx, params.Name, params.Arguments)
+
+	case "resources/list":
+		resources, err := backend.ListResources(ctx)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"resources": nonNil(resources)}, nil
+
+	case "resources/templates/list":
+		return map[string]interface{}{"resourceTemplates": []struct{}{}}, nil
+
+	case "resources/read":
+		var params ReadResourceParams
+		if err := decodeParams(request.Params, &params); err != nil {
+			return nil, err
+		}
+		if params.URI == "" {
+			return nil, NewError(CodeInvalidParams, "uri is required")
+		}
+		contents, err := backend.ReadResource(ctx, params.URI)
+		if err != nil {
+			return nil, err
+		}
+		return map[string]interface{}{"contents": nonNil(contents)}, nil
+	}
+
+	return nil, NewError(CodeMethodNotFound, "method %s not found", request.Method)
+}
+
+// initialize answers the handshake of a new session
+func (s *Server) initialize(params InitializeParams) *InitializeResult {
+	version := negotiateVersion(params.ProtocolVersion)
+	log.Printf("🧩 MCP host %s %s connected (protocol %s)", params.ClientInfo.Name, params.ClientInfo.Version, version)
+
+	return &InitializeResult{
+		ProtocolVersion: version,
+		Capabilities: ServerCapabilities{
+			Tools:     &ListCapability{},
+			Prompts:   &ListCapability{},
+			Resources: &ResourceCapability{},
+		},
+		ServerInfo:   s.config.Info,
+		Instructions: s.config.Instructions,
+	}
+}
+
+// nonNil keeps empty lists as [] rather than null in results
+func nonNil[T any](items []T) []T {
+	if items == nil {
+		return []T{}
+	}
+	return items
+}
+
+// encode marshals a response, which cannot fail for the types used here
+func encode(v interface{}) []byte {
+	data, err := json.Marshal(v)
+	if err != nil {
+		data, _ = json.Marshal(&Response{JSONRPC: "2.0", Error: NewError(CodeInternalError, "failed to encode response: %v", err)})
+	}
+	return data
+}
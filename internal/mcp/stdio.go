@@ -0,0 +1,162 @@
+package mcp
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"sync"
+)
+
+// ServeStdio serves a host that launched the gateway as a subprocess:
+// newline-delimited messages are read from in and answered on out until in
+// ends and the requests still running are answered. authenticate returns the
+// context to answer each message with, carrying the principal of the
+// session. Requests are answered concurrently, so a long tool call does not
+// hold up a ping.
+func (s *Server) ServeStdio(ctx context.Context, in io.Reader, out io.Writer, authenticate func(context.Context) (context.Context, error)) error {
+	var running sync.WaitGroup
+	defer running.Wait()
+
+	var writeMutex sync.Mutex
+	write := func(reply []byte) {
+		writeMutex.Lock()
+		defer writeMutex.Unlock()
+		if _, err := out.Write(append(reply, '\n')); err != nil {
+			log.Printf("❌ Failed to write MCP response: %v", err)
+		}
+	}
+
+	requestsInFlight := newInFlight()
+	scanner := bufio.NewScanner(in)
+	maxSize := s.maxMessageSize()
+	scanner.Buffer(make([]byte, 0, min(maxSize, 64*1024)), maxSize)
+	for scanner.Scan() {
+		line := bytes.TrimSpace(scanner.Bytes())
+		if len(line) == 0 {
+			continue
+		}
+
+		requests, batch, parseErr := parseMessage(line)
+		if parseErr != nil {
+			write(encode(&Response{JSONRPC: "2.0", Error: parseErr}))
+			continue
+		}
+		requestsInFlight.cancelRequested(requests)
+
+		sessionCtx, err := authenticate(ctx)
+		if err != nil {
+			log.Printf("❌ MCP stdio session unavailable: %v", err)
+			if reply := errorReplies(requests, batch, toError(err)); reply != nil {
+				write(reply)
+			}
+			continue
+		}
+
+		requestCtx, done := requestsInFlight.start(sessionCtx, requests)
+		running.Add(1)
+		go func() {
+			defer running.Done()
+			defer done()
+			reply := s.handleRequests(requestCtx, requests, batch)
+			// Cancelled requests are not answered
+			if reply != nil && requestCtx.Err() == nil {
+				write(reply)
+			}
+		}()
+	}
+	if err := scanner.Err(); errors.Is(err, bufio.ErrTooLong) {
+		return fmt.Errorf("message exceeds %d bytes", maxSize)
+	} else if err != nil {
+		return err
+	}
+	return nil
+}
+
+// errorReplies fails every request of a message with err
+func errorReplies(requests []*Request, batch bool, err *Error) []byte {
+	var responses []*Response
+	for _, request := range requests {
+		if !request.IsNotification() {
+			responses = append(responses, &Response{JSONRPC: "2.0", ID: request.ID, Error: err})
+		}
+	}
+	return encodeResponses(responses, batch)
+}
+
+// maxMessageSize is the size limit of a message, 4MB unless configured
+func (s *Server) maxMessageSize() int {
+	if s.config.MaxMessageSize > 0 {
+		return s.config.MaxMessageSize
+	}
+	return 4 << 20
+}
+
+// inFlight tracks the requests being answered, by id, so a
+// notifications/cancelled from the host can stop them
+type inFlight struct {
+	mutex   sync.Mutex
+	cancels map[string]context.CancelFunc
+}
+
+func newInFlight() *inFlight {
+	return &inFlight{cancels: make(map[string]context.CancelFunc)}
+}
+
+// start returns the context to answer requests with, cancelled when the host
+// cancels any of them, and the function to call once they are answered
+func (f *inFlight) start(ctx context.Context, requests []*Request) (context.Context, func()) {
+	ctx, cancel := context.WithCancel(ctx)
+	var ids []string
+	f.mutex.Lock()
+	for _, request := range requests {
+		if validID(request.ID) {
+			ids = append(ids, string(request.ID))
+			f.cancels[string(request.ID)] = cancel
+		}
+	}
+	f.mutex.Unlock()
+
+	return ctx, func() {
+		f.mutex.Lock()
+		for _, id := range ids {
+			delete(f.cancels, id)
+		}
+		f.mutex.Unlock()
+		cancel()
+	}
+}
+
+// cancelRequested cancels the requests named by the notifications/cancelled
+// among requests
+func (f *inFlight) cancelRequested(requests []*Request) {
+	for _, request := range requests {
+		if request.Method != "notifications/cancelled" {
+			continue
+		}
+		var params struct {
+			RequestID json.RawMessage `json:"requestId"`
+			Reason    string          `json:"reason"`
+		}
+		if decodeParams(request.Params, &params) != nil || params.RequestID == nil {
+			continue
+		}
+
+		f.mutex.Lock()
+		cancel, exists := f.cancels[string(params.RequestID)]
+		f.mutex.Unlock()
+		if !exists {
+			continue
+		}
+		if params.Reason != "" {
+			log.Printf("🚫 MCP request %s cancelled by the host: %s", params.RequestID, params.Reason)
+		} else {
+			log.Printf("🚫 MCP request %s cancelled by the host", params.RequestID)
+		}
+		cancel()
+	}
+}
@@ -0,0 +1,164 @@
+package ollama
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+)
+
+// Roles of the messages of a chat
+const (
+	RoleSystem    = "system"
+	RoleUser      = "user"
+	RoleAssistant = "assistant"
+	RoleTool      = "tool"
+)
+
+// Message is one message of a chat. Ollama applies the chat template of the
+// model to the messages, so prompts need no model-specific formatting.
+type Message struct {
+	Role    string   `json:"role"`
+	Content string   `json:"content"`
+	Images  []string `json:"images,omitempty"` // base64, for multimodal models
+
+	// ToolCalls are the tools an assistant message asks to run, and ToolName
+	// the tool whose result a tool message holds
+	ToolCalls []ToolCall `json:"tool_calls,omitempty"`
+	ToolName  string     `json:"tool_name,omitempty"`
+}
+
+// Tool describes a function the model may call
+type Tool struct {
+	Type     string       `json:"type"` // always "function"
+	Function ToolFunction `json:"function"`
+}
+
+// ToolFunction is the name, purpose and JSON Schema parameters of a tool
+type ToolFunction struct {
+	Name        string          `json:"name"`
+	Description string          `json:"description,omitempty"`
+	Parameters  json.RawMessage `json:"parameters"`
+}
+
+// ToolCall is a call to a tool requested by the model
+type ToolCall struct {
+	Function ToolCallFunction `json:"function"`
+}
+
+// ToolCallFunction names the tool to call and its arguments, a JSON object
+type ToolCallFunction struct {
+	Name      string          `json:"name"`
+	Arguments json.RawMessage `json:"arguments"`
+}
+
+// ChatRequest is a request to /api/chat
+type ChatRequest struct {
+	Model    string                 `json:"model"`
+	Messages []Message              `json:"messages"`
+	Stream   bool                   `json:"stream"`
+	Options  map[string]interface{} `json:"options,omitempty"`
+	Tools    []Tool                 `json:"tools,omitempty"` // tools the model may call
+	// Format constrains the answer: "json", or a JSON Schema
+	Format json.RawMessage `json:"format,omitempty"`
+}
+
+// ChatResponse is a response of /api/chat, or one chunk of a streamed one
+type ChatResponse struct {
+	Model      string  `json:"model"`
+	CreatedAt  string  `json:"created_at"`
+	Message    Message `json:"message"`
+	Done       bool    `json:"done"`
+	DoneReason string  `json:"done_reason,omitempty"` // "stop", "length"...
+	Error      string  `json:"error,omitempty"`
+	Metrics
+}
+
+// Chat sends a non-streaming chat request and returns the answer
+func (c *Client) Chat(ctx context.Context, req ChatRequest) (*ChatResponse, error) {
+	// 1. Disable streaming
+	req.Stream = false
+
+	// 2. Send the request
+	resp, err := c.post(ctx, c.httpClient, "/api/chat", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// 3. Decode the response
+	var chatResp ChatResponse
+	if err := json.NewDecoder(resp.Body).Decode(&chatResp); err != nil {
+		return nil, fmt.Errorf("failed to decode response: %w", err)
+	}
+	if chatResp.Error != "" {
+		return nil, fmt.Errorf("ollama error: %s", chatResp.Error)
+	}
+
+	return &chatResp, nil
+}
+
+// ChatStream sends a streaming chat request and calls onChunk for every
+// NDJSON chunk Ollama emits. It returns the final chunk (Done == true), which
+// carries the token counts and timings of the whole answer.
+func (c *Client) ChatStream(ctx context.Context, req ChatRequest, onChunk func(*ChatResponse) error) (*ChatResponse, error) {
+	// 1. Force streaming mode
+	req.Stream = true
+
+	// 2. Send the request
+	resp, err := c.post(ctx, c.streamClient, "/api/chat", req)
+	if err != nil {
+		return nil, err
+	}
+	defer resp.Body.Close()
+
+	// 3. Decode one JSON object per line until Ollama reports done
+	decoder := json.NewDecoder(resp.Body)
+	for {
+		var chunk ChatResponse
+		if err := decoder.Decode(&chunk); err != nil {
+			if err == io.EOF {
+				return nil, fmt.Errorf("ollama stream ended before completion")
+			}
+			return nil, fmt.Errorf("failed to decode stream chunk: %w", err)
+		}
+		if chunk.Error != "" {
+			return nil, fmt.Errorf("ollama stream error: %s", chunk.Error)
+		}
+
+		if err := onChunk(&chunk); err != nil {
+			return nil, err
+		}
+
+		if chunk.Done {
+			return &chunk, nil
+		}
+	}
+}
+
+// post sends body as JSON to an API path and checks the response status. The
+// caller closes the response body.
+func (c *Client) post(ctx context.Context, httpClient *http.Client, path string, body interface{}) (*http.Response, error) {
+	jsonData, err := json.Marshal(body)
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal request: %w", err)
+	}
+
+	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+path, bytes.NewBuffer(jsonData))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
+	}
+	httpReq.Header.Set("Content-Type", "application/json")
+
+	resp, err := httpClient.Do(httpReq)
+	if err != nil {
+		return nil, fmt.Errorf("failed to call ollama: %w", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		resp.Body.Close()
+		return nil, fmt.Errorf("ollama returned non-200 status: %s", resp.Status)
+	}
+	return resp, nil
+}
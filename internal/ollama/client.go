@@ -1,9 +1,7 @@
 package ollama
 
 import (
-	"bytes"
 	"context"
-	"encoding/json"
 	"fmt"
 	"net/http"
 	"time"
@@ -12,147 +10,73 @@ import (
 type Client struct {
 	baseURL    string
 	httpClient *http.Client
+	// streamClient has no overall timeout: a streamed generation can run for
+	// minutes on big models, so it is bounded by the request context instead
+	streamClient *http.Client
 }
 
-type GenerateRequest struct {
-	Model    string                 `json:"model"`
-	Prompt   string                 `json:"prompt"`
-	Stream   bool                   `json:"stream"`
-	Context  []int                  `json:"context,omitempty"`
-	Options  map[string]interface{} `json:"options,omitempty"`
-	System   string                 `json:"system,omitempty"`
-	Template string                 `json:"template,omitempty"`
-	Raw      bool                   `json:"raw,omitempty"`
+// Metrics are the token counts and timings (in nanoseconds) Ollama reports
+// with the last chunk of an answer
+type Metrics struct {
+	TotalDuration      int64 `json:"total_duration,omitempty"`
+	LoadDuration       int64 `json:"load_duration,omitempty"`
+	PromptEvalCount    int   `json:"prompt_eval_count,omitempty"`
+	PromptEvalDuration int64 `json:"prompt_eval_duration,omitempty"`
+	EvalCount          int   `json:"eval_count,omitempty"`
+	EvalDuration       int64 `json:"eval_duration,omitempty"`
 }
 
-type GenerateResponse struct {
-	Model              string `json:"model"`
-	CreatedAt          string `json:"created_at"`
-	Response           string `json:"response"`
-	Done               bool   `json:"done"`
-	Context            []int  `json:"context,omitempty"`
-	TotalDuration      int64  `json:"total_duration,omitempty"`
-	LoadDuration       int64  `json:"load_duration,omitempty"`
-	PromptEvalCount    int    `json:"prompt_eval_count,omitempty"`
-	PromptEvalDuration int64  `json:"prompt_eval_duration,omitempty"`
-	EvalCount          int    `json:"eval_count,omitempty"`
-	EvalDuration       int64  `json:"eval_duration,omitempty"`
+// Add adds the counts and timings of other, e.g. of another attempt at the
+// same answer
+func (m *Metrics) Add(other Metrics) {
+	m.TotalDuration += other.TotalDuration
+	m.LoadDuration += other.LoadDuration
+	m.PromptEvalCount += other.PromptEvalCount
+	m.PromptEvalDuration += other.PromptEvalDuration
+	m.EvalCount += other.EvalCount
+	m.EvalDuration += other.EvalDuration
 }
 
+// TokensPerSecond returns the generation speed: completion tokens over the
+// evaluation time (0 when Ollama did not report it)
+func (m *Metrics) TokensPerSecond() float64 {
+	if m.EvalDuration <= 0 {
+		return 0
+	}
+	return float64(m.EvalCount) / time.Duration(m.EvalDuration).Seconds()
+}
+
+// ChatSession is the conversation of one session with a model, sent along
+// every chat request
 type ChatSession struct {
-	Model   string
-	Context []int
-	System  string
+	Model  string
+	System string
+
+	// History holds the previous messages, oldest first, without the system
+	// prompt. It is trimmed by Append according to MaxTurns and MaxChars
+	// (0 disables a limit); a turn starts at a user message. Images count
+	// towards MaxChars with their base64 size, since they are sent again with
+	// every request.
+	History  []Message
+	MaxTurns int
+	MaxChars int
 }
 
-func NewClient(baseURL string) *Client {
+// NewClient creates a client for the Ollama API at baseURL. timeout bounds
+// non-streaming requests; 0 uses 30 seconds.
+func NewClient(baseURL string, timeout time.Duration) *Client {
+	if timeout <= 0 {
+		timeout = 30 * time.Second // Gemma3 may take a little while
+	}
 	return &Client{
 		baseURL: baseURL,
 		httpClient: &http.Client{
-			Timeout: 30 * time.Second, // Gemma3 may take a little while
+			Timeout: timeout,
 		},
+		streamClient: &http.Client{},
 	}
 }
 
-func (c *Client) Generate(ctx context.Context, model, prompt string) (string, error) {
-	return c.GenerateWithOptions(ctx, model, prompt, nil)
-}
-
-func (c *Client) GenerateWithOptions(ctx context.Context, model, prompt string, options map[string]interface{}) (string, error) {
-	// 1. Prepare the request
-	req := GenerateRequest{
-		Model:   model,
-		Prompt:  prompt,
-		Stream:  false,
-		Options: options,
-	}
-
-	// 2. Serialize the request to JSON
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return "", fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// 3. Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return "", fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	// 4. Set the headers
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// 5. Send the request
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return "", fmt.Errorf("failed to call ollama: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	// 6. Check the response status
-	if resp.StatusCode != http.StatusOK {
-		return "", fmt.Errorf("ollama returned non-200 status: %s", resp.Status)
-	}
-
-	// 7. Decode the response
-	var ollamaResp GenerateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return "", fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// 8. Return the generated text
-	return ollamaResp.Response, nil
-}
-
-func (c *Client) GenerateWithContext(ctx context.Context, model, prompt string, contextData []int, system string) (*GenerateResponse, error) {
-	// 1. Prepare the request with context
-	req := GenerateRequest{
-		Model:   model,
-		Prompt:  prompt,
-		Stream:  false,
-		Context: contextData,
-		System:  system,
-	}
-
-	// 2. Serialize the request to JSON
-	jsonData, err := json.Marshal(req)
-	if err != nil {
-		return nil, fmt.Errorf("failed to marshal request: %w", err)
-	}
-
-	// 3. Create the HTTP request
-	httpReq, err := http.NewRequestWithContext(ctx, "POST", c.baseURL+"/api/generate", bytes.NewBuffer(jsonData))
-	if err != nil {
-		return nil, fmt.Errorf("failed to create HTTP request: %w", err)
-	}
-
-	// 4. Set the headers
-	httpReq.Header.Set("Content-Type", "application/json")
-
-	// 5. Send the request
-	resp, err := c.httpClient.Do(httpReq)
-	if err != nil {
-		return nil, fmt.Errorf("failed to call ollama: %w", err)
-	}
-
-	defer resp.Body.Close()
-
-	// 6. Check the response status
-	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("ollama returned non-200 status: %s", resp.Status)
-	}
-
-	// 7. Decode the response
-	var ollamaResp GenerateResponse
-	if err := json.NewDecoder(resp.Body).Decode(&ollamaResp); err != nil {
-		return nil, fmt.Errorf("failed to decode response: %w", err)
-	}
-
-	// 8. Return the full response with context
-	return &ollamaResp, nil
-}
-
 func (c *Client) HealthCheck(ctx context.Context) error {
 	httpReq, err := http.NewRequestWithContext(ctx, "GET", c.baseURL+"/api/version", nil)
 	if err != nil {
@@ -174,22 +98,68 @@ func (c *Client) HealthCheck(ctx context.Context) error {
 
 func NewChatSession(model, system string) *ChatSession {
 	return &ChatSession{
-		Model:   model,
-		Context: make([]int, 0),
-		System:  system,
+		Model:  model,
+		System: system,
 	}
 }
 
-func (s *ChatSession) GenerateResponse(ctx context.Context, client *Client, prompt string) (string, error) {
-	resp, err := client.GenerateWithContext(ctx, s.Model, prompt, s.Context, s.System)
-	if err != nil {
-		return "", err
+// Messages returns the messages of a chat request for a new user message:
+// the system prompt, the history and the message itself
+func (s *ChatSession) Messages(userMessage Message) []Message {
+	messages := make([]Message, 0, len(s.History)+2)
+	if s.System != "" {
+		messages = append(messages, Message{Role: RoleSystem, Content: s.System})
 	}
+	messages = append(messages, s.History...)
+	return append(messages, userMessage)
+}
 
-	// Update context for conversation continuity
-	if len(resp.Context) > 0 {
-		s.Context = resp.Context
+// Record appends a completed exchange to the history: the user message, the
+// tool calls and results that led to the answer, and the answer. The images of
+// the user message stay in the history, so later questions can refer to them.
+func (s *ChatSession) Record(userMessage Message, toolMessages []Message, assistantMessage string) {
+	messages := make([]Message, 0, len(toolMessages)+2)
+	messages = append(messages, userMessage)
+	messages = append(messages, toolMessages...)
+	s.Append(append(messages, Message{Role: RoleAssistant, Content: assistantMessage})...)
+}
+
+// size is what a message adds to the history budget: its content and the
+// base64 data of its images
+func (m *Message) size() int {
+	size := len(m.Content)
+	for _, image := range m.Images {
+		size += len(image)
 	}
+	return size
+}
 
-	return resp.Response, nil
+// Append adds messages to the history and drops the oldest turns until the
+// history fits in MaxTurns and MaxChars
+func (s *ChatSession) Append(messages ...Message) {
+	s.History = append(s.History, messages...)
+
+	for {
+		turns, size, second := 0, 0, -1
+		for i, message := range s.History {
+			if message.Role == RoleUser {
+				turns++
+				if i > 0 && second < 0 {
+					second = i
+				}
+			}
+			size += message.size()
+		}
+
+		// Always keep the latest turn, even if it alone is over budget
+		if second < 0 {
+			return
+		}
+		overTurns := s.MaxTurns > 0 && turns > s.MaxTurns
+		overChars := s.MaxChars > 0 && size > s.MaxChars
+		if !overTurns && !overChars {
+			return
+		}
+		s.History = s.History[second:]
+	}
 }
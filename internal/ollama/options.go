@@ -0,0 +1,87 @@
+package ollama
+
+import "slices"
+
+// Options are the generation parameters of a request, named as in the
+// Ollama API. Nil fields leave the model defaults.
+type Options struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	TopK        *int     `json:"top_k,omitempty"`
+	NumCtx      *int     `json:"num_ctx,omitempty"`
+	Seed        *int64   `json:"seed,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+	NumPredict  *int     `json:"num_predict,omitempty"` // tokens to generate at most
+}
+
+// Merge returns a copy of o with the fields set in override replacing its
+// own. Either may be nil.
+func (o *Options) Merge(override *Options) *Options {
+	merged := &Options{}
+	if o != nil {
+		*merged = *o
+		merged.Stop = slices.Clone(o.Stop)
+	}
+	if override == nil {
+		return merged
+	}
+
+	if override.Temperature != nil {
+		merged.Temperature = override.Temperature
+	}
+	if override.TopP != nil {
+		merged.TopP = override.TopP
+	}
+	if override.TopK != nil {
+		merged.TopK = override.TopK
+	}
+	if override.NumCtx != nil {
+		merged.NumCtx = override.NumCtx
+	}
+	if override.Seed != nil {
+		merged.Seed = override.Seed
+	}
+	if len(override.Stop) > 0 {
+		merged.Stop = slices.Clone(override.Stop)
+	}
+	if override.NumPredict != nil {
+		merged.NumPredict = override.NumPredict
+	}
+	return merged
+}
+
+// Map returns the options in the form of ChatRequest.Options, nil when
+// none is set
+func (o *Options) Map() map[string]interface{} {
+	if o == nil {
+		return nil
+	}
+
+	options := make(map[string]interface{})
+	if o.Temperature != nil {
+		options["temperature"] = *o.Temperature
+	}
+	if o.TopP != nil {
+		options["top_p"] = *o.TopP
+	}
+	if o.TopK != nil {
+		options["top_k"] = *o.TopK
+	}
+	if o.NumCtx != nil {
+		options["num_ctx"] = *o.NumCtx
+	}
+	if o.Seed != nil {
+		options["seed"] = *o.Seed
+	}
+	if len(o.Stop) > 0 {
+		options["stop"] = o.Stop
+	}
+	if o.NumPredict != nil {
+		options["num_predict"] = *o.NumPredict
+	}
+
+	if len(options) == 0 {
+		return nil
+	}
+	return options
+}
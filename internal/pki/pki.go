@@ -0,0 +1,285 @@
+// Package pki creates a local certificate authority and the server and client
+// certificates signed by it, for development and self-hosted mTLS setups.
+package pki
+
+import (
+	"crypto"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"errors"
+	"fmt"
+	"math/big"
+	"net"
+	"net/url"
+	"os"
+	"path/filepath"
+	"time"
+)
+
+// Organization is the subject organization of every generated certificate
+const Organization = "Gentleman MCP"
+
+// Certificate is a certificate with its private key
+type Certificate struct {
+	Cert *x509.Certificate
+	Key  crypto.Signer
+}
+
+// CA is a certificate authority that can issue and revoke certificates
+type CA struct {
+	Certificate
+}
+
+// NewCA creates a self-signed CA
+func NewCA(commonName string, validity time.Duration) (*CA, error) {
+	template, err := newTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.IsCA = true
+	template.BasicConstraintsValid = true
+	template.MaxPathLenZero = true
+	template.KeyUsage = x509.KeyUsageCertSign | x509.KeyUsageCRLSign
+
+	cert, err := sign(template, nil)
+	if err != nil {
+		return nil, err
+	}
+	return &CA{Certificate: *cert}, nil
+}
+
+// LoadCA reads a CA certificate and its private key
+func LoadCA(certFile, keyFile string) (*CA, error) {
+	cert, err := Load(certFile, keyFile)
+	if err != nil {
+		return nil, err
+	}
+	if !cert.Cert.IsCA {
+		return nil, fmt.Errorf("%s is not a CA certificate", certFile)
+	}
+	return &CA{Certificate: *cert}, nil
+}
+
+// IssueServer issues a server certificate valid for the given DNS names and
+// IP addresses
+func (ca *CA) IssueServer(commonName string, hosts []string, validity time.Duration) (*Certificate, error) {
+	template, err := newTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageServerAuth}
+	for _, host := range hosts {
+		if ip := net.ParseIP(host); ip != nil {
+			template.IPAddresses = append(template.IPAddresses, ip)
+		} else {
+			template.DNSNames = append(template.DNSNames, host)
+		}
+	}
+
+	return sign(template, ca)
+}
+
+// IssueClient issues a client certificate. The URIs, typically a SPIFFEID,
+// let the gateway map the certificate to a tenant and agent.
+func (ca *CA) IssueClient(commonName string, uris []*url.URL, validity time.Duration) (*Certificate, error) {
+	template, err := newTemplate(commonName, validity)
+	if err != nil {
+		return nil, err
+	}
+	template.KeyUsage = x509.KeyUsageDigitalSignature
+	template.ExtKeyUsage = []x509.ExtKeyUsage{x509.ExtKeyUsageClientAuth}
+	template.URIs = uris
+
+	return sign(template, ca)
+}
+
+// Revoke adds certificates to the CRL at crlFile, creating it if needed, and
+// signs a new version of it valid for validity
+func (ca *CA) Revoke(crlFile string, validity time.Duration, certs ...*x509.Certificate) error {
+	list := &x509.RevocationList{Number: big.NewInt(1)}
+
+	if data, err := os.ReadFile(crlFile); err == nil {
+		if block, _ := pem.Decode(data); block != nil {
+			data = block.Bytes
+		}
+		existing, err := x509.ParseRevocationList(data)
+		if err != nil {
+			return fmt.Errorf("invalid CRL %s: %w", crlFile, err)
+		}
+		if err := existing.CheckSignatureFrom(ca.Cert); err != nil {
+			return fmt.Errorf("CRL %s was not signed by this CA: %w", crlFile, err)
+		}
+		list.RevokedCertificateEntries = existing.RevokedCertificateEntries
+		list.Number = new(big.Int).Add(existing.Number, big.NewInt(1))
+	} else if !errors.Is(err, os.ErrNotExist) {
+		return fmt.Errorf("failed to read CRL: %w", err)
+	}
+
+	now := time.Now()
+	for _, cert := range certs {
+		if err := cert.CheckSignatureFrom(ca.Cert); err != nil {
+			return fmt.Errorf("certificate %s was not issued by this CA", cert.Subject)
+		}
+		list.RevokedCertificateEntries = append(list.RevokedCertificateEntries, x509.RevocationListEntry{
+			SerialNumber:   cert.SerialNumber,
+			RevocationTime: now,
+		})
+	}
+	list.ThisUpdate = now
+	list.NextUpdate = now.Add(validity)
+
+	der, err := x509.CreateRevocationList(rand.Reader, list, ca.Cert, ca.Key)
+	if err != nil {
+		return fmt.Errorf("failed to sign CRL: %w", err)
+	}
+	return writeFile(crlFile, pem.EncodeToMemory(&pem.Block{Type: "X509 CRL", Bytes: der}), 0o644)
+}
+
+// SPIFFEID returns the URI SAN that identifies a tenant's agent:
+// spiffe://<trustDomain>/tenant/<tenantID>/agent/<agentID>
+func SPIFFEID(trustDomain, tenantID, agentID string) *url.URL {
+	return &url.URL{
+		Scheme: "spiffe",
+		Host:   trustDomain,
+		Path:   "/tenant/" + url.PathEscape(tenantID) + "/agent/" + url.PathEscape(agentID),
+	}
+}
+
+// Save writes the certificate and its private key as PEM files. The key is
+// only readable by its owner.
+func (c *Certificate) Save(certFile, keyFile string) error {
+	keyDER, err := x509.MarshalPKCS8PrivateKey(c.Key)
+	if err != nil {
+		return fmt.Errorf("failed to encode private key: %w", err)
+	}
+	if err := writeFile(keyFile, pem.EncodeToMemory(&pem.Block{Type: "PRIVATE KEY", Bytes: keyDER}), 0o600); err != nil {
+		return err
+	}
+	return writeFile(certFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: c.Cert.Raw}), 0o644)
+}
+
+// Load reads a PEM certificate and its PKCS#8, PKCS#1 or SEC 1 private key
+func Load(certFile, keyFile string) (*Certificate, error) {
+	cert, err := LoadCertificate(certFile)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err := os.ReadFile(keyFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read private key: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil {
+		return nil, fmt.Errorf("no PEM data found in %s", keyFile)
+	}
+	key, err := parsePrivateKey(block.Bytes)
+	if err != nil {
+		return nil, fmt.Errorf("invalid private key %s: %w", keyFile, err)
+	}
+
+	return &Certificate{Cert: cert, Key: key}, nil
+}
+
+// LoadCertificate reads the first certificate of a PEM file
+func LoadCertificate(certFile string) (*x509.Certificate, error) {
+	data, err := os.ReadFile(certFile)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read certificate: %w", err)
+	}
+	block, _ := pem.Decode(data)
+	if block == nil || block.Type != "CERTIFICATE" {
+		return nil, fmt.Errorf("no certificate found in %s", certFile)
+	}
+	return x509.ParseCertificate(block.Bytes)
+}
+
+// newTemplate returns a certificate template with a fresh serial number
+func newTemplate(commonName string, validity time.Duration) (*x509.Certificate, error) {
+	serial, err := rand.Int(rand.Reader, new(big.Int).Lsh(big.NewInt(1), 128))
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate serial number: %w", err)
+	}
+
+	now := time.Now()
+	return &x509.Certificate{
+		SerialNumber: serial,
+		Subject: pkix.Name{
+			Organization: []string{Organization},
+			CommonName:   commonName,
+		},
+		NotBefore: now.Add(-5 * time.Minute), // tolerate clock skew
+		NotAfter:  now.Add(validity),
+	}, nil
+}
+
+// sign generates a key for the template and signs it with the CA, or
+// self-signs it when ca is nil
+func sign(template *x509.Certificate, ca *CA) (*Certificate, error) {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	if err != nil {
+		return nil, fmt.Errorf("failed to generate key: %w", err)
+	}
+
+	parent, parentKey := template, crypto.Signer(key)
+	if ca != nil {
+		parent, parentKey = ca.Cert, ca.Key
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, parent, key.Public(), parentKey)
+	if err != nil {
+		return nil, fmt.Errorf("failed to sign certificate: %w", err)
+	}
+	cert, err := x509.ParseCertificate(der)
+	if err != nil {
+		return nil, err
+	}
+
+	return &Certificate{Cert: cert, Key: key}, nil
+}
+
+func parsePrivateKey(der []byte) (crypto.Signer, error) {
+	if key, err := x509.ParsePKCS8PrivateKey(der); err == nil {
+		if signer, ok := key.(crypto.Signer); ok {
+			return signer, nil
+		}
+		return nil, errors.New("unsupported key type")
+	}
+	if key, err := x509.ParsePKCS1PrivateKey(der); err == nil {
+		return key, nil
+	}
+	return x509.ParseECPrivateKey(der)
+}
+
+// writeFile replaces path atomically, so a server watching it never reads a
+// half-written certificate
+func writeFile(path string, data []byte, perm os.FileMode) error {
+	if err := os.MkdirAll(filepath.Dir(path), 0o755); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*")
+	if err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	defer os.Remove(tmp.Name())
+
+	if _, err := tmp.Write(data); err != nil {
+		tmp.Close()
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := tmp.Close(); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	if err := os.Chmod(tmp.Name(), perm); err != nil {
+		return err
+	}
+	if err := os.Rename(tmp.Name(), path); err != nil {
+		return fmt.Errorf("failed to write %s: %w", path, err)
+	}
+	return nil
+}
@@ -0,0 +1,231 @@
+// Package quota accounts what every tenant uses — sessions, requests and
+// Ollama tokens — per quota window, and enforces the tenant quotas before
+// work starts. Usage is kept in the session store, so it survives restarts
+// with a persistent database.
+package quota
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"sync"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+)
+
+// DefaultWindow is the quota window of tenants that do not set one
+const DefaultWindow = 24 * time.Hour
+
+// Tracker counts the usage of every tenant and rejects work beyond its quotas
+type Tracker struct {
+	usage   store.SessionStore
+	tenants *tenant.Registry
+
+	// Checking a quota and counting the request must not interleave with
+	// another request of the same process
+	mutex sync.Mutex
+}
+
+// Report is the usage of a tenant in the current window, with its quotas
+type Report struct {
+	Usage          store.Usage
+	ActiveSessions int
+	Quotas         tenant.Quotas
+}
+
+// NewTracker creates a Tracker keeping usage in the store and reading the
+// quotas of the tenant registry
+func NewTracker(usage store.SessionStore, tenants *tenant.Registry) *Tracker {
+	return &Tracker{
+		usage:   usage,
+		tenants: tenants,
+	}
+}
+
+// StartSession checks that the tenant can open one more session, stores it
+// with put and counts it. Checking and storing happen under one lock, so
+// concurrent registrations cannot both take the last slot, and nothing is
+// counted when put fails; its error is returned as is. Sessions free up when
+// they expire or are revoked.
+func (t *Tracker) StartSession(tenantID string, put func() error) error {
+	settings, err := t.tenants.Get(tenantID)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "tenant %s is not registered", tenantID)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	if limit := tenant.Value(settings.Quotas.MaxSessions); limit > 0 {
+		active, err := t.usage.CountTenantSessions(tenantID, now)
+		if err != nil {
+			return status.Errorf(codes.Internal, "failed to count sessions: %v", err)
+		}
+		if active >= limit {
+			return exceeded(tenantID, "sessions", fmt.Sprintf("%d active sessions", limit), 0)
+		}
+	}
+
+	if err := put(); err != nil {
+		return err
+	}
+	return t.add(window(settings, now), &store.Usage{Sessions: 1})
+}
+
+// StartRequest checks the request and token quotas of the tenant before a
+// generation and counts the request. Tokens are added afterwards with
+// AddTokens, so the generation that crosses the token quota still completes.
+func (t *Tracker) StartRequest(tenantID string) error {
+	settings, err := t.tenants.Get(tenantID)
+	if err != nil {
+		return status.Errorf(codes.PermissionDenied, "tenant %s is not registered", tenantID)
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	now := time.Now()
+	current := window(settings, now)
+	usage, err := t.get(current)
+	if err != nil {
+		return status.Errorf(codes.Internal, "failed to read usage: %v", err)
+	}
+
+	maxRequests, maxTokens := tenant.Value(settings.Quotas.MaxRequestsPerDay), tenant.Value(settings.Quotas.MaxTokensPerDay)
+	resetIn := current.WindowEnd.Sub(now)
+	if maxRequests > 0 && usage.Requests >= int64(maxRequests) {
+		return exceeded(tenantID, "requests", fmt.Sprintf("%d requests per %s", maxRequests, formatWindow(settings)), resetIn)
+	}
+	if maxTokens > 0 && usage.Tokens() >= int64(maxTokens) {
+		return exceeded(tenantID, "tokens", fmt.Sprintf("%d tokens per %s", maxTokens, formatWindow(settings)), resetIn)
+	}
+
+	return t.add(current, &store.Usage{Requests: 1})
+}
+
+// AddTokens counts the prompt and completion tokens of a generation. Failures
+// are logged: the answer was already delivered.
+func (t *Tracker) AddTokens(tenantID string, promptTokens, completionTokens int) {
+	settings, err := t.tenants.Get(tenantID)
+	if err != nil {
+		return
+	}
+
+	t.mutex.Lock()
+	defer t.mutex.Unlock()
+
+	err = t.add(window(settings, time.Now()), &store.Usage{
+		PromptTokens:     int64(promptTokens),
+		CompletionTokens: int64(completionTokens),
+	})
+	if err != nil {
+		log.Printf("❌ Failed to record token usage of tenant %s: %v", tenantID, err)
+	}
+}
+
+// Usage reports the usage of a tenant in the current window
+func (t *Tracker) Usage(tenantID string) (*Report, error) {
+	settings, err := t.tenants.Get(tenantID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := time.Now()
+	usage, err := t.get(window(settings, now))
+	if err != nil {
+		return nil, err
+	}
+	active, err := t.usage.CountTenantSessions(tenantID, now)
+	if err != nil {
+		return nil, err
+	}
+
+	quotas := settings.Quotas
+	quotas.Window = windowLength(settings)
+	return &Report{
+		Usage:          *usage,
+		ActiveSessions: active,
+		Quotas:         quotas,
+	}, nil
+}
+
+// get returns the usage of a window, empty when nothing was counted yet
+func (t *Tracker) get(current *store.Usage) (*store.Usage, error) {
+	usage, err := t.usage.GetUsage(current.TenantID, current.WindowStart)
+	if errors.Is(err, store.ErrNotFound) {
+		return current, nil
+	}
+	return usage, err
+}
+
+// add adds delta to the usage of a window
+func (t *Tracker) add(current *store.Usage, delta *store.Usage) error {
+	delta.TenantID = current.TenantID
+	delta.WindowStart = current.WindowStart
+	delta.WindowEnd = current.WindowEnd
+	if _, err := t.usage.AddUsage(delta); err != nil {
+		return status.Errorf(codes.Internal, "failed to record usage: %v", err)
+	}
+	return nil
+}
+
+// window returns an empty usage record for the window of the tenant that
+// contains now. Windows are aligned to multiples of their length since the
+// Unix epoch, so daily windows reset at midnight UTC.
+func window(settings *tenant.Tenant, now time.Time) *store.Usage {
+	length := windowLength(settings)
+	start := now.UTC().Truncate(length)
+	return &store.Usage{
+		TenantID:    settings.ID,
+		WindowStart: start,
+		WindowEnd:   start.Add(length),
+	}
+}
+
+func windowLength(settings *tenant.Tenant) time.Duration {
+	if settings.Quotas.Window > 0 {
+		return settings.Quotas.Window
+	}
+	return DefaultWindow
+}
+
+// formatWindow describes the window length of a tenant, e.g. "24h"
+func formatWindow(settings *tenant.Tenant) string {
+	length := windowLength(settings)
+	switch {
+	case length%time.Hour == 0:
+		return fmt.Sprintf("%dh", length/time.Hour)
+	case length%time.Minute == 0:
+		return fmt.Sprintf("%dm", length/time.Minute)
+	}
+	return length.String()
+}
+
+// exceeded builds the ResourceExhausted error of an exhausted quota. Quotas
+// that reset tell the client when with RetryInfo.
+func exceeded(tenantID, quota, description string, resetIn time.Duration) error {
+	st := status.Newf(codes.ResourceExhausted, "%s quota of tenant %s exhausted (%s)", quota, tenantID, description)
+	details := []protoadapt.MessageV1{
+		&errdetails.QuotaFailure{Violations: []*errdetails.QuotaFailure_Violation{{
+			Subject:     "tenant:" + tenantID,
+			Description: description,
+		}}},
+	}
+	if resetIn > 0 {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(resetIn)})
+	}
+	detailed, err := st.WithDetails(details...)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
@@ -0,0 +1,188 @@
+package quota
+
+import (
+	"errors"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/store"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+)
+
+func limit(n int) *int { return &n }
+
+func newTracker(quotas tenant.Quotas) (*Tracker, *store.MemoryStore) {
+	sessions := store.NewMemoryStore()
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", Quotas: quotas}})
+	return NewTracker(sessions, tenants), sessions
+}
+
+// retryDelay returns the RetryInfo delay of a ResourceExhausted error, 0
+// without one
+func retryDelay(t *testing.T, err error) time.Duration {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v (%v), want ResourceExhausted", st.Code(), err)
+	}
+	for _, detail := range st.Details() {
+		if retry, ok := detail.(*errdetails.RetryInfo); ok {
+			return retry.RetryDelay.AsDuration()
+		}
+	}
+	return 0
+}
+
+func TestStartRequestAndAddTokens(t *testing.T) {
+	tracker, _ := newTracker(tenant.Quotas{MaxRequestsPerDay: limit(3), MaxTokensPerDay: limit(100)})
+
+	if err := tracker.StartRequest("acme"); err != nil {
+		t.Fatalf("first request: %v", err)
+	}
+	tracker.AddTokens("acme", 30, 20)
+	if err := tracker.StartRequest("acme"); err != nil {
+		t.Fatalf("second request: %v", err)
+	}
+	report, err := tracker.Usage("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	usage := report.Usage
+	if usage.Requests != 2 || usage.PromptTokens != 30 || usage.CompletionTokens != 20 {
+		t.Errorf("usage = %+v, want 2 requests and 30+20 tokens", usage)
+	}
+
+	// The generation crossing the token quota completes; the next one is refused
+	tracker.AddTokens("acme", 40, 40)
+	err = tracker.StartRequest("acme")
+	if delay := retryDelay(t, err); delay <= 0 || delay > DefaultWindow {
+		t.Errorf("retry delay = %v, want the time until the window resets", delay)
+	}
+	report, _ = tracker.Usage("acme")
+	if report.Usage.Requests != 2 {
+		t.Errorf("requests = %d, a refused request must not be counted", report.Usage.Requests)
+	}
+
+	if err := tracker.StartRequest("stranger"); status.Code(err) != codes.PermissionDenied {
+		t.Errorf("request of an unknown tenant = %v, want PermissionDenied", err)
+	}
+}
+
+func TestRequestQuota(t *testing.T) {
+	tests := []struct {
+		name    string
+		quotas  tenant.Quotas
+		allowed int
+	}{
+		{"limited", tenant.Quotas{MaxRequestsPerDay: limit(2)}, 2},
+		{"unlimited", tenant.Quotas{MaxRequestsPerDay: limit(0)}, 10},
+		{"unset", tenant.Quotas{}, 10},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tracker, _ := newTracker(tt.quotas)
+			for i := 0; i < 10; i++ {
+				err := tracker.StartRequest("acme")
+				if allowed := err == nil; allowed != (i < tt.allowed) {
+					t.Fatalf("request %d: %v, want allowed %v", i+1, err, i < tt.allowed)
+				}
+			}
+		})
+	}
+}
+
+func TestWindowsAlignToEpoch(t *testing.T) {
+	tests := []struct {
+		name   string
+		window time.Duration
+		now    time.Time
+		start  time.Time
+	}{
+		{"day", 0, time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC), time.Date(2026, 3, 14, 0, 0, 0, 0, time.UTC)},
+		{"day in another zone", 0, time.Date(2026, 3, 14, 1, 0, 0, 0, time.FixedZone("UTC+3", 3*3600)), time.Date(2026, 3, 13, 0, 0, 0, 0, time.UTC)},
+		{"hour", time.Hour, time.Date(2026, 3, 14, 15, 9, 26, 0, time.UTC), time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC)},
+		{"window end", time.Hour, time.Date(2026, 3, 14, 15, 59, 59, 0, time.UTC), time.Date(2026, 3, 14, 15, 0, 0, 0, time.UTC)},
+		{"next window", time.Hour, time.Date(2026, 3, 14, 16, 0, 0, 0, time.UTC), time.Date(2026, 3, 14, 16, 0, 0, 0, time.UTC)},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			settings := &tenant.Tenant{ID: "acme", Quotas: tenant.Quotas{Window: tt.window}}
+			got := window(settings, tt.now)
+			length := windowLength(settings)
+			if !got.WindowStart.Equal(tt.start) || !got.WindowEnd.Equal(tt.start.Add(length)) {
+				t.Errorf("window = %v - %v, want %v - %v", got.WindowStart, got.WindowEnd, tt.start, tt.start.Add(length))
+			}
+		})
+	}
+}
+
+func TestUsageRollsOverWithTheWindow(t *testing.T) {
+	tracker, sessions := newTracker(tenant.Quotas{MaxRequestsPerDay: limit(1), Window: time.Minute})
+
+	// Usage of the previous window does not count against the current one
+	current := window(&tenant.Tenant{ID: "acme", Quotas: tenant.Quotas{Window: time.Minute}}, time.Now())
+	_, err := sessions.AddUsage(&store.Usage{
+		TenantID:    "acme",
+		WindowStart: current.WindowStart.Add(-time.Minute),
+		WindowEnd:   current.WindowStart,
+		Requests:    1,
+	})
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := tracker.StartRequest("acme"); err != nil {
+		// The minute may have turned between the two calls
+		if retryDelay(t, err) > time.Minute {
+			t.Fatalf("request in a new window: %v", err)
+		}
+	}
+
+	report, err := tracker.Usage("acme")
+	if err != nil {
+		t.Fatal(err)
+	}
+	if report.Quotas.Window != time.Minute || report.Usage.WindowEnd.Sub(report.Usage.WindowStart) != time.Minute {
+		t.Errorf("report = %+v, want a one-minute window", report)
+	}
+}
+
+func TestStartSession(t *testing.T) {
+	tracker, sessions := newTracker(tenant.Quotas{MaxSessions: limit(2)})
+	register := func(id string, expiresAt time.Time) error {
+		return tracker.StartSession("acme", func() error {
+			return sessions.PutSession(&store.Session{SessionID: id, TenantID: "acme", ExpiresAt: expiresAt})
+		})
+	}
+
+	// Expired sessions free their slot
+	if err := register("expired", time.Now().Add(-time.Minute)); err != nil {
+		t.Fatal(err)
+	}
+	for _, id := range []string{"first", "second"} {
+		if err := register(id, time.Now().Add(time.Hour)); err != nil {
+			t.Fatalf("session %s: %v", id, err)
+		}
+	}
+	err := register("third", time.Now().Add(time.Hour))
+	if delay := retryDelay(t, err); delay != 0 {
+		t.Errorf("retry delay = %v, the session quota does not reset with the window", delay)
+	}
+	if _, err := sessions.GetSession("third"); !errors.Is(err, store.ErrNotFound) {
+		t.Errorf("a refused session was stored: %v", err)
+	}
+
+	// Failures of put are returned as is and nothing is counted
+	failure := errors.New("disk full")
+	sessions.DeleteSession("second")
+	if err := tracker.StartSession("acme", func() error { return failure }); !errors.Is(err, failure) {
+		t.Errorf("StartSession = %v, want the error of put", err)
+	}
+	report, _ := tracker.Usage("acme")
+	if report.ActiveSessions != 1 || report.Usage.Sessions != 3 {
+		t.Errorf("report = %+v, want 1 active session and 3 registered", report)
+	}
+}
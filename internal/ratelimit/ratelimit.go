@@ -0,0 +1,294 @@
+// Package ratelimit throttles RPCs with token buckets: one per tenant (or
+// per agent, when the tenant asks for it), one per client address for calls
+// that are not authenticated yet, plus an optional gateway-wide bucket.
+//
+// Rejected calls fail with ResourceExhausted carrying RetryInfo and
+// QuotaFailure details, so clients know how long to back off.
+package ratelimit
+
+import (
+	"context"
+	"fmt"
+	"net"
+	"strings"
+	"sync"
+	"time"
+
+	"golang.org/x/time/rate"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/types/known/durationpb"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+// idleBucketTTL is how long the bucket of a quiet tenant or agent is kept;
+// a forgotten bucket starts full again
+const idleBucketTTL = 10 * time.Minute
+
+// GlobalLimit is the gateway-wide limit applied on top of the tenant limits
+type GlobalLimit struct {
+	Enabled           bool
+	RequestsPerSecond int
+	Burst             int
+}
+
+// Limiter enforces the rate limits of the tenant registry. Limits are read on
+// every call, so tenant changes apply to existing buckets right away.
+type Limiter struct {
+	tenants *tenant.Registry
+	exempt  []string
+
+	mutex       sync.Mutex
+	buckets     map[string]*bucket
+	global      *rate.Limiter
+	globalLimit GlobalLimit
+	lastPrune   time.Time
+}
+
+// bucket is the token bucket of one tenant or agent
+type bucket struct {
+	limiter  *rate.Limiter
+	lastUsed time.Time
+}
+
+// NewLimiter creates a Limiter for the tenants of the registry. Exempt
+// entries are full method names or service prefixes ending in a slash, like
+// the public methods of auth.NewAuthenticator.
+func NewLimiter(tenants *tenant.Registry, global GlobalLimit, exempt ...string) *Limiter {
+	l := &Limiter{
+		tenants:   tenants,
+		exempt:    exempt,
+		buckets:   make(map[string]*bucket),
+		lastPrune: time.Now(),
+	}
+	l.SetGlobalLimit(global)
+	return l
+}
+
+// SetGlobalLimit changes the gateway-wide limit. It can be called while
+// serving.
+func (l *Limiter) SetGlobalLimit(global GlobalLimit) {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	l.globalLimit = global
+	if !global.Enabled || global.RequestsPerSecond <= 0 {
+		l.global = nil
+		return
+	}
+	if l.global == nil {
+		l.global = rate.NewLimiter(rate.Limit(global.RequestsPerSecond), max(global.Burst, 1))
+		return
+	}
+	l.global.SetLimit(rate.Limit(global.RequestsPerSecond))
+	l.global.SetBurst(max(global.Burst, 1))
+}
+
+// UnaryServerInterceptor limits unary RPCs. It must run after the auth
+// interceptor, which identifies the tenant.
+func (l *Limiter) UnaryServerInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if !l.isExempt(info.FullMethod) {
+			if err := l.allowCaller(ctx); err != nil {
+				return nil, err
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// StreamServerInterceptor limits streaming RPCs message by message: every
+// request received from the client counts, so each user message of a Chat
+// stream is limited like a unary call. A rejected message ends the stream
+// with the ResourceExhausted status.
+func (l *Limiter) StreamServerInterceptor() grpc.StreamServerInterceptor {
+	return func(srv interface{}, stream grpc.ServerStream, info *grpc.StreamServerInfo, handler grpc.StreamHandler) error {
+		if l.isExempt(info.FullMethod) {
+			return handler(srv, stream)
+		}
+		return handler(srv, &limitedStream{ServerStream: stream, limiter: l})
+	}
+}
+
+// Allow takes a token for a call of an authenticated tenant and agent and
+// returns a ResourceExhausted status error when the call must wait. Only the
+// global limit applies to an empty or unknown tenant.
+func (l *Limiter) Allow(tenantID, agentID string) error {
+	var settings *tenant.Tenant
+	if tenantID != "" {
+		settings, _ = l.tenants.Get(tenantID)
+	}
+	if settings == nil {
+		return l.take("", tenant.RateLimit{})
+	}
+
+	key := "tenant:" + settings.ID
+	if settings.RateLimit.LimitsAgents() && agentID != "" {
+		key += "/agent:" + agentID
+	}
+	return l.take(key, settings.RateLimit)
+}
+
+// AllowAnonymous takes a token for a call of a client that has not
+// authenticated, like Register or Refresh. Its bucket is keyed by the network
+// address of the client and gets the limits of the default tenant: whatever
+// tenant the request names is never charged, so nobody can drain the bucket
+// of another tenant.
+func (l *Limiter) AllowAnonymous(address string) error {
+	return l.take("peer:"+address, l.tenants.Defaults().RateLimit)
+}
+
+// take takes a token from the bucket of key with the given limit (none for an
+// empty key or without requests per minute) and from the global bucket
+func (l *Limiter) take(key string, limit tenant.RateLimit) error {
+	l.mutex.Lock()
+	defer l.mutex.Unlock()
+
+	now := time.Now()
+	l.prune(now)
+
+	// Both buckets must have a token: reserve in each and give the tokens
+	// back when either one has to wait
+	var reservations []*rate.Reservation
+	var violations []*errdetails.QuotaFailure_Violation
+	var delay time.Duration
+	reserve := func(limiter *rate.Limiter, subject, description string) {
+		reservation := limiter.ReserveN(now, 1)
+		reservations = append(reservations, reservation)
+		wait := reservation.DelayFrom(now)
+		if !reservation.OK() || wait > 0 {
+			violations = append(violations, &errdetails.QuotaFailure_Violation{
+				Subject:     subject,
+				Description: description,
+			})
+			delay = max(delay, wait)
+		}
+	}
+
+	if perMinute := tenant.Value(limit.RequestsPerMinute); key != "" && perMinute > 0 {
+		reserve(l.bucket(key, limit, now), key,
+			fmt.Sprintf("%d requests per minute (burst %d)", perMinute, max(tenant.Value(limit.Burst), 1)))
+	}
+	if l.global != nil {
+		reserve(l.global, "global",
+			fmt.Sprintf("%d requests per second for the whole gateway", l.globalLimit.RequestsPerSecond))
+	}
+
+	if len(violations) == 0 {
+		return nil
+	}
+	for _, reservation := range reservations {
+		reservation.CancelAt(now)
+	}
+	return exhausted(violations, delay)
+}
+
+// bucket returns the token bucket for key, creating it on first use and
+// adjusting it when the tenant limits changed
+func (l *Limiter) bucket(key string, limit tenant.RateLimit, now time.Time) *rate.Limiter {
+	perSecond := rate.Limit(float64(tenant.Value(limit.RequestsPerMinute)) / 60)
+	burst := max(tenant.Value(limit.Burst), 1)
+
+	b, exists := l.buckets[key]
+	if !exists {
+		b = &bucket{limiter: rate.NewLimiter(perSecond, burst)}
+		l.buckets[key] = b
+	} else if b.limiter.Limit() != perSecond || b.limiter.Burst() != burst {
+		b.limiter.SetLimitAt(now, perSecond)
+		b.limiter.SetBurstAt(now, burst)
+	}
+	b.lastUsed = now
+	return b.limiter
+}
+
+// prune forgets buckets nobody used for a while
+func (l *Limiter) prune(now time.Time) {
+	if now.Sub(l.lastPrune) < idleBucketTTL {
+		return
+	}
+	l.lastPrune = now
+	for key, b := range l.buckets {
+		if now.Sub(b.lastUsed) > idleBucketTTL {
+			delete(l.buckets, key)
+		}
+	}
+}
+
+func (l *Limiter) isExempt(fullMethod string) bool {
+	for _, exempt := range l.exempt {
+		if exempt == fullMethod || (strings.HasSuffix(exempt, "/") && strings.HasPrefix(fullMethod, exempt)) {
+			return true
+		}
+	}
+	return false
+}
+
+// exhausted builds the ResourceExhausted error of a rejected call
+func exhausted(violations []*errdetails.QuotaFailure_Violation, delay time.Duration) error {
+	// Round up, so clients waiting exactly RetryDelay find a token
+	delay = delay.Round(time.Millisecond) + time.Millisecond
+
+	st := status.Newf(codes.ResourceExhausted, "rate limit exceeded for %s, retry in %s", violations[0].Subject, delay)
+	detailed, err := st.WithDetails(
+		&errdetails.RetryInfo{RetryDelay: durationpb.New(delay)},
+		&errdetails.QuotaFailure{Violations: violations},
+	)
+	if err != nil {
+		return st.Err()
+	}
+	return detailed.Err()
+}
+
+// allowCaller takes a token for a call: from the bucket of the authenticated
+// principal, or for public methods like Register from the bucket of the peer
+func (l *Limiter) allowCaller(ctx context.Context) error {
+	if principal, ok := auth.FromContext(ctx); ok {
+		return l.Allow(principal.TenantID, principal.AgentID)
+	}
+	return l.AllowAnonymous(peerAddress(ctx))
+}
+
+// peerAddress returns the host of the client of a call, without the port so
+// new connections share the bucket of the client
+func peerAddress(ctx context.Context) string {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return "unknown"
+	}
+	address := p.Addr.String()
+	if host, _, err := net.SplitHostPort(address); err == nil {
+		return host
+	}
+	return address
+}
+
+// limitedStream takes a token for every request received from the client.
+// Of the messages of a Chat stream only user messages are charged: tool
+// results belong to the answer that called the tool, and system and
+// assistant messages only shape the conversation.
+type limitedStream struct {
+	grpc.ServerStream
+	limiter *Limiter
+}
+
+func (s *limitedStream) RecvMsg(m interface{}) error {
+	if err := s.ServerStream.RecvMsg(m); err != nil {
+		return err
+	}
+	if msg, ok := m.(*mcpv1.ChatMessage); ok && !isUserMessage(msg) {
+		return nil
+	}
+	return s.limiter.allowCaller(s.Context())
+}
+
+// isUserMessage reports whether a Chat message asks for an answer. Messages
+// without a type are user messages.
+func isUserMessage(msg *mcpv1.ChatMessage) bool {
+	return msg.Type == mcpv1.MessageType_MESSAGE_TYPE_USER || msg.Type == mcpv1.MessageType_MESSAGE_TYPE_UNSPECIFIED
+}
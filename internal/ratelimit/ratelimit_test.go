@@ -0,0 +1,220 @@
+package ratelimit
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/auth"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/tenant"
+	mcpv1 "github.com/Gentleman-Programming/gentleman-mcp/proto/mcp/v1"
+)
+
+func limit(n int) *int { return &n }
+
+// rateLimit is a tenant rate limit of perMinute requests with a burst
+func rateLimit(perMinute, burst int, perAgent bool) tenant.RateLimit {
+	return tenant.RateLimit{RequestsPerMinute: limit(perMinute), Burst: limit(burst), PerAgent: &perAgent}
+}
+
+// details returns the RetryInfo and QuotaFailure details of a rejection
+func details(t *testing.T, err error) (*errdetails.RetryInfo, *errdetails.QuotaFailure) {
+	t.Helper()
+	st := status.Convert(err)
+	if st.Code() != codes.ResourceExhausted {
+		t.Fatalf("code = %v (%v), want ResourceExhausted", st.Code(), err)
+	}
+	var retry *errdetails.RetryInfo
+	var failure *errdetails.QuotaFailure
+	for _, detail := range st.Details() {
+		switch detail := detail.(type) {
+		case *errdetails.RetryInfo:
+			retry = detail
+		case *errdetails.QuotaFailure:
+			failure = detail
+		}
+	}
+	if retry == nil || failure == nil {
+		t.Fatalf("details = %v, want RetryInfo and QuotaFailure", st.Details())
+	}
+	return retry, failure
+}
+
+func TestAllowRefillsBucket(t *testing.T) {
+	// 6000 per minute refills a token every 10ms
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", RateLimit: rateLimit(6000, 2, false)}})
+	limiter := NewLimiter(tenants, GlobalLimit{})
+
+	for i := 0; i < 2; i++ {
+		if err := limiter.Allow("acme", "agent-1"); err != nil {
+			t.Fatalf("call %d within the burst: %v", i+1, err)
+		}
+	}
+	err := limiter.Allow("acme", "agent-1")
+	retry, _ := details(t, err)
+	delay := retry.RetryDelay.AsDuration()
+	if delay <= 0 || delay > 20*time.Millisecond {
+		t.Fatalf("retry delay = %v, want about the 10ms a token takes", delay)
+	}
+
+	// A rejected call takes no token, so waiting the delay is enough
+	time.Sleep(delay)
+	if err := limiter.Allow("acme", "agent-1"); err != nil {
+		t.Errorf("call after the retry delay: %v", err)
+	}
+}
+
+func TestAllowKeys(t *testing.T) {
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{
+		{ID: "shared", RateLimit: rateLimit(1, 1, false)},
+		{ID: "per-agent", RateLimit: rateLimit(1, 1, true)},
+		{ID: "unlimited", RateLimit: rateLimit(0, 1, false)},
+	})
+
+	tests := []struct {
+		name     string
+		tenantID string
+		first    string // agent that uses up the bucket
+		second   string // agent calling next
+		allowed  bool
+		subject  string
+	}{
+		{"same agent", "shared", "agent-1", "agent-1", false, "tenant:shared"},
+		{"agents share the tenant bucket", "shared", "agent-1", "agent-2", false, "tenant:shared"},
+		{"same agent of a per-agent tenant", "per-agent", "agent-1", "agent-1", false, "tenant:per-agent/agent:agent-1"},
+		{"agents have their own bucket", "per-agent", "agent-1", "agent-2", true, ""},
+		{"no requests per minute", "unlimited", "agent-1", "agent-1", true, ""},
+		{"unknown tenant", "stranger", "agent-1", "agent-1", true, ""},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			limiter := NewLimiter(tenants, GlobalLimit{})
+			if err := limiter.Allow(tt.tenantID, tt.first); err != nil {
+				t.Fatalf("first call: %v", err)
+			}
+			err := limiter.Allow(tt.tenantID, tt.second)
+			if tt.allowed {
+				if err != nil {
+					t.Errorf("second call: %v, want it allowed", err)
+				}
+				return
+			}
+			_, failure := details(t, err)
+			if len(failure.Violations) != 1 || failure.Violations[0].Subject != tt.subject {
+				t.Errorf("violations = %v, want one for %s", failure.Violations, tt.subject)
+			}
+		})
+	}
+}
+
+func TestAllowAnonymous(t *testing.T) {
+	tenants := tenant.NewRegistry(tenant.Tenant{RateLimit: rateLimit(1, 1, false)}, []tenant.Tenant{{ID: "acme"}})
+	limiter := NewLimiter(tenants, GlobalLimit{})
+
+	if err := limiter.AllowAnonymous("10.0.0.1"); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	_, failure := details(t, limiter.AllowAnonymous("10.0.0.1"))
+	if failure.Violations[0].Subject != "peer:10.0.0.1" {
+		t.Errorf("violations = %v, want one for the address", failure.Violations)
+	}
+
+	// Other addresses and the tenants keep their own buckets
+	if err := limiter.AllowAnonymous("10.0.0.2"); err != nil {
+		t.Errorf("call from another address: %v", err)
+	}
+	if err := limiter.Allow("acme", "agent-1"); err != nil {
+		t.Errorf("call of a tenant: %v", err)
+	}
+}
+
+func TestGlobalLimit(t *testing.T) {
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", RateLimit: rateLimit(1, 1, false)}})
+	limiter := NewLimiter(tenants, GlobalLimit{Enabled: true, RequestsPerSecond: 1, Burst: 1})
+
+	if err := limiter.Allow("acme", "agent-1"); err != nil {
+		t.Fatalf("first call: %v", err)
+	}
+	// Both buckets are empty, and both are reported
+	retry, failure := details(t, limiter.Allow("acme", "agent-1"))
+	var subjects []string
+	for _, violation := range failure.Violations {
+		subjects = append(subjects, violation.Subject)
+	}
+	if len(subjects) != 2 || subjects[0] != "tenant:acme" || subjects[1] != "global" {
+		t.Errorf("violations = %v, want the tenant and the global bucket", subjects)
+	}
+	// The delay is that of the slowest bucket, a minute for the tenant
+	if delay := retry.RetryDelay.AsDuration(); delay < 59*time.Second {
+		t.Errorf("retry delay = %v, want the minute of the tenant bucket", delay)
+	}
+
+	// The global bucket applies to callers without a tenant limit too
+	if err := limiter.Allow("stranger", ""); status.Code(err) != codes.ResourceExhausted {
+		t.Errorf("call of an unknown tenant = %v, want the global limit", err)
+	}
+	limiter.SetGlobalLimit(GlobalLimit{})
+	if err := limiter.Allow("stranger", ""); err != nil {
+		t.Errorf("call after disabling the global limit: %v", err)
+	}
+}
+
+// chatStream is the server side of a Chat stream receiving messages of the
+// given types
+type chatStream struct {
+	grpc.ServerStream
+	ctx   context.Context
+	types []mcpv1.MessageType
+}
+
+func (s *chatStream) Context() context.Context { return s.ctx }
+
+func (s *chatStream) RecvMsg(m interface{}) error {
+	m.(*mcpv1.ChatMessage).Type = s.types[0]
+	s.types = s.types[1:]
+	return nil
+}
+
+func TestStreamChargesUserMessages(t *testing.T) {
+	tenants := tenant.NewRegistry(tenant.Tenant{}, []tenant.Tenant{{ID: "acme", RateLimit: rateLimit(1, 2, false)}})
+	limiter := NewLimiter(tenants, GlobalLimit{})
+	ctx := auth.NewContext(context.Background(), &auth.Principal{TenantID: "acme", AgentID: "agent-1"})
+
+	messages := []struct {
+		messageType mcpv1.MessageType
+		allowed     bool
+	}{
+		{mcpv1.MessageType_MESSAGE_TYPE_USER, true},
+		{mcpv1.MessageType_MESSAGE_TYPE_TOOL_RESULT, true},
+		{mcpv1.MessageType_MESSAGE_TYPE_TOOL_RESULT, true},
+		{mcpv1.MessageType_MESSAGE_TYPE_SYSTEM, true},
+		{mcpv1.MessageType_MESSAGE_TYPE_ASSISTANT, true},
+		{mcpv1.MessageType_MESSAGE_TYPE_UNSPECIFIED, true}, // answered as a user message
+		{mcpv1.MessageType_MESSAGE_TYPE_USER, false},
+		{mcpv1.MessageType_MESSAGE_TYPE_TOOL_RESULT, true},
+	}
+	stream := &chatStream{ctx: ctx}
+	for _, message := range messages {
+		stream.types = append(stream.types, message.messageType)
+	}
+
+	var limited grpc.ServerStream
+	handler := func(srv interface{}, stream grpc.ServerStream) error {
+		limited = stream
+		return nil
+	}
+	if err := limiter.StreamServerInterceptor()(nil, stream, &grpc.StreamServerInfo{FullMethod: "/mcp.v1.AgentService/Chat"}, handler); err != nil {
+		t.Fatal(err)
+	}
+	for i, message := range messages {
+		err := limited.RecvMsg(&mcpv1.ChatMessage{})
+		if allowed := err == nil; allowed != message.allowed {
+			t.Errorf("message %d (%v): %v, want allowed %v", i, message.messageType, err, message.allowed)
+		}
+	}
+}
@@ -0,0 +1,162 @@
+// Package schema validates the JSON answers of models against the JSON
+// Schema a request asks for.
+package schema
+
+import (
+	"bytes"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/santhosh-tekuri/jsonschema/v6"
+)
+
+// resourceURL is the location the schema of a request is compiled under
+const resourceURL = "urn:gentleman:request"
+
+// ErrRemoteReference is returned for schemas referencing other documents,
+// which are never fetched
+var ErrRemoteReference = errors.New("schemas cannot reference other documents")
+
+// Schema is a compiled JSON Schema
+type Schema struct {
+	source   json.RawMessage
+	compiled *jsonschema.Schema // nil accepts any JSON value
+}
+
+// Violation is one reason an answer does not match its schema
+type Violation struct {
+	Location    string // JSON pointer to the offending value, "" for the root
+	Description string
+}
+
+// ValidationError lists why an answer is not valid
+type ValidationError struct {
+	Violations []Violation
+}
+
+func (e *ValidationError) Error() string {
+	return "answer does not match the schema: " + e.Reasons()
+}
+
+// Reasons lists the violations in one line
+func (e *ValidationError) Reasons() string {
+	descriptions := make([]string, len(e.Violations))
+	for i, violation := range e.Violations {
+		descriptions[i] = violation.String()
+	}
+	return strings.Join(descriptions, "; ")
+}
+
+func (v Violation) String() string {
+	if v.Location == "" {
+		return v.Description
+	}
+	return v.Location + ": " + v.Description
+}
+
+// Compile compiles a JSON Schema document. An empty document accepts any
+// JSON value.
+func Compile(document string) (*Schema, error) {
+	if strings.TrimSpace(document) == "" {
+		return &Schema{}, nil
+	}
+
+	doc, err := jsonschema.UnmarshalJSON(strings.NewReader(document))
+	if err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+
+	compiler := jsonschema.NewCompiler()
+	compiler.UseLoader(noLoader{})
+	if err := compiler.AddResource(resourceURL, doc); err != nil {
+		return nil, err
+	}
+	compiled, err := compiler.Compile(resourceURL)
+	if err != nil {
+		return nil, err
+	}
+
+	var source bytes.Buffer
+	if err := json.Compact(&source, []byte(document)); err != nil {
+		return nil, fmt.Errorf("schema is not valid JSON: %w", err)
+	}
+	return &Schema{source: source.Bytes(), compiled: compiled}, nil
+}
+
+// Format returns the format parameter of an Ollama request constrained by
+// the schema: the schema itself, or "json" when any JSON value goes
+func (s *Schema) Format() json.RawMessage {
+	if s.source == nil {
+		return json.RawMessage(`"json"`)
+	}
+	return s.source
+}
+
+// Validate checks that answer is one JSON value matching the schema and
+// returns it without the whitespace or Markdown code fence around it.
+// Answers that do not match return a *ValidationError.
+func (s *Schema) Validate(answer string) (string, error) {
+	answer = unfence(answer)
+
+	value, err := jsonschema.UnmarshalJSON(strings.NewReader(answer))
+	if err != nil {
+		return "", &ValidationError{Violations: []Violation{{Description: "not valid JSON: " + err.Error()}}}
+	}
+	if s.compiled == nil {
+		return answer, nil
+	}
+
+	err = s.compiled.Validate(value)
+	var validationErr *jsonschema.ValidationError
+	if errors.As(err, &validationErr) {
+		return "", &ValidationError{Violations: violations(validationErr)}
+	}
+	if err != nil {
+		return "", err
+	}
+	return answer, nil
+}
+
+// violations flattens the errors of the validator, keeping the leaves: the
+// nodes above them only say that a subschema failed
+func violations(err *jsonschema.ValidationError) []Violation {
+	var list []Violation
+	for _, unit := range err.BasicOutput().Errors {
+		if unit.Error == nil {
+			continue
+		}
+		list = append(list, Violation{
+			Location:    unit.InstanceLocation,
+			Description: unit.Error.String(),
+		})
+	}
+	if len(list) == 0 {
+		list = append(list, Violation{Description: err.Error()})
+	}
+	return list
+}
+
+// unfence trims an answer and the Markdown code fence models like to wrap
+// JSON in
+func unfence(answer string) string {
+	answer = strings.TrimSpace(answer)
+	if !strings.HasPrefix(answer, "```") || !strings.HasSuffix(answer, "```") || len(answer) < 6 {
+		return answer
+	}
+	answer = strings.TrimSuffix(strings.TrimPrefix(answer, "```"), "```")
+	// Drop the language tag of the opening fence
+	if newline := strings.IndexByte(answer, '\n'); newline >= 0 && !strings.ContainsAny(answer[:newline], "{[\"") {
+		answer = answer[newline+1:]
+	}
+	return strings.TrimSpace(answer)
+}
+
+// noLoader refuses every document a schema references: requests must not
+// make the gateway read files or call URLs
+type noLoader struct{}
+
+func (noLoader) Load(url string) (any, error) {
+	return nil, fmt.Errorf("%w: %s", ErrRemoteReference, url)
+}
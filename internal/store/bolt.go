@@ -0,0 +1,284 @@
+package store
+
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"time"
+
+	bolt "go.etcd.io/bbolt"
+)
+
+var (
+	sessionsBucket      = []byte("sessions")
+	refreshTokensBucket = []byte("refresh_tokens")
+	revokedBucket       = []byte("revoked_sessions")
+	usageBucket         = []byte("usage")
+)
+
+// BoltStore is a SessionStore persisted in an embedded BoltDB file, so
+// sessions survive restarts. A file can only be opened by one process.
+type BoltStore struct {
+	db *bolt.DB
+}
+
+// NewBoltStore opens (or creates) the database file at path
+func NewBoltStore(path string) (*BoltStore, error) {
+	if path == "" {
+		return nil, fmt.Errorf("bolt store requires a database path")
+	}
+	if err := os.MkdirAll(filepath.Dir(path), 0o700); err != nil {
+		return nil, fmt.Errorf("failed to create database directory: %w", err)
+	}
+
+	db, err := bolt.Open(path, 0o600, &bolt.Options{Timeout: 5 * time.Second})
+	if err != nil {
+		return nil, fmt.Errorf("failed to open session database %s: %w", path, err)
+	}
+
+	err = db.Update(func(tx *bolt.Tx) error {
+		for _, bucket := range [][]byte{sessionsBucket, refreshTokensBucket, revokedBucket, usageBucket} {
+			if _, err := tx.CreateBucketIfNotExists(bucket); err != nil {
+				return err
+			}
+		}
+		return nil
+	})
+	if err != nil {
+		db.Close()
+		return nil, fmt.Errorf("failed to initialize session database: %w", err)
+	}
+
+	return &BoltStore{db: db}, nil
+}
+
+func (b *BoltStore) PutSession(session *Session) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(sessionsBucket), session.SessionID, session)
+	})
+}
+
+func (b *BoltStore) GetSession(sessionID string) (*Session, error) {
+	var session Session
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(sessionsBucket), sessionID, &session)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &session, nil
+}
+
+func (b *BoltStore) DeleteSession(sessionID string) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return deleteSession(tx, sessionID)
+	})
+}
+
+func (b *BoltStore) CountSessions() (int, error) {
+	var count int
+	err := b.db.View(func(tx *bolt.Tx) error {
+		count = tx.Bucket(sessionsBucket).Stats().KeyN
+		return nil
+	})
+	return count, err
+}
+
+func (b *BoltStore) CountTenantSessions(tenantID string, now time.Time) (int, error) {
+	count := 0
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return tx.Bucket(sessionsBucket).ForEach(func(key, value []byte) error {
+			var session Session
+			if err := json.Unmarshal(value, &session); err != nil {
+				return fmt.Errorf("corrupt record %q: %w", key, err)
+			}
+			if session.TenantID == tenantID && !now.After(session.ExpiresAt) {
+				count++
+			}
+			return nil
+		})
+	})
+	return count, err
+}
+
+func (b *BoltStore) PutRefreshToken(hash string, token *RefreshToken) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		return putJSON(tx.Bucket(refreshTokensBucket), hash, token)
+	})
+}
+
+func (b *BoltStore) UseRefreshToken(hash, sessionID string) (*RefreshToken, error) {
+	var previous RefreshToken
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(refreshTokensBucket)
+		if err := getJSON(bucket, hash, &previous); err != nil {
+			return err
+		}
+		if previous.SessionID != sessionID {
+			return ErrNotFound
+		}
+
+		used := previous
+		used.Used = true
+		return putJSON(bucket, hash, &used)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &previous, nil
+}
+
+func (b *BoltStore) RevokeSession(sessionID string, until time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		if err := deleteSession(tx, sessionID); err != nil {
+			return err
+		}
+		return putJSON(tx.Bucket(revokedBucket), sessionID, until)
+	})
+}
+
+func (b *BoltStore) IsRevoked(sessionID string) (bool, error) {
+	var until time.Time
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(revokedBucket), sessionID, &until)
+	})
+	if err == ErrNotFound {
+		return false, nil
+	}
+	if err != nil {
+		return false, err
+	}
+	return time.Now().Before(until), nil
+}
+
+func (b *BoltStore) AddUsage(delta *Usage) (*Usage, error) {
+	var usage Usage
+	err := b.db.Update(func(tx *bolt.Tx) error {
+		bucket := tx.Bucket(usageBucket)
+		key := usageKey(delta.TenantID, delta.WindowStart)
+		err := getJSON(bucket, key, &usage)
+		if err == ErrNotFound {
+			usage = Usage{
+				TenantID:    delta.TenantID,
+				WindowStart: delta.WindowStart,
+				WindowEnd:   delta.WindowEnd,
+			}
+		} else if err != nil {
+			return err
+		}
+
+		usage.add(delta)
+		return putJSON(bucket, key, &usage)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (b *BoltStore) GetUsage(tenantID string, windowStart time.Time) (*Usage, error) {
+	var usage Usage
+	err := b.db.View(func(tx *bolt.Tx) error {
+		return getJSON(tx.Bucket(usageBucket), usageKey(tenantID, windowStart), &usage)
+	})
+	if err != nil {
+		return nil, err
+	}
+	return &usage, nil
+}
+
+func (b *BoltStore) DeleteExpired(now time.Time) error {
+	return b.db.Update(func(tx *bolt.Tx) error {
+		err := deleteWhere(tx.Bucket(sessionsBucket), func(value []byte) (bool, error) {
+			var session Session
+			err := json.Unmarshal(value, &session)
+			return err == nil && now.After(session.ExpiresAt), err
+		})
+		if err != nil {
+			return err
+		}
+
+		err = deleteWhere(tx.Bucket(refreshTokensBucket), func(value []byte) (bool, error) {
+			var token RefreshToken
+			err := json.Unmarshal(value, &token)
+			return err == nil && now.After(token.ExpiresAt), err
+		})
+		if err != nil {
+			return err
+		}
+
+		err = deleteWhere(tx.Bucket(revokedBucket), func(value []byte) (bool, error) {
+			var until time.Time
+			err := json.Unmarshal(value, &until)
+			return err == nil && now.After(until), err
+		})
+		if err != nil {
+			return err
+		}
+
+		return deleteWhere(tx.Bucket(usageBucket), func(value []byte) (bool, error) {
+			var usage Usage
+			err := json.Unmarshal(value, &usage)
+			return err == nil && now.After(usage.WindowEnd), err
+		})
+	})
+}
+
+func (b *BoltStore) Close() error {
+	return b.db.Close()
+}
+
+// deleteSession removes a session and its refresh tokens within tx
+func deleteSession(tx *bolt.Tx, sessionID string) error {
+	if err := tx.Bucket(sessionsBucket).Delete([]byte(sessionID)); err != nil {
+		return err
+	}
+	return deleteWhere(tx.Bucket(refreshTokensBucket), func(value []byte) (bool, error) {
+		var token RefreshToken
+		err := json.Unmarshal(value, &token)
+		return err == nil && token.SessionID == sessionID, err
+	})
+}
+
+// deleteWhere deletes every key of bucket whose value matches
+func deleteWhere(bucket *bolt.Bucket, match func(value []byte) (bool, error)) error {
+	var keys [][]byte
+	err := bucket.ForEach(func(key, value []byte) error {
+		matched, err := match(value)
+		if err != nil {
+			return fmt.Errorf("corrupt record %q: %w", key, err)
+		}
+		if matched {
+			keys = append(keys, append([]byte(nil), key...))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Keys cannot be deleted while iterating with ForEach
+	for _, key := range keys {
+		if err := bucket.Delete(key); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func putJSON(bucket *bolt.Bucket, key string, value interface{}) error {
+	data, err := json.Marshal(value)
+	if err != nil {
+		return err
+	}
+	return bucket.Put([]byte(key), data)
+}
+
+func getJSON(bucket *bolt.Bucket, key string, value interface{}) error {
+	data := bucket.Get([]byte(key))
+	if data == nil {
+		return ErrNotFound
+	}
+	return json.Unmarshal(data, value)
+}
@@ -0,0 +1,186 @@
+package store
+
+import (
+	"sync"
+	"time"
+)
+
+// MemoryStore is a SessionStore kept in RAM. Everything is lost on restart.
+type MemoryStore struct {
+	mutex         sync.RWMutex
+	sessions      map[string]*Session
+	refreshTokens map[string]*RefreshToken
+	revoked       map[string]time.Time
+	usage         map[string]*Usage
+}
+
+// NewMemoryStore creates an empty in-memory store
+func NewMemoryStore() *MemoryStore {
+	return &MemoryStore{
+		sessions:      make(map[string]*Session),
+		refreshTokens: make(map[string]*RefreshToken),
+		revoked:       make(map[string]time.Time),
+		usage:         make(map[string]*Usage),
+	}
+}
+
+func (m *MemoryStore) PutSession(session *Session) error {
+	copied := *session
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.sessions[session.SessionID] = &copied
+	return nil
+}
+
+func (m *MemoryStore) GetSession(sessionID string) (*Session, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	session, exists := m.sessions[sessionID]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	// Callers get a copy, so they cannot mutate the stored session unlocked
+	copied := *session
+	return &copied, nil
+}
+
+func (m *MemoryStore) DeleteSession(sessionID string) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.deleteSessionLocked(sessionID)
+	return nil
+}
+
+func (m *MemoryStore) CountSessions() (int, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+	return len(m.sessions), nil
+}
+
+func (m *MemoryStore) CountTenantSessions(tenantID string, now time.Time) (int, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	count := 0
+	for _, session := range m.sessions {
+		if session.TenantID == tenantID && !now.After(session.ExpiresAt) {
+			count++
+		}
+	}
+	return count, nil
+}
+
+func (m *MemoryStore) PutRefreshToken(hash string, token *RefreshToken) error {
+	copied := *token
+
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+	m.refreshTokens[hash] = &copied
+	return nil
+}
+
+func (m *MemoryStore) UseRefreshToken(hash, sessionID string) (*RefreshToken, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	token, exists := m.refreshTokens[hash]
+	if !exists || token.SessionID != sessionID {
+		return nil, ErrNotFound
+	}
+	previous := *token
+	token.Used = true
+	return &previous, nil
+}
+
+func (m *MemoryStore) RevokeSession(sessionID string, until time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	m.deleteSessionLocked(sessionID)
+	m.revoked[sessionID] = until
+	return nil
+}
+
+func (m *MemoryStore) IsRevoked(sessionID string) (bool, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	until, revoked := m.revoked[sessionID]
+	return revoked && time.Now().Before(until), nil
+}
+
+func (m *MemoryStore) AddUsage(delta *Usage) (*Usage, error) {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	key := usageKey(delta.TenantID, delta.WindowStart)
+	usage, exists := m.usage[key]
+	if !exists {
+		usage = &Usage{
+			TenantID:    delta.TenantID,
+			WindowStart: delta.WindowStart,
+			WindowEnd:   delta.WindowEnd,
+		}
+		m.usage[key] = usage
+	}
+	usage.add(delta)
+
+	copied := *usage
+	return &copied, nil
+}
+
+func (m *MemoryStore) GetUsage(tenantID string, windowStart time.Time) (*Usage, error) {
+	m.mutex.RLock()
+	defer m.mutex.RUnlock()
+
+	usage, exists := m.usage[usageKey(tenantID, windowStart)]
+	if !exists {
+		return nil, ErrNotFound
+	}
+	copied := *usage
+	return &copied, nil
+}
+
+func (m *MemoryStore) DeleteExpired(now time.Time) error {
+	m.mutex.Lock()
+	defer m.mutex.Unlock()
+
+	for sessionID, session := range m.sessions {
+		if now.After(session.ExpiresAt) {
+			delete(m.sessions, sessionID)
+		}
+	}
+	for hash, token := range m.refreshTokens {
+		if now.After(token.ExpiresAt) {
+			delete(m.refreshTokens, hash)
+		}
+	}
+	for sessionID, until := range m.revoked {
+		if now.After(until) {
+			delete(m.revoked, sessionID)
+		}
+	}
+	for key, usage := range m.usage {
+		if now.After(usage.WindowEnd) {
+			delete(m.usage, key)
+		}
+	}
+	return nil
+}
+
+func (m *MemoryStore) Close() error {
+	return nil
+}
+
+// deleteSessionLocked removes a session and its refresh tokens. The caller
+// holds the write lock.
+func (m *MemoryStore) deleteSessionLocked(sessionID string) {
+	delete(m.sessions, sessionID)
+	for hash, token := range m.refreshTokens {
+		if token.SessionID == sessionID {
+			delete(m.refreshTokens, hash)
+		}
+	}
+}
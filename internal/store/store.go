@@ -0,0 +1,128 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrNotFound is returned when a session, refresh token or usage record does
+// not exist
+var ErrNotFound = errors.New("not found")
+
+// Session is a registered agent session
+type Session struct {
+	SessionID string    `json:"session_id"`
+	TenantID  string    `json:"tenant_id"`
+	AgentID   string    `json:"agent_id"`
+	Model     string    `json:"model"`
+	CreatedAt time.Time `json:"created_at"`
+	ExpiresAt time.Time `json:"expires_at"`
+	// Options are the generation defaults chosen at Register
+	Options *GenerationOptions `json:"options,omitempty"`
+}
+
+// GenerationOptions are the generation defaults of a session, stored under
+// the names of the Ollama API. Nil fields leave the model defaults.
+type GenerationOptions struct {
+	Temperature *float64 `json:"temperature,omitempty"`
+	TopP        *float64 `json:"top_p,omitempty"`
+	TopK        *int     `json:"top_k,omitempty"`
+	NumCtx      *int     `json:"num_ctx,omitempty"`
+	Seed        *int64   `json:"seed,omitempty"`
+	Stop        []string `json:"stop,omitempty"`
+	NumPredict  *int     `json:"num_predict,omitempty"`
+}
+
+// RefreshToken is the server-side state of a single-use refresh token. Tokens
+// are stored under their hash, never in clear.
+type RefreshToken struct {
+	SessionID string    `json:"session_id"`
+	ExpiresAt time.Time `json:"expires_at"`
+	Used      bool      `json:"used"`
+}
+
+// Usage counts what a tenant used during one quota window
+type Usage struct {
+	TenantID         string    `json:"tenant_id"`
+	WindowStart      time.Time `json:"window_start"`
+	WindowEnd        time.Time `json:"window_end"`
+	Sessions         int64     `json:"sessions"` // registered during the window
+	Requests         int64     `json:"requests"`
+	PromptTokens     int64     `json:"prompt_tokens"`
+	CompletionTokens int64     `json:"completion_tokens"`
+}
+
+// Tokens returns the Ollama tokens used: prompt plus completion
+func (u *Usage) Tokens() int64 {
+	return u.PromptTokens + u.CompletionTokens
+}
+
+// add adds the counters of delta to u
+func (u *Usage) add(delta *Usage) {
+	u.Sessions += delta.Sessions
+	u.Requests += delta.Requests
+	u.PromptTokens += delta.PromptTokens
+	u.CompletionTokens += delta.CompletionTokens
+}
+
+// usageKey identifies the usage record of a tenant window
+func usageKey(tenantID string, windowStart time.Time) string {
+	return tenantID + "@" + windowStart.UTC().Format(time.RFC3339)
+}
+
+// SessionStore keeps the handshake state: sessions, refresh tokens and
+// revoked sessions, plus the quota usage of every tenant. Implementations are
+// safe for concurrent use.
+type SessionStore interface {
+	// PutSession creates or replaces a session
+	PutSession(session *Session) error
+	// GetSession returns a session, expired or not, or ErrNotFound
+	GetSession(sessionID string) (*Session, error)
+	// DeleteSession removes a session and its refresh tokens
+	DeleteSession(sessionID string) error
+	// CountSessions returns the number of stored sessions
+	CountSessions() (int, error)
+	// CountTenantSessions returns the number of sessions of a tenant that
+	// have not expired at now
+	CountTenantSessions(tenantID string, now time.Time) (int, error)
+
+	// PutRefreshToken stores a refresh token under its hash
+	PutRefreshToken(hash string, token *RefreshToken) error
+	// UseRefreshToken atomically marks the refresh token as used and returns
+	// it as it was before, so callers can detect reuse. It returns
+	// ErrNotFound when the hash is unknown or belongs to another session.
+	UseRefreshToken(hash, sessionID string) (*RefreshToken, error)
+
+	// RevokeSession deletes a session and its refresh tokens and remembers it
+	// as revoked until the given time
+	RevokeSession(sessionID string, until time.Time) error
+	// IsRevoked reports whether a session is currently revoked
+	IsRevoked(sessionID string) (bool, error)
+
+	// AddUsage adds the counters of delta to the usage record of
+	// delta.TenantID for the window starting at delta.WindowStart, creating
+	// it if needed, and returns the new totals
+	AddUsage(delta *Usage) (*Usage, error)
+	// GetUsage returns the usage record of a tenant window, or ErrNotFound
+	GetUsage(tenantID string, windowStart time.Time) (*Usage, error)
+
+	// DeleteExpired removes sessions, refresh tokens, revocations and usage
+	// windows that expired before now
+	DeleteExpired(now time.Time) error
+
+	Close() error
+}
+
+// Open creates the session store selected by the database.type setting:
+// "memory" (lost on restart) or "bolt" (an embedded file at path)
+func Open(storeType, path string) (SessionStore, error) {
+	switch storeType {
+	case "", "memory":
+		return NewMemoryStore(), nil
+	case "bolt", "boltdb":
+		return NewBoltStore(path)
+	default:
+		return nil, fmt.Errorf("unsupported database type %q (use memory or bolt)", storeType)
+	}
+}
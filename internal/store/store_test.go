@@ -0,0 +1,324 @@
+package store
+
+import (
+	"errors"
+	"fmt"
+	"path/filepath"
+	"sync"
+	"testing"
+	"time"
+)
+
+// stores runs the contract suite against every SessionStore implementation
+var stores = map[string]func(t *testing.T) SessionStore{
+	"memory": func(t *testing.T) SessionStore {
+		return NewMemoryStore()
+	},
+	"bolt": func(t *testing.T) SessionStore {
+		store, err := NewBoltStore(filepath.Join(t.TempDir(), "sessions.db"))
+		if err != nil {
+			t.Fatal(err)
+		}
+		return store
+	},
+}
+
+func forEachStore(t *testing.T, test func(t *testing.T, store SessionStore)) {
+	for name, open := range stores {
+		t.Run(name, func(t *testing.T) {
+			store := open(t)
+			t.Cleanup(func() {
+				if err := store.Close(); err != nil {
+					t.Errorf("Close: %v", err)
+				}
+			})
+			test(t, store)
+		})
+	}
+}
+
+func testSession(id, tenantID string, expiresAt time.Time) *Session {
+	return &Session{
+		SessionID: id,
+		TenantID:  tenantID,
+		AgentID:   "agent-1",
+		Model:     "gemma3:4b",
+		CreatedAt: time.Now(),
+		ExpiresAt: expiresAt,
+	}
+}
+
+func TestSessions(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store SessionStore) {
+		now := time.Now()
+		if _, err := store.GetSession("s1"); !errors.Is(err, ErrNotFound) {
+			t.Fatalf("GetSession of unknown session = %v, want ErrNotFound", err)
+		}
+
+		session := testSession("s1", "acme", now.Add(time.Hour))
+		if err := store.PutSession(session); err != nil {
+			t.Fatalf("PutSession: %v", err)
+		}
+		got, err := store.GetSession("s1")
+		if err != nil {
+			t.Fatalf("GetSession: %v", err)
+		}
+		if got.TenantID != "acme" || got.AgentID != "agent-1" || got.Model != "gemma3:4b" || !got.ExpiresAt.Equal(session.ExpiresAt) {
+			t.Errorf("GetSession = %+v, want %+v", got, session)
+		}
+
+		// Changing the returned copy must not change the stored session
+		got.Model = "changed"
+		if again, _ := store.GetSession("s1"); again.Model != "gemma3:4b" {
+			t.Errorf("stored session changed through a returned copy: %q", again.Model)
+		}
+
+		// Expired sessions are still returned, but not counted as active
+		if err := store.PutSession(testSession("s2", "acme", now.Add(-time.Minute))); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.PutSession(testSession("s3", "other", now.Add(time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.GetSession("s2"); err != nil {
+			t.Errorf("GetSession of expired session: %v", err)
+		}
+		if count, err := store.CountSessions(); err != nil || count != 3 {
+			t.Errorf("CountSessions = %d, %v, want 3", count, err)
+		}
+		if count, err := store.CountTenantSessions("acme", now); err != nil || count != 1 {
+			t.Errorf("CountTenantSessions = %d, %v, want 1", count, err)
+		}
+
+		// Deleting a session removes its refresh tokens too
+		if err := store.PutRefreshToken("hash-1", &RefreshToken{SessionID: "s1", ExpiresAt: now.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.DeleteSession("s1"); err != nil {
+			t.Fatalf("DeleteSession: %v", err)
+		}
+		if _, err := store.GetSession("s1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetSession after delete = %v, want ErrNotFound", err)
+		}
+		if _, err := store.UseRefreshToken("hash-1", "s1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("UseRefreshToken after delete = %v, want ErrNotFound", err)
+		}
+		if err := store.DeleteSession("s1"); err != nil {
+			t.Errorf("DeleteSession of a deleted session: %v", err)
+		}
+	})
+}
+
+func TestUseRefreshToken(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store SessionStore) {
+		token := &RefreshToken{SessionID: "s1", ExpiresAt: time.Now().Add(time.Hour)}
+		if err := store.PutRefreshToken("hash-1", token); err != nil {
+			t.Fatalf("PutRefreshToken: %v", err)
+		}
+
+		if _, err := store.UseRefreshToken("unknown", "s1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("UseRefreshToken of unknown hash = %v, want ErrNotFound", err)
+		}
+		if _, err := store.UseRefreshToken("hash-1", "s2"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("UseRefreshToken of another session = %v, want ErrNotFound", err)
+		}
+
+		first, err := store.UseRefreshToken("hash-1", "s1")
+		if err != nil {
+			t.Fatalf("UseRefreshToken: %v", err)
+		}
+		if first.Used {
+			t.Error("first use reported the token as already used")
+		}
+		second, err := store.UseRefreshToken("hash-1", "s1")
+		if err != nil {
+			t.Fatalf("UseRefreshToken: %v", err)
+		}
+		if !second.Used {
+			t.Error("reuse of the token was not detected")
+		}
+	})
+}
+
+func TestRevokeSession(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store SessionStore) {
+		now := time.Now()
+		if err := store.PutSession(testSession("s1", "acme", now.Add(time.Hour))); err != nil {
+			t.Fatal(err)
+		}
+		if err := store.PutRefreshToken("hash-1", &RefreshToken{SessionID: "s1", ExpiresAt: now.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+
+		if revoked, err := store.IsRevoked("s1"); err != nil || revoked {
+			t.Errorf("IsRevoked before revocation = %v, %v", revoked, err)
+		}
+		if err := store.RevokeSession("s1", now.Add(time.Hour)); err != nil {
+			t.Fatalf("RevokeSession: %v", err)
+		}
+		if revoked, err := store.IsRevoked("s1"); err != nil || !revoked {
+			t.Errorf("IsRevoked = %v, %v, want true", revoked, err)
+		}
+		if _, err := store.GetSession("s1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetSession of revoked session = %v, want ErrNotFound", err)
+		}
+		if _, err := store.UseRefreshToken("hash-1", "s1"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("UseRefreshToken of revoked session = %v, want ErrNotFound", err)
+		}
+
+		// A revocation only lasts until the given time
+		if err := store.RevokeSession("s2", now.Add(-time.Second)); err != nil {
+			t.Fatal(err)
+		}
+		if revoked, err := store.IsRevoked("s2"); err != nil || revoked {
+			t.Errorf("IsRevoked after the revocation expired = %v, %v", revoked, err)
+		}
+	})
+}
+
+func TestDeleteExpired(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store SessionStore) {
+		now := time.Now()
+		for _, put := range []error{
+			store.PutSession(testSession("live", "acme", now.Add(time.Hour))),
+			store.PutSession(testSession("expired", "acme", now.Add(-time.Hour))),
+			store.PutRefreshToken("live", &RefreshToken{SessionID: "live", ExpiresAt: now.Add(time.Hour)}),
+			store.PutRefreshToken("expired", &RefreshToken{SessionID: "live", ExpiresAt: now.Add(-time.Hour)}),
+			store.RevokeSession("revoked-live", now.Add(time.Hour)),
+			store.RevokeSession("revoked-expired", now.Add(-time.Hour)),
+		} {
+			if put != nil {
+				t.Fatal(put)
+			}
+		}
+		if _, err := store.AddUsage(&Usage{TenantID: "acme", WindowStart: now.Add(-2 * time.Hour), WindowEnd: now.Add(-time.Hour), Requests: 1}); err != nil {
+			t.Fatal(err)
+		}
+		if _, err := store.AddUsage(&Usage{TenantID: "acme", WindowStart: now, WindowEnd: now.Add(time.Hour), Requests: 1}); err != nil {
+			t.Fatal(err)
+		}
+
+		if err := store.DeleteExpired(now); err != nil {
+			t.Fatalf("DeleteExpired: %v", err)
+		}
+
+		if _, err := store.GetSession("live"); err != nil {
+			t.Errorf("live session deleted: %v", err)
+		}
+		if _, err := store.GetSession("expired"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expired session kept: %v", err)
+		}
+		if _, err := store.UseRefreshToken("live", "live"); err != nil {
+			t.Errorf("live refresh token deleted: %v", err)
+		}
+		if _, err := store.UseRefreshToken("expired", "live"); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expired refresh token kept: %v", err)
+		}
+		if revoked, _ := store.IsRevoked("revoked-live"); !revoked {
+			t.Error("live revocation deleted")
+		}
+		if _, err := store.GetUsage("acme", now); err != nil {
+			t.Errorf("current usage window deleted: %v", err)
+		}
+		if _, err := store.GetUsage("acme", now.Add(-2*time.Hour)); !errors.Is(err, ErrNotFound) {
+			t.Errorf("expired usage window kept: %v", err)
+		}
+	})
+}
+
+func TestUsage(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store SessionStore) {
+		start := time.Now().Truncate(time.Hour)
+		end := start.Add(24 * time.Hour)
+
+		if _, err := store.GetUsage("acme", start); !errors.Is(err, ErrNotFound) {
+			t.Errorf("GetUsage of unknown window = %v, want ErrNotFound", err)
+		}
+		if _, err := store.AddUsage(&Usage{TenantID: "acme", WindowStart: start, WindowEnd: end, Sessions: 1, Requests: 1, PromptTokens: 10}); err != nil {
+			t.Fatal(err)
+		}
+		total, err := store.AddUsage(&Usage{TenantID: "acme", WindowStart: start, WindowEnd: end, Requests: 1, CompletionTokens: 5})
+		if err != nil {
+			t.Fatal(err)
+		}
+		if total.Sessions != 1 || total.Requests != 2 || total.Tokens() != 15 {
+			t.Errorf("AddUsage totals = %+v", total)
+		}
+
+		got, err := store.GetUsage("acme", start)
+		if err != nil {
+			t.Fatalf("GetUsage: %v", err)
+		}
+		if got.Requests != 2 || got.PromptTokens != 10 || got.CompletionTokens != 5 || !got.WindowEnd.Equal(end) {
+			t.Errorf("GetUsage = %+v", got)
+		}
+	})
+}
+
+func TestConcurrentAccess(t *testing.T) {
+	forEachStore(t, func(t *testing.T, store SessionStore) {
+		const workers = 16
+		now := time.Now()
+		start := now.Truncate(time.Hour)
+
+		if err := store.PutRefreshToken("shared", &RefreshToken{SessionID: "shared", ExpiresAt: now.Add(time.Hour)}); err != nil {
+			t.Fatal(err)
+		}
+
+		var wg sync.WaitGroup
+		var mutex sync.Mutex
+		unusedSeen := 0
+		errs := make(chan error, workers*8)
+		for i := 0; i < workers; i++ {
+			wg.Add(1)
+			go func(i int) {
+				defer wg.Done()
+				id := fmt.Sprintf("s%d", i)
+
+				errs <- store.PutSession(testSession(id, "acme", now.Add(time.Hour)))
+				if _, err := store.GetSession(id); err != nil {
+					errs <- err
+				}
+				if _, err := store.CountTenantSessions("acme", now); err != nil {
+					errs <- err
+				}
+				_, err := store.AddUsage(&Usage{TenantID: "acme", WindowStart: start, WindowEnd: start.Add(time.Hour), Requests: 1})
+				errs <- err
+
+				// Only one caller may see the shared token unused
+				token, err := store.UseRefreshToken("shared", "shared")
+				if err != nil {
+					errs <- err
+					return
+				}
+				if !token.Used {
+					mutex.Lock()
+					unusedSeen++
+					mutex.Unlock()
+				}
+
+				if i%2 == 0 {
+					errs <- store.RevokeSession(id, now.Add(time.Hour))
+				}
+				errs <- store.DeleteExpired(now)
+			}(i)
+		}
+		wg.Wait()
+		close(errs)
+
+		for err := range errs {
+			if err != nil {
+				t.Error(err)
+			}
+		}
+		if unusedSeen != 1 {
+			t.Errorf("%d callers saw the refresh token unused, want 1", unusedSeen)
+		}
+		if count, err := store.CountSessions(); err != nil || count != workers/2 {
+			t.Errorf("CountSessions = %d, %v, want %d", count, err, workers/2)
+		}
+		if usage, err := store.GetUsage("acme", start); err != nil || usage.Requests != workers {
+			t.Errorf("GetUsage = %+v, %v, want %d requests", usage, err, workers)
+		}
+	})
+}
@@ -0,0 +1,245 @@
+// Package tenant keeps the tenants allowed to use the gateway and the
+// settings each one gets: default model, system prompt, allowed models and
+// MCP tools, limits and generation caps.
+package tenant
+
+import (
+	"errors"
+	"fmt"
+	"path"
+	"slices"
+	"sort"
+	"sync"
+	"time"
+)
+
+var (
+	// ErrUnknownTenant is returned for tenants that are not registered
+	ErrUnknownTenant = errors.New("unknown tenant")
+	// ErrModelNotAllowed is returned for models outside a tenant allowlist
+	ErrModelNotAllowed = errors.New("model not allowed")
+)
+
+// Tenant holds the settings of one tenant
+type Tenant struct {
+	ID            string
+	DefaultModel  string   // model for sessions that do not request one
+	SystemPrompt  string   // system prompt for new conversations
+	AllowedModels []string // empty allows any model
+	MCPTools      []string // tools of external MCP servers allowed, by name or pattern (empty allows none)
+	RateLimit     RateLimit
+	Quotas        Quotas
+	Generation    Generation
+}
+
+// RateLimit bounds how fast a tenant can call the gateway. Like every
+// limit, a nil value inherits the default and 0 is unlimited, so a tenant can
+// opt out of a default limit.
+type RateLimit struct {
+	RequestsPerMinute *int
+	Burst             *int
+	// PerAgent gives every agent its own bucket instead of one for the
+	// tenant. Nil inherits the default, so a tenant can turn it off.
+	PerAgent *bool
+}
+
+// LimitsAgents reports whether every agent gets its own bucket
+func (r RateLimit) LimitsAgents() bool {
+	return r.PerAgent != nil && *r.PerAgent
+}
+
+// Quotas bound how much a tenant can use the gateway (nil inherits, 0 is
+// unlimited). Requests and tokens are counted per Window, a day unless set.
+type Quotas struct {
+	MaxSessions       *int // active at once
+	MaxRequestsPerDay *int
+	MaxTokensPerDay   *int // prompt plus completion tokens
+	Window            time.Duration
+}
+
+// Generation caps the generation options of a tenant (nil inherits, 0 is no
+// cap)
+type Generation struct {
+	MaxTokens  *int // tokens generated per answer
+	MaxContext *int // context window in tokens
+}
+
+// Value returns an optional setting, or the zero value when it is unset
+func Value[T any](setting *T) T {
+	var value T
+	if setting != nil {
+		value = *setting
+	}
+	return value
+}
+
+// AllowsModel reports whether the tenant may use model
+func (t *Tenant) AllowsModel(model string) bool {
+	return len(t.AllowedModels) == 0 || slices.Contains(t.AllowedModels, model)
+}
+
+// AllowsMCPTool reports whether the tenant may use a tool of an external MCP
+// server, named or matched by a pattern such as files_*
+func (t *Tenant) AllowsMCPTool(name string) bool {
+	for _, pattern := range t.MCPTools {
+		if matched, _ := path.Match(pattern, name); matched {
+			return true
+		}
+	}
+	return false
+}
+
+// Model returns the model to use for a request: requested, or the tenant
+// default when empty. Models outside the allowlist return ErrModelNotAllowed.
+func (t *Tenant) Model(requested string) (string, error) {
+	if requested == "" {
+		requested = t.DefaultModel
+	}
+	if !t.AllowsModel(requested) {
+		return "", fmt.Errorf("%w: %s is not allowed for tenant %s", ErrModelNotAllowed, requested, t.ID)
+	}
+	return requested, nil
+}
+
+// Inherit fills the unset settings of t from defaults. It is the one place
+// inheritance happens, for the registry and for the configuration.
+func (t *Tenant) Inherit(defaults Tenant) {
+	if t.DefaultModel == "" {
+		t.DefaultModel = defaults.DefaultModel
+	}
+	if t.SystemPrompt == "" {
+		t.SystemPrompt = defaults.SystemPrompt
+	}
+	if t.AllowedModels == nil {
+		t.AllowedModels = defaults.AllowedModels
+	}
+	if t.MCPTools == nil {
+		t.MCPTools = defaults.MCPTools
+	}
+	inherit(&t.RateLimit.RequestsPerMinute, defaults.RateLimit.RequestsPerMinute)
+	inherit(&t.RateLimit.Burst, defaults.RateLimit.Burst)
+	inherit(&t.RateLimit.PerAgent, defaults.RateLimit.PerAgent)
+	inherit(&t.Quotas.MaxSessions, defaults.Quotas.MaxSessions)
+	inherit(&t.Quotas.MaxRequestsPerDay, defaults.Quotas.MaxRequestsPerDay)
+	inherit(&t.Quotas.MaxTokensPerDay, defaults.Quotas.MaxTokensPerDay)
+	inherit(&t.Generation.MaxTokens, defaults.Generation.MaxTokens)
+	inherit(&t.Generation.MaxContext, defaults.Generation.MaxContext)
+	if t.Quotas.Window == 0 {
+		t.Quotas.Window = defaults.Quotas.Window
+	}
+}
+
+// inherit sets an optional setting to its default when it is unset
+func inherit[T any](setting **T, defaults *T) {
+	if *setting == nil {
+		*setting = defaults
+	}
+}
+
+// Registry holds the registered tenants. It is safe for concurrent use and
+// can be changed while the server runs.
+type Registry struct {
+	mutex    sync.RWMutex
+	defaults Tenant
+	tenants  map[string]*Tenant
+	// open registries accept any tenant ID with the default settings
+	open bool
+}
+
+// NewRegistry creates a registry of tenants, whose unset settings are
+// inherited from defaults. Without tenants the registry is open: every
+// tenant ID is accepted with the default settings.
+func NewRegistry(defaults Tenant, tenants []Tenant) *Registry {
+	r := &Registry{}
+	r.Replace(defaults, tenants)
+	return r
+}
+
+// Replace swaps in a new set of tenants, e.g. after a configuration reload.
+// Tenants added with Put are dropped.
+func (r *Registry) Replace(defaults Tenant, tenants []Tenant) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	r.defaults = defaults
+	r.tenants = make(map[string]*Tenant, len(tenants))
+	r.open = len(tenants) == 0
+	for _, tenant := range tenants {
+		r.put(tenant)
+	}
+}
+
+// Put registers a tenant or replaces its settings. Unset settings are
+// inherited from the registry defaults.
+func (r *Registry) Put(tenant Tenant) {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.put(tenant)
+}
+
+func (r *Registry) put(tenant Tenant) {
+	tenant.Inherit(r.defaults)
+	r.tenants[tenant.ID] = &tenant
+}
+
+// Delete removes a tenant, reporting whether it was registered. Its sessions
+// are rejected from then on (in an open registry they fall back to the
+// default settings).
+func (r *Registry) Delete(id string) bool {
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+
+	_, exists := r.tenants[id]
+	delete(r.tenants, id)
+	return exists
+}
+
+// Get returns the settings of a tenant, or ErrUnknownTenant
+func (r *Registry) Get(id string) (*Tenant, error) {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	if tenant, exists := r.tenants[id]; exists {
+		settings := *tenant
+		settings.AllowedModels = slices.Clone(tenant.AllowedModels)
+		settings.MCPTools = slices.Clone(tenant.MCPTools)
+		return &settings, nil
+	}
+	if r.open && id != "" {
+		tenant := r.defaults
+		tenant.ID = id
+		return &tenant, nil
+	}
+	return nil, fmt.Errorf("%w: %s", ErrUnknownTenant, id)
+}
+
+// Defaults returns the settings every tenant inherits
+func (r *Registry) Defaults() Tenant {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	defaults := r.defaults
+	defaults.AllowedModels = slices.Clone(r.defaults.AllowedModels)
+	defaults.MCPTools = slices.Clone(r.defaults.MCPTools)
+	return defaults
+}
+
+// List returns the registered tenants sorted by ID
+func (r *Registry) List() []Tenant {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	tenants := make([]Tenant, 0, len(r.tenants))
+	for _, tenant := range r.tenants {
+		tenants = append(tenants, *tenant)
+	}
+	sort.Slice(tenants, func(i, j int) bool { return tenants[i].ID < tenants[j].ID })
+	return tenants
+}
+
+// Open reports whether unregistered tenants are accepted
+func (r *Registry) Open() bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	return r.open
+}
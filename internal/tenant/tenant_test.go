@@ -0,0 +1,195 @@
+package tenant
+
+import (
+	"errors"
+	"slices"
+	"testing"
+	"time"
+)
+
+func limit(n int) *int { return &n }
+
+func TestInherit(t *testing.T) {
+	perAgent, shared := true, false
+	defaults := Tenant{
+		DefaultModel:  "gemma3:4b",
+		SystemPrompt:  "You are helpful.",
+		AllowedModels: []string{"gemma3:4b", "llama3"},
+		MCPTools:      []string{"files_*"},
+		RateLimit:     RateLimit{RequestsPerMinute: limit(60), Burst: limit(10), PerAgent: &perAgent},
+		Quotas:        Quotas{MaxSessions: limit(5), MaxRequestsPerDay: limit(1000), MaxTokensPerDay: limit(100000), Window: time.Hour},
+		Generation:    Generation{MaxTokens: limit(512), MaxContext: limit(8192)},
+	}
+
+	tests := []struct {
+		name   string
+		tenant Tenant
+		check  func(t *testing.T, got Tenant)
+	}{
+		{"unset inherits everything", Tenant{ID: "acme"}, func(t *testing.T, got Tenant) {
+			if got.DefaultModel != "gemma3:4b" || got.SystemPrompt != "You are helpful." ||
+				!slices.Equal(got.AllowedModels, defaults.AllowedModels) || !slices.Equal(got.MCPTools, defaults.MCPTools) {
+				t.Errorf("settings = %+v, want the defaults", got)
+			}
+			if Value(got.RateLimit.RequestsPerMinute) != 60 || Value(got.RateLimit.Burst) != 10 || !got.RateLimit.LimitsAgents() {
+				t.Errorf("rate limit = %+v, want the default", got.RateLimit)
+			}
+			if Value(got.Quotas.MaxSessions) != 5 || Value(got.Quotas.MaxRequestsPerDay) != 1000 ||
+				Value(got.Quotas.MaxTokensPerDay) != 100000 || got.Quotas.Window != time.Hour {
+				t.Errorf("quotas = %+v, want the default", got.Quotas)
+			}
+			if Value(got.Generation.MaxTokens) != 512 || Value(got.Generation.MaxContext) != 8192 {
+				t.Errorf("generation = %+v, want the default", got.Generation)
+			}
+		}},
+		{"set values are kept", Tenant{
+			DefaultModel: "llama3",
+			RateLimit:    RateLimit{RequestsPerMinute: limit(120)},
+			Quotas:       Quotas{MaxSessions: limit(1)},
+			Generation:   Generation{MaxContext: limit(2048)},
+		}, func(t *testing.T, got Tenant) {
+			if got.DefaultModel != "llama3" || Value(got.RateLimit.RequestsPerMinute) != 120 ||
+				Value(got.Quotas.MaxSessions) != 1 || Value(got.Generation.MaxContext) != 2048 {
+				t.Errorf("settings = %+v, want the values of the tenant", got)
+			}
+			if Value(got.RateLimit.Burst) != 10 || Value(got.Generation.MaxTokens) != 512 {
+				t.Errorf("settings = %+v, want the unset values inherited", got)
+			}
+		}},
+		{"zero opts out of a default limit", Tenant{
+			RateLimit:  RateLimit{RequestsPerMinute: limit(0), PerAgent: &shared},
+			Quotas:     Quotas{MaxSessions: limit(0), MaxRequestsPerDay: limit(0), MaxTokensPerDay: limit(0)},
+			Generation: Generation{MaxTokens: limit(0), MaxContext: limit(0)},
+		}, func(t *testing.T, got Tenant) {
+			if Value(got.RateLimit.RequestsPerMinute) != 0 || got.RateLimit.LimitsAgents() {
+				t.Errorf("rate limit = %+v, want unlimited and shared", got.RateLimit)
+			}
+			if Value(got.Quotas.MaxSessions) != 0 || Value(got.Quotas.MaxRequestsPerDay) != 0 || Value(got.Quotas.MaxTokensPerDay) != 0 {
+				t.Errorf("quotas = %+v, want unlimited", got.Quotas)
+			}
+			if Value(got.Generation.MaxTokens) != 0 || Value(got.Generation.MaxContext) != 0 {
+				t.Errorf("generation = %+v, want no caps", got.Generation)
+			}
+		}},
+		{"empty allowlists are kept", Tenant{AllowedModels: []string{}, MCPTools: []string{}}, func(t *testing.T, got Tenant) {
+			if len(got.AllowedModels) != 0 || len(got.MCPTools) != 0 {
+				t.Errorf("allowlists = %v, %v, want the empty lists of the tenant", got.AllowedModels, got.MCPTools)
+			}
+		}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := tt.tenant
+			got.Inherit(defaults)
+			tt.check(t, got)
+		})
+	}
+}
+
+func TestTenantModelAndTools(t *testing.T) {
+	settings := Tenant{ID: "acme", DefaultModel: "gemma3:4b", AllowedModels: []string{"gemma3:4b", "llama3"}, MCPTools: []string{"files_*", "search"}}
+
+	if model, err := settings.Model(""); err != nil || model != "gemma3:4b" {
+		t.Errorf("Model(\"\") = %q, %v, want the default model", model, err)
+	}
+	if model, err := settings.Model("llama3"); err != nil || model != "llama3" {
+		t.Errorf("Model(llama3) = %q, %v", model, err)
+	}
+	if _, err := settings.Model("mistral"); !errors.Is(err, ErrModelNotAllowed) {
+		t.Errorf("Model(mistral) = %v, want ErrModelNotAllowed", err)
+	}
+
+	for name, want := range map[string]bool{"files_read": true, "search": true, "search_web": false, "shell": false} {
+		if got := settings.AllowsMCPTool(name); got != want {
+			t.Errorf("AllowsMCPTool(%s) = %v, want %v", name, got, want)
+		}
+	}
+	if (&Tenant{}).AllowsMCPTool("files_read") {
+		t.Error("a tenant without mcp_tools was allowed an MCP tool")
+	}
+}
+
+func TestRegistry(t *testing.T) {
+	defaults := Tenant{DefaultModel: "gemma3:4b", RateLimit: RateLimit{RequestsPerMinute: limit(60)}}
+
+	t.Run("open", func(t *testing.T) {
+		registry := NewRegistry(defaults, nil)
+		if !registry.Open() {
+			t.Fatal("a registry without tenants is not open")
+		}
+		settings, err := registry.Get("anyone")
+		if err != nil || settings.ID != "anyone" || settings.DefaultModel != "gemma3:4b" {
+			t.Errorf("Get(anyone) = %+v, %v, want the defaults", settings, err)
+		}
+		if _, err := registry.Get(""); !errors.Is(err, ErrUnknownTenant) {
+			t.Errorf("Get(\"\") = %v, want ErrUnknownTenant", err)
+		}
+	})
+
+	t.Run("closed", func(t *testing.T) {
+		registry := NewRegistry(defaults, []Tenant{
+			{ID: "acme", RateLimit: RateLimit{RequestsPerMinute: limit(0)}},
+			{ID: "globex", DefaultModel: "llama3"},
+		})
+		if registry.Open() {
+			t.Fatal("a registry with tenants is open")
+		}
+		if _, err := registry.Get("stranger"); !errors.Is(err, ErrUnknownTenant) {
+			t.Errorf("Get(stranger) = %v, want ErrUnknownTenant", err)
+		}
+
+		acme, err := registry.Get("acme")
+		if err != nil {
+			t.Fatal(err)
+		}
+		if acme.DefaultModel != "gemma3:4b" || Value(acme.RateLimit.RequestsPerMinute) != 0 {
+			t.Errorf("acme = %+v, want the default model and no rate limit", acme)
+		}
+		globex, _ := registry.Get("globex")
+		if globex.DefaultModel != "llama3" || Value(globex.RateLimit.RequestsPerMinute) != 60 {
+			t.Errorf("globex = %+v, want its model and the default rate limit", globex)
+		}
+
+		var ids []string
+		for _, tenant := range registry.List() {
+			ids = append(ids, tenant.ID)
+		}
+		if !slices.Equal(ids, []string{"acme", "globex"}) {
+			t.Errorf("List = %v, want the tenants sorted by ID", ids)
+		}
+	})
+
+	t.Run("changes", func(t *testing.T) {
+		registry := NewRegistry(defaults, []Tenant{{ID: "acme", AllowedModels: []string{"gemma3:4b"}}})
+
+		// Get returns a copy the caller can change
+		acme, _ := registry.Get("acme")
+		acme.AllowedModels[0] = "llama3"
+		if again, _ := registry.Get("acme"); again.AllowedModels[0] != "gemma3:4b" {
+			t.Error("changing the result of Get changed the registry")
+		}
+
+		registry.Put(Tenant{ID: "initech"})
+		if settings, err := registry.Get("initech"); err != nil || settings.DefaultModel != "gemma3:4b" {
+			t.Errorf("Get after Put = %+v, %v, want the tenant with the defaults", settings, err)
+		}
+		if !registry.Delete("initech") || registry.Delete("initech") {
+			t.Error("Delete did not report whether the tenant was registered")
+		}
+		if _, err := registry.Get("initech"); !errors.Is(err, ErrUnknownTenant) {
+			t.Errorf("Get after Delete = %v, want ErrUnknownTenant", err)
+		}
+
+		// Replace drops the previous tenants and defaults
+		registry.Replace(Tenant{DefaultModel: "llama3"}, []Tenant{{ID: "globex"}})
+		if _, err := registry.Get("acme"); !errors.Is(err, ErrUnknownTenant) {
+			t.Errorf("Get(acme) after Replace = %v, want ErrUnknownTenant", err)
+		}
+		if settings, _ := registry.Get("globex"); settings == nil || settings.DefaultModel != "llama3" {
+			t.Errorf("Get(globex) after Replace = %+v, want the new defaults", settings)
+		}
+		if registry.Defaults().DefaultModel != "llama3" {
+			t.Errorf("Defaults = %+v, want the new defaults", registry.Defaults())
+		}
+	})
+}
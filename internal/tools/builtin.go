@@ -0,0 +1,222 @@
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Builtin returns the tools shipped with the gateway
+func Builtin() []Tool {
+	return []Tool{CurrentTime(), Calculator()}
+}
+
+// CurrentTime tells the model the date and time, which it cannot know
+func CurrentTime() Tool {
+	return Tool{
+		Name:        "current_time",
+		Description: "Returns the current date and time. Use it whenever the answer depends on today's date or the time of day.",
+		Parameters: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"timezone": {"type": "string", "description": "IANA time zone, e.g. Europe/Madrid. Defaults to UTC."}
+			}
+		}`),
+		Handler: func(ctx context.Context, arguments json.RawMessage) (string, error) {
+			var args struct {
+				Timezone string `json:"timezone"`
+			}
+			if err := json.Unmarshal(arguments, &args); err != nil {
+				return "", err
+			}
+
+			location := time.UTC
+			if args.Timezone != "" {
+				var err error
+				location, err = time.LoadLocation(args.Timezone)
+				if err != nil {
+					return "", fmt.Errorf("unknown time zone %q", args.Timezone)
+				}
+			}
+			now := time.Now().In(location)
+			return now.Format("Monday, 2 January 2006 15:04:05 MST (-07:00)"), nil
+		},
+	}
+}
+
+// Calculator evaluates arithmetic, which models get wrong surprisingly often
+func Calculator() Tool {
+	return Tool{
+		Name:        "calculator",
+		Description: "Evaluates an arithmetic expression with + - * / and parentheses, e.g. (12.5 + 3) * 4. Use it for any calculation.",
+		Parameters: json.RawMessage(`{
+			"type": "object",
+			"properties": {
+				"expression": {"type": "string", "minLength": 1, "maxLength": 256}
+			},
+			"required": ["expression"]
+		}`),
+		Handler: func(ctx context.Context, arguments json.RawMessage) (string, error) {
+			var args struct {
+				Expression string `json:"expression"`
+			}
+			if err := json.Unmarshal(arguments, &args); err != nil {
+				return "", err
+			}
+
+			result, err := evaluate(args.Expression)
+			if err != nil {
+				return "", err
+			}
+			return strconv.FormatFloat(result, 'g', -1, 64), nil
+		},
+	}
+}
+
+// evaluate computes an arithmetic expression with the usual precedence
+func evaluate(expression string) (float64, error) {
+	p := &parser{input: expression}
+	value, err := p.expression()
+	if err != nil {
+		return 0, err
+	}
+	p.skipSpaces()
+	if p.pos < len(p.input) {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos+1)
+	}
+	return value, nil
+}
+
+// parser is a recursive descent parser over the grammar
+//
+//	expression = term { ("+" | "-") term }
+//	term       = factor { ("*" | "/") factor }
+//	factor     = [ "-" | "+" ] ( number | "(" expression ")" )
+type parser struct {
+	input string
+	pos   int
+	depth int
+}
+
+// maxDepth bounds the nesting of parentheses and signs
+const maxDepth = 64
+
+func (p *parser) expression() (float64, error) {
+	value, err := p.term()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '+':
+			p.pos++
+			right, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			value += right
+		case '-':
+			p.pos++
+			right, err := p.term()
+			if err != nil {
+				return 0, err
+			}
+			value -= right
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *parser) term() (float64, error) {
+	value, err := p.factor()
+	if err != nil {
+		return 0, err
+	}
+	for {
+		switch p.peek() {
+		case '*':
+			p.pos++
+			right, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			value *= right
+		case '/':
+			p.pos++
+			right, err := p.factor()
+			if err != nil {
+				return 0, err
+			}
+			if right == 0 {
+				return 0, errors.New("division by zero")
+			}
+			value /= right
+		default:
+			return value, nil
+		}
+	}
+}
+
+func (p *parser) factor() (float64, error) {
+	p.depth++
+	defer func() { p.depth-- }()
+	if p.depth > maxDepth {
+		return 0, errors.New("expression is nested too deeply")
+	}
+
+	switch p.peek() {
+	case '-':
+		p.pos++
+		value, err := p.factor()
+		return -value, err
+	case '+':
+		p.pos++
+		return p.factor()
+	case '(':
+		p.pos++
+		value, err := p.expression()
+		if err != nil {
+			return 0, err
+		}
+		if p.peek() != ')' {
+			return 0, errors.New("missing closing parenthesis")
+		}
+		p.pos++
+		return value, nil
+	case 0:
+		return 0, errors.New("unexpected end of expression")
+	}
+
+	start := p.pos
+	for p.pos < len(p.input) && strings.IndexByte("0123456789.", p.input[p.pos]) >= 0 {
+		p.pos++
+	}
+	if start == p.pos {
+		return 0, fmt.Errorf("unexpected %q at position %d", p.input[p.pos], p.pos+1)
+	}
+	value, err := strconv.ParseFloat(p.input[start:p.pos], 64)
+	if err != nil {
+		return 0, fmt.Errorf("invalid number %q", p.input[start:p.pos])
+	}
+	return value, nil
+}
+
+// peek skips spaces and returns the next byte, 0 at the end of the input
+func (p *parser) peek() byte {
+	p.skipSpaces()
+	if p.pos >= len(p.input) {
+		return 0
+	}
+	return p.input[p.pos]
+}
+
+func (p *parser) skipSpaces() {
+	for p.pos < len(p.input) && (p.input[p.pos] == ' ' || p.input[p.pos] == '\t') {
+		p.pos++
+	}
+}
@@ -0,0 +1,156 @@
+// Package tools keeps the Go functions the model can call through Ollama
+// function calling. Every tool declares its parameters as a JSON Schema;
+// calls are validated against it before the handler runs.
+package tools
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"regexp"
+	"sort"
+	"sync"
+
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/ollama"
+	"github.com/Gentleman-Programming/gentleman-mcp/internal/schema"
+)
+
+// ErrUnknownTool is returned for calls to tools that are not registered
+var ErrUnknownTool = errors.New("unknown tool")
+
+// validName matches the tool names models handle well
+var validName = regexp.MustCompile(`^[a-zA-Z0-9_-]{1,64}$`)
+
+// Handler runs a tool with the arguments of a call, a JSON object matching
+// the tool parameters, and returns the result for the model
+type Handler func(ctx context.Context, arguments json.RawMessage) (string, error)
+
+// Tool is a function the model can call
+type Tool struct {
+	Name        string
+	Description string          // tells the model when to call the tool
+	Parameters  json.RawMessage // JSON Schema of the arguments object
+	Handler     Handler
+}
+
+// registered is a tool with its compiled parameters schema
+type registered struct {
+	Tool
+	parameters *schema.Schema
+}
+
+// Registry holds the tools of the gateway. It is safe for concurrent use.
+type Registry struct {
+	mutex sync.RWMutex
+	tools map[string]*registered
+}
+
+// NewRegistry creates a registry holding tools
+func NewRegistry(tools ...Tool) (*Registry, error) {
+	r := &Registry{tools: make(map[string]*registered)}
+	for _, tool := range tools {
+		if err := r.Register(tool); err != nil {
+			return nil, err
+		}
+	}
+	return r, nil
+}
+
+// CheckName rejects tool names models may not handle
+func CheckName(name string) error {
+	if !validName.MatchString(name) {
+		return fmt.Errorf("invalid tool name %q: use 1 to 64 letters, digits, _ or -", name)
+	}
+	return nil
+}
+
+// Register adds a tool or replaces the one with the same name
+func (r *Registry) Register(tool Tool) error {
+	if err := CheckName(tool.Name); err != nil {
+		return err
+	}
+	if tool.Handler == nil {
+		return fmt.Errorf("tool %s has no handler", tool.Name)
+	}
+	if len(tool.Parameters) == 0 {
+		tool.Parameters = json.RawMessage(`{"type":"object","properties":{}}`)
+	}
+	parameters, err := schema.Compile(string(tool.Parameters))
+	if err != nil {
+		return fmt.Errorf("tool %s: invalid parameters schema: %w", tool.Name, err)
+	}
+
+	r.mutex.Lock()
+	defer r.mutex.Unlock()
+	r.tools[tool.Name] = &registered{Tool: tool, parameters: parameters}
+	return nil
+}
+
+// Has reports whether a tool is registered
+func (r *Registry) Has(name string) bool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+	_, exists := r.tools[name]
+	return exists
+}
+
+// Names returns the names of the registered tools, sorted
+func (r *Registry) Names() []string {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	names := make([]string, 0, len(r.tools))
+	for name := range r.tools {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+// Definitions returns the named tools for the tools field of an Ollama
+// request, skipping names that are not registered
+func (r *Registry) Definitions(names []string) []ollama.Tool {
+	r.mutex.RLock()
+	defer r.mutex.RUnlock()
+
+	var definitions []ollama.Tool
+	for _, name := range names {
+		tool, exists := r.tools[name]
+		if !exists {
+			continue
+		}
+		definitions = append(definitions, ollama.Tool{
+			Type: "function",
+			Function: ollama.ToolFunction{
+				Name:        tool.Name,
+				Description: tool.Description,
+				Parameters:  tool.Parameters,
+			},
+		})
+	}
+	return definitions
+}
+
+// Call runs a tool. Arguments that do not match the tool parameters are
+// rejected without running it.
+func (r *Registry) Call(ctx context.Context, name string, arguments json.RawMessage) (string, error) {
+	r.mutex.RLock()
+	tool, exists := r.tools[name]
+	r.mutex.RUnlock()
+	if !exists {
+		return "", fmt.Errorf("%w: %s", ErrUnknownTool, name)
+	}
+
+	if len(arguments) == 0 {
+		arguments = json.RawMessage(`{}`)
+	}
+	if _, err := tool.parameters.Validate(string(arguments)); err != nil {
+		var invalid *schema.ValidationError
+		if errors.As(err, &invalid) {
+			return "", fmt.Errorf("invalid arguments: %s", invalid.Reasons())
+		}
+		return "", fmt.Errorf("invalid arguments: %w", err)
+	}
+	return tool.Handler(ctx, arguments)
+}
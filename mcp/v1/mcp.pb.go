@@ -9,6 +9,7 @@ package mcpv1
 import (
 	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
 	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	durationpb "google.golang.org/protobuf/types/known/durationpb"
 	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
 	reflect "reflect"
 	sync "sync"
@@ -22,6 +23,11 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// Type of a chat message, mapped onto the Ollama chat roles. From the client,
+// USER messages are answered, SYSTEM replaces the system prompt and ASSISTANT
+// is added to the history as a model answer. While the model calls tools
+// before its answer, the server sends TOOL_CALL messages and the TOOL_RESULT
+// of its own tools; the agent sends the TOOL_RESULT of the tools it declared.
 type MessageType int32
 
 const (
@@ -29,6 +35,8 @@ const (
 	MessageType_MESSAGE_TYPE_USER        MessageType = 1
 	MessageType_MESSAGE_TYPE_ASSISTANT   MessageType = 2
 	MessageType_MESSAGE_TYPE_SYSTEM      MessageType = 3
+	MessageType_MESSAGE_TYPE_TOOL_CALL   MessageType = 4
+	MessageType_MESSAGE_TYPE_TOOL_RESULT MessageType = 5
 )
 
 // Enum value maps for MessageType.
@@ -38,12 +46,16 @@ var (
 		1: "MESSAGE_TYPE_USER",
 		2: "MESSAGE_TYPE_ASSISTANT",
 		3: "MESSAGE_TYPE_SYSTEM",
+		4: "MESSAGE_TYPE_TOOL_CALL",
+		5: "MESSAGE_TYPE_TOOL_RESULT",
 	}
 	MessageType_value = map[string]int32{
 		"MESSAGE_TYPE_UNSPECIFIED": 0,
 		"MESSAGE_TYPE_USER":        1,
 		"MESSAGE_TYPE_ASSISTANT":   2,
 		"MESSAGE_TYPE_SYSTEM":      3,
+		"MESSAGE_TYPE_TOOL_CALL":   4,
+		"MESSAGE_TYPE_TOOL_RESULT": 5,
 	}
 )
 
@@ -75,10 +87,12 @@ func (MessageType) EnumDescriptor() ([]byte, []int) {
 }
 
 type RegisterRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	TenantId      string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
-	AgentId       string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
-	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"` // "gemma3:4b"
+	state    protoimpl.MessageState `protogen:"open.v1"`
+	TenantId string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	AgentId  string                 `protobuf:"bytes,2,opt,name=agent_id,json=agentId,proto3" json:"agent_id,omitempty"`
+	Model    string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"` // "gemma3:4b"
+	// Defaults for every generation of the session
+	Options       *GenerationOptions `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
@@ -134,13 +148,22 @@ func (x *RegisterRequest) GetModel() string {
 	return ""
 }
 
+func (x *RegisterRequest) GetOptions() *GenerationOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
 type RegisterResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	JwtToken      string                 `protobuf:"bytes,2,opt,name=jwt_token,json=jwtToken,proto3" json:"jwt_token,omitempty"`
-	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SessionId        string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	JwtToken         string                 `protobuf:"bytes,2,opt,name=jwt_token,json=jwtToken,proto3" json:"jwt_token,omitempty"`
+	ExpiresAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RefreshToken     string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	RefreshExpiresAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=refresh_expires_at,json=refreshExpiresAt,proto3" json:"refresh_expires_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *RegisterResponse) Reset() {
@@ -194,6 +217,20 @@ func (x *RegisterResponse) GetExpiresAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *RegisterResponse) GetRefreshToken() string {
+	if x != nil {
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RegisterResponse) GetRefreshExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshExpiresAt
+	}
+	return nil
+}
+
 type AuthRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	JwtToken      string                 `protobuf:"bytes,1,opt,name=jwt_token,json=jwtToken,proto3" json:"jwt_token,omitempty"`
@@ -298,31 +335,28 @@ func (x *AuthResponse) GetAgentId() string {
 	return ""
 }
 
-type ChatMessage struct {
+type RefreshRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	MessageId     string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
-	SessionId     string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
-	Type          MessageType            `protobuf:"varint,4,opt,name=type,proto3,enum=mcp.v1.MessageType" json:"type,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	RefreshToken  string                 `protobuf:"bytes,1,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	JwtToken      string                 `protobuf:"bytes,2,opt,name=jwt_token,json=jwtToken,proto3" json:"jwt_token,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *ChatMessage) Reset() {
-	*x = ChatMessage{}
+func (x *RefreshRequest) Reset() {
+	*x = RefreshRequest{}
 	mi := &file_mcp_v1_mcp_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *ChatMessage) String() string {
+func (x *RefreshRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*ChatMessage) ProtoMessage() {}
+func (*RefreshRequest) ProtoMessage() {}
 
-func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+func (x *RefreshRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_mcp_v1_mcp_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -334,70 +368,167 @@ func (x *ChatMessage) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
-func (*ChatMessage) Descriptor() ([]byte, []int) {
+// Deprecated: Use RefreshRequest.ProtoReflect.Descriptor instead.
+func (*RefreshRequest) Descriptor() ([]byte, []int) {
 	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *ChatMessage) GetMessageId() string {
+func (x *RefreshRequest) GetRefreshToken() string {
 	if x != nil {
-		return x.MessageId
+		return x.RefreshToken
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetSessionId() string {
+func (x *RefreshRequest) GetJwtToken() string {
+	if x != nil {
+		return x.JwtToken
+	}
+	return ""
+}
+
+type RefreshResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	SessionId        string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	JwtToken         string                 `protobuf:"bytes,2,opt,name=jwt_token,json=jwtToken,proto3" json:"jwt_token,omitempty"`
+	ExpiresAt        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"`
+	RefreshToken     string                 `protobuf:"bytes,4,opt,name=refresh_token,json=refreshToken,proto3" json:"refresh_token,omitempty"`
+	RefreshExpiresAt *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=refresh_expires_at,json=refreshExpiresAt,proto3" json:"refresh_expires_at,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *RefreshResponse) Reset() {
+	*x = RefreshResponse{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RefreshResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RefreshResponse) ProtoMessage() {}
+
+func (x *RefreshResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RefreshResponse.ProtoReflect.Descriptor instead.
+func (*RefreshResponse) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *RefreshResponse) GetSessionId() string {
 	if x != nil {
 		return x.SessionId
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetContent() string {
+func (x *RefreshResponse) GetJwtToken() string {
 	if x != nil {
-		return x.Content
+		return x.JwtToken
 	}
 	return ""
 }
 
-func (x *ChatMessage) GetType() MessageType {
+func (x *RefreshResponse) GetExpiresAt() *timestamppb.Timestamp {
 	if x != nil {
-		return x.Type
+		return x.ExpiresAt
 	}
-	return MessageType_MESSAGE_TYPE_UNSPECIFIED
+	return nil
 }
 
-func (x *ChatMessage) GetTimestamp() *timestamppb.Timestamp {
+func (x *RefreshResponse) GetRefreshToken() string {
 	if x != nil {
-		return x.Timestamp
+		return x.RefreshToken
+	}
+	return ""
+}
+
+func (x *RefreshResponse) GetRefreshExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.RefreshExpiresAt
 	}
 	return nil
 }
 
-type SingleChatRequest struct {
+type RevokeRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	JwtToken      string                 `protobuf:"bytes,1,opt,name=jwt_token,json=jwtToken,proto3" json:"jwt_token,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *RevokeRequest) Reset() {
+	*x = RevokeRequest{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *RevokeRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*RevokeRequest) ProtoMessage() {}
+
+func (x *RevokeRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use RevokeRequest.ProtoReflect.Descriptor instead.
+func (*RevokeRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *RevokeRequest) GetJwtToken() string {
+	if x != nil {
+		return x.JwtToken
+	}
+	return ""
+}
+
+type RevokeResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
 	SessionId     string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
-	Content       string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
-	Model         string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"` // "gemma3:4b"
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SingleChatRequest) Reset() {
-	*x = SingleChatRequest{}
-	mi := &file_mcp_v1_mcp_proto_msgTypes[5]
+func (x *RevokeResponse) Reset() {
+	*x = RevokeResponse{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[7]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SingleChatRequest) String() string {
+func (x *RevokeResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SingleChatRequest) ProtoMessage() {}
+func (*RevokeResponse) ProtoMessage() {}
 
-func (x *SingleChatRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_v1_mcp_proto_msgTypes[5]
+func (x *RevokeResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[7]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -408,55 +539,194 @@ func (x *SingleChatRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SingleChatRequest.ProtoReflect.Descriptor instead.
-func (*SingleChatRequest) Descriptor() ([]byte, []int) {
-	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use RevokeResponse.ProtoReflect.Descriptor instead.
+func (*RevokeResponse) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{7}
 }
 
-func (x *SingleChatRequest) GetSessionId() string {
+func (x *RevokeResponse) GetSessionId() string {
 	if x != nil {
 		return x.SessionId
 	}
 	return ""
 }
 
-func (x *SingleChatRequest) GetContent() string {
+type ChatMessage struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	MessageId string                 `protobuf:"bytes,1,opt,name=message_id,json=messageId,proto3" json:"message_id,omitempty"`
+	SessionId string                 `protobuf:"bytes,2,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Content   string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	Type      MessageType            `protobuf:"varint,4,opt,name=type,proto3,enum=mcp.v1.MessageType" json:"type,omitempty"`
+	Timestamp *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Done      bool                   `protobuf:"varint,6,opt,name=done,proto3" json:"done,omitempty"` // true on the last chunk of a streamed response
+	// Overrides the session options for the answer to this message
+	Options *GenerationOptions `protobuf:"bytes,7,opt,name=options,proto3" json:"options,omitempty"`
+	// Set on the done message of an assistant answer
+	Usage *Usage `protobuf:"bytes,8,opt,name=usage,proto3" json:"usage,omitempty"`
+	// Images for the model to look at, on USER messages
+	Attachments []*Attachment `protobuf:"bytes,9,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	// Asks for a JSON answer, on USER messages. The answer then arrives in one
+	// message instead of token by token.
+	ResponseFormat *ResponseFormat `protobuf:"bytes,10,opt,name=response_format,json=responseFormat,proto3" json:"response_format,omitempty"`
+	// The tool the model calls, on TOOL_CALL messages
+	ToolCall *ToolCall `protobuf:"bytes,11,opt,name=tool_call,json=toolCall,proto3" json:"tool_call,omitempty"`
+	// What the tool returned, on TOOL_RESULT messages
+	ToolResult *ToolResult `protobuf:"bytes,12,opt,name=tool_result,json=toolResult,proto3" json:"tool_result,omitempty"`
+	// Tools the agent runs itself, declared on the first message of a Chat
+	// stream. A first message with tools and no content only declares them.
+	Tools         []*ToolDefinition `protobuf:"bytes,13,rep,name=tools,proto3" json:"tools,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ChatMessage) Reset() {
+	*x = ChatMessage{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ChatMessage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ChatMessage) ProtoMessage() {}
+
+func (x *ChatMessage) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[8]
 	if x != nil {
-		return x.Content
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ChatMessage.ProtoReflect.Descriptor instead.
+func (*ChatMessage) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *ChatMessage) GetMessageId() string {
+	if x != nil {
+		return x.MessageId
 	}
 	return ""
 }
 
-func (x *SingleChatRequest) GetModel() string {
+func (x *ChatMessage) GetSessionId() string {
 	if x != nil {
-		return x.Model
+		return x.SessionId
 	}
 	return ""
 }
 
-type SingleChatResponse struct {
+func (x *ChatMessage) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ChatMessage) GetType() MessageType {
+	if x != nil {
+		return x.Type
+	}
+	return MessageType_MESSAGE_TYPE_UNSPECIFIED
+}
+
+func (x *ChatMessage) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetDone() bool {
+	if x != nil {
+		return x.Done
+	}
+	return false
+}
+
+func (x *ChatMessage) GetOptions() *GenerationOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetResponseFormat() *ResponseFormat {
+	if x != nil {
+		return x.ResponseFormat
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetToolCall() *ToolCall {
+	if x != nil {
+		return x.ToolCall
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetToolResult() *ToolResult {
+	if x != nil {
+		return x.ToolResult
+	}
+	return nil
+}
+
+func (x *ChatMessage) GetTools() []*ToolDefinition {
+	if x != nil {
+		return x.Tools
+	}
+	return nil
+}
+
+// ToolDefinition describes a tool of the agent to the model. When the model
+// calls it, the gateway sends a TOOL_CALL message down the Chat stream and
+// waits for the TOOL_RESULT message with the same id (chat.tools.client_timeout).
+type ToolDefinition struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
-	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"` // letters, digits, _ and -; cannot shadow a gateway tool
+	Description   string                 `protobuf:"bytes,2,opt,name=description,proto3" json:"description,omitempty"`
+	Parameters    string                 `protobuf:"bytes,3,opt,name=parameters,proto3" json:"parameters,omitempty"` // JSON Schema of the arguments object
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *SingleChatResponse) Reset() {
-	*x = SingleChatResponse{}
-	mi := &file_mcp_v1_mcp_proto_msgTypes[6]
+func (x *ToolDefinition) Reset() {
+	*x = ToolDefinition{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[9]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *SingleChatResponse) String() string {
+func (x *ToolDefinition) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*SingleChatResponse) ProtoMessage() {}
+func (*ToolDefinition) ProtoMessage() {}
 
-func (x *SingleChatResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_mcp_v1_mcp_proto_msgTypes[6]
+func (x *ToolDefinition) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[9]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -467,46 +737,863 @@ func (x *SingleChatResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use SingleChatResponse.ProtoReflect.Descriptor instead.
-func (*SingleChatResponse) Descriptor() ([]byte, []int) {
-	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{6}
+// Deprecated: Use ToolDefinition.ProtoReflect.Descriptor instead.
+func (*ToolDefinition) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{9}
 }
 
-func (x *SingleChatResponse) GetContent() string {
+func (x *ToolDefinition) GetName() string {
 	if x != nil {
-		return x.Content
+		return x.Name
 	}
 	return ""
 }
 
-func (x *SingleChatResponse) GetTimestamp() *timestamppb.Timestamp {
+func (x *ToolDefinition) GetDescription() string {
 	if x != nil {
-		return x.Timestamp
+		return x.Description
 	}
-	return nil
+	return ""
 }
 
-var File_mcp_v1_mcp_proto protoreflect.FileDescriptor
+func (x *ToolDefinition) GetParameters() string {
+	if x != nil {
+		return x.Parameters
+	}
+	return ""
+}
 
-const file_mcp_v1_mcp_proto_rawDesc = "" +
-	"\n" +
-	"\x10mcp/v1/mcp.proto\x12\x06mcp.v1\x1a\x1fgoogle/protobuf/timestamp.proto\"_\n" +
-	"\x0fRegisterRequest\x12\x1b\n" +
-	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x19\n" +
+// ToolCall is a call of the model to a tool, sent to the client before the
+// tool runs. For tools of the agent it asks the agent to run it.
+type ToolCall struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // pairs the call with its result
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Arguments     string                 `protobuf:"bytes,3,opt,name=arguments,proto3" json:"arguments,omitempty"` // JSON object
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolCall) Reset() {
+	*x = ToolCall{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolCall) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolCall) ProtoMessage() {}
+
+func (x *ToolCall) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolCall.ProtoReflect.Descriptor instead.
+func (*ToolCall) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *ToolCall) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ToolCall) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolCall) GetArguments() string {
+	if x != nil {
+		return x.Arguments
+	}
+	return ""
+}
+
+// ToolResult is what a tool returned to the model. Agents send one for every
+// TOOL_CALL of their own tools.
+type ToolResult struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"` // id of the call
+	Name          string                 `protobuf:"bytes,2,opt,name=name,proto3" json:"name,omitempty"`
+	Content       string                 `protobuf:"bytes,3,opt,name=content,proto3" json:"content,omitempty"`
+	IsError       bool                   `protobuf:"varint,4,opt,name=is_error,json=isError,proto3" json:"is_error,omitempty"` // the tool failed; content holds the error
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ToolResult) Reset() {
+	*x = ToolResult{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ToolResult) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ToolResult) ProtoMessage() {}
+
+func (x *ToolResult) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ToolResult.ProtoReflect.Descriptor instead.
+func (*ToolResult) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *ToolResult) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *ToolResult) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *ToolResult) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *ToolResult) GetIsError() bool {
+	if x != nil {
+		return x.IsError
+	}
+	return false
+}
+
+type SingleChatRequest struct {
+	state     protoimpl.MessageState `protogen:"open.v1"`
+	SessionId string                 `protobuf:"bytes,1,opt,name=session_id,json=sessionId,proto3" json:"session_id,omitempty"`
+	Content   string                 `protobuf:"bytes,2,opt,name=content,proto3" json:"content,omitempty"`
+	Model     string                 `protobuf:"bytes,3,opt,name=model,proto3" json:"model,omitempty"` // "gemma3:4b"
+	// Overrides the session options for this request
+	Options *GenerationOptions `protobuf:"bytes,4,opt,name=options,proto3" json:"options,omitempty"`
+	// Images for the model to look at
+	Attachments []*Attachment `protobuf:"bytes,5,rep,name=attachments,proto3" json:"attachments,omitempty"`
+	// Asks for a JSON answer
+	ResponseFormat *ResponseFormat `protobuf:"bytes,6,opt,name=response_format,json=responseFormat,proto3" json:"response_format,omitempty"`
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *SingleChatRequest) Reset() {
+	*x = SingleChatRequest{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SingleChatRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SingleChatRequest) ProtoMessage() {}
+
+func (x *SingleChatRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SingleChatRequest.ProtoReflect.Descriptor instead.
+func (*SingleChatRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *SingleChatRequest) GetSessionId() string {
+	if x != nil {
+		return x.SessionId
+	}
+	return ""
+}
+
+func (x *SingleChatRequest) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SingleChatRequest) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *SingleChatRequest) GetOptions() *GenerationOptions {
+	if x != nil {
+		return x.Options
+	}
+	return nil
+}
+
+func (x *SingleChatRequest) GetAttachments() []*Attachment {
+	if x != nil {
+		return x.Attachments
+	}
+	return nil
+}
+
+func (x *SingleChatRequest) GetResponseFormat() *ResponseFormat {
+	if x != nil {
+		return x.ResponseFormat
+	}
+	return nil
+}
+
+// ResponseFormat asks for a machine-readable answer. The gateway checks the
+// answer against the schema and asks the model to repair invalid ones; when
+// that fails too, the request fails with FAILED_PRECONDITION and a
+// PreconditionFailure detail listing the violations.
+type ResponseFormat struct {
+	state protoimpl.MessageState `protogen:"open.v1"`
+	// JSON Schema the answer must match. Empty accepts any JSON value. The
+	// schema cannot reference other documents.
+	JsonSchema    string `protobuf:"bytes,1,opt,name=json_schema,json=jsonSchema,proto3" json:"json_schema,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ResponseFormat) Reset() {
+	*x = ResponseFormat{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ResponseFormat) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ResponseFormat) ProtoMessage() {}
+
+func (x *ResponseFormat) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ResponseFormat.ProtoReflect.Descriptor instead.
+func (*ResponseFormat) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *ResponseFormat) GetJsonSchema() string {
+	if x != nil {
+		return x.JsonSchema
+	}
+	return ""
+}
+
+// Attachment is a file sent along a message. Gemma 3 reads images: the types
+// and sizes accepted are set in security.validation.attachments.
+type Attachment struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Data          []byte                 `protobuf:"bytes,1,opt,name=data,proto3" json:"data,omitempty"`
+	MimeType      string                 `protobuf:"bytes,2,opt,name=mime_type,json=mimeType,proto3" json:"mime_type,omitempty"` // "image/png"; detected from data when empty
+	Name          string                 `protobuf:"bytes,3,opt,name=name,proto3" json:"name,omitempty"`                         // file name, informative only
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Attachment) Reset() {
+	*x = Attachment{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Attachment) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Attachment) ProtoMessage() {}
+
+func (x *Attachment) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Attachment.ProtoReflect.Descriptor instead.
+func (*Attachment) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *Attachment) GetData() []byte {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Attachment) GetMimeType() string {
+	if x != nil {
+		return x.MimeType
+	}
+	return ""
+}
+
+func (x *Attachment) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+// Sampling parameters passed to Ollama. Unset fields fall back to the session
+// options given at Register, then to the model defaults. Values outside the
+// ranges below, or above the limits of the tenant, are rejected.
+type GenerationOptions struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Temperature   *float64               `protobuf:"fixed64,1,opt,name=temperature,proto3,oneof" json:"temperature,omitempty"`             // 0 to 2
+	TopP          *float64               `protobuf:"fixed64,2,opt,name=top_p,json=topP,proto3,oneof" json:"top_p,omitempty"`               // 0 to 1
+	TopK          *int32                 `protobuf:"varint,3,opt,name=top_k,json=topK,proto3,oneof" json:"top_k,omitempty"`                // 1 to 1000
+	NumCtx        *int32                 `protobuf:"varint,4,opt,name=num_ctx,json=numCtx,proto3,oneof" json:"num_ctx,omitempty"`          // context window in tokens
+	Seed          *int64                 `protobuf:"varint,5,opt,name=seed,proto3,oneof" json:"seed,omitempty"`                            // same seed and prompt, same answer
+	Stop          []string               `protobuf:"bytes,6,rep,name=stop,proto3" json:"stop,omitempty"`                                   // up to 8 stop sequences
+	MaxTokens     *int32                 `protobuf:"varint,7,opt,name=max_tokens,json=maxTokens,proto3,oneof" json:"max_tokens,omitempty"` // tokens to generate at most
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GenerationOptions) Reset() {
+	*x = GenerationOptions{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GenerationOptions) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GenerationOptions) ProtoMessage() {}
+
+func (x *GenerationOptions) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GenerationOptions.ProtoReflect.Descriptor instead.
+func (*GenerationOptions) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GenerationOptions) GetTemperature() float64 {
+	if x != nil && x.Temperature != nil {
+		return *x.Temperature
+	}
+	return 0
+}
+
+func (x *GenerationOptions) GetTopP() float64 {
+	if x != nil && x.TopP != nil {
+		return *x.TopP
+	}
+	return 0
+}
+
+func (x *GenerationOptions) GetTopK() int32 {
+	if x != nil && x.TopK != nil {
+		return *x.TopK
+	}
+	return 0
+}
+
+func (x *GenerationOptions) GetNumCtx() int32 {
+	if x != nil && x.NumCtx != nil {
+		return *x.NumCtx
+	}
+	return 0
+}
+
+func (x *GenerationOptions) GetSeed() int64 {
+	if x != nil && x.Seed != nil {
+		return *x.Seed
+	}
+	return 0
+}
+
+func (x *GenerationOptions) GetStop() []string {
+	if x != nil {
+		return x.Stop
+	}
+	return nil
+}
+
+func (x *GenerationOptions) GetMaxTokens() int32 {
+	if x != nil && x.MaxTokens != nil {
+		return *x.MaxTokens
+	}
+	return 0
+}
+
+type SingleChatResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Content       string                 `protobuf:"bytes,1,opt,name=content,proto3" json:"content,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Usage         *Usage                 `protobuf:"bytes,3,opt,name=usage,proto3" json:"usage,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *SingleChatResponse) Reset() {
+	*x = SingleChatResponse{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *SingleChatResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*SingleChatResponse) ProtoMessage() {}
+
+func (x *SingleChatResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use SingleChatResponse.ProtoReflect.Descriptor instead.
+func (*SingleChatResponse) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *SingleChatResponse) GetContent() string {
+	if x != nil {
+		return x.Content
+	}
+	return ""
+}
+
+func (x *SingleChatResponse) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *SingleChatResponse) GetUsage() *Usage {
+	if x != nil {
+		return x.Usage
+	}
+	return nil
+}
+
+// Usage describes the generation of one answer, as reported by Ollama
+type Usage struct {
+	state              protoimpl.MessageState `protogen:"open.v1"`
+	Model              string                 `protobuf:"bytes,1,opt,name=model,proto3" json:"model,omitempty"` // the model that actually answered
+	PromptTokens       int32                  `protobuf:"varint,2,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CompletionTokens   int32                  `protobuf:"varint,3,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+	TotalDuration      *durationpb.Duration   `protobuf:"bytes,4,opt,name=total_duration,json=totalDuration,proto3" json:"total_duration,omitempty"`
+	LoadDuration       *durationpb.Duration   `protobuf:"bytes,5,opt,name=load_duration,json=loadDuration,proto3" json:"load_duration,omitempty"`                     // loading the model
+	PromptEvalDuration *durationpb.Duration   `protobuf:"bytes,6,opt,name=prompt_eval_duration,json=promptEvalDuration,proto3" json:"prompt_eval_duration,omitempty"` // reading the prompt
+	EvalDuration       *durationpb.Duration   `protobuf:"bytes,7,opt,name=eval_duration,json=evalDuration,proto3" json:"eval_duration,omitempty"`                     // generating the answer
+	TokensPerSecond    float64                `protobuf:"fixed64,8,opt,name=tokens_per_second,json=tokensPerSecond,proto3" json:"tokens_per_second,omitempty"`        // completion tokens over eval_duration
+	DoneReason         string                 `protobuf:"bytes,9,opt,name=done_reason,json=doneReason,proto3" json:"done_reason,omitempty"`                           // "stop", "length"...
+	unknownFields      protoimpl.UnknownFields
+	sizeCache          protoimpl.SizeCache
+}
+
+func (x *Usage) Reset() {
+	*x = Usage{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Usage) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Usage) ProtoMessage() {}
+
+func (x *Usage) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Usage.ProtoReflect.Descriptor instead.
+func (*Usage) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *Usage) GetModel() string {
+	if x != nil {
+		return x.Model
+	}
+	return ""
+}
+
+func (x *Usage) GetPromptTokens() int32 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *Usage) GetCompletionTokens() int32 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+func (x *Usage) GetTotalDuration() *durationpb.Duration {
+	if x != nil {
+		return x.TotalDuration
+	}
+	return nil
+}
+
+func (x *Usage) GetLoadDuration() *durationpb.Duration {
+	if x != nil {
+		return x.LoadDuration
+	}
+	return nil
+}
+
+func (x *Usage) GetPromptEvalDuration() *durationpb.Duration {
+	if x != nil {
+		return x.PromptEvalDuration
+	}
+	return nil
+}
+
+func (x *Usage) GetEvalDuration() *durationpb.Duration {
+	if x != nil {
+		return x.EvalDuration
+	}
+	return nil
+}
+
+func (x *Usage) GetTokensPerSecond() float64 {
+	if x != nil {
+		return x.TokensPerSecond
+	}
+	return 0
+}
+
+func (x *Usage) GetDoneReason() string {
+	if x != nil {
+		return x.DoneReason
+	}
+	return ""
+}
+
+type GetUsageRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetUsageRequest) Reset() {
+	*x = GetUsageRequest{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageRequest) ProtoMessage() {}
+
+func (x *GetUsageRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageRequest.ProtoReflect.Descriptor instead.
+func (*GetUsageRequest) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{18}
+}
+
+type GetUsageResponse struct {
+	state            protoimpl.MessageState `protogen:"open.v1"`
+	TenantId         string                 `protobuf:"bytes,1,opt,name=tenant_id,json=tenantId,proto3" json:"tenant_id,omitempty"`
+	WindowStart      *timestamppb.Timestamp `protobuf:"bytes,2,opt,name=window_start,json=windowStart,proto3" json:"window_start,omitempty"`
+	WindowEnd        *timestamppb.Timestamp `protobuf:"bytes,3,opt,name=window_end,json=windowEnd,proto3" json:"window_end,omitempty"` // when requests and tokens reset
+	Sessions         *Quota                 `protobuf:"bytes,4,opt,name=sessions,proto3" json:"sessions,omitempty"`                    // active sessions
+	Requests         *Quota                 `protobuf:"bytes,5,opt,name=requests,proto3" json:"requests,omitempty"`
+	Tokens           *Quota                 `protobuf:"bytes,6,opt,name=tokens,proto3" json:"tokens,omitempty"` // prompt plus completion tokens
+	PromptTokens     int64                  `protobuf:"varint,7,opt,name=prompt_tokens,json=promptTokens,proto3" json:"prompt_tokens,omitempty"`
+	CompletionTokens int64                  `protobuf:"varint,8,opt,name=completion_tokens,json=completionTokens,proto3" json:"completion_tokens,omitempty"`
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
+}
+
+func (x *GetUsageResponse) Reset() {
+	*x = GetUsageResponse{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetUsageResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetUsageResponse) ProtoMessage() {}
+
+func (x *GetUsageResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetUsageResponse.ProtoReflect.Descriptor instead.
+func (*GetUsageResponse) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *GetUsageResponse) GetTenantId() string {
+	if x != nil {
+		return x.TenantId
+	}
+	return ""
+}
+
+func (x *GetUsageResponse) GetWindowStart() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowStart
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetWindowEnd() *timestamppb.Timestamp {
+	if x != nil {
+		return x.WindowEnd
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetSessions() *Quota {
+	if x != nil {
+		return x.Sessions
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetRequests() *Quota {
+	if x != nil {
+		return x.Requests
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetTokens() *Quota {
+	if x != nil {
+		return x.Tokens
+	}
+	return nil
+}
+
+func (x *GetUsageResponse) GetPromptTokens() int64 {
+	if x != nil {
+		return x.PromptTokens
+	}
+	return 0
+}
+
+func (x *GetUsageResponse) GetCompletionTokens() int64 {
+	if x != nil {
+		return x.CompletionTokens
+	}
+	return 0
+}
+
+type Quota struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Used          int64                  `protobuf:"varint,1,opt,name=used,proto3" json:"used,omitempty"`
+	Limit         int64                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`         // 0 = unlimited
+	Remaining     int64                  `protobuf:"varint,3,opt,name=remaining,proto3" json:"remaining,omitempty"` // -1 when unlimited
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Quota) Reset() {
+	*x = Quota{}
+	mi := &file_mcp_v1_mcp_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Quota) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Quota) ProtoMessage() {}
+
+func (x *Quota) ProtoReflect() protoreflect.Message {
+	mi := &file_mcp_v1_mcp_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Quota.ProtoReflect.Descriptor instead.
+func (*Quota) Descriptor() ([]byte, []int) {
+	return file_mcp_v1_mcp_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *Quota) GetUsed() int64 {
+	if x != nil {
+		return x.Used
+	}
+	return 0
+}
+
+func (x *Quota) GetLimit() int64 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *Quota) GetRemaining() int64 {
+	if x != nil {
+		return x.Remaining
+	}
+	return 0
+}
+
+var File_mcp_v1_mcp_proto protoreflect.FileDescriptor
+
+const file_mcp_v1_mcp_proto_rawDesc = "" +
+	"\n" +
+	"\x10mcp/v1/mcp.proto\x12\x06mcp.v1\x1a\x1egoogle/protobuf/duration.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x94\x01\n" +
+	"\x0fRegisterRequest\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12\x19\n" +
 	"\bagent_id\x18\x02 \x01(\tR\aagentId\x12\x14\n" +
-	"\x05model\x18\x03 \x01(\tR\x05model\"\x89\x01\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x123\n" +
+	"\aoptions\x18\x04 \x01(\v2\x19.mcp.v1.GenerationOptionsR\aoptions\"\xf8\x01\n" +
 	"\x10RegisterResponse\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1b\n" +
 	"\tjwt_token\x18\x02 \x01(\tR\bjwtToken\x129\n" +
 	"\n" +
-	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\"*\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12H\n" +
+	"\x12refresh_expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x10refreshExpiresAt\"*\n" +
 	"\vAuthRequest\x12\x1b\n" +
 	"\tjwt_token\x18\x01 \x01(\tR\bjwtToken\"\\\n" +
 	"\fAuthResponse\x12\x14\n" +
 	"\x05valid\x18\x01 \x01(\bR\x05valid\x12\x1b\n" +
 	"\ttenant_id\x18\x02 \x01(\tR\btenantId\x12\x19\n" +
-	"\bagent_id\x18\x03 \x01(\tR\aagentId\"\xc8\x01\n" +
+	"\bagent_id\x18\x03 \x01(\tR\aagentId\"R\n" +
+	"\x0eRefreshRequest\x12#\n" +
+	"\rrefresh_token\x18\x01 \x01(\tR\frefreshToken\x12\x1b\n" +
+	"\tjwt_token\x18\x02 \x01(\tR\bjwtToken\"\xf7\x01\n" +
+	"\x0fRefreshResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x1b\n" +
+	"\tjwt_token\x18\x02 \x01(\tR\bjwtToken\x129\n" +
+	"\n" +
+	"expires_at\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x12#\n" +
+	"\rrefresh_token\x18\x04 \x01(\tR\frefreshToken\x12H\n" +
+	"\x12refresh_expires_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\x10refreshExpiresAt\",\n" +
+	"\rRevokeRequest\x12\x1b\n" +
+	"\tjwt_token\x18\x01 \x01(\tR\bjwtToken\"/\n" +
+	"\x0eRevokeResponse\x12\x1d\n" +
+	"\n" +
+	"session_id\x18\x01 \x01(\tR\tsessionId\"\xbf\x04\n" +
 	"\vChatMessage\x12\x1d\n" +
 	"\n" +
 	"message_id\x18\x01 \x01(\tR\tmessageId\x12\x1d\n" +
@@ -514,27 +1601,113 @@ const file_mcp_v1_mcp_proto_rawDesc = "" +
 	"session_id\x18\x02 \x01(\tR\tsessionId\x12\x18\n" +
 	"\acontent\x18\x03 \x01(\tR\acontent\x12'\n" +
 	"\x04type\x18\x04 \x01(\x0e2\x13.mcp.v1.MessageTypeR\x04type\x128\n" +
-	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"b\n" +
+	"\ttimestamp\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x12\n" +
+	"\x04done\x18\x06 \x01(\bR\x04done\x123\n" +
+	"\aoptions\x18\a \x01(\v2\x19.mcp.v1.GenerationOptionsR\aoptions\x12#\n" +
+	"\x05usage\x18\b \x01(\v2\r.mcp.v1.UsageR\x05usage\x124\n" +
+	"\vattachments\x18\t \x03(\v2\x12.mcp.v1.AttachmentR\vattachments\x12?\n" +
+	"\x0fresponse_format\x18\n" +
+	" \x01(\v2\x16.mcp.v1.ResponseFormatR\x0eresponseFormat\x12-\n" +
+	"\ttool_call\x18\v \x01(\v2\x10.mcp.v1.ToolCallR\btoolCall\x123\n" +
+	"\vtool_result\x18\f \x01(\v2\x12.mcp.v1.ToolResultR\n" +
+	"toolResult\x12,\n" +
+	"\x05tools\x18\r \x03(\v2\x16.mcp.v1.ToolDefinitionR\x05tools\"f\n" +
+	"\x0eToolDefinition\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12 \n" +
+	"\vdescription\x18\x02 \x01(\tR\vdescription\x12\x1e\n" +
+	"\n" +
+	"parameters\x18\x03 \x01(\tR\n" +
+	"parameters\"L\n" +
+	"\bToolCall\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x1c\n" +
+	"\targuments\x18\x03 \x01(\tR\targuments\"e\n" +
+	"\n" +
+	"ToolResult\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04name\x18\x02 \x01(\tR\x04name\x12\x18\n" +
+	"\acontent\x18\x03 \x01(\tR\acontent\x12\x19\n" +
+	"\bis_error\x18\x04 \x01(\bR\aisError\"\x8e\x02\n" +
 	"\x11SingleChatRequest\x12\x1d\n" +
 	"\n" +
 	"session_id\x18\x01 \x01(\tR\tsessionId\x12\x18\n" +
 	"\acontent\x18\x02 \x01(\tR\acontent\x12\x14\n" +
-	"\x05model\x18\x03 \x01(\tR\x05model\"h\n" +
+	"\x05model\x18\x03 \x01(\tR\x05model\x123\n" +
+	"\aoptions\x18\x04 \x01(\v2\x19.mcp.v1.GenerationOptionsR\aoptions\x124\n" +
+	"\vattachments\x18\x05 \x03(\v2\x12.mcp.v1.AttachmentR\vattachments\x12?\n" +
+	"\x0fresponse_format\x18\x06 \x01(\v2\x16.mcp.v1.ResponseFormatR\x0eresponseFormat\"1\n" +
+	"\x0eResponseFormat\x12\x1f\n" +
+	"\vjson_schema\x18\x01 \x01(\tR\n" +
+	"jsonSchema\"Q\n" +
+	"\n" +
+	"Attachment\x12\x12\n" +
+	"\x04data\x18\x01 \x01(\fR\x04data\x12\x1b\n" +
+	"\tmime_type\x18\x02 \x01(\tR\bmimeType\x12\x12\n" +
+	"\x04name\x18\x03 \x01(\tR\x04name\"\xa5\x02\n" +
+	"\x11GenerationOptions\x12%\n" +
+	"\vtemperature\x18\x01 \x01(\x01H\x00R\vtemperature\x88\x01\x01\x12\x18\n" +
+	"\x05top_p\x18\x02 \x01(\x01H\x01R\x04topP\x88\x01\x01\x12\x18\n" +
+	"\x05top_k\x18\x03 \x01(\x05H\x02R\x04topK\x88\x01\x01\x12\x1c\n" +
+	"\anum_ctx\x18\x04 \x01(\x05H\x03R\x06numCtx\x88\x01\x01\x12\x17\n" +
+	"\x04seed\x18\x05 \x01(\x03H\x04R\x04seed\x88\x01\x01\x12\x12\n" +
+	"\x04stop\x18\x06 \x03(\tR\x04stop\x12\"\n" +
+	"\n" +
+	"max_tokens\x18\a \x01(\x05H\x05R\tmaxTokens\x88\x01\x01B\x0e\n" +
+	"\f_temperatureB\b\n" +
+	"\x06_top_pB\b\n" +
+	"\x06_top_kB\n" +
+	"\n" +
+	"\b_num_ctxB\a\n" +
+	"\x05_seedB\r\n" +
+	"\v_max_tokens\"\x8d\x01\n" +
 	"\x12SingleChatResponse\x12\x18\n" +
 	"\acontent\x18\x01 \x01(\tR\acontent\x128\n" +
-	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp*w\n" +
+	"\ttimestamp\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12#\n" +
+	"\x05usage\x18\x03 \x01(\v2\r.mcp.v1.UsageR\x05usage\"\xcb\x03\n" +
+	"\x05Usage\x12\x14\n" +
+	"\x05model\x18\x01 \x01(\tR\x05model\x12#\n" +
+	"\rprompt_tokens\x18\x02 \x01(\x05R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\x03 \x01(\x05R\x10completionTokens\x12@\n" +
+	"\x0etotal_duration\x18\x04 \x01(\v2\x19.google.protobuf.DurationR\rtotalDuration\x12>\n" +
+	"\rload_duration\x18\x05 \x01(\v2\x19.google.protobuf.DurationR\floadDuration\x12K\n" +
+	"\x14prompt_eval_duration\x18\x06 \x01(\v2\x19.google.protobuf.DurationR\x12promptEvalDuration\x12>\n" +
+	"\reval_duration\x18\a \x01(\v2\x19.google.protobuf.DurationR\fevalDuration\x12*\n" +
+	"\x11tokens_per_second\x18\b \x01(\x01R\x0ftokensPerSecond\x12\x1f\n" +
+	"\vdone_reason\x18\t \x01(\tR\n" +
+	"doneReason\"\x11\n" +
+	"\x0fGetUsageRequest\"\xf8\x02\n" +
+	"\x10GetUsageResponse\x12\x1b\n" +
+	"\ttenant_id\x18\x01 \x01(\tR\btenantId\x12=\n" +
+	"\fwindow_start\x18\x02 \x01(\v2\x1a.google.protobuf.TimestampR\vwindowStart\x129\n" +
+	"\n" +
+	"window_end\x18\x03 \x01(\v2\x1a.google.protobuf.TimestampR\twindowEnd\x12)\n" +
+	"\bsessions\x18\x04 \x01(\v2\r.mcp.v1.QuotaR\bsessions\x12)\n" +
+	"\brequests\x18\x05 \x01(\v2\r.mcp.v1.QuotaR\brequests\x12%\n" +
+	"\x06tokens\x18\x06 \x01(\v2\r.mcp.v1.QuotaR\x06tokens\x12#\n" +
+	"\rprompt_tokens\x18\a \x01(\x03R\fpromptTokens\x12+\n" +
+	"\x11completion_tokens\x18\b \x01(\x03R\x10completionTokens\"O\n" +
+	"\x05Quota\x12\x12\n" +
+	"\x04used\x18\x01 \x01(\x03R\x04used\x12\x14\n" +
+	"\x05limit\x18\x02 \x01(\x03R\x05limit\x12\x1c\n" +
+	"\tremaining\x18\x03 \x01(\x03R\tremaining*\xb1\x01\n" +
 	"\vMessageType\x12\x1c\n" +
 	"\x18MESSAGE_TYPE_UNSPECIFIED\x10\x00\x12\x15\n" +
 	"\x11MESSAGE_TYPE_USER\x10\x01\x12\x1a\n" +
 	"\x16MESSAGE_TYPE_ASSISTANT\x10\x02\x12\x17\n" +
-	"\x13MESSAGE_TYPE_SYSTEM\x10\x032\x8c\x01\n" +
+	"\x13MESSAGE_TYPE_SYSTEM\x10\x03\x12\x1a\n" +
+	"\x16MESSAGE_TYPE_TOOL_CALL\x10\x04\x12\x1c\n" +
+	"\x18MESSAGE_TYPE_TOOL_RESULT\x10\x052\x81\x02\n" +
 	"\x10HandshakeService\x12=\n" +
 	"\bRegister\x12\x17.mcp.v1.RegisterRequest\x1a\x18.mcp.v1.RegisterResponse\x129\n" +
-	"\fAuthenticate\x12\x13.mcp.v1.AuthRequest\x1a\x14.mcp.v1.AuthResponse2\x89\x01\n" +
+	"\fAuthenticate\x12\x13.mcp.v1.AuthRequest\x1a\x14.mcp.v1.AuthResponse\x12:\n" +
+	"\aRefresh\x12\x16.mcp.v1.RefreshRequest\x1a\x17.mcp.v1.RefreshResponse\x127\n" +
+	"\x06Revoke\x12\x15.mcp.v1.RevokeRequest\x1a\x16.mcp.v1.RevokeResponse2\x8c\x02\n" +
 	"\fAgentService\x124\n" +
 	"\x04Chat\x12\x13.mcp.v1.ChatMessage\x1a\x13.mcp.v1.ChatMessage(\x010\x01\x12C\n" +
 	"\n" +
-	"SingleChat\x12\x19.mcp.v1.SingleChatRequest\x1a\x1a.mcp.v1.SingleChatResponseB\x8c\x01\n" +
+	"SingleChat\x12\x19.mcp.v1.SingleChatRequest\x1a\x1a.mcp.v1.SingleChatResponse\x12B\n" +
+	"\x0eGenerateStream\x12\x19.mcp.v1.SingleChatRequest\x1a\x13.mcp.v1.ChatMessage0\x01\x12=\n" +
+	"\bGetUsage\x12\x17.mcp.v1.GetUsageRequest\x1a\x18.mcp.v1.GetUsageResponseB\x8c\x01\n" +
 	"\n" +
 	"com.mcp.v1B\bMcpProtoP\x01Z;github.com/Gentleman-Programming/gentleman-mcp/mcp/v1;mcpv1\xa2\x02\x03MXX\xaa\x02\x06Mcp.V1\xca\x02\x06Mcp\\V1\xe2\x02\x12Mcp\\V1\\GPBMetadata\xea\x02\aMcp::V1b\x06proto3"
 
@@ -551,36 +1724,83 @@ func file_mcp_v1_mcp_proto_rawDescGZIP() []byte {
 }
 
 var file_mcp_v1_mcp_proto_enumTypes = make([]protoimpl.EnumInfo, 1)
-var file_mcp_v1_mcp_proto_msgTypes = make([]protoimpl.MessageInfo, 7)
+var file_mcp_v1_mcp_proto_msgTypes = make([]protoimpl.MessageInfo, 21)
 var file_mcp_v1_mcp_proto_goTypes = []any{
 	(MessageType)(0),              // 0: mcp.v1.MessageType
 	(*RegisterRequest)(nil),       // 1: mcp.v1.RegisterRequest
 	(*RegisterResponse)(nil),      // 2: mcp.v1.RegisterResponse
 	(*AuthRequest)(nil),           // 3: mcp.v1.AuthRequest
 	(*AuthResponse)(nil),          // 4: mcp.v1.AuthResponse
-	(*ChatMessage)(nil),           // 5: mcp.v1.ChatMessage
-	(*SingleChatRequest)(nil),     // 6: mcp.v1.SingleChatRequest
-	(*SingleChatResponse)(nil),    // 7: mcp.v1.SingleChatResponse
-	(*timestamppb.Timestamp)(nil), // 8: google.protobuf.Timestamp
+	(*RefreshRequest)(nil),        // 5: mcp.v1.RefreshRequest
+	(*RefreshResponse)(nil),       // 6: mcp.v1.RefreshResponse
+	(*RevokeRequest)(nil),         // 7: mcp.v1.RevokeRequest
+	(*RevokeResponse)(nil),        // 8: mcp.v1.RevokeResponse
+	(*ChatMessage)(nil),           // 9: mcp.v1.ChatMessage
+	(*ToolDefinition)(nil),        // 10: mcp.v1.ToolDefinition
+	(*ToolCall)(nil),              // 11: mcp.v1.ToolCall
+	(*ToolResult)(nil),            // 12: mcp.v1.ToolResult
+	(*SingleChatRequest)(nil),     // 13: mcp.v1.SingleChatRequest
+	(*ResponseFormat)(nil),        // 14: mcp.v1.ResponseFormat
+	(*Attachment)(nil),            // 15: mcp.v1.Attachment
+	(*GenerationOptions)(nil),     // 16: mcp.v1.GenerationOptions
+	(*SingleChatResponse)(nil),    // 17: mcp.v1.SingleChatResponse
+	(*Usage)(nil),                 // 18: mcp.v1.Usage
+	(*GetUsageRequest)(nil),       // 19: mcp.v1.GetUsageRequest
+	(*GetUsageResponse)(nil),      // 20: mcp.v1.GetUsageResponse
+	(*Quota)(nil),                 // 21: mcp.v1.Quota
+	(*timestamppb.Timestamp)(nil), // 22: google.protobuf.Timestamp
+	(*durationpb.Duration)(nil),   // 23: google.protobuf.Duration
 }
 var file_mcp_v1_mcp_proto_depIdxs = []int32{
-	8, // 0: mcp.v1.RegisterResponse.expires_at:type_name -> google.protobuf.Timestamp
-	0, // 1: mcp.v1.ChatMessage.type:type_name -> mcp.v1.MessageType
-	8, // 2: mcp.v1.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
-	8, // 3: mcp.v1.SingleChatResponse.timestamp:type_name -> google.protobuf.Timestamp
-	1, // 4: mcp.v1.HandshakeService.Register:input_type -> mcp.v1.RegisterRequest
-	3, // 5: mcp.v1.HandshakeService.Authenticate:input_type -> mcp.v1.AuthRequest
-	5, // 6: mcp.v1.AgentService.Chat:input_type -> mcp.v1.ChatMessage
-	6, // 7: mcp.v1.AgentService.SingleChat:input_type -> mcp.v1.SingleChatRequest
-	2, // 8: mcp.v1.HandshakeService.Register:output_type -> mcp.v1.RegisterResponse
-	4, // 9: mcp.v1.HandshakeService.Authenticate:output_type -> mcp.v1.AuthResponse
-	5, // 10: mcp.v1.AgentService.Chat:output_type -> mcp.v1.ChatMessage
-	7, // 11: mcp.v1.AgentService.SingleChat:output_type -> mcp.v1.SingleChatResponse
-	8, // [8:12] is the sub-list for method output_type
-	4, // [4:8] is the sub-list for method input_type
-	4, // [4:4] is the sub-list for extension type_name
-	4, // [4:4] is the sub-list for extension extendee
-	0, // [0:4] is the sub-list for field type_name
+	16, // 0: mcp.v1.RegisterRequest.options:type_name -> mcp.v1.GenerationOptions
+	22, // 1: mcp.v1.RegisterResponse.expires_at:type_name -> google.protobuf.Timestamp
+	22, // 2: mcp.v1.RegisterResponse.refresh_expires_at:type_name -> google.protobuf.Timestamp
+	22, // 3: mcp.v1.RefreshResponse.expires_at:type_name -> google.protobuf.Timestamp
+	22, // 4: mcp.v1.RefreshResponse.refresh_expires_at:type_name -> google.protobuf.Timestamp
+	0,  // 5: mcp.v1.ChatMessage.type:type_name -> mcp.v1.MessageType
+	22, // 6: mcp.v1.ChatMessage.timestamp:type_name -> google.protobuf.Timestamp
+	16, // 7: mcp.v1.ChatMessage.options:type_name -> mcp.v1.GenerationOptions
+	18, // 8: mcp.v1.ChatMessage.usage:type_name -> mcp.v1.Usage
+	15, // 9: mcp.v1.ChatMessage.attachments:type_name -> mcp.v1.Attachment
+	14, // 10: mcp.v1.ChatMessage.response_format:type_name -> mcp.v1.ResponseFormat
+	11, // 11: mcp.v1.ChatMessage.tool_call:type_name -> mcp.v1.ToolCall
+	12, // 12: mcp.v1.ChatMessage.tool_result:type_name -> mcp.v1.ToolResult
+	10, // 13: mcp.v1.ChatMessage.tools:type_name -> mcp.v1.ToolDefinition
+	16, // 14: mcp.v1.SingleChatRequest.options:type_name -> mcp.v1.GenerationOptions
+	15, // 15: mcp.v1.SingleChatRequest.attachments:type_name -> mcp.v1.Attachment
+	14, // 16: mcp.v1.SingleChatRequest.response_format:type_name -> mcp.v1.ResponseFormat
+	22, // 17: mcp.v1.SingleChatResponse.timestamp:type_name -> google.protobuf.Timestamp
+	18, // 18: mcp.v1.SingleChatResponse.usage:type_name -> mcp.v1.Usage
+	23, // 19: mcp.v1.Usage.total_duration:type_name -> google.protobuf.Duration
+	23, // 20: mcp.v1.Usage.load_duration:type_name -> google.protobuf.Duration
+	23, // 21: mcp.v1.Usage.prompt_eval_duration:type_name -> google.protobuf.Duration
+	23, // 22: mcp.v1.Usage.eval_duration:type_name -> google.protobuf.Duration
+	22, // 23: mcp.v1.GetUsageResponse.window_start:type_name -> google.protobuf.Timestamp
+	22, // 24: mcp.v1.GetUsageResponse.window_end:type_name -> google.protobuf.Timestamp
+	21, // 25: mcp.v1.GetUsageResponse.sessions:type_name -> mcp.v1.Quota
+	21, // 26: mcp.v1.GetUsageResponse.requests:type_name -> mcp.v1.Quota
+	21, // 27: mcp.v1.GetUsageResponse.tokens:type_name -> mcp.v1.Quota
+	1,  // 28: mcp.v1.HandshakeService.Register:input_type -> mcp.v1.RegisterRequest
+	3,  // 29: mcp.v1.HandshakeService.Authenticate:input_type -> mcp.v1.AuthRequest
+	5,  // 30: mcp.v1.HandshakeService.Refresh:input_type -> mcp.v1.RefreshRequest
+	7,  // 31: mcp.v1.HandshakeService.Revoke:input_type -> mcp.v1.RevokeRequest
+	9,  // 32: mcp.v1.AgentService.Chat:input_type -> mcp.v1.ChatMessage
+	13, // 33: mcp.v1.AgentService.SingleChat:input_type -> mcp.v1.SingleChatRequest
+	13, // 34: mcp.v1.AgentService.GenerateStream:input_type -> mcp.v1.SingleChatRequest
+	19, // 35: mcp.v1.AgentService.GetUsage:input_type -> mcp.v1.GetUsageRequest
+	2,  // 36: mcp.v1.HandshakeService.Register:output_type -> mcp.v1.RegisterResponse
+	4,  // 37: mcp.v1.HandshakeService.Authenticate:output_type -> mcp.v1.AuthResponse
+	6,  // 38: mcp.v1.HandshakeService.Refresh:output_type -> mcp.v1.RefreshResponse
+	8,  // 39: mcp.v1.HandshakeService.Revoke:output_type -> mcp.v1.RevokeResponse
+	9,  // 40: mcp.v1.AgentService.Chat:output_type -> mcp.v1.ChatMessage
+	17, // 41: mcp.v1.AgentService.SingleChat:output_type -> mcp.v1.SingleChatResponse
+	9,  // 42: mcp.v1.AgentService.GenerateStream:output_type -> mcp.v1.ChatMessage
+	20, // 43: mcp.v1.AgentService.GetUsage:output_type -> mcp.v1.GetUsageResponse
+	36, // [36:44] is the sub-list for method output_type
+	28, // [28:36] is the sub-list for method input_type
+	28, // [28:28] is the sub-list for extension type_name
+	28, // [28:28] is the sub-list for extension extendee
+	0,  // [0:28] is the sub-list for field type_name
 }
 
 func init() { file_mcp_v1_mcp_proto_init() }
@@ -588,13 +1808,14 @@ func file_mcp_v1_mcp_proto_init() {
 	if File_mcp_v1_mcp_proto != nil {
 		return
 	}
+	file_mcp_v1_mcp_proto_msgTypes[15].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_mcp_v1_mcp_proto_rawDesc), len(file_mcp_v1_mcp_proto_rawDesc)),
 			NumEnums:      1,
-			NumMessages:   7,
+			NumMessages:   21,
 			NumExtensions: 0,
 			NumServices:   2,
 		},
@@ -21,6 +21,8 @@ const _ = grpc.SupportPackageIsVersion9
 const (
 	HandshakeService_Register_FullMethodName     = "/mcp.v1.HandshakeService/Register"
 	HandshakeService_Authenticate_FullMethodName = "/mcp.v1.HandshakeService/Authenticate"
+	HandshakeService_Refresh_FullMethodName      = "/mcp.v1.HandshakeService/Refresh"
+	HandshakeService_Revoke_FullMethodName       = "/mcp.v1.HandshakeService/Revoke"
 )
 
 // HandshakeServiceClient is the client API for HandshakeService service.
@@ -29,6 +31,11 @@ const (
 type HandshakeServiceClient interface {
 	Register(ctx context.Context, in *RegisterRequest, opts ...grpc.CallOption) (*RegisterResponse, error)
 	Authenticate(ctx context.Context, in *AuthRequest, opts ...grpc.CallOption) (*AuthResponse, error)
+	// Exchange a refresh token and the (valid or just-expired) access token for
+	// a new pair bound to the same session. Refresh tokens are single-use.
+	Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error)
+	// Logout: invalidate every token of the session and end its chat streams
+	Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error)
 }
 
 type handshakeServiceClient struct {
@@ -59,12 +66,37 @@ func (c *handshakeServiceClient) Authenticate(ctx context.Context, in *AuthReque
 	return out, nil
 }
 
+func (c *handshakeServiceClient) Refresh(ctx context.Context, in *RefreshRequest, opts ...grpc.CallOption) (*RefreshResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RefreshResponse)
+	err := c.cc.Invoke(ctx, HandshakeService_Refresh_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *handshakeServiceClient) Revoke(ctx context.Context, in *RevokeRequest, opts ...grpc.CallOption) (*RevokeResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(RevokeResponse)
+	err := c.cc.Invoke(ctx, HandshakeService_Revoke_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // HandshakeServiceServer is the server API for HandshakeService service.
 // All implementations must embed UnimplementedHandshakeServiceServer
 // for forward compatibility.
 type HandshakeServiceServer interface {
 	Register(context.Context, *RegisterRequest) (*RegisterResponse, error)
 	Authenticate(context.Context, *AuthRequest) (*AuthResponse, error)
+	// Exchange a refresh token and the (valid or just-expired) access token for
+	// a new pair bound to the same session. Refresh tokens are single-use.
+	Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error)
+	// Logout: invalidate every token of the session and end its chat streams
+	Revoke(context.Context, *RevokeRequest) (*RevokeResponse, error)
 	mustEmbedUnimplementedHandshakeServiceServer()
 }
 
@@ -81,6 +113,12 @@ func (UnimplementedHandshakeServiceServer) Register(context.Context, *RegisterRe
 func (UnimplementedHandshakeServiceServer) Authenticate(context.Context, *AuthRequest) (*AuthResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method Authenticate not implemented")
 }
+func (UnimplementedHandshakeServiceServer) Refresh(context.Context, *RefreshRequest) (*RefreshResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Refresh not implemented")
+}
+func (UnimplementedHandshakeServiceServer) Revoke(context.Context, *RevokeRequest) (*RevokeResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method Revoke not implemented")
+}
 func (UnimplementedHandshakeServiceServer) mustEmbedUnimplementedHandshakeServiceServer() {}
 func (UnimplementedHandshakeServiceServer) testEmbeddedByValue()                          {}
 
@@ -138,6 +176,42 @@ func _HandshakeService_Authenticate_Handler(srv interface{}, ctx context.Context
 	return interceptor(ctx, in, info, handler)
 }
 
+func _HandshakeService_Refresh_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RefreshRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandshakeServiceServer).Refresh(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HandshakeService_Refresh_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandshakeServiceServer).Refresh(ctx, req.(*RefreshRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _HandshakeService_Revoke_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(RevokeRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(HandshakeServiceServer).Revoke(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: HandshakeService_Revoke_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(HandshakeServiceServer).Revoke(ctx, req.(*RevokeRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // HandshakeService_ServiceDesc is the grpc.ServiceDesc for HandshakeService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -153,14 +227,24 @@ var HandshakeService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "Authenticate",
 			Handler:    _HandshakeService_Authenticate_Handler,
 		},
+		{
+			MethodName: "Refresh",
+			Handler:    _HandshakeService_Refresh_Handler,
+		},
+		{
+			MethodName: "Revoke",
+			Handler:    _HandshakeService_Revoke_Handler,
+		},
 	},
 	Streams:  []grpc.StreamDesc{},
 	Metadata: "mcp/v1/mcp.proto",
 }
 
 const (
-	AgentService_Chat_FullMethodName       = "/mcp.v1.AgentService/Chat"
-	AgentService_SingleChat_FullMethodName = "/mcp.v1.AgentService/SingleChat"
+	AgentService_Chat_FullMethodName           = "/mcp.v1.AgentService/Chat"
+	AgentService_SingleChat_FullMethodName     = "/mcp.v1.AgentService/SingleChat"
+	AgentService_GenerateStream_FullMethodName = "/mcp.v1.AgentService/GenerateStream"
+	AgentService_GetUsage_FullMethodName       = "/mcp.v1.AgentService/GetUsage"
 )
 
 // AgentServiceClient is the client API for AgentService service.
@@ -169,6 +253,14 @@ const (
 type AgentServiceClient interface {
 	Chat(ctx context.Context, opts ...grpc.CallOption) (grpc.BidiStreamingClient[ChatMessage, ChatMessage], error)
 	SingleChat(ctx context.Context, in *SingleChatRequest, opts ...grpc.CallOption) (*SingleChatResponse, error)
+	// Server-streaming variant of SingleChat for clients that cannot open a
+	// bidirectional stream (gRPC-Web): partial ChatMessages, the last one done.
+	// Like SingleChat, every request stands alone: the conversation history of
+	// Chat is neither sent with it nor extended by it.
+	GenerateStream(ctx context.Context, in *SingleChatRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatMessage], error)
+	// Usage of the caller's tenant in the current quota window, with the
+	// budget that remains
+	GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error)
 }
 
 type agentServiceClient struct {
@@ -202,12 +294,49 @@ func (c *agentServiceClient) SingleChat(ctx context.Context, in *SingleChatReque
 	return out, nil
 }
 
+func (c *agentServiceClient) GenerateStream(ctx context.Context, in *SingleChatRequest, opts ...grpc.CallOption) (grpc.ServerStreamingClient[ChatMessage], error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	stream, err := c.cc.NewStream(ctx, &AgentService_ServiceDesc.Streams[1], AgentService_GenerateStream_FullMethodName, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	x := &grpc.GenericClientStream[SingleChatRequest, ChatMessage]{ClientStream: stream}
+	if err := x.ClientStream.SendMsg(in); err != nil {
+		return nil, err
+	}
+	if err := x.ClientStream.CloseSend(); err != nil {
+		return nil, err
+	}
+	return x, nil
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_GenerateStreamClient = grpc.ServerStreamingClient[ChatMessage]
+
+func (c *agentServiceClient) GetUsage(ctx context.Context, in *GetUsageRequest, opts ...grpc.CallOption) (*GetUsageResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetUsageResponse)
+	err := c.cc.Invoke(ctx, AgentService_GetUsage_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 // AgentServiceServer is the server API for AgentService service.
 // All implementations must embed UnimplementedAgentServiceServer
 // for forward compatibility.
 type AgentServiceServer interface {
 	Chat(grpc.BidiStreamingServer[ChatMessage, ChatMessage]) error
 	SingleChat(context.Context, *SingleChatRequest) (*SingleChatResponse, error)
+	// Server-streaming variant of SingleChat for clients that cannot open a
+	// bidirectional stream (gRPC-Web): partial ChatMessages, the last one done.
+	// Like SingleChat, every request stands alone: the conversation history of
+	// Chat is neither sent with it nor extended by it.
+	GenerateStream(*SingleChatRequest, grpc.ServerStreamingServer[ChatMessage]) error
+	// Usage of the caller's tenant in the current quota window, with the
+	// budget that remains
+	GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error)
 	mustEmbedUnimplementedAgentServiceServer()
 }
 
@@ -224,6 +353,12 @@ func (UnimplementedAgentServiceServer) Chat(grpc.BidiStreamingServer[ChatMessage
 func (UnimplementedAgentServiceServer) SingleChat(context.Context, *SingleChatRequest) (*SingleChatResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method SingleChat not implemented")
 }
+func (UnimplementedAgentServiceServer) GenerateStream(*SingleChatRequest, grpc.ServerStreamingServer[ChatMessage]) error {
+	return status.Errorf(codes.Unimplemented, "method GenerateStream not implemented")
+}
+func (UnimplementedAgentServiceServer) GetUsage(context.Context, *GetUsageRequest) (*GetUsageResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetUsage not implemented")
+}
 func (UnimplementedAgentServiceServer) mustEmbedUnimplementedAgentServiceServer() {}
 func (UnimplementedAgentServiceServer) testEmbeddedByValue()                      {}
 
@@ -270,6 +405,35 @@ func _AgentService_SingleChat_Handler(srv interface{}, ctx context.Context, dec
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AgentService_GenerateStream_Handler(srv interface{}, stream grpc.ServerStream) error {
+	m := new(SingleChatRequest)
+	if err := stream.RecvMsg(m); err != nil {
+		return err
+	}
+	return srv.(AgentServiceServer).GenerateStream(m, &grpc.GenericServerStream[SingleChatRequest, ChatMessage]{ServerStream: stream})
+}
+
+// This type alias is provided for backwards compatibility with existing code that references the prior non-generic stream type by name.
+type AgentService_GenerateStreamServer = grpc.ServerStreamingServer[ChatMessage]
+
+func _AgentService_GetUsage_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetUsageRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AgentServiceServer).GetUsage(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AgentService_GetUsage_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AgentServiceServer).GetUsage(ctx, req.(*GetUsageRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 // AgentService_ServiceDesc is the grpc.ServiceDesc for AgentService service.
 // It's only intended for direct use with grpc.RegisterService,
 // and not to be introspected or modified (even as a copy)
@@ -281,6 +445,10 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "SingleChat",
 			Handler:    _AgentService_SingleChat_Handler,
 		},
+		{
+			MethodName: "GetUsage",
+			Handler:    _AgentService_GetUsage_Handler,
+		},
 	},
 	Streams: []grpc.StreamDesc{
 		{
@@ -289,6 +457,11 @@ var AgentService_ServiceDesc = grpc.ServiceDesc{
 			ServerStreams: true,
 			ClientStreams: true,
 		},
+		{
+			StreamName:    "GenerateStream",
+			Handler:       _AgentService_GenerateStream_Handler,
+			ServerStreams: true,
+		},
 	},
 	Metadata: "mcp/v1/mcp.proto",
 }
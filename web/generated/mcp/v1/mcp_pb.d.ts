@@ -1,5 +1,6 @@
 import * as jspb from 'google-protobuf'
 
+import * as google_protobuf_duration_pb from 'google-protobuf/google/protobuf/duration_pb'; // proto import: "google/protobuf/duration.proto"
 import * as google_protobuf_timestamp_pb from 'google-protobuf/google/protobuf/timestamp_pb'; // proto import: "google/protobuf/timestamp.proto"
 
 
@@ -13,6 +14,11 @@ export class RegisterRequest extends jspb.Message {
   getModel(): string;
   setModel(value: string): RegisterRequest;
 
+  getOptions(): GenerationOptions | undefined;
+  setOptions(value?: GenerationOptions): RegisterRequest;
+  hasOptions(): boolean;
+  clearOptions(): RegisterRequest;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): RegisterRequest.AsObject;
   static toObject(includeInstance: boolean, msg: RegisterRequest): RegisterRequest.AsObject;
@@ -26,6 +32,7 @@ export namespace RegisterRequest {
     tenantId: string,
     agentId: string,
     model: string,
+    options?: GenerationOptions.AsObject,
   }
 }
 
@@ -41,6 +48,14 @@ export class RegisterResponse extends jspb.Message {
   hasExpiresAt(): boolean;
   clearExpiresAt(): RegisterResponse;
 
+  getRefreshToken(): string;
+  setRefreshToken(value: string): RegisterResponse;
+
+  getRefreshExpiresAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+  setRefreshExpiresAt(value?: google_protobuf_timestamp_pb.Timestamp): RegisterResponse;
+  hasRefreshExpiresAt(): boolean;
+  clearRefreshExpiresAt(): RegisterResponse;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): RegisterResponse.AsObject;
   static toObject(includeInstance: boolean, msg: RegisterResponse): RegisterResponse.AsObject;
@@ -54,6 +69,8 @@ export namespace RegisterResponse {
     sessionId: string,
     jwtToken: string,
     expiresAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    refreshToken: string,
+    refreshExpiresAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
   }
 }
 
@@ -101,6 +118,102 @@ export namespace AuthResponse {
   }
 }
 
+export class RefreshRequest extends jspb.Message {
+  getRefreshToken(): string;
+  setRefreshToken(value: string): RefreshRequest;
+
+  getJwtToken(): string;
+  setJwtToken(value: string): RefreshRequest;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): RefreshRequest.AsObject;
+  static toObject(includeInstance: boolean, msg: RefreshRequest): RefreshRequest.AsObject;
+  static serializeBinaryToWriter(message: RefreshRequest, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): RefreshRequest;
+  static deserializeBinaryFromReader(message: RefreshRequest, reader: jspb.BinaryReader): RefreshRequest;
+}
+
+export namespace RefreshRequest {
+  export type AsObject = {
+    refreshToken: string,
+    jwtToken: string,
+  }
+}
+
+export class RefreshResponse extends jspb.Message {
+  getSessionId(): string;
+  setSessionId(value: string): RefreshResponse;
+
+  getJwtToken(): string;
+  setJwtToken(value: string): RefreshResponse;
+
+  getExpiresAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+  setExpiresAt(value?: google_protobuf_timestamp_pb.Timestamp): RefreshResponse;
+  hasExpiresAt(): boolean;
+  clearExpiresAt(): RefreshResponse;
+
+  getRefreshToken(): string;
+  setRefreshToken(value: string): RefreshResponse;
+
+  getRefreshExpiresAt(): google_protobuf_timestamp_pb.Timestamp | undefined;
+  setRefreshExpiresAt(value?: google_protobuf_timestamp_pb.Timestamp): RefreshResponse;
+  hasRefreshExpiresAt(): boolean;
+  clearRefreshExpiresAt(): RefreshResponse;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): RefreshResponse.AsObject;
+  static toObject(includeInstance: boolean, msg: RefreshResponse): RefreshResponse.AsObject;
+  static serializeBinaryToWriter(message: RefreshResponse, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): RefreshResponse;
+  static deserializeBinaryFromReader(message: RefreshResponse, reader: jspb.BinaryReader): RefreshResponse;
+}
+
+export namespace RefreshResponse {
+  export type AsObject = {
+    sessionId: string,
+    jwtToken: string,
+    expiresAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    refreshToken: string,
+    refreshExpiresAt?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+  }
+}
+
+export class RevokeRequest extends jspb.Message {
+  getJwtToken(): string;
+  setJwtToken(value: string): RevokeRequest;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): RevokeRequest.AsObject;
+  static toObject(includeInstance: boolean, msg: RevokeRequest): RevokeRequest.AsObject;
+  static serializeBinaryToWriter(message: RevokeRequest, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): RevokeRequest;
+  static deserializeBinaryFromReader(message: RevokeRequest, reader: jspb.BinaryReader): RevokeRequest;
+}
+
+export namespace RevokeRequest {
+  export type AsObject = {
+    jwtToken: string,
+  }
+}
+
+export class RevokeResponse extends jspb.Message {
+  getSessionId(): string;
+  setSessionId(value: string): RevokeResponse;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): RevokeResponse.AsObject;
+  static toObject(includeInstance: boolean, msg: RevokeResponse): RevokeResponse.AsObject;
+  static serializeBinaryToWriter(message: RevokeResponse, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): RevokeResponse;
+  static deserializeBinaryFromReader(message: RevokeResponse, reader: jspb.BinaryReader): RevokeResponse;
+}
+
+export namespace RevokeResponse {
+  export type AsObject = {
+    sessionId: string,
+  }
+}
+
 export class ChatMessage extends jspb.Message {
   getMessageId(): string;
   setMessageId(value: string): ChatMessage;
@@ -119,6 +232,44 @@ export class ChatMessage extends jspb.Message {
   hasTimestamp(): boolean;
   clearTimestamp(): ChatMessage;
 
+  getDone(): boolean;
+  setDone(value: boolean): ChatMessage;
+
+  getOptions(): GenerationOptions | undefined;
+  setOptions(value?: GenerationOptions): ChatMessage;
+  hasOptions(): boolean;
+  clearOptions(): ChatMessage;
+
+  getUsage(): Usage | undefined;
+  setUsage(value?: Usage): ChatMessage;
+  hasUsage(): boolean;
+  clearUsage(): ChatMessage;
+
+  getAttachmentsList(): Array<Attachment>;
+  setAttachmentsList(value: Array<Attachment>): ChatMessage;
+  clearAttachmentsList(): ChatMessage;
+  addAttachments(value?: Attachment, index?: number): Attachment;
+
+  getResponseFormat(): ResponseFormat | undefined;
+  setResponseFormat(value?: ResponseFormat): ChatMessage;
+  hasResponseFormat(): boolean;
+  clearResponseFormat(): ChatMessage;
+
+  getToolCall(): ToolCall | undefined;
+  setToolCall(value?: ToolCall): ChatMessage;
+  hasToolCall(): boolean;
+  clearToolCall(): ChatMessage;
+
+  getToolResult(): ToolResult | undefined;
+  setToolResult(value?: ToolResult): ChatMessage;
+  hasToolResult(): boolean;
+  clearToolResult(): ChatMessage;
+
+  getToolsList(): Array<ToolDefinition>;
+  setToolsList(value: Array<ToolDefinition>): ChatMessage;
+  clearToolsList(): ChatMessage;
+  addTools(value?: ToolDefinition, index?: number): ToolDefinition;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): ChatMessage.AsObject;
   static toObject(includeInstance: boolean, msg: ChatMessage): ChatMessage.AsObject;
@@ -134,6 +285,96 @@ export namespace ChatMessage {
     content: string,
     type: MessageType,
     timestamp?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    done: boolean,
+    options?: GenerationOptions.AsObject,
+    usage?: Usage.AsObject,
+    attachmentsList: Array<Attachment.AsObject>,
+    responseFormat?: ResponseFormat.AsObject,
+    toolCall?: ToolCall.AsObject,
+    toolResult?: ToolResult.AsObject,
+    toolsList: Array<ToolDefinition.AsObject>,
+  }
+}
+
+export class ToolDefinition extends jspb.Message {
+  getName(): string;
+  setName(value: string): ToolDefinition;
+
+  getDescription(): string;
+  setDescription(value: string): ToolDefinition;
+
+  getParameters(): string;
+  setParameters(value: string): ToolDefinition;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): ToolDefinition.AsObject;
+  static toObject(includeInstance: boolean, msg: ToolDefinition): ToolDefinition.AsObject;
+  static serializeBinaryToWriter(message: ToolDefinition, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): ToolDefinition;
+  static deserializeBinaryFromReader(message: ToolDefinition, reader: jspb.BinaryReader): ToolDefinition;
+}
+
+export namespace ToolDefinition {
+  export type AsObject = {
+    name: string,
+    description: string,
+    parameters: string,
+  }
+}
+
+export class ToolCall extends jspb.Message {
+  getId(): string;
+  setId(value: string): ToolCall;
+
+  getName(): string;
+  setName(value: string): ToolCall;
+
+  getArguments(): string;
+  setArguments(value: string): ToolCall;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): ToolCall.AsObject;
+  static toObject(includeInstance: boolean, msg: ToolCall): ToolCall.AsObject;
+  static serializeBinaryToWriter(message: ToolCall, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): ToolCall;
+  static deserializeBinaryFromReader(message: ToolCall, reader: jspb.BinaryReader): ToolCall;
+}
+
+export namespace ToolCall {
+  export type AsObject = {
+    id: string,
+    name: string,
+    arguments: string,
+  }
+}
+
+export class ToolResult extends jspb.Message {
+  getId(): string;
+  setId(value: string): ToolResult;
+
+  getName(): string;
+  setName(value: string): ToolResult;
+
+  getContent(): string;
+  setContent(value: string): ToolResult;
+
+  getIsError(): boolean;
+  setIsError(value: boolean): ToolResult;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): ToolResult.AsObject;
+  static toObject(includeInstance: boolean, msg: ToolResult): ToolResult.AsObject;
+  static serializeBinaryToWriter(message: ToolResult, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): ToolResult;
+  static deserializeBinaryFromReader(message: ToolResult, reader: jspb.BinaryReader): ToolResult;
+}
+
+export namespace ToolResult {
+  export type AsObject = {
+    id: string,
+    name: string,
+    content: string,
+    isError: boolean,
   }
 }
 
@@ -147,6 +388,21 @@ export class SingleChatRequest extends jspb.Message {
   getModel(): string;
   setModel(value: string): SingleChatRequest;
 
+  getOptions(): GenerationOptions | undefined;
+  setOptions(value?: GenerationOptions): SingleChatRequest;
+  hasOptions(): boolean;
+  clearOptions(): SingleChatRequest;
+
+  getAttachmentsList(): Array<Attachment>;
+  setAttachmentsList(value: Array<Attachment>): SingleChatRequest;
+  clearAttachmentsList(): SingleChatRequest;
+  addAttachments(value?: Attachment, index?: number): Attachment;
+
+  getResponseFormat(): ResponseFormat | undefined;
+  setResponseFormat(value?: ResponseFormat): SingleChatRequest;
+  hasResponseFormat(): boolean;
+  clearResponseFormat(): SingleChatRequest;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): SingleChatRequest.AsObject;
   static toObject(includeInstance: boolean, msg: SingleChatRequest): SingleChatRequest.AsObject;
@@ -160,6 +416,141 @@ export namespace SingleChatRequest {
     sessionId: string,
     content: string,
     model: string,
+    options?: GenerationOptions.AsObject,
+    attachmentsList: Array<Attachment.AsObject>,
+    responseFormat?: ResponseFormat.AsObject,
+  }
+}
+
+export class ResponseFormat extends jspb.Message {
+  getJsonSchema(): string;
+  setJsonSchema(value: string): ResponseFormat;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): ResponseFormat.AsObject;
+  static toObject(includeInstance: boolean, msg: ResponseFormat): ResponseFormat.AsObject;
+  static serializeBinaryToWriter(message: ResponseFormat, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): ResponseFormat;
+  static deserializeBinaryFromReader(message: ResponseFormat, reader: jspb.BinaryReader): ResponseFormat;
+}
+
+export namespace ResponseFormat {
+  export type AsObject = {
+    jsonSchema: string,
+  }
+}
+
+export class Attachment extends jspb.Message {
+  getData(): Uint8Array | string;
+  getData_asU8(): Uint8Array;
+  getData_asB64(): string;
+  setData(value: Uint8Array | string): Attachment;
+
+  getMimeType(): string;
+  setMimeType(value: string): Attachment;
+
+  getName(): string;
+  setName(value: string): Attachment;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): Attachment.AsObject;
+  static toObject(includeInstance: boolean, msg: Attachment): Attachment.AsObject;
+  static serializeBinaryToWriter(message: Attachment, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): Attachment;
+  static deserializeBinaryFromReader(message: Attachment, reader: jspb.BinaryReader): Attachment;
+}
+
+export namespace Attachment {
+  export type AsObject = {
+    data: Uint8Array | string,
+    mimeType: string,
+    name: string,
+  }
+}
+
+export class GenerationOptions extends jspb.Message {
+  getTemperature(): number;
+  setTemperature(value: number): GenerationOptions;
+  hasTemperature(): boolean;
+  clearTemperature(): GenerationOptions;
+
+  getTopP(): number;
+  setTopP(value: number): GenerationOptions;
+  hasTopP(): boolean;
+  clearTopP(): GenerationOptions;
+
+  getTopK(): number;
+  setTopK(value: number): GenerationOptions;
+  hasTopK(): boolean;
+  clearTopK(): GenerationOptions;
+
+  getNumCtx(): number;
+  setNumCtx(value: number): GenerationOptions;
+  hasNumCtx(): boolean;
+  clearNumCtx(): GenerationOptions;
+
+  getSeed(): number;
+  setSeed(value: number): GenerationOptions;
+  hasSeed(): boolean;
+  clearSeed(): GenerationOptions;
+
+  getStopList(): Array<string>;
+  setStopList(value: Array<string>): GenerationOptions;
+  clearStopList(): GenerationOptions;
+  addStop(value: string, index?: number): GenerationOptions;
+
+  getMaxTokens(): number;
+  setMaxTokens(value: number): GenerationOptions;
+  hasMaxTokens(): boolean;
+  clearMaxTokens(): GenerationOptions;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): GenerationOptions.AsObject;
+  static toObject(includeInstance: boolean, msg: GenerationOptions): GenerationOptions.AsObject;
+  static serializeBinaryToWriter(message: GenerationOptions, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): GenerationOptions;
+  static deserializeBinaryFromReader(message: GenerationOptions, reader: jspb.BinaryReader): GenerationOptions;
+}
+
+export namespace GenerationOptions {
+  export type AsObject = {
+    temperature?: number,
+    topP?: number,
+    topK?: number,
+    numCtx?: number,
+    seed?: number,
+    stopList: Array<string>,
+    maxTokens?: number,
+  }
+
+  export enum TemperatureCase { 
+    _TEMPERATURE_NOT_SET = 0,
+    TEMPERATURE = 1,
+  }
+
+  export enum TopPCase { 
+    _TOP_P_NOT_SET = 0,
+    TOP_P = 2,
+  }
+
+  export enum TopKCase { 
+    _TOP_K_NOT_SET = 0,
+    TOP_K = 3,
+  }
+
+  export enum NumCtxCase { 
+    _NUM_CTX_NOT_SET = 0,
+    NUM_CTX = 4,
+  }
+
+  export enum SeedCase { 
+    _SEED_NOT_SET = 0,
+    SEED = 5,
+  }
+
+  export enum MaxTokensCase { 
+    _MAX_TOKENS_NOT_SET = 0,
+    MAX_TOKENS = 7,
   }
 }
 
@@ -172,6 +563,11 @@ export class SingleChatResponse extends jspb.Message {
   hasTimestamp(): boolean;
   clearTimestamp(): SingleChatResponse;
 
+  getUsage(): Usage | undefined;
+  setUsage(value?: Usage): SingleChatResponse;
+  hasUsage(): boolean;
+  clearUsage(): SingleChatResponse;
+
   serializeBinary(): Uint8Array;
   toObject(includeInstance?: boolean): SingleChatResponse.AsObject;
   static toObject(includeInstance: boolean, msg: SingleChatResponse): SingleChatResponse.AsObject;
@@ -184,6 +580,161 @@ export namespace SingleChatResponse {
   export type AsObject = {
     content: string,
     timestamp?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    usage?: Usage.AsObject,
+  }
+}
+
+export class Usage extends jspb.Message {
+  getModel(): string;
+  setModel(value: string): Usage;
+
+  getPromptTokens(): number;
+  setPromptTokens(value: number): Usage;
+
+  getCompletionTokens(): number;
+  setCompletionTokens(value: number): Usage;
+
+  getTotalDuration(): google_protobuf_duration_pb.Duration | undefined;
+  setTotalDuration(value?: google_protobuf_duration_pb.Duration): Usage;
+  hasTotalDuration(): boolean;
+  clearTotalDuration(): Usage;
+
+  getLoadDuration(): google_protobuf_duration_pb.Duration | undefined;
+  setLoadDuration(value?: google_protobuf_duration_pb.Duration): Usage;
+  hasLoadDuration(): boolean;
+  clearLoadDuration(): Usage;
+
+  getPromptEvalDuration(): google_protobuf_duration_pb.Duration | undefined;
+  setPromptEvalDuration(value?: google_protobuf_duration_pb.Duration): Usage;
+  hasPromptEvalDuration(): boolean;
+  clearPromptEvalDuration(): Usage;
+
+  getEvalDuration(): google_protobuf_duration_pb.Duration | undefined;
+  setEvalDuration(value?: google_protobuf_duration_pb.Duration): Usage;
+  hasEvalDuration(): boolean;
+  clearEvalDuration(): Usage;
+
+  getTokensPerSecond(): number;
+  setTokensPerSecond(value: number): Usage;
+
+  getDoneReason(): string;
+  setDoneReason(value: string): Usage;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): Usage.AsObject;
+  static toObject(includeInstance: boolean, msg: Usage): Usage.AsObject;
+  static serializeBinaryToWriter(message: Usage, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): Usage;
+  static deserializeBinaryFromReader(message: Usage, reader: jspb.BinaryReader): Usage;
+}
+
+export namespace Usage {
+  export type AsObject = {
+    model: string,
+    promptTokens: number,
+    completionTokens: number,
+    totalDuration?: google_protobuf_duration_pb.Duration.AsObject,
+    loadDuration?: google_protobuf_duration_pb.Duration.AsObject,
+    promptEvalDuration?: google_protobuf_duration_pb.Duration.AsObject,
+    evalDuration?: google_protobuf_duration_pb.Duration.AsObject,
+    tokensPerSecond: number,
+    doneReason: string,
+  }
+}
+
+export class GetUsageRequest extends jspb.Message {
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): GetUsageRequest.AsObject;
+  static toObject(includeInstance: boolean, msg: GetUsageRequest): GetUsageRequest.AsObject;
+  static serializeBinaryToWriter(message: GetUsageRequest, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): GetUsageRequest;
+  static deserializeBinaryFromReader(message: GetUsageRequest, reader: jspb.BinaryReader): GetUsageRequest;
+}
+
+export namespace GetUsageRequest {
+  export type AsObject = {
+  }
+}
+
+export class GetUsageResponse extends jspb.Message {
+  getTenantId(): string;
+  setTenantId(value: string): GetUsageResponse;
+
+  getWindowStart(): google_protobuf_timestamp_pb.Timestamp | undefined;
+  setWindowStart(value?: google_protobuf_timestamp_pb.Timestamp): GetUsageResponse;
+  hasWindowStart(): boolean;
+  clearWindowStart(): GetUsageResponse;
+
+  getWindowEnd(): google_protobuf_timestamp_pb.Timestamp | undefined;
+  setWindowEnd(value?: google_protobuf_timestamp_pb.Timestamp): GetUsageResponse;
+  hasWindowEnd(): boolean;
+  clearWindowEnd(): GetUsageResponse;
+
+  getSessions(): Quota | undefined;
+  setSessions(value?: Quota): GetUsageResponse;
+  hasSessions(): boolean;
+  clearSessions(): GetUsageResponse;
+
+  getRequests(): Quota | undefined;
+  setRequests(value?: Quota): GetUsageResponse;
+  hasRequests(): boolean;
+  clearRequests(): GetUsageResponse;
+
+  getTokens(): Quota | undefined;
+  setTokens(value?: Quota): GetUsageResponse;
+  hasTokens(): boolean;
+  clearTokens(): GetUsageResponse;
+
+  getPromptTokens(): number;
+  setPromptTokens(value: number): GetUsageResponse;
+
+  getCompletionTokens(): number;
+  setCompletionTokens(value: number): GetUsageResponse;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): GetUsageResponse.AsObject;
+  static toObject(includeInstance: boolean, msg: GetUsageResponse): GetUsageResponse.AsObject;
+  static serializeBinaryToWriter(message: GetUsageResponse, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): GetUsageResponse;
+  static deserializeBinaryFromReader(message: GetUsageResponse, reader: jspb.BinaryReader): GetUsageResponse;
+}
+
+export namespace GetUsageResponse {
+  export type AsObject = {
+    tenantId: string,
+    windowStart?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    windowEnd?: google_protobuf_timestamp_pb.Timestamp.AsObject,
+    sessions?: Quota.AsObject,
+    requests?: Quota.AsObject,
+    tokens?: Quota.AsObject,
+    promptTokens: number,
+    completionTokens: number,
+  }
+}
+
+export class Quota extends jspb.Message {
+  getUsed(): number;
+  setUsed(value: number): Quota;
+
+  getLimit(): number;
+  setLimit(value: number): Quota;
+
+  getRemaining(): number;
+  setRemaining(value: number): Quota;
+
+  serializeBinary(): Uint8Array;
+  toObject(includeInstance?: boolean): Quota.AsObject;
+  static toObject(includeInstance: boolean, msg: Quota): Quota.AsObject;
+  static serializeBinaryToWriter(message: Quota, writer: jspb.BinaryWriter): void;
+  static deserializeBinary(bytes: Uint8Array): Quota;
+  static deserializeBinaryFromReader(message: Quota, reader: jspb.BinaryReader): Quota;
+}
+
+export namespace Quota {
+  export type AsObject = {
+    used: number,
+    limit: number,
+    remaining: number,
   }
 }
 
@@ -192,4 +743,6 @@ export enum MessageType {
   MESSAGE_TYPE_USER = 1,
   MESSAGE_TYPE_ASSISTANT = 2,
   MESSAGE_TYPE_SYSTEM = 3,
+  MESSAGE_TYPE_TOOL_CALL = 4,
+  MESSAGE_TYPE_TOOL_RESULT = 5,
 }
@@ -137,6 +137,106 @@ export class HandshakeServiceClient {
       this.methodDescriptorAuthenticate,
     );
   }
+
+  methodDescriptorRefresh = new grpcWeb.MethodDescriptor(
+    "/mcp.v1.HandshakeService/Refresh",
+    grpcWeb.MethodType.UNARY,
+    mcp_v1_mcp_pb.RefreshRequest,
+    mcp_v1_mcp_pb.RefreshResponse,
+    (request: mcp_v1_mcp_pb.RefreshRequest) => {
+      return request.serializeBinary();
+    },
+    mcp_v1_mcp_pb.RefreshResponse.deserializeBinary,
+  );
+
+  refresh(
+    request: mcp_v1_mcp_pb.RefreshRequest,
+    metadata?: grpcWeb.Metadata | null,
+  ): Promise<mcp_v1_mcp_pb.RefreshResponse>;
+
+  refresh(
+    request: mcp_v1_mcp_pb.RefreshRequest,
+    metadata: grpcWeb.Metadata | null,
+    callback: (
+      err: grpcWeb.RpcError,
+      response: mcp_v1_mcp_pb.RefreshResponse,
+    ) => void,
+  ): grpcWeb.ClientReadableStream<mcp_v1_mcp_pb.RefreshResponse>;
+
+  refresh(
+    request: mcp_v1_mcp_pb.RefreshRequest,
+    metadata?: grpcWeb.Metadata | null,
+    callback?: (
+      err: grpcWeb.RpcError,
+      response: mcp_v1_mcp_pb.RefreshResponse,
+    ) => void,
+  ) {
+    if (callback !== undefined) {
+      return this.client_.rpcCall(
+        this.hostname_ + "/mcp.v1.HandshakeService/Refresh",
+        request,
+        metadata || {},
+        this.methodDescriptorRefresh,
+        callback,
+      );
+    }
+    return this.client_.unaryCall(
+      this.hostname_ + "/mcp.v1.HandshakeService/Refresh",
+      request,
+      metadata || {},
+      this.methodDescriptorRefresh,
+    );
+  }
+
+  methodDescriptorRevoke = new grpcWeb.MethodDescriptor(
+    "/mcp.v1.HandshakeService/Revoke",
+    grpcWeb.MethodType.UNARY,
+    mcp_v1_mcp_pb.RevokeRequest,
+    mcp_v1_mcp_pb.RevokeResponse,
+    (request: mcp_v1_mcp_pb.RevokeRequest) => {
+      return request.serializeBinary();
+    },
+    mcp_v1_mcp_pb.RevokeResponse.deserializeBinary,
+  );
+
+  revoke(
+    request: mcp_v1_mcp_pb.RevokeRequest,
+    metadata?: grpcWeb.Metadata | null,
+  ): Promise<mcp_v1_mcp_pb.RevokeResponse>;
+
+  revoke(
+    request: mcp_v1_mcp_pb.RevokeRequest,
+    metadata: grpcWeb.Metadata | null,
+    callback: (
+      err: grpcWeb.RpcError,
+      response: mcp_v1_mcp_pb.RevokeResponse,
+    ) => void,
+  ): grpcWeb.ClientReadableStream<mcp_v1_mcp_pb.RevokeResponse>;
+
+  revoke(
+    request: mcp_v1_mcp_pb.RevokeRequest,
+    metadata?: grpcWeb.Metadata | null,
+    callback?: (
+      err: grpcWeb.RpcError,
+      response: mcp_v1_mcp_pb.RevokeResponse,
+    ) => void,
+  ) {
+    if (callback !== undefined) {
+      return this.client_.rpcCall(
+        this.hostname_ + "/mcp.v1.HandshakeService/Revoke",
+        request,
+        metadata || {},
+        this.methodDescriptorRevoke,
+        callback,
+      );
+    }
+    return this.client_.unaryCall(
+      this.hostname_ + "/mcp.v1.HandshakeService/Revoke",
+      request,
+      metadata || {},
+      this.methodDescriptorRevoke,
+    );
+  }
 }
 
 export class AgentServiceClient {
@@ -209,4 +309,77 @@ export class AgentServiceClient {
       this.methodDescriptorSingleChat,
     );
   }
+
+  methodDescriptorGenerateStream = new grpcWeb.MethodDescriptor(
+    "/mcp.v1.AgentService/GenerateStream",
+    grpcWeb.MethodType.SERVER_STREAMING,
+    mcp_v1_mcp_pb.SingleChatRequest,
+    mcp_v1_mcp_pb.ChatMessage,
+    (request: mcp_v1_mcp_pb.SingleChatRequest) => {
+      return request.serializeBinary();
+    },
+    mcp_v1_mcp_pb.ChatMessage.deserializeBinary,
+  );
+
+  generateStream(
+    request: mcp_v1_mcp_pb.SingleChatRequest,
+    metadata?: grpcWeb.Metadata,
+  ): grpcWeb.ClientReadableStream<mcp_v1_mcp_pb.ChatMessage> {
+    return this.client_.serverStreaming(
+      this.hostname_ + "/mcp.v1.AgentService/GenerateStream",
+      request,
+      metadata || {},
+      this.methodDescriptorGenerateStream,
+    );
+  }
+
+  methodDescriptorGetUsage = new grpcWeb.MethodDescriptor(
+    "/mcp.v1.AgentService/GetUsage",
+    grpcWeb.MethodType.UNARY,
+    mcp_v1_mcp_pb.GetUsageRequest,
+    mcp_v1_mcp_pb.GetUsageResponse,
+    (request: mcp_v1_mcp_pb.GetUsageRequest) => {
+      return request.serializeBinary();
+    },
+    mcp_v1_mcp_pb.GetUsageResponse.deserializeBinary,
+  );
+
+  getUsage(
+    request: mcp_v1_mcp_pb.GetUsageRequest,
+    metadata?: grpcWeb.Metadata | null,
+  ): Promise<mcp_v1_mcp_pb.GetUsageResponse>;
+
+  getUsage(
+    request: mcp_v1_mcp_pb.GetUsageRequest,
+    metadata: grpcWeb.Metadata | null,
+    callback: (
+      err: grpcWeb.RpcError,
+      response: mcp_v1_mcp_pb.GetUsageResponse,
+    ) => void,
+  ): grpcWeb.ClientReadableStream<mcp_v1_mcp_pb.GetUsageResponse>;
+
+  getUsage(
+    request: mcp_v1_mcp_pb.GetUsageRequest,
+    metadata?: grpcWeb.Metadata | null,
+    callback?: (
+      err: grpcWeb.RpcError,
+      response: mcp_v1_mcp_pb.GetUsageResponse,
+    ) => void,
+  ) {
+    if (callback !== undefined) {
+      return this.client_.rpcCall(
+        this.hostname_ + "/mcp.v1.AgentService/GetUsage",
+        request,
+        metadata || {},
+        this.methodDescriptorGetUsage,
+        callback,
+      );
+    }
+    return this.client_.unaryCall(
+      this.hostname_ + "/mcp.v1.AgentService/GetUsage",
+      request,
+      metadata || {},
+      this.methodDescriptorGetUsage,
+    );
+  }
 }
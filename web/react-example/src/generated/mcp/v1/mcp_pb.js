@@ -21,16 +21,32 @@ var global =
     (function () { return this; }).call(null) ||
     Function('return this')();
 
+var google_protobuf_duration_pb = require('google-protobuf/google/protobuf/duration_pb.js');
+goog.object.extend(proto, google_protobuf_duration_pb);
 var google_protobuf_timestamp_pb = require('google-protobuf/google/protobuf/timestamp_pb.js');
 goog.object.extend(proto, google_protobuf_timestamp_pb);
+goog.exportSymbol('proto.mcp.v1.Attachment', null, global);
 goog.exportSymbol('proto.mcp.v1.AuthRequest', null, global);
 goog.exportSymbol('proto.mcp.v1.AuthResponse', null, global);
 goog.exportSymbol('proto.mcp.v1.ChatMessage', null, global);
+goog.exportSymbol('proto.mcp.v1.GenerationOptions', null, global);
+goog.exportSymbol('proto.mcp.v1.GetUsageRequest', null, global);
+goog.exportSymbol('proto.mcp.v1.GetUsageResponse', null, global);
 goog.exportSymbol('proto.mcp.v1.MessageType', null, global);
+goog.exportSymbol('proto.mcp.v1.Quota', null, global);
+goog.exportSymbol('proto.mcp.v1.RefreshRequest', null, global);
+goog.exportSymbol('proto.mcp.v1.RefreshResponse', null, global);
 goog.exportSymbol('proto.mcp.v1.RegisterRequest', null, global);
 goog.exportSymbol('proto.mcp.v1.RegisterResponse', null, global);
+goog.exportSymbol('proto.mcp.v1.ResponseFormat', null, global);
+goog.exportSymbol('proto.mcp.v1.RevokeRequest', null, global);
+goog.exportSymbol('proto.mcp.v1.RevokeResponse', null, global);
 goog.exportSymbol('proto.mcp.v1.SingleChatRequest', null, global);
 goog.exportSymbol('proto.mcp.v1.SingleChatResponse', null, global);
+goog.exportSymbol('proto.mcp.v1.ToolCall', null, global);
+goog.exportSymbol('proto.mcp.v1.ToolDefinition', null, global);
+goog.exportSymbol('proto.mcp.v1.ToolResult', null, global);
+goog.exportSymbol('proto.mcp.v1.Usage', null, global);
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -125,9 +141,93 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.mcp.v1.ChatMessage = function(opt_data) {
+proto.mcp.v1.RefreshRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.RefreshRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.RefreshRequest.displayName = 'proto.mcp.v1.RefreshRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.RefreshResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.RefreshResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.RefreshResponse.displayName = 'proto.mcp.v1.RefreshResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.RevokeRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.RevokeRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.RevokeRequest.displayName = 'proto.mcp.v1.RevokeRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.RevokeResponse = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
+goog.inherits(proto.mcp.v1.RevokeResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.RevokeResponse.displayName = 'proto.mcp.v1.RevokeResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.ChatMessage = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.mcp.v1.ChatMessage.repeatedFields_, null);
+};
 goog.inherits(proto.mcp.v1.ChatMessage, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
@@ -146,9 +246,72 @@ if (goog.DEBUG && !COMPILED) {
  * @extends {jspb.Message}
  * @constructor
  */
-proto.mcp.v1.SingleChatRequest = function(opt_data) {
+proto.mcp.v1.ToolDefinition = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.ToolDefinition, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.ToolDefinition.displayName = 'proto.mcp.v1.ToolDefinition';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.ToolCall = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.ToolCall, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.ToolCall.displayName = 'proto.mcp.v1.ToolCall';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.ToolResult = function(opt_data) {
   jspb.Message.initialize(this, opt_data, 0, -1, null, null);
 };
+goog.inherits(proto.mcp.v1.ToolResult, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.ToolResult.displayName = 'proto.mcp.v1.ToolResult';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.SingleChatRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.mcp.v1.SingleChatRequest.repeatedFields_, null);
+};
 goog.inherits(proto.mcp.v1.SingleChatRequest, jspb.Message);
 if (goog.DEBUG && !COMPILED) {
   /**
@@ -157,6 +320,69 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.mcp.v1.SingleChatRequest.displayName = 'proto.mcp.v1.SingleChatRequest';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.ResponseFormat = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.ResponseFormat, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.ResponseFormat.displayName = 'proto.mcp.v1.ResponseFormat';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.Attachment = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.Attachment, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.Attachment.displayName = 'proto.mcp.v1.Attachment';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.GenerationOptions = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, proto.mcp.v1.GenerationOptions.repeatedFields_, null);
+};
+goog.inherits(proto.mcp.v1.GenerationOptions, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.GenerationOptions.displayName = 'proto.mcp.v1.GenerationOptions';
+}
 /**
  * Generated by JsPbCodeGenerator.
  * @param {Array=} opt_data Optional initial data array, typically from a
@@ -178,6 +404,90 @@ if (goog.DEBUG && !COMPILED) {
    */
   proto.mcp.v1.SingleChatResponse.displayName = 'proto.mcp.v1.SingleChatResponse';
 }
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.Usage = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.Usage, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.Usage.displayName = 'proto.mcp.v1.Usage';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.GetUsageRequest = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.GetUsageRequest, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.GetUsageRequest.displayName = 'proto.mcp.v1.GetUsageRequest';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.GetUsageResponse = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.GetUsageResponse, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.GetUsageResponse.displayName = 'proto.mcp.v1.GetUsageResponse';
+}
+/**
+ * Generated by JsPbCodeGenerator.
+ * @param {Array=} opt_data Optional initial data array, typically from a
+ * server response, or constructed directly in Javascript. The array is used
+ * in place and becomes part of the constructed object. It is not cloned.
+ * If no data is provided, the constructed object will be empty, but still
+ * valid.
+ * @extends {jspb.Message}
+ * @constructor
+ */
+proto.mcp.v1.Quota = function(opt_data) {
+  jspb.Message.initialize(this, opt_data, 0, -1, null, null);
+};
+goog.inherits(proto.mcp.v1.Quota, jspb.Message);
+if (goog.DEBUG && !COMPILED) {
+  /**
+   * @public
+   * @override
+   */
+  proto.mcp.v1.Quota.displayName = 'proto.mcp.v1.Quota';
+}
 
 
 
@@ -212,7 +522,8 @@ proto.mcp.v1.RegisterRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
 tenantId: jspb.Message.getFieldWithDefault(msg, 1, ""),
 agentId: jspb.Message.getFieldWithDefault(msg, 2, ""),
-model: jspb.Message.getFieldWithDefault(msg, 3, "")
+model: jspb.Message.getFieldWithDefault(msg, 3, ""),
+options: (f = msg.getOptions()) && proto.mcp.v1.GenerationOptions.toObject(includeInstance, f)
   };
 
   if (includeInstance) {
@@ -261,6 +572,11 @@ proto.mcp.v1.RegisterRequest.deserializeBinaryFromReader = function(msg, reader)
       var value = /** @type {string} */ (reader.readString());
       msg.setModel(value);
       break;
+    case 4:
+      var value = new proto.mcp.v1.GenerationOptions;
+      reader.readMessage(value,proto.mcp.v1.GenerationOptions.deserializeBinaryFromReader);
+      msg.setOptions(value);
+      break;
     default:
       reader.skipField();
       break;
@@ -308,63 +624,4073 @@ proto.mcp.v1.RegisterRequest.serializeBinaryToWriter = function(message, writer)
   if (f.length > 0) {
     writer.writeString(
       3,
-      f
+      f
+    );
+  }
+  f = message.getOptions();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.mcp.v1.GenerationOptions.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string tenant_id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.RegisterRequest.prototype.getTenantId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RegisterRequest} returns this
+ */
+proto.mcp.v1.RegisterRequest.prototype.setTenantId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string agent_id = 2;
+ * @return {string}
+ */
+proto.mcp.v1.RegisterRequest.prototype.getAgentId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RegisterRequest} returns this
+ */
+proto.mcp.v1.RegisterRequest.prototype.setAgentId = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string model = 3;
+ * @return {string}
+ */
+proto.mcp.v1.RegisterRequest.prototype.getModel = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RegisterRequest} returns this
+ */
+proto.mcp.v1.RegisterRequest.prototype.setModel = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional GenerationOptions options = 4;
+ * @return {?proto.mcp.v1.GenerationOptions}
+ */
+proto.mcp.v1.RegisterRequest.prototype.getOptions = function() {
+  return /** @type{?proto.mcp.v1.GenerationOptions} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.GenerationOptions, 4));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.GenerationOptions|undefined} value
+ * @return {!proto.mcp.v1.RegisterRequest} returns this
+*/
+proto.mcp.v1.RegisterRequest.prototype.setOptions = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.RegisterRequest} returns this
+ */
+proto.mcp.v1.RegisterRequest.prototype.clearOptions = function() {
+  return this.setOptions(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.RegisterRequest.prototype.hasOptions = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.RegisterResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.RegisterResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.RegisterResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RegisterResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+sessionId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+jwtToken: jspb.Message.getFieldWithDefault(msg, 2, ""),
+expiresAt: (f = msg.getExpiresAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+refreshToken: jspb.Message.getFieldWithDefault(msg, 4, ""),
+refreshExpiresAt: (f = msg.getRefreshExpiresAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.RegisterResponse}
+ */
+proto.mcp.v1.RegisterResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.RegisterResponse;
+  return proto.mcp.v1.RegisterResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.RegisterResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.RegisterResponse}
+ */
+proto.mcp.v1.RegisterResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSessionId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setJwtToken(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setExpiresAt(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRefreshToken(value);
+      break;
+    case 5:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setRefreshExpiresAt(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.RegisterResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.RegisterResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.RegisterResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RegisterResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSessionId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getJwtToken();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getExpiresAt();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getRefreshToken();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getRefreshExpiresAt();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string session_id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.RegisterResponse.prototype.getSessionId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+ */
+proto.mcp.v1.RegisterResponse.prototype.setSessionId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string jwt_token = 2;
+ * @return {string}
+ */
+proto.mcp.v1.RegisterResponse.prototype.getJwtToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+ */
+proto.mcp.v1.RegisterResponse.prototype.setJwtToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp expires_at = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.mcp.v1.RegisterResponse.prototype.getExpiresAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+*/
+proto.mcp.v1.RegisterResponse.prototype.setExpiresAt = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+ */
+proto.mcp.v1.RegisterResponse.prototype.clearExpiresAt = function() {
+  return this.setExpiresAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.RegisterResponse.prototype.hasExpiresAt = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional string refresh_token = 4;
+ * @return {string}
+ */
+proto.mcp.v1.RegisterResponse.prototype.getRefreshToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+ */
+proto.mcp.v1.RegisterResponse.prototype.setRefreshToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp refresh_expires_at = 5;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.mcp.v1.RegisterResponse.prototype.getRefreshExpiresAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 5));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+*/
+proto.mcp.v1.RegisterResponse.prototype.setRefreshExpiresAt = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.RegisterResponse} returns this
+ */
+proto.mcp.v1.RegisterResponse.prototype.clearRefreshExpiresAt = function() {
+  return this.setRefreshExpiresAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.RegisterResponse.prototype.hasRefreshExpiresAt = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.AuthRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.AuthRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.AuthRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.AuthRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+jwtToken: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.AuthRequest}
+ */
+proto.mcp.v1.AuthRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.AuthRequest;
+  return proto.mcp.v1.AuthRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.AuthRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.AuthRequest}
+ */
+proto.mcp.v1.AuthRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setJwtToken(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.AuthRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.AuthRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.AuthRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.AuthRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getJwtToken();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string jwt_token = 1;
+ * @return {string}
+ */
+proto.mcp.v1.AuthRequest.prototype.getJwtToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.AuthRequest} returns this
+ */
+proto.mcp.v1.AuthRequest.prototype.setJwtToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.AuthResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.AuthResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.AuthResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.AuthResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+valid: jspb.Message.getBooleanFieldWithDefault(msg, 1, false),
+tenantId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+agentId: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.AuthResponse}
+ */
+proto.mcp.v1.AuthResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.AuthResponse;
+  return proto.mcp.v1.AuthResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.AuthResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.AuthResponse}
+ */
+proto.mcp.v1.AuthResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setValid(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setTenantId(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setAgentId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.AuthResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.AuthResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.AuthResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.AuthResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getValid();
+  if (f) {
+    writer.writeBool(
+      1,
+      f
+    );
+  }
+  f = message.getTenantId();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getAgentId();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional bool valid = 1;
+ * @return {boolean}
+ */
+proto.mcp.v1.AuthResponse.prototype.getValid = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 1, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.mcp.v1.AuthResponse} returns this
+ */
+proto.mcp.v1.AuthResponse.prototype.setValid = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 1, value);
+};
+
+
+/**
+ * optional string tenant_id = 2;
+ * @return {string}
+ */
+proto.mcp.v1.AuthResponse.prototype.getTenantId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.AuthResponse} returns this
+ */
+proto.mcp.v1.AuthResponse.prototype.setTenantId = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string agent_id = 3;
+ * @return {string}
+ */
+proto.mcp.v1.AuthResponse.prototype.getAgentId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.AuthResponse} returns this
+ */
+proto.mcp.v1.AuthResponse.prototype.setAgentId = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.RefreshRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.RefreshRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.RefreshRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RefreshRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+refreshToken: jspb.Message.getFieldWithDefault(msg, 1, ""),
+jwtToken: jspb.Message.getFieldWithDefault(msg, 2, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.RefreshRequest}
+ */
+proto.mcp.v1.RefreshRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.RefreshRequest;
+  return proto.mcp.v1.RefreshRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.RefreshRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.RefreshRequest}
+ */
+proto.mcp.v1.RefreshRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRefreshToken(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setJwtToken(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.RefreshRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.RefreshRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.RefreshRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RefreshRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getRefreshToken();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getJwtToken();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string refresh_token = 1;
+ * @return {string}
+ */
+proto.mcp.v1.RefreshRequest.prototype.getRefreshToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RefreshRequest} returns this
+ */
+proto.mcp.v1.RefreshRequest.prototype.setRefreshToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string jwt_token = 2;
+ * @return {string}
+ */
+proto.mcp.v1.RefreshRequest.prototype.getJwtToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RefreshRequest} returns this
+ */
+proto.mcp.v1.RefreshRequest.prototype.setJwtToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.RefreshResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.RefreshResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.RefreshResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RefreshResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+sessionId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+jwtToken: jspb.Message.getFieldWithDefault(msg, 2, ""),
+expiresAt: (f = msg.getExpiresAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+refreshToken: jspb.Message.getFieldWithDefault(msg, 4, ""),
+refreshExpiresAt: (f = msg.getRefreshExpiresAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.RefreshResponse}
+ */
+proto.mcp.v1.RefreshResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.RefreshResponse;
+  return proto.mcp.v1.RefreshResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.RefreshResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.RefreshResponse}
+ */
+proto.mcp.v1.RefreshResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSessionId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setJwtToken(value);
+      break;
+    case 3:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setExpiresAt(value);
+      break;
+    case 4:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setRefreshToken(value);
+      break;
+    case 5:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setRefreshExpiresAt(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.RefreshResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.RefreshResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.RefreshResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RefreshResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSessionId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getJwtToken();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getExpiresAt();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getRefreshToken();
+  if (f.length > 0) {
+    writer.writeString(
+      4,
+      f
+    );
+  }
+  f = message.getRefreshExpiresAt();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string session_id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.RefreshResponse.prototype.getSessionId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+ */
+proto.mcp.v1.RefreshResponse.prototype.setSessionId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string jwt_token = 2;
+ * @return {string}
+ */
+proto.mcp.v1.RefreshResponse.prototype.getJwtToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+ */
+proto.mcp.v1.RefreshResponse.prototype.setJwtToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp expires_at = 3;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.mcp.v1.RefreshResponse.prototype.getExpiresAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+*/
+proto.mcp.v1.RefreshResponse.prototype.setExpiresAt = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+ */
+proto.mcp.v1.RefreshResponse.prototype.clearExpiresAt = function() {
+  return this.setExpiresAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.RefreshResponse.prototype.hasExpiresAt = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional string refresh_token = 4;
+ * @return {string}
+ */
+proto.mcp.v1.RefreshResponse.prototype.getRefreshToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 4, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+ */
+proto.mcp.v1.RefreshResponse.prototype.setRefreshToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 4, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp refresh_expires_at = 5;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.mcp.v1.RefreshResponse.prototype.getRefreshExpiresAt = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 5));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+*/
+proto.mcp.v1.RefreshResponse.prototype.setRefreshExpiresAt = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.RefreshResponse} returns this
+ */
+proto.mcp.v1.RefreshResponse.prototype.clearRefreshExpiresAt = function() {
+  return this.setRefreshExpiresAt(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.RefreshResponse.prototype.hasRefreshExpiresAt = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.RevokeRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.RevokeRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.RevokeRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RevokeRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+jwtToken: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.RevokeRequest}
+ */
+proto.mcp.v1.RevokeRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.RevokeRequest;
+  return proto.mcp.v1.RevokeRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.RevokeRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.RevokeRequest}
+ */
+proto.mcp.v1.RevokeRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setJwtToken(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.RevokeRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.RevokeRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.RevokeRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RevokeRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getJwtToken();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string jwt_token = 1;
+ * @return {string}
+ */
+proto.mcp.v1.RevokeRequest.prototype.getJwtToken = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RevokeRequest} returns this
+ */
+proto.mcp.v1.RevokeRequest.prototype.setJwtToken = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.RevokeResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.RevokeResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.RevokeResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RevokeResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+sessionId: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.RevokeResponse}
+ */
+proto.mcp.v1.RevokeResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.RevokeResponse;
+  return proto.mcp.v1.RevokeResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.RevokeResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.RevokeResponse}
+ */
+proto.mcp.v1.RevokeResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSessionId(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.RevokeResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.RevokeResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.RevokeResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.RevokeResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSessionId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string session_id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.RevokeResponse.prototype.getSessionId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.RevokeResponse} returns this
+ */
+proto.mcp.v1.RevokeResponse.prototype.setSessionId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.mcp.v1.ChatMessage.repeatedFields_ = [9,13];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.ChatMessage.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.ChatMessage.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.ChatMessage} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ChatMessage.toObject = function(includeInstance, msg) {
+  var f, obj = {
+messageId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+sessionId: jspb.Message.getFieldWithDefault(msg, 2, ""),
+content: jspb.Message.getFieldWithDefault(msg, 3, ""),
+type: jspb.Message.getFieldWithDefault(msg, 4, 0),
+timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+done: jspb.Message.getBooleanFieldWithDefault(msg, 6, false),
+options: (f = msg.getOptions()) && proto.mcp.v1.GenerationOptions.toObject(includeInstance, f),
+usage: (f = msg.getUsage()) && proto.mcp.v1.Usage.toObject(includeInstance, f),
+attachmentsList: jspb.Message.toObjectList(msg.getAttachmentsList(),
+    proto.mcp.v1.Attachment.toObject, includeInstance),
+responseFormat: (f = msg.getResponseFormat()) && proto.mcp.v1.ResponseFormat.toObject(includeInstance, f),
+toolCall: (f = msg.getToolCall()) && proto.mcp.v1.ToolCall.toObject(includeInstance, f),
+toolResult: (f = msg.getToolResult()) && proto.mcp.v1.ToolResult.toObject(includeInstance, f),
+toolsList: jspb.Message.toObjectList(msg.getToolsList(),
+    proto.mcp.v1.ToolDefinition.toObject, includeInstance)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.ChatMessage}
+ */
+proto.mcp.v1.ChatMessage.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.ChatMessage;
+  return proto.mcp.v1.ChatMessage.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.ChatMessage} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.ChatMessage}
+ */
+proto.mcp.v1.ChatMessage.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMessageId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSessionId(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setContent(value);
+      break;
+    case 4:
+      var value = /** @type {!proto.mcp.v1.MessageType} */ (reader.readEnum());
+      msg.setType(value);
+      break;
+    case 5:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setTimestamp(value);
+      break;
+    case 6:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setDone(value);
+      break;
+    case 7:
+      var value = new proto.mcp.v1.GenerationOptions;
+      reader.readMessage(value,proto.mcp.v1.GenerationOptions.deserializeBinaryFromReader);
+      msg.setOptions(value);
+      break;
+    case 8:
+      var value = new proto.mcp.v1.Usage;
+      reader.readMessage(value,proto.mcp.v1.Usage.deserializeBinaryFromReader);
+      msg.setUsage(value);
+      break;
+    case 9:
+      var value = new proto.mcp.v1.Attachment;
+      reader.readMessage(value,proto.mcp.v1.Attachment.deserializeBinaryFromReader);
+      msg.addAttachments(value);
+      break;
+    case 10:
+      var value = new proto.mcp.v1.ResponseFormat;
+      reader.readMessage(value,proto.mcp.v1.ResponseFormat.deserializeBinaryFromReader);
+      msg.setResponseFormat(value);
+      break;
+    case 11:
+      var value = new proto.mcp.v1.ToolCall;
+      reader.readMessage(value,proto.mcp.v1.ToolCall.deserializeBinaryFromReader);
+      msg.setToolCall(value);
+      break;
+    case 12:
+      var value = new proto.mcp.v1.ToolResult;
+      reader.readMessage(value,proto.mcp.v1.ToolResult.deserializeBinaryFromReader);
+      msg.setToolResult(value);
+      break;
+    case 13:
+      var value = new proto.mcp.v1.ToolDefinition;
+      reader.readMessage(value,proto.mcp.v1.ToolDefinition.deserializeBinaryFromReader);
+      msg.addTools(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.ChatMessage.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.ChatMessage.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.ChatMessage} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ChatMessage.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getMessageId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getSessionId();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getContent();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getType();
+  if (f !== 0.0) {
+    writer.writeEnum(
+      4,
+      f
+    );
+  }
+  f = message.getTimestamp();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getDone();
+  if (f) {
+    writer.writeBool(
+      6,
+      f
+    );
+  }
+  f = message.getOptions();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      proto.mcp.v1.GenerationOptions.serializeBinaryToWriter
+    );
+  }
+  f = message.getUsage();
+  if (f != null) {
+    writer.writeMessage(
+      8,
+      f,
+      proto.mcp.v1.Usage.serializeBinaryToWriter
+    );
+  }
+  f = message.getAttachmentsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      9,
+      f,
+      proto.mcp.v1.Attachment.serializeBinaryToWriter
+    );
+  }
+  f = message.getResponseFormat();
+  if (f != null) {
+    writer.writeMessage(
+      10,
+      f,
+      proto.mcp.v1.ResponseFormat.serializeBinaryToWriter
+    );
+  }
+  f = message.getToolCall();
+  if (f != null) {
+    writer.writeMessage(
+      11,
+      f,
+      proto.mcp.v1.ToolCall.serializeBinaryToWriter
+    );
+  }
+  f = message.getToolResult();
+  if (f != null) {
+    writer.writeMessage(
+      12,
+      f,
+      proto.mcp.v1.ToolResult.serializeBinaryToWriter
+    );
+  }
+  f = message.getToolsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      13,
+      f,
+      proto.mcp.v1.ToolDefinition.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string message_id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.ChatMessage.prototype.getMessageId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.setMessageId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string session_id = 2;
+ * @return {string}
+ */
+proto.mcp.v1.ChatMessage.prototype.getSessionId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.setSessionId = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string content = 3;
+ * @return {string}
+ */
+proto.mcp.v1.ChatMessage.prototype.getContent = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.setContent = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional MessageType type = 4;
+ * @return {!proto.mcp.v1.MessageType}
+ */
+proto.mcp.v1.ChatMessage.prototype.getType = function() {
+  return /** @type {!proto.mcp.v1.MessageType} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/**
+ * @param {!proto.mcp.v1.MessageType} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.setType = function(value) {
+  return jspb.Message.setProto3EnumField(this, 4, value);
+};
+
+
+/**
+ * optional google.protobuf.Timestamp timestamp = 5;
+ * @return {?proto.google.protobuf.Timestamp}
+ */
+proto.mcp.v1.ChatMessage.prototype.getTimestamp = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 5));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setTimestamp = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearTimestamp = function() {
+  return this.setTimestamp(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.hasTimestamp = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * optional bool done = 6;
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.getDone = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 6, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.setDone = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 6, value);
+};
+
+
+/**
+ * optional GenerationOptions options = 7;
+ * @return {?proto.mcp.v1.GenerationOptions}
+ */
+proto.mcp.v1.ChatMessage.prototype.getOptions = function() {
+  return /** @type{?proto.mcp.v1.GenerationOptions} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.GenerationOptions, 7));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.GenerationOptions|undefined} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setOptions = function(value) {
+  return jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearOptions = function() {
+  return this.setOptions(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.hasOptions = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+/**
+ * optional Usage usage = 8;
+ * @return {?proto.mcp.v1.Usage}
+ */
+proto.mcp.v1.ChatMessage.prototype.getUsage = function() {
+  return /** @type{?proto.mcp.v1.Usage} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.Usage, 8));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.Usage|undefined} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setUsage = function(value) {
+  return jspb.Message.setWrapperField(this, 8, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearUsage = function() {
+  return this.setUsage(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.hasUsage = function() {
+  return jspb.Message.getField(this, 8) != null;
+};
+
+
+/**
+ * repeated Attachment attachments = 9;
+ * @return {!Array<!proto.mcp.v1.Attachment>}
+ */
+proto.mcp.v1.ChatMessage.prototype.getAttachmentsList = function() {
+  return /** @type{!Array<!proto.mcp.v1.Attachment>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.mcp.v1.Attachment, 9));
+};
+
+
+/**
+ * @param {!Array<!proto.mcp.v1.Attachment>} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setAttachmentsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 9, value);
+};
+
+
+/**
+ * @param {!proto.mcp.v1.Attachment=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.mcp.v1.Attachment}
+ */
+proto.mcp.v1.ChatMessage.prototype.addAttachments = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 9, opt_value, proto.mcp.v1.Attachment, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearAttachmentsList = function() {
+  return this.setAttachmentsList([]);
+};
+
+
+/**
+ * optional ResponseFormat response_format = 10;
+ * @return {?proto.mcp.v1.ResponseFormat}
+ */
+proto.mcp.v1.ChatMessage.prototype.getResponseFormat = function() {
+  return /** @type{?proto.mcp.v1.ResponseFormat} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.ResponseFormat, 10));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.ResponseFormat|undefined} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setResponseFormat = function(value) {
+  return jspb.Message.setWrapperField(this, 10, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearResponseFormat = function() {
+  return this.setResponseFormat(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.hasResponseFormat = function() {
+  return jspb.Message.getField(this, 10) != null;
+};
+
+
+/**
+ * optional ToolCall tool_call = 11;
+ * @return {?proto.mcp.v1.ToolCall}
+ */
+proto.mcp.v1.ChatMessage.prototype.getToolCall = function() {
+  return /** @type{?proto.mcp.v1.ToolCall} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.ToolCall, 11));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.ToolCall|undefined} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setToolCall = function(value) {
+  return jspb.Message.setWrapperField(this, 11, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearToolCall = function() {
+  return this.setToolCall(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.hasToolCall = function() {
+  return jspb.Message.getField(this, 11) != null;
+};
+
+
+/**
+ * optional ToolResult tool_result = 12;
+ * @return {?proto.mcp.v1.ToolResult}
+ */
+proto.mcp.v1.ChatMessage.prototype.getToolResult = function() {
+  return /** @type{?proto.mcp.v1.ToolResult} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.ToolResult, 12));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.ToolResult|undefined} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setToolResult = function(value) {
+  return jspb.Message.setWrapperField(this, 12, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearToolResult = function() {
+  return this.setToolResult(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.ChatMessage.prototype.hasToolResult = function() {
+  return jspb.Message.getField(this, 12) != null;
+};
+
+
+/**
+ * repeated ToolDefinition tools = 13;
+ * @return {!Array<!proto.mcp.v1.ToolDefinition>}
+ */
+proto.mcp.v1.ChatMessage.prototype.getToolsList = function() {
+  return /** @type{!Array<!proto.mcp.v1.ToolDefinition>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.mcp.v1.ToolDefinition, 13));
+};
+
+
+/**
+ * @param {!Array<!proto.mcp.v1.ToolDefinition>} value
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+*/
+proto.mcp.v1.ChatMessage.prototype.setToolsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 13, value);
+};
+
+
+/**
+ * @param {!proto.mcp.v1.ToolDefinition=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.mcp.v1.ToolDefinition}
+ */
+proto.mcp.v1.ChatMessage.prototype.addTools = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 13, opt_value, proto.mcp.v1.ToolDefinition, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.mcp.v1.ChatMessage} returns this
+ */
+proto.mcp.v1.ChatMessage.prototype.clearToolsList = function() {
+  return this.setToolsList([]);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.ToolDefinition.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.ToolDefinition.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.ToolDefinition} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ToolDefinition.toObject = function(includeInstance, msg) {
+  var f, obj = {
+name: jspb.Message.getFieldWithDefault(msg, 1, ""),
+description: jspb.Message.getFieldWithDefault(msg, 2, ""),
+parameters: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.ToolDefinition}
+ */
+proto.mcp.v1.ToolDefinition.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.ToolDefinition;
+  return proto.mcp.v1.ToolDefinition.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.ToolDefinition} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.ToolDefinition}
+ */
+proto.mcp.v1.ToolDefinition.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDescription(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setParameters(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.ToolDefinition.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.ToolDefinition.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.ToolDefinition} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ToolDefinition.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getDescription();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getParameters();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string name = 1;
+ * @return {string}
+ */
+proto.mcp.v1.ToolDefinition.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolDefinition} returns this
+ */
+proto.mcp.v1.ToolDefinition.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string description = 2;
+ * @return {string}
+ */
+proto.mcp.v1.ToolDefinition.prototype.getDescription = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolDefinition} returns this
+ */
+proto.mcp.v1.ToolDefinition.prototype.setDescription = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string parameters = 3;
+ * @return {string}
+ */
+proto.mcp.v1.ToolDefinition.prototype.getParameters = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolDefinition} returns this
+ */
+proto.mcp.v1.ToolDefinition.prototype.setParameters = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.ToolCall.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.ToolCall.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.ToolCall} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ToolCall.toObject = function(includeInstance, msg) {
+  var f, obj = {
+id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+name: jspb.Message.getFieldWithDefault(msg, 2, ""),
+arguments: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.ToolCall}
+ */
+proto.mcp.v1.ToolCall.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.ToolCall;
+  return proto.mcp.v1.ToolCall.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.ToolCall} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.ToolCall}
+ */
+proto.mcp.v1.ToolCall.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setArguments(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.ToolCall.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.ToolCall.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.ToolCall} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ToolCall.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getArguments();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.ToolCall.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolCall} returns this
+ */
+proto.mcp.v1.ToolCall.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string name = 2;
+ * @return {string}
+ */
+proto.mcp.v1.ToolCall.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolCall} returns this
+ */
+proto.mcp.v1.ToolCall.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string arguments = 3;
+ * @return {string}
+ */
+proto.mcp.v1.ToolCall.prototype.getArguments = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolCall} returns this
+ */
+proto.mcp.v1.ToolCall.prototype.setArguments = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.ToolResult.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.ToolResult.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.ToolResult} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ToolResult.toObject = function(includeInstance, msg) {
+  var f, obj = {
+id: jspb.Message.getFieldWithDefault(msg, 1, ""),
+name: jspb.Message.getFieldWithDefault(msg, 2, ""),
+content: jspb.Message.getFieldWithDefault(msg, 3, ""),
+isError: jspb.Message.getBooleanFieldWithDefault(msg, 4, false)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.ToolResult}
+ */
+proto.mcp.v1.ToolResult.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.ToolResult;
+  return proto.mcp.v1.ToolResult.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.ToolResult} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.ToolResult}
+ */
+proto.mcp.v1.ToolResult.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setContent(value);
+      break;
+    case 4:
+      var value = /** @type {boolean} */ (reader.readBool());
+      msg.setIsError(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.ToolResult.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.ToolResult.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.ToolResult} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ToolResult.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getContent();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getIsError();
+  if (f) {
+    writer.writeBool(
+      4,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.ToolResult.prototype.getId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolResult} returns this
+ */
+proto.mcp.v1.ToolResult.prototype.setId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string name = 2;
+ * @return {string}
+ */
+proto.mcp.v1.ToolResult.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolResult} returns this
+ */
+proto.mcp.v1.ToolResult.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string content = 3;
+ * @return {string}
+ */
+proto.mcp.v1.ToolResult.prototype.getContent = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ToolResult} returns this
+ */
+proto.mcp.v1.ToolResult.prototype.setContent = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional bool is_error = 4;
+ * @return {boolean}
+ */
+proto.mcp.v1.ToolResult.prototype.getIsError = function() {
+  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 4, false));
+};
+
+
+/**
+ * @param {boolean} value
+ * @return {!proto.mcp.v1.ToolResult} returns this
+ */
+proto.mcp.v1.ToolResult.prototype.setIsError = function(value) {
+  return jspb.Message.setProto3BooleanField(this, 4, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.mcp.v1.SingleChatRequest.repeatedFields_ = [5];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.SingleChatRequest.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.SingleChatRequest} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.SingleChatRequest.toObject = function(includeInstance, msg) {
+  var f, obj = {
+sessionId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+content: jspb.Message.getFieldWithDefault(msg, 2, ""),
+model: jspb.Message.getFieldWithDefault(msg, 3, ""),
+options: (f = msg.getOptions()) && proto.mcp.v1.GenerationOptions.toObject(includeInstance, f),
+attachmentsList: jspb.Message.toObjectList(msg.getAttachmentsList(),
+    proto.mcp.v1.Attachment.toObject, includeInstance),
+responseFormat: (f = msg.getResponseFormat()) && proto.mcp.v1.ResponseFormat.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.SingleChatRequest}
+ */
+proto.mcp.v1.SingleChatRequest.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.SingleChatRequest;
+  return proto.mcp.v1.SingleChatRequest.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.SingleChatRequest} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.SingleChatRequest}
+ */
+proto.mcp.v1.SingleChatRequest.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setSessionId(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setContent(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setModel(value);
+      break;
+    case 4:
+      var value = new proto.mcp.v1.GenerationOptions;
+      reader.readMessage(value,proto.mcp.v1.GenerationOptions.deserializeBinaryFromReader);
+      msg.setOptions(value);
+      break;
+    case 5:
+      var value = new proto.mcp.v1.Attachment;
+      reader.readMessage(value,proto.mcp.v1.Attachment.deserializeBinaryFromReader);
+      msg.addAttachments(value);
+      break;
+    case 6:
+      var value = new proto.mcp.v1.ResponseFormat;
+      reader.readMessage(value,proto.mcp.v1.ResponseFormat.deserializeBinaryFromReader);
+      msg.setResponseFormat(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.SingleChatRequest.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.SingleChatRequest} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.SingleChatRequest.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getSessionId();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getContent();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getModel();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+  f = message.getOptions();
+  if (f != null) {
+    writer.writeMessage(
+      4,
+      f,
+      proto.mcp.v1.GenerationOptions.serializeBinaryToWriter
+    );
+  }
+  f = message.getAttachmentsList();
+  if (f.length > 0) {
+    writer.writeRepeatedMessage(
+      5,
+      f,
+      proto.mcp.v1.Attachment.serializeBinaryToWriter
+    );
+  }
+  f = message.getResponseFormat();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      proto.mcp.v1.ResponseFormat.serializeBinaryToWriter
+    );
+  }
+};
+
+
+/**
+ * optional string session_id = 1;
+ * @return {string}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.getSessionId = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ */
+proto.mcp.v1.SingleChatRequest.prototype.setSessionId = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+/**
+ * optional string content = 2;
+ * @return {string}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.getContent = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ */
+proto.mcp.v1.SingleChatRequest.prototype.setContent = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string model = 3;
+ * @return {string}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.getModel = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ */
+proto.mcp.v1.SingleChatRequest.prototype.setModel = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+/**
+ * optional GenerationOptions options = 4;
+ * @return {?proto.mcp.v1.GenerationOptions}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.getOptions = function() {
+  return /** @type{?proto.mcp.v1.GenerationOptions} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.GenerationOptions, 4));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.GenerationOptions|undefined} value
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+*/
+proto.mcp.v1.SingleChatRequest.prototype.setOptions = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ */
+proto.mcp.v1.SingleChatRequest.prototype.clearOptions = function() {
+  return this.setOptions(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.hasOptions = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * repeated Attachment attachments = 5;
+ * @return {!Array<!proto.mcp.v1.Attachment>}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.getAttachmentsList = function() {
+  return /** @type{!Array<!proto.mcp.v1.Attachment>} */ (
+    jspb.Message.getRepeatedWrapperField(this, proto.mcp.v1.Attachment, 5));
+};
+
+
+/**
+ * @param {!Array<!proto.mcp.v1.Attachment>} value
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+*/
+proto.mcp.v1.SingleChatRequest.prototype.setAttachmentsList = function(value) {
+  return jspb.Message.setRepeatedWrapperField(this, 5, value);
+};
+
+
+/**
+ * @param {!proto.mcp.v1.Attachment=} opt_value
+ * @param {number=} opt_index
+ * @return {!proto.mcp.v1.Attachment}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.addAttachments = function(opt_value, opt_index) {
+  return jspb.Message.addToRepeatedWrapperField(this, 5, opt_value, proto.mcp.v1.Attachment, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ */
+proto.mcp.v1.SingleChatRequest.prototype.clearAttachmentsList = function() {
+  return this.setAttachmentsList([]);
+};
+
+
+/**
+ * optional ResponseFormat response_format = 6;
+ * @return {?proto.mcp.v1.ResponseFormat}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.getResponseFormat = function() {
+  return /** @type{?proto.mcp.v1.ResponseFormat} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.ResponseFormat, 6));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.ResponseFormat|undefined} value
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+*/
+proto.mcp.v1.SingleChatRequest.prototype.setResponseFormat = function(value) {
+  return jspb.Message.setWrapperField(this, 6, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ */
+proto.mcp.v1.SingleChatRequest.prototype.clearResponseFormat = function() {
+  return this.setResponseFormat(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.SingleChatRequest.prototype.hasResponseFormat = function() {
+  return jspb.Message.getField(this, 6) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.ResponseFormat.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.ResponseFormat.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.ResponseFormat} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ResponseFormat.toObject = function(includeInstance, msg) {
+  var f, obj = {
+jsonSchema: jspb.Message.getFieldWithDefault(msg, 1, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.ResponseFormat}
+ */
+proto.mcp.v1.ResponseFormat.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.ResponseFormat;
+  return proto.mcp.v1.ResponseFormat.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.ResponseFormat} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.ResponseFormat}
+ */
+proto.mcp.v1.ResponseFormat.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setJsonSchema(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.ResponseFormat.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.ResponseFormat.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.ResponseFormat} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.ResponseFormat.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getJsonSchema();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional string json_schema = 1;
+ * @return {string}
+ */
+proto.mcp.v1.ResponseFormat.prototype.getJsonSchema = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.ResponseFormat} returns this
+ */
+proto.mcp.v1.ResponseFormat.prototype.setJsonSchema = function(value) {
+  return jspb.Message.setProto3StringField(this, 1, value);
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.Attachment.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.Attachment.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.Attachment} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.Attachment.toObject = function(includeInstance, msg) {
+  var f, obj = {
+data: msg.getData_asB64(),
+mimeType: jspb.Message.getFieldWithDefault(msg, 2, ""),
+name: jspb.Message.getFieldWithDefault(msg, 3, "")
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.Attachment}
+ */
+proto.mcp.v1.Attachment.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.Attachment;
+  return proto.mcp.v1.Attachment.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.Attachment} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.Attachment}
+ */
+proto.mcp.v1.Attachment.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {!Uint8Array} */ (reader.readBytes());
+      msg.setData(value);
+      break;
+    case 2:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setMimeType(value);
+      break;
+    case 3:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setName(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.Attachment.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.Attachment.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.Attachment} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.Attachment.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getData_asU8();
+  if (f.length > 0) {
+    writer.writeBytes(
+      1,
+      f
+    );
+  }
+  f = message.getMimeType();
+  if (f.length > 0) {
+    writer.writeString(
+      2,
+      f
+    );
+  }
+  f = message.getName();
+  if (f.length > 0) {
+    writer.writeString(
+      3,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional bytes data = 1;
+ * @return {!(string|Uint8Array)}
+ */
+proto.mcp.v1.Attachment.prototype.getData = function() {
+  return /** @type {!(string|Uint8Array)} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+};
+
+
+/**
+ * optional bytes data = 1;
+ * This is a type-conversion wrapper around `getData()`
+ * @return {string}
+ */
+proto.mcp.v1.Attachment.prototype.getData_asB64 = function() {
+  return /** @type {string} */ (jspb.Message.bytesAsB64(
+      this.getData()));
+};
+
+
+/**
+ * optional bytes data = 1;
+ * Note that Uint8Array is not supported on all browsers.
+ * @see http://caniuse.com/Uint8Array
+ * This is a type-conversion wrapper around `getData()`
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.Attachment.prototype.getData_asU8 = function() {
+  return /** @type {!Uint8Array} */ (jspb.Message.bytesAsU8(
+      this.getData()));
+};
+
+
+/**
+ * @param {!(string|Uint8Array)} value
+ * @return {!proto.mcp.v1.Attachment} returns this
+ */
+proto.mcp.v1.Attachment.prototype.setData = function(value) {
+  return jspb.Message.setProto3BytesField(this, 1, value);
+};
+
+
+/**
+ * optional string mime_type = 2;
+ * @return {string}
+ */
+proto.mcp.v1.Attachment.prototype.getMimeType = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.Attachment} returns this
+ */
+proto.mcp.v1.Attachment.prototype.setMimeType = function(value) {
+  return jspb.Message.setProto3StringField(this, 2, value);
+};
+
+
+/**
+ * optional string name = 3;
+ * @return {string}
+ */
+proto.mcp.v1.Attachment.prototype.getName = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+};
+
+
+/**
+ * @param {string} value
+ * @return {!proto.mcp.v1.Attachment} returns this
+ */
+proto.mcp.v1.Attachment.prototype.setName = function(value) {
+  return jspb.Message.setProto3StringField(this, 3, value);
+};
+
+
+
+/**
+ * List of repeated fields within this message type.
+ * @private {!Array<number>}
+ * @const
+ */
+proto.mcp.v1.GenerationOptions.repeatedFields_ = [6];
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.GenerationOptions.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.GenerationOptions.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.GenerationOptions} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.GenerationOptions.toObject = function(includeInstance, msg) {
+  var f, obj = {
+temperature: (f = jspb.Message.getOptionalFloatingPointField(msg, 1)) == null ? undefined : f,
+topP: (f = jspb.Message.getOptionalFloatingPointField(msg, 2)) == null ? undefined : f,
+topK: (f = jspb.Message.getField(msg, 3)) == null ? undefined : f,
+numCtx: (f = jspb.Message.getField(msg, 4)) == null ? undefined : f,
+seed: (f = jspb.Message.getField(msg, 5)) == null ? undefined : f,
+stopList: (f = jspb.Message.getRepeatedField(msg, 6)) == null ? undefined : f,
+maxTokens: (f = jspb.Message.getField(msg, 7)) == null ? undefined : f
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.GenerationOptions}
+ */
+proto.mcp.v1.GenerationOptions.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.GenerationOptions;
+  return proto.mcp.v1.GenerationOptions.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.GenerationOptions} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.GenerationOptions}
+ */
+proto.mcp.v1.GenerationOptions.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {number} */ (reader.readDouble());
+      msg.setTemperature(value);
+      break;
+    case 2:
+      var value = /** @type {number} */ (reader.readDouble());
+      msg.setTopP(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setTopK(value);
+      break;
+    case 4:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setNumCtx(value);
+      break;
+    case 5:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setSeed(value);
+      break;
+    case 6:
+      var value = /** @type {string} */ (reader.readString());
+      msg.addStop(value);
+      break;
+    case 7:
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setMaxTokens(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.GenerationOptions.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.GenerationOptions.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.GenerationOptions} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.GenerationOptions.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = /** @type {number} */ (jspb.Message.getField(message, 1));
+  if (f != null) {
+    writer.writeDouble(
+      1,
+      f
+    );
+  }
+  f = /** @type {number} */ (jspb.Message.getField(message, 2));
+  if (f != null) {
+    writer.writeDouble(
+      2,
+      f
+    );
+  }
+  f = /** @type {number} */ (jspb.Message.getField(message, 3));
+  if (f != null) {
+    writer.writeInt32(
+      3,
+      f
+    );
+  }
+  f = /** @type {number} */ (jspb.Message.getField(message, 4));
+  if (f != null) {
+    writer.writeInt32(
+      4,
+      f
+    );
+  }
+  f = /** @type {number} */ (jspb.Message.getField(message, 5));
+  if (f != null) {
+    writer.writeInt64(
+      5,
+      f
+    );
+  }
+  f = message.getStopList();
+  if (f.length > 0) {
+    writer.writeRepeatedString(
+      6,
+      f
+    );
+  }
+  f = /** @type {number} */ (jspb.Message.getField(message, 7));
+  if (f != null) {
+    writer.writeInt32(
+      7,
+      f
+    );
+  }
+};
+
+
+/**
+ * optional double temperature = 1;
+ * @return {number}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getTemperature = function() {
+  return /** @type {number} */ (jspb.Message.getFloatingPointFieldWithDefault(this, 1, 0.0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setTemperature = function(value) {
+  return jspb.Message.setField(this, 1, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearTemperature = function() {
+  return jspb.Message.setField(this, 1, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.GenerationOptions.prototype.hasTemperature = function() {
+  return jspb.Message.getField(this, 1) != null;
+};
+
+
+/**
+ * optional double top_p = 2;
+ * @return {number}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getTopP = function() {
+  return /** @type {number} */ (jspb.Message.getFloatingPointFieldWithDefault(this, 2, 0.0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setTopP = function(value) {
+  return jspb.Message.setField(this, 2, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearTopP = function() {
+  return jspb.Message.setField(this, 2, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.GenerationOptions.prototype.hasTopP = function() {
+  return jspb.Message.getField(this, 2) != null;
+};
+
+
+/**
+ * optional int32 top_k = 3;
+ * @return {number}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getTopK = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setTopK = function(value) {
+  return jspb.Message.setField(this, 3, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearTopK = function() {
+  return jspb.Message.setField(this, 3, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.GenerationOptions.prototype.hasTopK = function() {
+  return jspb.Message.getField(this, 3) != null;
+};
+
+
+/**
+ * optional int32 num_ctx = 4;
+ * @return {number}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getNumCtx = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setNumCtx = function(value) {
+  return jspb.Message.setField(this, 4, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearNumCtx = function() {
+  return jspb.Message.setField(this, 4, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.GenerationOptions.prototype.hasNumCtx = function() {
+  return jspb.Message.getField(this, 4) != null;
+};
+
+
+/**
+ * optional int64 seed = 5;
+ * @return {number}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getSeed = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 5, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setSeed = function(value) {
+  return jspb.Message.setField(this, 5, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearSeed = function() {
+  return jspb.Message.setField(this, 5, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.GenerationOptions.prototype.hasSeed = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
+
+
+/**
+ * repeated string stop = 6;
+ * @return {!Array<string>}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getStopList = function() {
+  return /** @type {!Array<string>} */ (jspb.Message.getRepeatedField(this, 6));
+};
+
+
+/**
+ * @param {!Array<string>} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setStopList = function(value) {
+  return jspb.Message.setField(this, 6, value || []);
+};
+
+
+/**
+ * @param {string} value
+ * @param {number=} opt_index
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.addStop = function(value, opt_index) {
+  return jspb.Message.addToRepeatedField(this, 6, value, opt_index);
+};
+
+
+/**
+ * Clears the list making it empty but non-null.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearStopList = function() {
+  return this.setStopList([]);
+};
+
+
+/**
+ * optional int32 max_tokens = 7;
+ * @return {number}
+ */
+proto.mcp.v1.GenerationOptions.prototype.getMaxTokens = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
+};
+
+
+/**
+ * @param {number} value
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.setMaxTokens = function(value) {
+  return jspb.Message.setField(this, 7, value);
+};
+
+
+/**
+ * Clears the field making it undefined.
+ * @return {!proto.mcp.v1.GenerationOptions} returns this
+ */
+proto.mcp.v1.GenerationOptions.prototype.clearMaxTokens = function() {
+  return jspb.Message.setField(this, 7, undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.GenerationOptions.prototype.hasMaxTokens = function() {
+  return jspb.Message.getField(this, 7) != null;
+};
+
+
+
+
+
+if (jspb.Message.GENERATE_TO_OBJECT) {
+/**
+ * Creates an object representation of this proto.
+ * Field names that are reserved in JavaScript and will be renamed to pb_name.
+ * Optional fields that are not set will be set to undefined.
+ * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
+ * For the list of reserved names please see:
+ *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
+ * @param {boolean=} opt_includeInstance Deprecated. whether to include the
+ *     JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @return {!Object}
+ */
+proto.mcp.v1.SingleChatResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.SingleChatResponse.toObject(opt_includeInstance, this);
+};
+
+
+/**
+ * Static version of the {@see toObject} method.
+ * @param {boolean|undefined} includeInstance Deprecated. Whether to include
+ *     the JSPB instance for transitional soy proto support:
+ *     http://goto/soy-param-migration
+ * @param {!proto.mcp.v1.SingleChatResponse} msg The msg instance to transform.
+ * @return {!Object}
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.SingleChatResponse.toObject = function(includeInstance, msg) {
+  var f, obj = {
+content: jspb.Message.getFieldWithDefault(msg, 1, ""),
+timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+usage: (f = msg.getUsage()) && proto.mcp.v1.Usage.toObject(includeInstance, f)
+  };
+
+  if (includeInstance) {
+    obj.$jspbMessageInstance = msg;
+  }
+  return obj;
+};
+}
+
+
+/**
+ * Deserializes binary data (in protobuf wire format).
+ * @param {jspb.ByteSource} bytes The bytes to deserialize.
+ * @return {!proto.mcp.v1.SingleChatResponse}
+ */
+proto.mcp.v1.SingleChatResponse.deserializeBinary = function(bytes) {
+  var reader = new jspb.BinaryReader(bytes);
+  var msg = new proto.mcp.v1.SingleChatResponse;
+  return proto.mcp.v1.SingleChatResponse.deserializeBinaryFromReader(msg, reader);
+};
+
+
+/**
+ * Deserializes binary data (in protobuf wire format) from the
+ * given reader into the given message object.
+ * @param {!proto.mcp.v1.SingleChatResponse} msg The message object to deserialize into.
+ * @param {!jspb.BinaryReader} reader The BinaryReader to use.
+ * @return {!proto.mcp.v1.SingleChatResponse}
+ */
+proto.mcp.v1.SingleChatResponse.deserializeBinaryFromReader = function(msg, reader) {
+  while (reader.nextField()) {
+    if (reader.isEndGroup()) {
+      break;
+    }
+    var field = reader.getFieldNumber();
+    switch (field) {
+    case 1:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setContent(value);
+      break;
+    case 2:
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setTimestamp(value);
+      break;
+    case 3:
+      var value = new proto.mcp.v1.Usage;
+      reader.readMessage(value,proto.mcp.v1.Usage.deserializeBinaryFromReader);
+      msg.setUsage(value);
+      break;
+    default:
+      reader.skipField();
+      break;
+    }
+  }
+  return msg;
+};
+
+
+/**
+ * Serializes the message to binary data (in protobuf wire format).
+ * @return {!Uint8Array}
+ */
+proto.mcp.v1.SingleChatResponse.prototype.serializeBinary = function() {
+  var writer = new jspb.BinaryWriter();
+  proto.mcp.v1.SingleChatResponse.serializeBinaryToWriter(this, writer);
+  return writer.getResultBuffer();
+};
+
+
+/**
+ * Serializes the given message to binary data (in protobuf wire
+ * format), writing to the given BinaryWriter.
+ * @param {!proto.mcp.v1.SingleChatResponse} message
+ * @param {!jspb.BinaryWriter} writer
+ * @suppress {unusedLocalVariables} f is only used for nested messages
+ */
+proto.mcp.v1.SingleChatResponse.serializeBinaryToWriter = function(message, writer) {
+  var f = undefined;
+  f = message.getContent();
+  if (f.length > 0) {
+    writer.writeString(
+      1,
+      f
+    );
+  }
+  f = message.getTimestamp();
+  if (f != null) {
+    writer.writeMessage(
+      2,
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+    );
+  }
+  f = message.getUsage();
+  if (f != null) {
+    writer.writeMessage(
+      3,
+      f,
+      proto.mcp.v1.Usage.serializeBinaryToWriter
     );
   }
 };
 
 
 /**
- * optional string tenant_id = 1;
+ * optional string content = 1;
  * @return {string}
  */
-proto.mcp.v1.RegisterRequest.prototype.getTenantId = function() {
+proto.mcp.v1.SingleChatResponse.prototype.getContent = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.mcp.v1.RegisterRequest} returns this
+ * @return {!proto.mcp.v1.SingleChatResponse} returns this
  */
-proto.mcp.v1.RegisterRequest.prototype.setTenantId = function(value) {
+proto.mcp.v1.SingleChatResponse.prototype.setContent = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string agent_id = 2;
- * @return {string}
+ * optional google.protobuf.Timestamp timestamp = 2;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.mcp.v1.RegisterRequest.prototype.getAgentId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.mcp.v1.SingleChatResponse.prototype.getTimestamp = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 2));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.RegisterRequest} returns this
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.SingleChatResponse} returns this
+*/
+proto.mcp.v1.SingleChatResponse.prototype.setTimestamp = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.SingleChatResponse} returns this
  */
-proto.mcp.v1.RegisterRequest.prototype.setAgentId = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.mcp.v1.SingleChatResponse.prototype.clearTimestamp = function() {
+  return this.setTimestamp(undefined);
 };
 
 
 /**
- * optional string model = 3;
- * @return {string}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.RegisterRequest.prototype.getModel = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+proto.mcp.v1.SingleChatResponse.prototype.hasTimestamp = function() {
+  return jspb.Message.getField(this, 2) != null;
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.RegisterRequest} returns this
+ * optional Usage usage = 3;
+ * @return {?proto.mcp.v1.Usage}
  */
-proto.mcp.v1.RegisterRequest.prototype.setModel = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
+proto.mcp.v1.SingleChatResponse.prototype.getUsage = function() {
+  return /** @type{?proto.mcp.v1.Usage} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.Usage, 3));
+};
+
+
+/**
+ * @param {?proto.mcp.v1.Usage|undefined} value
+ * @return {!proto.mcp.v1.SingleChatResponse} returns this
+*/
+proto.mcp.v1.SingleChatResponse.prototype.setUsage = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.SingleChatResponse} returns this
+ */
+proto.mcp.v1.SingleChatResponse.prototype.clearUsage = function() {
+  return this.setUsage(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.SingleChatResponse.prototype.hasUsage = function() {
+  return jspb.Message.getField(this, 3) != null;
 };
 
 
@@ -384,8 +4710,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.mcp.v1.RegisterResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.mcp.v1.RegisterResponse.toObject(opt_includeInstance, this);
+proto.mcp.v1.Usage.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.Usage.toObject(opt_includeInstance, this);
 };
 
 
@@ -394,15 +4720,21 @@ proto.mcp.v1.RegisterResponse.prototype.toObject = function(opt_includeInstance)
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.mcp.v1.RegisterResponse} msg The msg instance to transform.
+ * @param {!proto.mcp.v1.Usage} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.RegisterResponse.toObject = function(includeInstance, msg) {
+proto.mcp.v1.Usage.toObject = function(includeInstance, msg) {
   var f, obj = {
-sessionId: jspb.Message.getFieldWithDefault(msg, 1, ""),
-jwtToken: jspb.Message.getFieldWithDefault(msg, 2, ""),
-expiresAt: (f = msg.getExpiresAt()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+model: jspb.Message.getFieldWithDefault(msg, 1, ""),
+promptTokens: jspb.Message.getFieldWithDefault(msg, 2, 0),
+completionTokens: jspb.Message.getFieldWithDefault(msg, 3, 0),
+totalDuration: (f = msg.getTotalDuration()) && google_protobuf_duration_pb.Duration.toObject(includeInstance, f),
+loadDuration: (f = msg.getLoadDuration()) && google_protobuf_duration_pb.Duration.toObject(includeInstance, f),
+promptEvalDuration: (f = msg.getPromptEvalDuration()) && google_protobuf_duration_pb.Duration.toObject(includeInstance, f),
+evalDuration: (f = msg.getEvalDuration()) && google_protobuf_duration_pb.Duration.toObject(includeInstance, f),
+tokensPerSecond: jspb.Message.getFloatingPointFieldWithDefault(msg, 8, 0.0),
+doneReason: jspb.Message.getFieldWithDefault(msg, 9, "")
   };
 
   if (includeInstance) {
@@ -416,23 +4748,23 @@ expiresAt: (f = msg.getExpiresAt()) && google_protobuf_timestamp_pb.Timestamp.to
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.mcp.v1.RegisterResponse}
+ * @return {!proto.mcp.v1.Usage}
  */
-proto.mcp.v1.RegisterResponse.deserializeBinary = function(bytes) {
+proto.mcp.v1.Usage.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.mcp.v1.RegisterResponse;
-  return proto.mcp.v1.RegisterResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.mcp.v1.Usage;
+  return proto.mcp.v1.Usage.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.mcp.v1.RegisterResponse} msg The message object to deserialize into.
+ * @param {!proto.mcp.v1.Usage} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.mcp.v1.RegisterResponse}
+ * @return {!proto.mcp.v1.Usage}
  */
-proto.mcp.v1.RegisterResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.mcp.v1.Usage.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -441,16 +4773,43 @@ proto.mcp.v1.RegisterResponse.deserializeBinaryFromReader = function(msg, reader
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setSessionId(value);
+      msg.setModel(value);
       break;
     case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setJwtToken(value);
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setPromptTokens(value);
       break;
     case 3:
-      var value = new google_protobuf_timestamp_pb.Timestamp;
-      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
-      msg.setExpiresAt(value);
+      var value = /** @type {number} */ (reader.readInt32());
+      msg.setCompletionTokens(value);
+      break;
+    case 4:
+      var value = new google_protobuf_duration_pb.Duration;
+      reader.readMessage(value,google_protobuf_duration_pb.Duration.deserializeBinaryFromReader);
+      msg.setTotalDuration(value);
+      break;
+    case 5:
+      var value = new google_protobuf_duration_pb.Duration;
+      reader.readMessage(value,google_protobuf_duration_pb.Duration.deserializeBinaryFromReader);
+      msg.setLoadDuration(value);
+      break;
+    case 6:
+      var value = new google_protobuf_duration_pb.Duration;
+      reader.readMessage(value,google_protobuf_duration_pb.Duration.deserializeBinaryFromReader);
+      msg.setPromptEvalDuration(value);
+      break;
+    case 7:
+      var value = new google_protobuf_duration_pb.Duration;
+      reader.readMessage(value,google_protobuf_duration_pb.Duration.deserializeBinaryFromReader);
+      msg.setEvalDuration(value);
+      break;
+    case 8:
+      var value = /** @type {number} */ (reader.readDouble());
+      msg.setTokensPerSecond(value);
+      break;
+    case 9:
+      var value = /** @type {string} */ (reader.readString());
+      msg.setDoneReason(value);
       break;
     default:
       reader.skipField();
@@ -465,9 +4824,9 @@ proto.mcp.v1.RegisterResponse.deserializeBinaryFromReader = function(msg, reader
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.mcp.v1.RegisterResponse.prototype.serializeBinary = function() {
+proto.mcp.v1.Usage.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.mcp.v1.RegisterResponse.serializeBinaryToWriter(this, writer);
+  proto.mcp.v1.Usage.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -475,98 +4834,161 @@ proto.mcp.v1.RegisterResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.mcp.v1.RegisterResponse} message
+ * @param {!proto.mcp.v1.Usage} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.RegisterResponse.serializeBinaryToWriter = function(message, writer) {
+proto.mcp.v1.Usage.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getSessionId();
+  f = message.getModel();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getJwtToken();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getPromptTokens();
+  if (f !== 0) {
+    writer.writeInt32(
       2,
       f
     );
   }
-  f = message.getExpiresAt();
+  f = message.getCompletionTokens();
+  if (f !== 0) {
+    writer.writeInt32(
+      3,
+      f
+    );
+  }
+  f = message.getTotalDuration();
   if (f != null) {
     writer.writeMessage(
-      3,
+      4,
       f,
-      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+      google_protobuf_duration_pb.Duration.serializeBinaryToWriter
+    );
+  }
+  f = message.getLoadDuration();
+  if (f != null) {
+    writer.writeMessage(
+      5,
+      f,
+      google_protobuf_duration_pb.Duration.serializeBinaryToWriter
+    );
+  }
+  f = message.getPromptEvalDuration();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      google_protobuf_duration_pb.Duration.serializeBinaryToWriter
+    );
+  }
+  f = message.getEvalDuration();
+  if (f != null) {
+    writer.writeMessage(
+      7,
+      f,
+      google_protobuf_duration_pb.Duration.serializeBinaryToWriter
+    );
+  }
+  f = message.getTokensPerSecond();
+  if (f !== 0.0) {
+    writer.writeDouble(
+      8,
+      f
+    );
+  }
+  f = message.getDoneReason();
+  if (f.length > 0) {
+    writer.writeString(
+      9,
+      f
     );
   }
 };
 
 
 /**
- * optional string session_id = 1;
+ * optional string model = 1;
  * @return {string}
  */
-proto.mcp.v1.RegisterResponse.prototype.getSessionId = function() {
+proto.mcp.v1.Usage.prototype.getModel = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.mcp.v1.RegisterResponse} returns this
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.RegisterResponse.prototype.setSessionId = function(value) {
+proto.mcp.v1.Usage.prototype.setModel = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string jwt_token = 2;
- * @return {string}
+ * optional int32 prompt_tokens = 2;
+ * @return {number}
  */
-proto.mcp.v1.RegisterResponse.prototype.getJwtToken = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.mcp.v1.Usage.prototype.getPromptTokens = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.RegisterResponse} returns this
+ * @param {number} value
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.RegisterResponse.prototype.setJwtToken = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.mcp.v1.Usage.prototype.setPromptTokens = function(value) {
+  return jspb.Message.setProto3IntField(this, 2, value);
 };
 
 
 /**
- * optional google.protobuf.Timestamp expires_at = 3;
- * @return {?proto.google.protobuf.Timestamp}
+ * optional int32 completion_tokens = 3;
+ * @return {number}
  */
-proto.mcp.v1.RegisterResponse.prototype.getExpiresAt = function() {
-  return /** @type{?proto.google.protobuf.Timestamp} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
+proto.mcp.v1.Usage.prototype.getCompletionTokens = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Timestamp|undefined} value
- * @return {!proto.mcp.v1.RegisterResponse} returns this
+ * @param {number} value
+ * @return {!proto.mcp.v1.Usage} returns this
+ */
+proto.mcp.v1.Usage.prototype.setCompletionTokens = function(value) {
+  return jspb.Message.setProto3IntField(this, 3, value);
+};
+
+
+/**
+ * optional google.protobuf.Duration total_duration = 4;
+ * @return {?proto.google.protobuf.Duration}
+ */
+proto.mcp.v1.Usage.prototype.getTotalDuration = function() {
+  return /** @type{?proto.google.protobuf.Duration} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_duration_pb.Duration, 4));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Duration|undefined} value
+ * @return {!proto.mcp.v1.Usage} returns this
 */
-proto.mcp.v1.RegisterResponse.prototype.setExpiresAt = function(value) {
-  return jspb.Message.setWrapperField(this, 3, value);
+proto.mcp.v1.Usage.prototype.setTotalDuration = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.mcp.v1.RegisterResponse} returns this
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.RegisterResponse.prototype.clearExpiresAt = function() {
-  return this.setExpiresAt(undefined);
+proto.mcp.v1.Usage.prototype.clearTotalDuration = function() {
+  return this.setTotalDuration(undefined);
 };
 
 
@@ -574,138 +4996,155 @@ proto.mcp.v1.RegisterResponse.prototype.clearExpiresAt = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.mcp.v1.RegisterResponse.prototype.hasExpiresAt = function() {
-  return jspb.Message.getField(this, 3) != null;
+proto.mcp.v1.Usage.prototype.hasTotalDuration = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
+/**
+ * optional google.protobuf.Duration load_duration = 5;
+ * @return {?proto.google.protobuf.Duration}
+ */
+proto.mcp.v1.Usage.prototype.getLoadDuration = function() {
+  return /** @type{?proto.google.protobuf.Duration} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_duration_pb.Duration, 5));
+};
+
 
+/**
+ * @param {?proto.google.protobuf.Duration|undefined} value
+ * @return {!proto.mcp.v1.Usage} returns this
+*/
+proto.mcp.v1.Usage.prototype.setLoadDuration = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
+};
 
 
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.AuthRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.mcp.v1.AuthRequest.toObject(opt_includeInstance, this);
+proto.mcp.v1.Usage.prototype.clearLoadDuration = function() {
+  return this.setLoadDuration(undefined);
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.mcp.v1.AuthRequest} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.AuthRequest.toObject = function(includeInstance, msg) {
-  var f, obj = {
-jwtToken: jspb.Message.getFieldWithDefault(msg, 1, "")
-  };
+proto.mcp.v1.Usage.prototype.hasLoadDuration = function() {
+  return jspb.Message.getField(this, 5) != null;
+};
 
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+
+/**
+ * optional google.protobuf.Duration prompt_eval_duration = 6;
+ * @return {?proto.google.protobuf.Duration}
+ */
+proto.mcp.v1.Usage.prototype.getPromptEvalDuration = function() {
+  return /** @type{?proto.google.protobuf.Duration} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_duration_pb.Duration, 6));
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.mcp.v1.AuthRequest}
+ * @param {?proto.google.protobuf.Duration|undefined} value
+ * @return {!proto.mcp.v1.Usage} returns this
+*/
+proto.mcp.v1.Usage.prototype.setPromptEvalDuration = function(value) {
+  return jspb.Message.setWrapperField(this, 6, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.Usage} returns this
+ */
+proto.mcp.v1.Usage.prototype.clearPromptEvalDuration = function() {
+  return this.setPromptEvalDuration(undefined);
+};
+
+
+/**
+ * Returns whether this field is set.
+ * @return {boolean}
+ */
+proto.mcp.v1.Usage.prototype.hasPromptEvalDuration = function() {
+  return jspb.Message.getField(this, 6) != null;
+};
+
+
+/**
+ * optional google.protobuf.Duration eval_duration = 7;
+ * @return {?proto.google.protobuf.Duration}
+ */
+proto.mcp.v1.Usage.prototype.getEvalDuration = function() {
+  return /** @type{?proto.google.protobuf.Duration} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_duration_pb.Duration, 7));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Duration|undefined} value
+ * @return {!proto.mcp.v1.Usage} returns this
+*/
+proto.mcp.v1.Usage.prototype.setEvalDuration = function(value) {
+  return jspb.Message.setWrapperField(this, 7, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.AuthRequest.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.mcp.v1.AuthRequest;
-  return proto.mcp.v1.AuthRequest.deserializeBinaryFromReader(msg, reader);
+proto.mcp.v1.Usage.prototype.clearEvalDuration = function() {
+  return this.setEvalDuration(undefined);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.mcp.v1.AuthRequest} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.mcp.v1.AuthRequest}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.AuthRequest.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setJwtToken(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.mcp.v1.Usage.prototype.hasEvalDuration = function() {
+  return jspb.Message.getField(this, 7) != null;
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * optional double tokens_per_second = 8;
+ * @return {number}
  */
-proto.mcp.v1.AuthRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.mcp.v1.AuthRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.mcp.v1.Usage.prototype.getTokensPerSecond = function() {
+  return /** @type {number} */ (jspb.Message.getFloatingPointFieldWithDefault(this, 8, 0.0));
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.mcp.v1.AuthRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
+ * @param {number} value
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.AuthRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getJwtToken();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
+proto.mcp.v1.Usage.prototype.setTokensPerSecond = function(value) {
+  return jspb.Message.setProto3FloatField(this, 8, value);
 };
 
 
 /**
- * optional string jwt_token = 1;
+ * optional string done_reason = 9;
  * @return {string}
  */
-proto.mcp.v1.AuthRequest.prototype.getJwtToken = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.mcp.v1.Usage.prototype.getDoneReason = function() {
+  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 9, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.mcp.v1.AuthRequest} returns this
+ * @return {!proto.mcp.v1.Usage} returns this
  */
-proto.mcp.v1.AuthRequest.prototype.setJwtToken = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.mcp.v1.Usage.prototype.setDoneReason = function(value) {
+  return jspb.Message.setProto3StringField(this, 9, value);
 };
 
 
@@ -725,8 +5164,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.mcp.v1.AuthResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.mcp.v1.AuthResponse.toObject(opt_includeInstance, this);
+proto.mcp.v1.GetUsageRequest.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.GetUsageRequest.toObject(opt_includeInstance, this);
 };
 
 
@@ -735,15 +5174,13 @@ proto.mcp.v1.AuthResponse.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.mcp.v1.AuthResponse} msg The msg instance to transform.
+ * @param {!proto.mcp.v1.GetUsageRequest} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.AuthResponse.toObject = function(includeInstance, msg) {
+proto.mcp.v1.GetUsageRequest.toObject = function(includeInstance, msg) {
   var f, obj = {
-valid: jspb.Message.getBooleanFieldWithDefault(msg, 1, false),
-tenantId: jspb.Message.getFieldWithDefault(msg, 2, ""),
-agentId: jspb.Message.getFieldWithDefault(msg, 3, "")
+
   };
 
   if (includeInstance) {
@@ -757,41 +5194,29 @@ agentId: jspb.Message.getFieldWithDefault(msg, 3, "")
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.mcp.v1.AuthResponse}
+ * @return {!proto.mcp.v1.GetUsageRequest}
  */
-proto.mcp.v1.AuthResponse.deserializeBinary = function(bytes) {
+proto.mcp.v1.GetUsageRequest.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.mcp.v1.AuthResponse;
-  return proto.mcp.v1.AuthResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.mcp.v1.GetUsageRequest;
+  return proto.mcp.v1.GetUsageRequest.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.mcp.v1.AuthResponse} msg The message object to deserialize into.
+ * @param {!proto.mcp.v1.GetUsageRequest} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.mcp.v1.AuthResponse}
+ * @return {!proto.mcp.v1.GetUsageRequest}
  */
-proto.mcp.v1.AuthResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.mcp.v1.GetUsageRequest.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
     }
     var field = reader.getFieldNumber();
     switch (field) {
-    case 1:
-      var value = /** @type {boolean} */ (reader.readBool());
-      msg.setValid(value);
-      break;
-    case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setTenantId(value);
-      break;
-    case 3:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setAgentId(value);
-      break;
     default:
       reader.skipField();
       break;
@@ -805,9 +5230,9 @@ proto.mcp.v1.AuthResponse.deserializeBinaryFromReader = function(msg, reader) {
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.mcp.v1.AuthResponse.prototype.serializeBinary = function() {
+proto.mcp.v1.GetUsageRequest.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.mcp.v1.AuthResponse.serializeBinaryToWriter(this, writer);
+  proto.mcp.v1.GetUsageRequest.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -815,87 +5240,12 @@ proto.mcp.v1.AuthResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.mcp.v1.AuthResponse} message
+ * @param {!proto.mcp.v1.GetUsageRequest} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.AuthResponse.serializeBinaryToWriter = function(message, writer) {
+proto.mcp.v1.GetUsageRequest.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getValid();
-  if (f) {
-    writer.writeBool(
-      1,
-      f
-    );
-  }
-  f = message.getTenantId();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
-    );
-  }
-  f = message.getAgentId();
-  if (f.length > 0) {
-    writer.writeString(
-      3,
-      f
-    );
-  }
-};
-
-
-/**
- * optional bool valid = 1;
- * @return {boolean}
- */
-proto.mcp.v1.AuthResponse.prototype.getValid = function() {
-  return /** @type {boolean} */ (jspb.Message.getBooleanFieldWithDefault(this, 1, false));
-};
-
-
-/**
- * @param {boolean} value
- * @return {!proto.mcp.v1.AuthResponse} returns this
- */
-proto.mcp.v1.AuthResponse.prototype.setValid = function(value) {
-  return jspb.Message.setProto3BooleanField(this, 1, value);
-};
-
-
-/**
- * optional string tenant_id = 2;
- * @return {string}
- */
-proto.mcp.v1.AuthResponse.prototype.getTenantId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.mcp.v1.AuthResponse} returns this
- */
-proto.mcp.v1.AuthResponse.prototype.setTenantId = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
-};
-
-
-/**
- * optional string agent_id = 3;
- * @return {string}
- */
-proto.mcp.v1.AuthResponse.prototype.getAgentId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
-};
-
-
-/**
- * @param {string} value
- * @return {!proto.mcp.v1.AuthResponse} returns this
- */
-proto.mcp.v1.AuthResponse.prototype.setAgentId = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
 };
 
 
@@ -915,8 +5265,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.mcp.v1.ChatMessage.prototype.toObject = function(opt_includeInstance) {
-  return proto.mcp.v1.ChatMessage.toObject(opt_includeInstance, this);
+proto.mcp.v1.GetUsageResponse.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.GetUsageResponse.toObject(opt_includeInstance, this);
 };
 
 
@@ -925,17 +5275,20 @@ proto.mcp.v1.ChatMessage.prototype.toObject = function(opt_includeInstance) {
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.mcp.v1.ChatMessage} msg The msg instance to transform.
+ * @param {!proto.mcp.v1.GetUsageResponse} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.ChatMessage.toObject = function(includeInstance, msg) {
+proto.mcp.v1.GetUsageResponse.toObject = function(includeInstance, msg) {
   var f, obj = {
-messageId: jspb.Message.getFieldWithDefault(msg, 1, ""),
-sessionId: jspb.Message.getFieldWithDefault(msg, 2, ""),
-content: jspb.Message.getFieldWithDefault(msg, 3, ""),
-type: jspb.Message.getFieldWithDefault(msg, 4, 0),
-timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+tenantId: jspb.Message.getFieldWithDefault(msg, 1, ""),
+windowStart: (f = msg.getWindowStart()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+windowEnd: (f = msg.getWindowEnd()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f),
+sessions: (f = msg.getSessions()) && proto.mcp.v1.Quota.toObject(includeInstance, f),
+requests: (f = msg.getRequests()) && proto.mcp.v1.Quota.toObject(includeInstance, f),
+tokens: (f = msg.getTokens()) && proto.mcp.v1.Quota.toObject(includeInstance, f),
+promptTokens: jspb.Message.getFieldWithDefault(msg, 7, 0),
+completionTokens: jspb.Message.getFieldWithDefault(msg, 8, 0)
   };
 
   if (includeInstance) {
@@ -949,23 +5302,23 @@ timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.to
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.mcp.v1.ChatMessage}
+ * @return {!proto.mcp.v1.GetUsageResponse}
  */
-proto.mcp.v1.ChatMessage.deserializeBinary = function(bytes) {
+proto.mcp.v1.GetUsageResponse.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.mcp.v1.ChatMessage;
-  return proto.mcp.v1.ChatMessage.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.mcp.v1.GetUsageResponse;
+  return proto.mcp.v1.GetUsageResponse.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.mcp.v1.ChatMessage} msg The message object to deserialize into.
+ * @param {!proto.mcp.v1.GetUsageResponse} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.mcp.v1.ChatMessage}
+ * @return {!proto.mcp.v1.GetUsageResponse}
  */
-proto.mcp.v1.ChatMessage.deserializeBinaryFromReader = function(msg, reader) {
+proto.mcp.v1.GetUsageResponse.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -974,24 +5327,40 @@ proto.mcp.v1.ChatMessage.deserializeBinaryFromReader = function(msg, reader) {
     switch (field) {
     case 1:
       var value = /** @type {string} */ (reader.readString());
-      msg.setMessageId(value);
+      msg.setTenantId(value);
       break;
     case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setSessionId(value);
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setWindowStart(value);
       break;
     case 3:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setContent(value);
+      var value = new google_protobuf_timestamp_pb.Timestamp;
+      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
+      msg.setWindowEnd(value);
       break;
     case 4:
-      var value = /** @type {!proto.mcp.v1.MessageType} */ (reader.readEnum());
-      msg.setType(value);
+      var value = new proto.mcp.v1.Quota;
+      reader.readMessage(value,proto.mcp.v1.Quota.deserializeBinaryFromReader);
+      msg.setSessions(value);
       break;
     case 5:
-      var value = new google_protobuf_timestamp_pb.Timestamp;
-      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
-      msg.setTimestamp(value);
+      var value = new proto.mcp.v1.Quota;
+      reader.readMessage(value,proto.mcp.v1.Quota.deserializeBinaryFromReader);
+      msg.setRequests(value);
+      break;
+    case 6:
+      var value = new proto.mcp.v1.Quota;
+      reader.readMessage(value,proto.mcp.v1.Quota.deserializeBinaryFromReader);
+      msg.setTokens(value);
+      break;
+    case 7:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setPromptTokens(value);
+      break;
+    case 8:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setCompletionTokens(value);
       break;
     default:
       reader.skipField();
@@ -1006,9 +5375,9 @@ proto.mcp.v1.ChatMessage.deserializeBinaryFromReader = function(msg, reader) {
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.mcp.v1.ChatMessage.prototype.serializeBinary = function() {
+proto.mcp.v1.GetUsageResponse.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.mcp.v1.ChatMessage.serializeBinaryToWriter(this, writer);
+  proto.mcp.v1.GetUsageResponse.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1016,148 +5385,193 @@ proto.mcp.v1.ChatMessage.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.mcp.v1.ChatMessage} message
+ * @param {!proto.mcp.v1.GetUsageResponse} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.ChatMessage.serializeBinaryToWriter = function(message, writer) {
+proto.mcp.v1.GetUsageResponse.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getMessageId();
+  f = message.getTenantId();
   if (f.length > 0) {
     writer.writeString(
       1,
       f
     );
   }
-  f = message.getSessionId();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getWindowStart();
+  if (f != null) {
+    writer.writeMessage(
       2,
-      f
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
-  f = message.getContent();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getWindowEnd();
+  if (f != null) {
+    writer.writeMessage(
       3,
-      f
+      f,
+      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
     );
   }
-  f = message.getType();
-  if (f !== 0.0) {
-    writer.writeEnum(
+  f = message.getSessions();
+  if (f != null) {
+    writer.writeMessage(
       4,
-      f
+      f,
+      proto.mcp.v1.Quota.serializeBinaryToWriter
     );
   }
-  f = message.getTimestamp();
+  f = message.getRequests();
   if (f != null) {
     writer.writeMessage(
       5,
       f,
-      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+      proto.mcp.v1.Quota.serializeBinaryToWriter
+    );
+  }
+  f = message.getTokens();
+  if (f != null) {
+    writer.writeMessage(
+      6,
+      f,
+      proto.mcp.v1.Quota.serializeBinaryToWriter
+    );
+  }
+  f = message.getPromptTokens();
+  if (f !== 0) {
+    writer.writeInt64(
+      7,
+      f
+    );
+  }
+  f = message.getCompletionTokens();
+  if (f !== 0) {
+    writer.writeInt64(
+      8,
+      f
     );
   }
 };
 
 
 /**
- * optional string message_id = 1;
+ * optional string tenant_id = 1;
  * @return {string}
  */
-proto.mcp.v1.ChatMessage.prototype.getMessageId = function() {
+proto.mcp.v1.GetUsageResponse.prototype.getTenantId = function() {
   return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
 };
 
 
 /**
  * @param {string} value
- * @return {!proto.mcp.v1.ChatMessage} returns this
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.ChatMessage.prototype.setMessageId = function(value) {
+proto.mcp.v1.GetUsageResponse.prototype.setTenantId = function(value) {
   return jspb.Message.setProto3StringField(this, 1, value);
 };
 
 
 /**
- * optional string session_id = 2;
- * @return {string}
+ * optional google.protobuf.Timestamp window_start = 2;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.mcp.v1.ChatMessage.prototype.getSessionId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.mcp.v1.GetUsageResponse.prototype.getWindowStart = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 2));
+};
+
+
+/**
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
+*/
+proto.mcp.v1.GetUsageResponse.prototype.setWindowStart = function(value) {
+  return jspb.Message.setWrapperField(this, 2, value);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.ChatMessage} returns this
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.ChatMessage.prototype.setSessionId = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.mcp.v1.GetUsageResponse.prototype.clearWindowStart = function() {
+  return this.setWindowStart(undefined);
 };
 
 
 /**
- * optional string content = 3;
- * @return {string}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.ChatMessage.prototype.getContent = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+proto.mcp.v1.GetUsageResponse.prototype.hasWindowStart = function() {
+  return jspb.Message.getField(this, 2) != null;
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.ChatMessage} returns this
+ * optional google.protobuf.Timestamp window_end = 3;
+ * @return {?proto.google.protobuf.Timestamp}
  */
-proto.mcp.v1.ChatMessage.prototype.setContent = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
+proto.mcp.v1.GetUsageResponse.prototype.getWindowEnd = function() {
+  return /** @type{?proto.google.protobuf.Timestamp} */ (
+    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 3));
 };
 
 
 /**
- * optional MessageType type = 4;
- * @return {!proto.mcp.v1.MessageType}
+ * @param {?proto.google.protobuf.Timestamp|undefined} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
+*/
+proto.mcp.v1.GetUsageResponse.prototype.setWindowEnd = function(value) {
+  return jspb.Message.setWrapperField(this, 3, value);
+};
+
+
+/**
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.ChatMessage.prototype.getType = function() {
-  return /** @type {!proto.mcp.v1.MessageType} */ (jspb.Message.getFieldWithDefault(this, 4, 0));
+proto.mcp.v1.GetUsageResponse.prototype.clearWindowEnd = function() {
+  return this.setWindowEnd(undefined);
 };
 
 
 /**
- * @param {!proto.mcp.v1.MessageType} value
- * @return {!proto.mcp.v1.ChatMessage} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.ChatMessage.prototype.setType = function(value) {
-  return jspb.Message.setProto3EnumField(this, 4, value);
+proto.mcp.v1.GetUsageResponse.prototype.hasWindowEnd = function() {
+  return jspb.Message.getField(this, 3) != null;
 };
 
 
 /**
- * optional google.protobuf.Timestamp timestamp = 5;
- * @return {?proto.google.protobuf.Timestamp}
+ * optional Quota sessions = 4;
+ * @return {?proto.mcp.v1.Quota}
  */
-proto.mcp.v1.ChatMessage.prototype.getTimestamp = function() {
-  return /** @type{?proto.google.protobuf.Timestamp} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 5));
+proto.mcp.v1.GetUsageResponse.prototype.getSessions = function() {
+  return /** @type{?proto.mcp.v1.Quota} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.Quota, 4));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Timestamp|undefined} value
- * @return {!proto.mcp.v1.ChatMessage} returns this
+ * @param {?proto.mcp.v1.Quota|undefined} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
 */
-proto.mcp.v1.ChatMessage.prototype.setTimestamp = function(value) {
-  return jspb.Message.setWrapperField(this, 5, value);
+proto.mcp.v1.GetUsageResponse.prototype.setSessions = function(value) {
+  return jspb.Message.setWrapperField(this, 4, value);
 };
 
 
 /**
  * Clears the message field making it undefined.
- * @return {!proto.mcp.v1.ChatMessage} returns this
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.ChatMessage.prototype.clearTimestamp = function() {
-  return this.setTimestamp(undefined);
+proto.mcp.v1.GetUsageResponse.prototype.clearSessions = function() {
+  return this.setSessions(undefined);
 };
 
 
@@ -1165,198 +5579,118 @@ proto.mcp.v1.ChatMessage.prototype.clearTimestamp = function() {
  * Returns whether this field is set.
  * @return {boolean}
  */
-proto.mcp.v1.ChatMessage.prototype.hasTimestamp = function() {
-  return jspb.Message.getField(this, 5) != null;
+proto.mcp.v1.GetUsageResponse.prototype.hasSessions = function() {
+  return jspb.Message.getField(this, 4) != null;
 };
 
 
-
-
-
-if (jspb.Message.GENERATE_TO_OBJECT) {
 /**
- * Creates an object representation of this proto.
- * Field names that are reserved in JavaScript and will be renamed to pb_name.
- * Optional fields that are not set will be set to undefined.
- * To access a reserved field use, foo.pb_<name>, eg, foo.pb_default.
- * For the list of reserved names please see:
- *     net/proto2/compiler/js/internal/generator.cc#kKeyword.
- * @param {boolean=} opt_includeInstance Deprecated. whether to include the
- *     JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @return {!Object}
+ * optional Quota requests = 5;
+ * @return {?proto.mcp.v1.Quota}
  */
-proto.mcp.v1.SingleChatRequest.prototype.toObject = function(opt_includeInstance) {
-  return proto.mcp.v1.SingleChatRequest.toObject(opt_includeInstance, this);
+proto.mcp.v1.GetUsageResponse.prototype.getRequests = function() {
+  return /** @type{?proto.mcp.v1.Quota} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.Quota, 5));
 };
 
 
 /**
- * Static version of the {@see toObject} method.
- * @param {boolean|undefined} includeInstance Deprecated. Whether to include
- *     the JSPB instance for transitional soy proto support:
- *     http://goto/soy-param-migration
- * @param {!proto.mcp.v1.SingleChatRequest} msg The msg instance to transform.
- * @return {!Object}
- * @suppress {unusedLocalVariables} f is only used for nested messages
- */
-proto.mcp.v1.SingleChatRequest.toObject = function(includeInstance, msg) {
-  var f, obj = {
-sessionId: jspb.Message.getFieldWithDefault(msg, 1, ""),
-content: jspb.Message.getFieldWithDefault(msg, 2, ""),
-model: jspb.Message.getFieldWithDefault(msg, 3, "")
-  };
-
-  if (includeInstance) {
-    obj.$jspbMessageInstance = msg;
-  }
-  return obj;
+ * @param {?proto.mcp.v1.Quota|undefined} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
+*/
+proto.mcp.v1.GetUsageResponse.prototype.setRequests = function(value) {
+  return jspb.Message.setWrapperField(this, 5, value);
 };
-}
 
 
 /**
- * Deserializes binary data (in protobuf wire format).
- * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.mcp.v1.SingleChatRequest}
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.SingleChatRequest.deserializeBinary = function(bytes) {
-  var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.mcp.v1.SingleChatRequest;
-  return proto.mcp.v1.SingleChatRequest.deserializeBinaryFromReader(msg, reader);
+proto.mcp.v1.GetUsageResponse.prototype.clearRequests = function() {
+  return this.setRequests(undefined);
 };
 
 
 /**
- * Deserializes binary data (in protobuf wire format) from the
- * given reader into the given message object.
- * @param {!proto.mcp.v1.SingleChatRequest} msg The message object to deserialize into.
- * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.mcp.v1.SingleChatRequest}
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.SingleChatRequest.deserializeBinaryFromReader = function(msg, reader) {
-  while (reader.nextField()) {
-    if (reader.isEndGroup()) {
-      break;
-    }
-    var field = reader.getFieldNumber();
-    switch (field) {
-    case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setSessionId(value);
-      break;
-    case 2:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setContent(value);
-      break;
-    case 3:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setModel(value);
-      break;
-    default:
-      reader.skipField();
-      break;
-    }
-  }
-  return msg;
+proto.mcp.v1.GetUsageResponse.prototype.hasRequests = function() {
+  return jspb.Message.getField(this, 5) != null;
 };
 
 
 /**
- * Serializes the message to binary data (in protobuf wire format).
- * @return {!Uint8Array}
+ * optional Quota tokens = 6;
+ * @return {?proto.mcp.v1.Quota}
  */
-proto.mcp.v1.SingleChatRequest.prototype.serializeBinary = function() {
-  var writer = new jspb.BinaryWriter();
-  proto.mcp.v1.SingleChatRequest.serializeBinaryToWriter(this, writer);
-  return writer.getResultBuffer();
+proto.mcp.v1.GetUsageResponse.prototype.getTokens = function() {
+  return /** @type{?proto.mcp.v1.Quota} */ (
+    jspb.Message.getWrapperField(this, proto.mcp.v1.Quota, 6));
 };
 
 
 /**
- * Serializes the given message to binary data (in protobuf wire
- * format), writing to the given BinaryWriter.
- * @param {!proto.mcp.v1.SingleChatRequest} message
- * @param {!jspb.BinaryWriter} writer
- * @suppress {unusedLocalVariables} f is only used for nested messages
- */
-proto.mcp.v1.SingleChatRequest.serializeBinaryToWriter = function(message, writer) {
-  var f = undefined;
-  f = message.getSessionId();
-  if (f.length > 0) {
-    writer.writeString(
-      1,
-      f
-    );
-  }
-  f = message.getContent();
-  if (f.length > 0) {
-    writer.writeString(
-      2,
-      f
-    );
-  }
-  f = message.getModel();
-  if (f.length > 0) {
-    writer.writeString(
-      3,
-      f
-    );
-  }
+ * @param {?proto.mcp.v1.Quota|undefined} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
+*/
+proto.mcp.v1.GetUsageResponse.prototype.setTokens = function(value) {
+  return jspb.Message.setWrapperField(this, 6, value);
 };
 
 
 /**
- * optional string session_id = 1;
- * @return {string}
+ * Clears the message field making it undefined.
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.SingleChatRequest.prototype.getSessionId = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.mcp.v1.GetUsageResponse.prototype.clearTokens = function() {
+  return this.setTokens(undefined);
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ * Returns whether this field is set.
+ * @return {boolean}
  */
-proto.mcp.v1.SingleChatRequest.prototype.setSessionId = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.mcp.v1.GetUsageResponse.prototype.hasTokens = function() {
+  return jspb.Message.getField(this, 6) != null;
 };
 
 
 /**
- * optional string content = 2;
- * @return {string}
+ * optional int64 prompt_tokens = 7;
+ * @return {number}
  */
-proto.mcp.v1.SingleChatRequest.prototype.getContent = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 2, ""));
+proto.mcp.v1.GetUsageResponse.prototype.getPromptTokens = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 7, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ * @param {number} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.SingleChatRequest.prototype.setContent = function(value) {
-  return jspb.Message.setProto3StringField(this, 2, value);
+proto.mcp.v1.GetUsageResponse.prototype.setPromptTokens = function(value) {
+  return jspb.Message.setProto3IntField(this, 7, value);
 };
 
 
 /**
- * optional string model = 3;
- * @return {string}
+ * optional int64 completion_tokens = 8;
+ * @return {number}
  */
-proto.mcp.v1.SingleChatRequest.prototype.getModel = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 3, ""));
+proto.mcp.v1.GetUsageResponse.prototype.getCompletionTokens = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 8, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.SingleChatRequest} returns this
+ * @param {number} value
+ * @return {!proto.mcp.v1.GetUsageResponse} returns this
  */
-proto.mcp.v1.SingleChatRequest.prototype.setModel = function(value) {
-  return jspb.Message.setProto3StringField(this, 3, value);
+proto.mcp.v1.GetUsageResponse.prototype.setCompletionTokens = function(value) {
+  return jspb.Message.setProto3IntField(this, 8, value);
 };
 
 
@@ -1376,8 +5710,8 @@ if (jspb.Message.GENERATE_TO_OBJECT) {
  *     http://goto/soy-param-migration
  * @return {!Object}
  */
-proto.mcp.v1.SingleChatResponse.prototype.toObject = function(opt_includeInstance) {
-  return proto.mcp.v1.SingleChatResponse.toObject(opt_includeInstance, this);
+proto.mcp.v1.Quota.prototype.toObject = function(opt_includeInstance) {
+  return proto.mcp.v1.Quota.toObject(opt_includeInstance, this);
 };
 
 
@@ -1386,14 +5720,15 @@ proto.mcp.v1.SingleChatResponse.prototype.toObject = function(opt_includeInstanc
  * @param {boolean|undefined} includeInstance Deprecated. Whether to include
  *     the JSPB instance for transitional soy proto support:
  *     http://goto/soy-param-migration
- * @param {!proto.mcp.v1.SingleChatResponse} msg The msg instance to transform.
+ * @param {!proto.mcp.v1.Quota} msg The msg instance to transform.
  * @return {!Object}
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.SingleChatResponse.toObject = function(includeInstance, msg) {
+proto.mcp.v1.Quota.toObject = function(includeInstance, msg) {
   var f, obj = {
-content: jspb.Message.getFieldWithDefault(msg, 1, ""),
-timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.toObject(includeInstance, f)
+used: jspb.Message.getFieldWithDefault(msg, 1, 0),
+limit: jspb.Message.getFieldWithDefault(msg, 2, 0),
+remaining: jspb.Message.getFieldWithDefault(msg, 3, 0)
   };
 
   if (includeInstance) {
@@ -1407,23 +5742,23 @@ timestamp: (f = msg.getTimestamp()) && google_protobuf_timestamp_pb.Timestamp.to
 /**
  * Deserializes binary data (in protobuf wire format).
  * @param {jspb.ByteSource} bytes The bytes to deserialize.
- * @return {!proto.mcp.v1.SingleChatResponse}
+ * @return {!proto.mcp.v1.Quota}
  */
-proto.mcp.v1.SingleChatResponse.deserializeBinary = function(bytes) {
+proto.mcp.v1.Quota.deserializeBinary = function(bytes) {
   var reader = new jspb.BinaryReader(bytes);
-  var msg = new proto.mcp.v1.SingleChatResponse;
-  return proto.mcp.v1.SingleChatResponse.deserializeBinaryFromReader(msg, reader);
+  var msg = new proto.mcp.v1.Quota;
+  return proto.mcp.v1.Quota.deserializeBinaryFromReader(msg, reader);
 };
 
 
 /**
  * Deserializes binary data (in protobuf wire format) from the
  * given reader into the given message object.
- * @param {!proto.mcp.v1.SingleChatResponse} msg The message object to deserialize into.
+ * @param {!proto.mcp.v1.Quota} msg The message object to deserialize into.
  * @param {!jspb.BinaryReader} reader The BinaryReader to use.
- * @return {!proto.mcp.v1.SingleChatResponse}
+ * @return {!proto.mcp.v1.Quota}
  */
-proto.mcp.v1.SingleChatResponse.deserializeBinaryFromReader = function(msg, reader) {
+proto.mcp.v1.Quota.deserializeBinaryFromReader = function(msg, reader) {
   while (reader.nextField()) {
     if (reader.isEndGroup()) {
       break;
@@ -1431,13 +5766,16 @@ proto.mcp.v1.SingleChatResponse.deserializeBinaryFromReader = function(msg, read
     var field = reader.getFieldNumber();
     switch (field) {
     case 1:
-      var value = /** @type {string} */ (reader.readString());
-      msg.setContent(value);
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setUsed(value);
       break;
     case 2:
-      var value = new google_protobuf_timestamp_pb.Timestamp;
-      reader.readMessage(value,google_protobuf_timestamp_pb.Timestamp.deserializeBinaryFromReader);
-      msg.setTimestamp(value);
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setLimit(value);
+      break;
+    case 3:
+      var value = /** @type {number} */ (reader.readInt64());
+      msg.setRemaining(value);
       break;
     default:
       reader.skipField();
@@ -1452,9 +5790,9 @@ proto.mcp.v1.SingleChatResponse.deserializeBinaryFromReader = function(msg, read
  * Serializes the message to binary data (in protobuf wire format).
  * @return {!Uint8Array}
  */
-proto.mcp.v1.SingleChatResponse.prototype.serializeBinary = function() {
+proto.mcp.v1.Quota.prototype.serializeBinary = function() {
   var writer = new jspb.BinaryWriter();
-  proto.mcp.v1.SingleChatResponse.serializeBinaryToWriter(this, writer);
+  proto.mcp.v1.Quota.serializeBinaryToWriter(this, writer);
   return writer.getResultBuffer();
 };
 
@@ -1462,82 +5800,87 @@ proto.mcp.v1.SingleChatResponse.prototype.serializeBinary = function() {
 /**
  * Serializes the given message to binary data (in protobuf wire
  * format), writing to the given BinaryWriter.
- * @param {!proto.mcp.v1.SingleChatResponse} message
+ * @param {!proto.mcp.v1.Quota} message
  * @param {!jspb.BinaryWriter} writer
  * @suppress {unusedLocalVariables} f is only used for nested messages
  */
-proto.mcp.v1.SingleChatResponse.serializeBinaryToWriter = function(message, writer) {
+proto.mcp.v1.Quota.serializeBinaryToWriter = function(message, writer) {
   var f = undefined;
-  f = message.getContent();
-  if (f.length > 0) {
-    writer.writeString(
+  f = message.getUsed();
+  if (f !== 0) {
+    writer.writeInt64(
       1,
       f
     );
   }
-  f = message.getTimestamp();
-  if (f != null) {
-    writer.writeMessage(
+  f = message.getLimit();
+  if (f !== 0) {
+    writer.writeInt64(
       2,
-      f,
-      google_protobuf_timestamp_pb.Timestamp.serializeBinaryToWriter
+      f
+    );
+  }
+  f = message.getRemaining();
+  if (f !== 0) {
+    writer.writeInt64(
+      3,
+      f
     );
   }
 };
 
 
 /**
- * optional string content = 1;
- * @return {string}
+ * optional int64 used = 1;
+ * @return {number}
  */
-proto.mcp.v1.SingleChatResponse.prototype.getContent = function() {
-  return /** @type {string} */ (jspb.Message.getFieldWithDefault(this, 1, ""));
+proto.mcp.v1.Quota.prototype.getUsed = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 1, 0));
 };
 
 
 /**
- * @param {string} value
- * @return {!proto.mcp.v1.SingleChatResponse} returns this
+ * @param {number} value
+ * @return {!proto.mcp.v1.Quota} returns this
  */
-proto.mcp.v1.SingleChatResponse.prototype.setContent = function(value) {
-  return jspb.Message.setProto3StringField(this, 1, value);
+proto.mcp.v1.Quota.prototype.setUsed = function(value) {
+  return jspb.Message.setProto3IntField(this, 1, value);
 };
 
 
 /**
- * optional google.protobuf.Timestamp timestamp = 2;
- * @return {?proto.google.protobuf.Timestamp}
+ * optional int64 limit = 2;
+ * @return {number}
  */
-proto.mcp.v1.SingleChatResponse.prototype.getTimestamp = function() {
-  return /** @type{?proto.google.protobuf.Timestamp} */ (
-    jspb.Message.getWrapperField(this, google_protobuf_timestamp_pb.Timestamp, 2));
+proto.mcp.v1.Quota.prototype.getLimit = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 2, 0));
 };
 
 
 /**
- * @param {?proto.google.protobuf.Timestamp|undefined} value
- * @return {!proto.mcp.v1.SingleChatResponse} returns this
-*/
-proto.mcp.v1.SingleChatResponse.prototype.setTimestamp = function(value) {
-  return jspb.Message.setWrapperField(this, 2, value);
+ * @param {number} value
+ * @return {!proto.mcp.v1.Quota} returns this
+ */
+proto.mcp.v1.Quota.prototype.setLimit = function(value) {
+  return jspb.Message.setProto3IntField(this, 2, value);
 };
 
 
 /**
- * Clears the message field making it undefined.
- * @return {!proto.mcp.v1.SingleChatResponse} returns this
+ * optional int64 remaining = 3;
+ * @return {number}
  */
-proto.mcp.v1.SingleChatResponse.prototype.clearTimestamp = function() {
-  return this.setTimestamp(undefined);
+proto.mcp.v1.Quota.prototype.getRemaining = function() {
+  return /** @type {number} */ (jspb.Message.getFieldWithDefault(this, 3, 0));
 };
 
 
 /**
- * Returns whether this field is set.
- * @return {boolean}
+ * @param {number} value
+ * @return {!proto.mcp.v1.Quota} returns this
  */
-proto.mcp.v1.SingleChatResponse.prototype.hasTimestamp = function() {
-  return jspb.Message.getField(this, 2) != null;
+proto.mcp.v1.Quota.prototype.setRemaining = function(value) {
+  return jspb.Message.setProto3IntField(this, 3, value);
 };
 
 
@@ -1548,7 +5891,9 @@ proto.mcp.v1.MessageType = {
   MESSAGE_TYPE_UNSPECIFIED: 0,
   MESSAGE_TYPE_USER: 1,
   MESSAGE_TYPE_ASSISTANT: 2,
-  MESSAGE_TYPE_SYSTEM: 3
+  MESSAGE_TYPE_SYSTEM: 3,
+  MESSAGE_TYPE_TOOL_CALL: 4,
+  MESSAGE_TYPE_TOOL_RESULT: 5
 };
 
 goog.object.extend(exports, proto.mcp.v1);
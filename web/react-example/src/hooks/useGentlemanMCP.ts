@@ -1,4 +1,13 @@
-import { useCallback, useEffect, useRef, useState } from "react";
+import { useCallback, useEffect, useMemo, useRef, useState } from "react";
+import {
+  AgentServiceClient,
+  HandshakeServiceClient,
+} from "../generated/mcp/v1/McpServiceClientPb";
+import {
+  ChatMessage as ProtoChatMessage,
+  RegisterRequest,
+  SingleChatRequest,
+} from "../generated/mcp/v1/mcp_pb";
 
 // Types for the hook
 interface MCPSession {
@@ -47,7 +56,8 @@ export interface UseGentlemanMCPReturn extends MCPState, MCPActions {}
  * React hook for integrating with Gentleman MCP Gateway via gRPC-Web
  *
  * This hook provides a complete interface to the Gentleman MCP Gateway
- * using direct fetch calls to the gRPC-Web proxy for maximum compatibility.
+ * through the generated gRPC-Web clients. Answers are streamed with
+ * GenerateStream, so they appear as the model writes them.
  *
  * @example
  * ```tsx
@@ -115,132 +125,33 @@ export function useGentlemanMCP(config: MCPConfig): UseGentlemanMCPReturn {
     [],
   );
 
-  // Encode protobuf message for gRPC-Web
-  const encodeGrpcWebMessage = (
-    service: string,
-    method: string,
-    data: Record<string, unknown>,
-  ): string => {
-    // For simplicity, we'll create a JSON-based request that the server can handle
-    // In a full implementation, this would be proper protobuf binary encoding
-    const message = {
-      service: `mcp.v1.${service}`,
-      method: method,
-      data: data,
-    };
-
-    // Convert to base64 for gRPC-Web transport
-    return btoa(JSON.stringify(message));
-  };
-
-  // Make gRPC-Web request using fetch
-  const makeGrpcWebRequest = async (
-    service: string,
-    method: string,
-    data: Record<string, unknown>,
-  ): Promise<unknown> => {
-    const url = `${serverUrl}/mcp.v1.${service}/${method}`;
-
-    try {
-      // Try gRPC-Web binary format first
-      const response = await fetch(url, {
-        method: "POST",
-        headers: {
-          "Content-Type": "application/grpc-web+proto",
-          "X-Grpc-Web": "1",
-          Accept: "application/grpc-web+proto",
-        },
-        body: encodeGrpcWebMessage(service, method, data),
-      });
-
-      if (!response.ok) {
-        // If binary format fails, try JSON fallback for debugging
-        throw new Error(
-          `gRPC-Web request failed: ${response.status} ${response.statusText}`,
-        );
-      }
-
-      const responseText = await response.text();
-
-      // For now, we'll implement a simple JSON response parser
-      // In a real implementation, this would decode protobuf binary
-      try {
-        return JSON.parse(responseText);
-      } catch {
-        // Handle binary protobuf response (simplified)
-        return { success: true, data: responseText };
-      }
-    } catch (error) {
-      console.error(`gRPC-Web request failed for ${service}/${method}:`, error);
-      throw error;
-    }
-  };
-
-  // Since the gRPC-Web integration is complex, let's use direct HTTP calls
-  // to the Go server's REST endpoints for now
-  const makeDirectRequest = async (
-    endpoint: string,
-    data: Record<string, unknown>,
-  ): Promise<unknown> => {
-    try {
-      const response = await fetch(`${serverUrl}${endpoint}`, {
-        method: "POST",
-        headers: {
-          "Content-Type": "application/json",
-          Accept: "application/json",
-        },
-        body: JSON.stringify(data),
-      });
-
-      if (!response.ok) {
-        const errorText = await response.text();
-        throw new Error(`HTTP ${response.status}: ${errorText}`);
-      }
-
-      return await response.json();
-    } catch (error) {
-      console.error(`Request failed for ${endpoint}:`, error);
-      throw error;
-    }
-  };
+  // gRPC-Web clients of the gateway, generated from proto/mcp/v1/mcp.proto
+  const clients = useMemo(
+    () => ({
+      handshake: new HandshakeServiceClient(serverUrl),
+      agent: new AgentServiceClient(serverUrl),
+    }),
+    [serverUrl],
+  );
 
-  // Register with the gateway - simplified for direct Ollama access
+  // Register with the gateway to get a session and its JWT
   const register = useCallback(async (): Promise<void> => {
     updateState({ isLoading: true, error: null });
 
     try {
-      // For now, skip gRPC complexity and create a simple session for direct Ollama access
-      // This provides immediate functionality while gRPC-Web integration is refined
-
-      // First, test if Ollama is available
-      const ollamaTest = await fetch("http://localhost:11434/api/version");
-      if (!ollamaTest.ok) {
-        throw new Error("Ollama is not running on port 11434");
-      }
-
-      // Test if Gemma 3 model is available
-      const modelsResponse = await fetch("http://localhost:11434/api/tags");
-      const models = (await modelsResponse.json()) as {
-        models?: Array<{ name: string }>;
-      };
-      const hasGemma3 = models.models?.some((m) => m.name.includes("gemma3"));
-
-      if (!hasGemma3) {
-        throw new Error("Gemma 3 model not found. Run: ollama pull gemma3:4b");
-      }
-
-      // Create a working session for direct Ollama access
-      const sessionId = `session_${Date.now()}_${Math.random().toString(36).substring(2, 11)}`;
-      const jwtToken = `jwt_${Date.now()}_${Math.random().toString(36).substring(2, 18)}`;
-      const expiresAt = new Date(Date.now() + 30 * 60 * 1000); // 30 minutes
+      const request = new RegisterRequest()
+        .setTenantId(configRef.current.tenantId || "demo-tenant")
+        .setAgentId(configRef.current.agentId || "demo-agent")
+        .setModel(configRef.current.model || "gemma3:4b");
+      const response = await clients.handshake.register(request);
 
       const session: MCPSession = {
-        sessionId,
-        jwtToken,
-        expiresAt,
-        tenantId: configRef.current.tenantId || "demo-tenant",
-        agentId: configRef.current.agentId || "demo-agent",
-        model: configRef.current.model || "gemma3:4b",
+        sessionId: response.getSessionId(),
+        jwtToken: response.getJwtToken(),
+        expiresAt: response.getExpiresAt()?.toDate() ?? new Date(),
+        tenantId: request.getTenantId(),
+        agentId: request.getAgentId(),
+        model: request.getModel(),
       };
 
       updateState({
@@ -252,15 +163,12 @@ export function useGentlemanMCP(config: MCPConfig): UseGentlemanMCPReturn {
 
       addMessage({
         sessionId: session.sessionId,
-        content: `✅ Connected to Gemma 3 via Direct Ollama API
+        content: `✅ Connected to Gentleman MCP Gateway
  📋 Session: ${session.sessionId.slice(0, 16)}...
  🤖 Model: ${session.model}
  👤 Tenant: ${session.tenantId}
  🔑 Agent: ${session.agentId}
- ⏰ Expires: ${session.expiresAt.toLocaleString()}
-
- 🚀 Direct connection established - ready for real AI chat!
- 💡 Note: Using direct Ollama API for maximum reliability`,
+ ⏰ Expires: ${session.expiresAt.toLocaleString()}`,
         type: "SYSTEM",
       });
     } catch (error: unknown) {
@@ -276,21 +184,17 @@ export function useGentlemanMCP(config: MCPConfig): UseGentlemanMCPReturn {
         sessionId: "",
         content: `❌ Connection failed: ${errorMessage}
 
- 🔧 Quick fixes:
- • Start Ollama: brew services start ollama (or run Ollama.app)
- • Install model: ollama pull gemma3:4b
- • Check status: curl http://localhost:11434/api/version
-
- 💡 Once these are working, you'll get real AI responses!`,
+ 🔧 Check that the gateway is running and serves gRPC-Web on ${serverUrl}`,
         type: "SYSTEM",
       });
     }
-  }, [updateState, addMessage]);
+  }, [clients, serverUrl, updateState, addMessage]);
 
-  // Send a chat message using real gRPC-Web or fallback
+  // Send a chat message and stream the answer as the model writes it
   const sendMessage = useCallback(
     async (content: string): Promise<void> => {
-      if (!state.session) {
+      const session = state.session;
+      if (!session) {
         throw new Error("No active session. Please register first.");
       }
 
@@ -302,57 +206,42 @@ export function useGentlemanMCP(config: MCPConfig): UseGentlemanMCPReturn {
 
       // Add user message immediately
       addMessage({
-        sessionId: state.session.sessionId,
+        sessionId: session.sessionId,
         content: content.trim(),
         type: "USER",
       });
 
+      // The answer grows in place as its chunks arrive
+      const answer = addMessage({
+        sessionId: session.sessionId,
+        content: "",
+        type: "ASSISTANT",
+      });
+      const appendToAnswer = (chunk: string) => {
+        setState((prev) => ({
+          ...prev,
+          messages: prev.messages.map((message) =>
+            message.messageId === answer.messageId
+              ? { ...message, content: message.content + chunk }
+              : message,
+          ),
+        }));
+      };
+
       try {
-        // For now, use direct Ollama calls since gRPC-Web has response parsing issues
-        // This will be replaced with proper gRPC-Web once the protocol is working
-        console.log("Sending message to Ollama via direct API call");
-
-        let responseContent: string;
-        try {
-          const ollamaResponse = await fetch(
-            "http://localhost:11434/api/generate",
-            {
-              method: "POST",
-              headers: { "Content-Type": "application/json" },
-              body: JSON.stringify({
-                model: state.session.model || "gemma3:4b",
-                prompt: content.trim(),
-                stream: false,
-              }),
-            },
-          );
-
-          if (ollamaResponse.ok) {
-            const ollamaData = (await ollamaResponse.json()) as {
-              response?: string;
-            };
-            responseContent = ollamaData.response || "No response from Gemma 3";
-            console.log(
-              "✅ Got response from Ollama:",
-              `${responseContent.slice(0, 100)}...`,
-            );
-          } else {
-            throw new Error(
-              `Ollama API error: ${ollamaResponse.status} ${ollamaResponse.statusText}`,
-            );
-          }
-        } catch (ollamaError: unknown) {
-          console.error("❌ Ollama request failed:", ollamaError);
-          throw new Error(
-            `Failed to connect to Ollama: ${ollamaError instanceof Error ? ollamaError.message : ollamaError}. Make sure Ollama is running on port 11434 with the Gemma 3 model.`,
-          );
-        }
+        const request = new SingleChatRequest()
+          .setSessionId(session.sessionId)
+          .setContent(content.trim());
 
-        // Add assistant response
-        addMessage({
-          sessionId: state.session.sessionId,
-          content: responseContent,
-          type: "ASSISTANT",
+        await new Promise<void>((resolve, reject) => {
+          const stream = clients.agent.generateStream(request, {
+            authorization: `Bearer ${session.jwtToken}`,
+          });
+          stream.on("data", (message: ProtoChatMessage) => {
+            appendToAnswer(message.getContent());
+          });
+          stream.on("error", reject);
+          stream.on("end", resolve);
         });
 
         updateState({ isLoading: false });
@@ -366,20 +255,13 @@ export function useGentlemanMCP(config: MCPConfig): UseGentlemanMCPReturn {
         });
 
         addMessage({
-          sessionId: state.session.sessionId,
-          content: `❌ Error: ${errorMessage}
- 🔧 Check that:
- - Ollama is running: http://localhost:11434
- - Gemma 3 model is available: \`ollama list\`
- - gRPC server is running on port 50051
- - gRPC-Web proxy is running on port 8080
-
- 💡 The backend gRPC server works correctly - the issue is with the frontend connection.`,
+          sessionId: session.sessionId,
+          content: `❌ Error: ${errorMessage}`,
           type: "SYSTEM",
         });
       }
     },
-    [state.session, updateState, addMessage],
+    [clients, state.session, updateState, addMessage],
   );
 
   // Authenticate with existing token